@@ -19,6 +19,8 @@ import (
 	"citizen-appeals/pkg/auth"
 	"citizen-appeals/pkg/classification"
 	"citizen-appeals/pkg/database"
+	"citizen-appeals/pkg/geo"
+	"citizen-appeals/pkg/mail"
 	"citizen-appeals/pkg/storage"
 
 	"github.com/go-chi/chi/v5"
@@ -74,6 +76,14 @@ func main() {
 	userServiceRepo := repository.NewUserServiceRepository(db.Pool)
 	commentRepo := repository.NewCommentRepository(db.Pool)
 	notificationRepo := repository.NewNotificationRepository(db.Pool)
+	slaPolicyRepo := repository.NewSLAPolicyRepository(db.Pool)
+	priorityRuleRepo := repository.NewPriorityRuleRepository(db.Pool)
+	districtRepo := repository.NewDistrictRepository(db.Pool)
+	hotspotRepo := repository.NewHotspotRepository(db.Pool)
+	volumeAlertRepo := repository.NewVolumeAlertRepository(db.Pool)
+	reportSubscriptionRepo := repository.NewReportSubscriptionRepository(db.Pool)
+	savedViewRepo := repository.NewSavedViewRepository(db.Pool)
+	tagRepo := repository.NewTagRepository(db.Pool)
 
 	// Initialize services
 	tokenService := auth.NewTokenService(cfg.JWT.Secret, cfg.JWT.Expiration)
@@ -84,9 +94,14 @@ func main() {
 	systemSettingsLoader := func(ctx context.Context) (*models.SystemSettings, error) {
 		return systemSettingsHandler.GetSettings()
 	}
+	cityBoundaryLoader := func(ctx context.Context) (geo.Area, error) {
+		return systemSettingsHandler.GetCityBoundary()
+	}
 
-	appealService := service.NewAppealService(appealRepo, serviceRepo, classifier, systemSettingsLoader)
+	appealService := service.NewAppealService(appealRepo, serviceRepo, categoryRepo, slaPolicyRepo, userServiceRepo, priorityRuleRepo, districtRepo, classifier, systemSettingsLoader, cityBoundaryLoader)
 	notificationService := service.NewNotificationService(notificationRepo, userRepo, appealRepo, serviceRepo)
+	savedViewService := service.NewSavedViewService(savedViewRepo, appealRepo, notificationService)
+	tagService := service.NewTagService(tagRepo)
 
 	// Initialize storage
 	fileStorage, err := storage.NewLocalStorage(cfg)
@@ -94,11 +109,17 @@ func main() {
 		log.Fatalf("Failed to initialize storage: %v", err)
 	}
 
+	// Initialize mail
+	mailSender, err := mail.NewSender(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize mail sender: %v", err)
+	}
+
 	// Initialize handlers
 	validator := validator.New()
 	authHandler := handler.NewAuthHandler(userRepo, tokenService)
 	userHandler := handler.NewUserHandler(userRepo, validator)
-	appealHandler := handler.NewAppealHandler(appealRepo, appealService, notificationService)
+	appealHandler := handler.NewAppealHandler(appealRepo, appealService, notificationService, savedViewService)
 	categoryHandler := handler.NewCategoryHandler(categoryRepo)
 	// Формуємо URL бекенду для синхронізації (використовуємо localhost замість 0.0.0.0)
 	backendHost := cfg.Server.Host
@@ -109,9 +130,39 @@ func main() {
 	serviceHandler := handler.NewServiceHandler(serviceRepo, embeddingRepo, cfg.Classification.ServiceURL, backendURL)
 	categoryServiceHandler := handler.NewCategoryServiceHandler(categoryServiceRepo)
 	userServiceHandler := handler.NewUserServiceHandler(userServiceRepo)
-	photoHandler := handler.NewPhotoHandler(photoRepo, appealRepo, fileStorage)
-	commentHandler := handler.NewCommentHandler(commentRepo, appealRepo, notificationService)
+	photoHandler := handler.NewPhotoHandler(photoRepo, appealRepo, commentRepo, appealService, fileStorage)
+	commentHandler := handler.NewCommentHandler(commentRepo, appealRepo, notificationService, fileStorage)
 	notificationHandler := handler.NewNotificationHandler(notificationRepo)
+	slaPolicyHandler := handler.NewSLAPolicyHandler(slaPolicyRepo)
+	priorityRuleHandler := handler.NewPriorityRuleHandler(priorityRuleRepo)
+	districtHandler := handler.NewDistrictHandler(districtRepo)
+	hotspotDetector := service.NewHotspotDetector(hotspotRepo, systemSettingsLoader, cfg.Jobs.HotspotInterval)
+	hotspotHandler := handler.NewHotspotHandler(hotspotRepo, hotspotDetector)
+	volumeAlertHandler := handler.NewVolumeAlertHandler(volumeAlertRepo)
+	reportScheduler := service.NewReportScheduler(reportSubscriptionRepo, appealRepo, mailSender, cfg.Jobs.ReportInterval)
+	reportSubscriptionHandler := handler.NewReportSubscriptionHandler(reportSubscriptionRepo, reportScheduler)
+	savedViewHandler := handler.NewSavedViewHandler(savedViewRepo, savedViewService)
+	tagHandler := handler.NewTagHandler(tagRepo, tagService)
+
+	// Background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	slaEscalator := service.NewSLAEscalator(appealRepo, notificationService, cfg.Jobs.SLACheckInterval)
+	go slaEscalator.Run(jobsCtx)
+
+	autoCloser := service.NewAppealAutoCloser(appealRepo, notificationService, systemSettingsLoader, cfg.Jobs.AutoCloseInterval)
+	go autoCloser.Run(jobsCtx)
+
+	go hotspotDetector.Run(jobsCtx)
+
+	spikeDetector := service.NewVolumeSpikeDetector(volumeAlertRepo, notificationService, systemSettingsLoader, cfg.Jobs.SpikeInterval)
+	go spikeDetector.Run(jobsCtx)
+
+	go reportScheduler.Run(jobsCtx)
+
+	stagedPhotoCleaner := service.NewStagedPhotoCleaner(photoRepo, fileStorage, cfg.Upload.StagedPhotoTTL, cfg.Jobs.StagedPhotoInterval)
+	go stagedPhotoCleaner.Run(jobsCtx)
 
 	// Setup router
 	r := chi.NewRouter()
@@ -167,17 +218,23 @@ func main() {
 			// Read for any authenticated user
 			r.Get("/", systemSettingsHandler.Get)
 
+			r.Get("/boundary", systemSettingsHandler.GetBoundary)
+
 			// Update only for admin
 			r.Group(func(r chi.Router) {
 				r.Use(middleware.RequireRole(models.RoleAdmin))
 				r.Put("/", systemSettingsHandler.Update)
+				r.Put("/boundary", systemSettingsHandler.UpdateBoundary)
+				r.Delete("/boundary", systemSettingsHandler.DeleteBoundary)
 			})
 		})
 
 		// Categories routes (public read, admin write)
 		r.Route("/categories", func(r chi.Router) {
 			r.Get("/", categoryHandler.List)
+			r.Get("/tree", categoryHandler.Tree)
 			r.Get("/{id}", categoryHandler.GetByID)
+			r.Get("/{id}/subcategories", categoryHandler.Subcategories)
 
 			// Admin only
 			r.Group(func(r chi.Router) {
@@ -188,6 +245,102 @@ func main() {
 			})
 		})
 
+		// Tags routes (authenticated read, admin write of curated tags)
+		r.Route("/tags", func(r chi.Router) {
+			r.Get("/", tagHandler.List)
+
+			// Admin only
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(models.RoleAdmin))
+				r.Post("/", tagHandler.Create)
+				r.Put("/{id}", tagHandler.Update)
+				r.Delete("/{id}", tagHandler.Delete)
+			})
+		})
+
+		// SLA policies routes (dispatcher read, admin write)
+		r.Route("/sla-policies", func(r chi.Router) {
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(models.RoleDispatcher, models.RoleAdmin))
+				r.Get("/", slaPolicyHandler.List)
+			})
+
+			// Admin only
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(models.RoleAdmin))
+				r.Post("/", slaPolicyHandler.Create)
+				r.Put("/{id}", slaPolicyHandler.Update)
+				r.Delete("/{id}", slaPolicyHandler.Delete)
+			})
+		})
+
+		// Priority rules (admin)
+		r.Route("/priority-rules", func(r chi.Router) {
+			r.Use(middleware.RequireRole(models.RoleAdmin))
+			r.Get("/", priorityRuleHandler.List)
+			r.Post("/", priorityRuleHandler.Create)
+			r.Put("/{id}", priorityRuleHandler.Update)
+			r.Delete("/{id}", priorityRuleHandler.Delete)
+		})
+
+		// Districts routes (dispatcher read, admin write)
+		r.Route("/districts", func(r chi.Router) {
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(models.RoleDispatcher, models.RoleAdmin))
+				r.Get("/", districtHandler.List)
+				r.Get("/{id}", districtHandler.GetByID)
+			})
+
+			// Admin only
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(models.RoleAdmin))
+				r.Post("/", districtHandler.Create)
+				r.Post("/import", districtHandler.Import)
+				r.Put("/{id}", districtHandler.Update)
+				r.Delete("/{id}", districtHandler.Delete)
+			})
+		})
+
+		// Routing rules: (category, district) -> service (admin)
+		r.Route("/routing-rules", func(r chi.Router) {
+			r.Use(middleware.RequireRole(models.RoleAdmin))
+			r.Get("/", districtHandler.ListRoutingRules)
+			r.Post("/", districtHandler.CreateRoutingRule)
+			r.Put("/{id}", districtHandler.UpdateRoutingRule)
+			r.Delete("/{id}", districtHandler.DeleteRoutingRule)
+		})
+
+		// Volume spike alerts (dispatcher, admin)
+		r.Route("/volume-alerts", func(r chi.Router) {
+			r.Use(middleware.RequireRole(models.RoleDispatcher, models.RoleAdmin))
+			r.Get("/", volumeAlertHandler.List)
+			r.Post("/{id}/group", volumeAlertHandler.Group)
+			r.Post("/{id}/dismiss", volumeAlertHandler.Dismiss)
+		})
+
+		// Scheduled email reports (admin only)
+		r.Route("/report-subscriptions", func(r chi.Router) {
+			r.Use(middleware.RequireRole(models.RoleAdmin))
+			r.Get("/", reportSubscriptionHandler.List)
+			r.Post("/", reportSubscriptionHandler.Create)
+			r.Put("/{id}", reportSubscriptionHandler.Update)
+			r.Delete("/{id}", reportSubscriptionHandler.Delete)
+			r.Get("/{id}/deliveries", reportSubscriptionHandler.ListDeliveries)
+			r.Post("/{id}/send", reportSubscriptionHandler.Send)
+		})
+
+		// Saved appeal list views (staff); changes are limited to the owner by the handler
+		r.Route("/saved-views", func(r chi.Router) {
+			r.Use(middleware.RequireRole(models.RoleDispatcher, models.RoleExecutor, models.RoleAdmin))
+			r.Get("/", savedViewHandler.List)
+			r.Post("/", savedViewHandler.Create)
+			r.Get("/{id}", savedViewHandler.GetByID)
+			r.Put("/{id}", savedViewHandler.Update)
+			r.Delete("/{id}", savedViewHandler.Delete)
+			r.Post("/{id}/subscribe", savedViewHandler.Subscribe)
+			r.Delete("/{id}/subscribe", savedViewHandler.Unsubscribe)
+		})
+
 		// Services routes (public read, admin write)
 		r.Route("/services", func(r chi.Router) {
 			r.Get("/", serviceHandler.List)
@@ -234,12 +387,14 @@ func main() {
 		// Appeals routes
 		r.Route("/appeals", func(r chi.Router) {
 			r.Get("/", appealHandler.List)
+			r.Get("/map", appealHandler.Map)
 			r.Post("/", appealHandler.Create)
 
 			// Statistics (admin, dispatcher) - must be before /{id}
 			r.Group(func(r chi.Router) {
 				r.Use(middleware.RequireRole(models.RoleDispatcher, models.RoleAdmin))
 				r.Get("/statistics", appealHandler.GetStatistics)
+				r.Get("/export", appealHandler.Export)
 			})
 
 			// Dashboards - must be before /{id}
@@ -250,6 +405,8 @@ func main() {
 			r.Group(func(r chi.Router) {
 				r.Use(middleware.RequireRole(models.RoleAdmin))
 				r.Get("/dashboard/admin", appealHandler.GetAdminDashboard)
+				r.Get("/dashboard/admin/hotspots", hotspotHandler.List)
+				r.Post("/dashboard/admin/hotspots/recompute", hotspotHandler.Recompute)
 			})
 			// Service statistics доступні всім авторизованим користувачам
 			r.Get("/services/{service_id}/statistics", appealHandler.GetServiceStatistics)
@@ -261,6 +418,9 @@ func main() {
 			// Classification (public for all authenticated users) - must be before /{id}
 			r.Post("/classify", appealHandler.Classify)
 
+			// Duplicate check before submission - must be before /{id}
+			r.Post("/duplicates", appealHandler.FindDuplicates)
+
 			// Specific routes that must come before /{id}
 			r.Get("/{id}/history", appealHandler.GetHistory)
 
@@ -270,6 +430,10 @@ func main() {
 				r.Post("/", photoHandler.Upload)
 			})
 
+			// Author's answer to a completed appeal (authorship is checked by the service)
+			r.Post("/{id}/confirm", appealHandler.Confirm)
+			r.Post("/{id}/reopen", appealHandler.Reopen)
+
 			// Status update (dispatcher, executor, admin)
 			r.Group(func(r chi.Router) {
 				r.Use(middleware.RequireRole(models.RoleDispatcher, models.RoleExecutor, models.RoleAdmin))
@@ -277,10 +441,21 @@ func main() {
 				r.Patch("/{id}/priority", appealHandler.UpdatePriority)
 			})
 
-			// Assign appeal (dispatcher, admin)
+			// Executor takes an appeal of their service
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(models.RoleExecutor))
+				r.Post("/{id}/claim", appealHandler.Claim)
+			})
+
+			// Assign, merge and unmerge appeals, bulk tagging (dispatcher, admin)
 			r.Group(func(r chi.Router) {
 				r.Use(middleware.RequireRole(models.RoleDispatcher, models.RoleAdmin))
 				r.Patch("/{id}/assign", appealHandler.Assign)
+				r.Patch("/{id}/assignee", appealHandler.AssignExecutor)
+				r.Post("/{id}/merge", appealHandler.Merge)
+				r.Post("/{id}/unmerge", appealHandler.Unmerge)
+				r.Post("/tag", tagHandler.Tag)
+				r.Post("/untag", tagHandler.Untag)
 			})
 
 			// General routes (must be last)
@@ -290,6 +465,7 @@ func main() {
 
 		// Photos routes (standalone)
 		r.Route("/photos", func(r chi.Router) {
+			r.Post("/", photoHandler.UploadStaged)
 			r.Get("/{id}", photoHandler.Get)
 			r.Delete("/{id}", photoHandler.Delete)
 		})
@@ -355,6 +531,7 @@ func main() {
 	<-quit
 
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
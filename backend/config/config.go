@@ -20,6 +20,8 @@ type Config struct {
 	Redis          RedisConfig
 	Classification ClassificationConfig
 	MongoDB        MongoDBConfig
+	Jobs           JobsConfig
+	Mail           MailConfig
 	Env            string
 }
 
@@ -49,6 +51,8 @@ type CORSConfig struct {
 type UploadConfig struct {
 	MaxSize    int64
 	UploadPath string
+	// StagedPhotoTTL is how long a photo uploaded ahead of its appeal or comment waits to be attached
+	StagedPhotoTTL time.Duration
 }
 
 type AWSConfig struct {
@@ -78,6 +82,27 @@ type MongoDBConfig struct {
 	Enabled    bool
 }
 
+// JobsConfig holds intervals of background jobs
+type JobsConfig struct {
+	SLACheckInterval    time.Duration
+	AutoCloseInterval   time.Duration
+	HotspotInterval     time.Duration
+	SpikeInterval       time.Duration
+	ReportInterval      time.Duration
+	StagedPhotoInterval time.Duration
+}
+
+// MailConfig selects how emails are delivered: smtp, file (.eml files in OutboxDir) or log
+type MailConfig struct {
+	Driver       string
+	From         string
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	OutboxDir    string
+}
+
 func Load() (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
@@ -104,6 +129,41 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid REDIS_DB: %w", err)
 	}
 
+	slaCheckInterval, err := time.ParseDuration(getEnv("SLA_CHECK_INTERVAL", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SLA_CHECK_INTERVAL: %w", err)
+	}
+
+	autoCloseInterval, err := time.ParseDuration(getEnv("AUTO_CLOSE_INTERVAL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid AUTO_CLOSE_INTERVAL: %w", err)
+	}
+
+	hotspotInterval, err := time.ParseDuration(getEnv("HOTSPOT_INTERVAL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HOTSPOT_INTERVAL: %w", err)
+	}
+
+	spikeInterval, err := time.ParseDuration(getEnv("SPIKE_CHECK_INTERVAL", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SPIKE_CHECK_INTERVAL: %w", err)
+	}
+
+	reportInterval, err := time.ParseDuration(getEnv("REPORT_CHECK_INTERVAL", "1m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REPORT_CHECK_INTERVAL: %w", err)
+	}
+
+	stagedPhotoTTL, err := time.ParseDuration(getEnv("STAGED_PHOTO_TTL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STAGED_PHOTO_TTL: %w", err)
+	}
+
+	stagedPhotoInterval, err := time.ParseDuration(getEnv("STAGED_PHOTO_CLEANUP_INTERVAL", "1h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid STAGED_PHOTO_CLEANUP_INTERVAL: %w", err)
+	}
+
 	config := &Config{
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -125,8 +185,9 @@ func Load() (*Config, error) {
 			AllowedOrigins: getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3000", "http://localhost:5173"}),
 		},
 		Upload: UploadConfig{
-			MaxSize:    maxUploadSize,
-			UploadPath: getEnv("UPLOAD_PATH", "./uploads"),
+			MaxSize:        maxUploadSize,
+			UploadPath:     getEnv("UPLOAD_PATH", "./uploads"),
+			StagedPhotoTTL: stagedPhotoTTL,
 		},
 		AWS: AWSConfig{
 			Region:          getEnv("AWS_REGION", "us-east-1"),
@@ -151,6 +212,23 @@ func Load() (*Config, error) {
 			AuthSource: getEnv("MONGODB_AUTH_SOURCE", "admin"),
 			Enabled:    getEnv("MONGODB_ENABLED", "true") == "true",
 		},
+		Jobs: JobsConfig{
+			SLACheckInterval:    slaCheckInterval,
+			AutoCloseInterval:   autoCloseInterval,
+			HotspotInterval:     hotspotInterval,
+			SpikeInterval:       spikeInterval,
+			ReportInterval:      reportInterval,
+			StagedPhotoInterval: stagedPhotoInterval,
+		},
+		Mail: MailConfig{
+			Driver:       getEnv("MAIL_DRIVER", "log"),
+			From:         getEnv("MAIL_FROM", "noreply@citizen-appeals.local"),
+			SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+			SMTPPort:     getEnv("SMTP_PORT", "587"),
+			SMTPUsername: getEnv("SMTP_USERNAME", ""),
+			SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+			OutboxDir:    getEnv("MAIL_OUTBOX_DIR", "./mail_outbox"),
+		},
 		Env: getEnv("ENV", "development"),
 	}
 
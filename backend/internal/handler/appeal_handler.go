@@ -2,9 +2,12 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -13,25 +16,32 @@ import (
 	"citizen-appeals/internal/models"
 	"citizen-appeals/internal/repository"
 	"citizen-appeals/internal/service"
+	"citizen-appeals/pkg/geo"
 )
 
+// maxNearRadiusMeters limits the radius of a "near" search to roughly a city
+const maxNearRadiusMeters = 50000
+
 type AppealHandler struct {
 	appealRepo         *repository.AppealRepository
 	validator          *validator.Validate
 	service            *service.AppealService
 	notificationService *service.NotificationService
+	savedViews          *service.SavedViewService
 }
 
 func NewAppealHandler(
 	appealRepo *repository.AppealRepository,
 	service *service.AppealService,
 	notificationService *service.NotificationService,
+	savedViews *service.SavedViewService,
 ) *AppealHandler {
 	return &AppealHandler{
 		appealRepo:          appealRepo,
 		validator:           validator.New(),
 		service:             service,
 		notificationService: notificationService,
+		savedViews:          savedViews,
 	}
 }
 
@@ -63,6 +73,29 @@ func (h *AppealHandler) Classify(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// FindDuplicates returns open appeals that likely describe the same problem,
+// so the citizen can join one of them before submitting a new appeal
+func (h *AppealHandler) FindDuplicates(w http.ResponseWriter, r *http.Request) {
+	var req models.FindDuplicatesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	duplicates, err := h.service.FindDuplicates(r.Context(), req.CategoryID, req.Latitude, req.Longitude, req.Description)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to find duplicates", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, duplicates)
+}
+
 // Create creates a new appeal
 func (h *AppealHandler) Create(w http.ResponseWriter, r *http.Request) {
 	userID, _ := middleware.GetUserID(r.Context())
@@ -78,9 +111,20 @@ func (h *AppealHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Citizen chose to join an existing appeal instead of creating a duplicate
+	if req.AttachToAppealID != nil {
+		appeal, err := h.service.AttachToAppeal(r.Context(), *req.AttachToAppealID, userID, req.Description, req.PhotoIDs)
+		if err != nil {
+			respondStatusError(w, err, "Failed to attach to appeal")
+			return
+		}
+		respondJSON(w, http.StatusOK, appeal)
+		return
+	}
+
 	appeal, err := h.service.CreateAppeal(r.Context(), userID, req)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to create appeal", err)
+		respondStatusError(w, err, "Failed to create appeal")
 		return
 	}
 
@@ -90,6 +134,18 @@ func (h *AppealHandler) Create(w http.ResponseWriter, r *http.Request) {
 			// Log error but don't fail the request
 			log.Printf("Failed to send appeal created notification: %v", err)
 		}
+		if appeal.AssigneeID != nil {
+			if err := h.notificationService.SendExecutorAssigned(r.Context(), appeal, *appeal.AssigneeID); err != nil {
+				log.Printf("Failed to send executor assignment notification: %v", err)
+			}
+		}
+	}
+
+	// Notify subscribers of saved views the new appeal matches
+	if h.savedViews != nil {
+		if err := h.savedViews.NotifyMatches(r.Context(), appeal); err != nil {
+			log.Printf("Failed to send saved view notifications: %v", err)
+		}
 	}
 
 	respondJSON(w, http.StatusCreated, appeal)
@@ -114,12 +170,169 @@ func (h *AppealHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Appeals merged into this one
+	linked, err := h.appealRepo.GetChildren(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get linked appeals", err)
+		return
+	}
+	appeal.LinkedAppeals = linked
+
+	// Why the appeal got its priority
+	rules, err := h.service.FiredPriorityRules(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get priority rules", err)
+		return
+	}
+	appeal.PriorityRules = rules
+
 	// All authenticated users can view any appeal
 	respondJSON(w, http.StatusOK, appeal)
 }
 
-// List retrieves appeals with filters
+// List retrieves appeals with filters, or those of a saved view (view_id=)
 func (h *AppealHandler) List(w http.ResponseWriter, r *http.Request) {
+	filters, ok := h.listFilters(w, r)
+	if !ok {
+		return
+	}
+
+	// All authenticated users can see all appeals
+	// Executors see all appeals (like dispatcher), not just assigned ones
+	// They can then assign appeals to themselves
+
+	appeals, total, cursors, err := h.appealRepo.List(r.Context(), filters)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			respondError(w, http.StatusBadRequest, "Invalid cursor", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to list appeals", err)
+		return
+	}
+
+	totalPages := int(total) / filters.Limit
+	if int(total)%filters.Limit > 0 {
+		totalPages++
+	}
+
+	page := filters.Page
+	if filters.Cursor != nil {
+		page = 0
+	}
+
+	response := models.PaginatedResponse{
+		Items:      appeals,
+		Total:      total,
+		Page:       page,
+		Limit:      filters.Limit,
+		TotalPages: totalPages,
+		NextCursor: cursors.Next,
+		PrevCursor: cursors.Prev,
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// Export streams all filtered appeals as a CSV or XLSX spreadsheet (dispatcher, admin).
+// It takes the same query params as List, without pagination; format is csv (default) or xlsx.
+func (h *AppealHandler) Export(w http.ResponseWriter, r *http.Request) {
+	filters, ok := h.listFilters(w, r)
+	if !ok {
+		return
+	}
+
+	format := models.AppealExportFormat(r.URL.Query().Get("format"))
+	contentType := ""
+	switch format {
+	case "", models.ExportCSV:
+		format = models.ExportCSV
+		contentType = "text/csv; charset=utf-8"
+	case models.ExportXLSX:
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		respondError(w, http.StatusBadRequest, "Unknown export format, expected csv or xlsx")
+		return
+	}
+
+	filename := fmt.Sprintf("appeals_%s.%s", time.Now().Format("2006-01-02"), format)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	// The status line is sent with the first row, so a failure midway can only be logged
+	if err := h.service.ExportAppeals(r.Context(), w, filters, format); err != nil {
+		log.Printf("Failed to export appeals: %v", err)
+	}
+}
+
+// Map returns the filtered appeals as a GeoJSON FeatureCollection.
+// cluster=true groups them on a grid for the given zoom (the system map zoom by default).
+func (h *AppealHandler) Map(w http.ResponseWriter, r *http.Request) {
+	filters, err := parseAppealFilters(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	cluster, _ := strconv.ParseBool(r.URL.Query().Get("cluster"))
+
+	zoom := 0
+	if zoomStr := r.URL.Query().Get("zoom"); zoomStr != "" {
+		zoom, err = strconv.Atoi(zoomStr)
+		if err != nil || zoom < 1 {
+			respondError(w, http.StatusBadRequest, "zoom must be a positive integer", err)
+			return
+		}
+	}
+
+	collection, err := h.service.AppealMap(r.Context(), filters, cluster, zoom)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to build appeal map", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, collection)
+}
+
+// listFilters reads the list filters from the query string. With view_id= the filters
+// and sort order of that saved view are used instead, only pagination is read from the query.
+// It writes the error response if the filters cannot be read.
+func (h *AppealHandler) listFilters(w http.ResponseWriter, r *http.Request) (*models.AppealFilters, bool) {
+	filters, err := parseAppealFilters(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return nil, false
+	}
+
+	viewIDStr := r.URL.Query().Get("view_id")
+	if viewIDStr == "" || h.savedViews == nil {
+		return filters, true
+	}
+
+	viewID, err := strconv.ParseInt(viewIDStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid saved view ID", err)
+		return nil, false
+	}
+
+	userID, _ := middleware.GetUserID(r.Context())
+	role, _ := middleware.GetUserRole(r.Context())
+	viewFilters, err := h.savedViews.ListFilters(r.Context(), viewID, userID, role)
+	if err != nil {
+		if errors.Is(err, repository.ErrSavedViewNotFound) {
+			respondError(w, http.StatusNotFound, "Saved view not found", err)
+			return nil, false
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to get saved view", err)
+		return nil, false
+	}
+
+	viewFilters.Page, viewFilters.Limit, viewFilters.Cursor = filters.Page, filters.Limit, filters.Cursor
+	return viewFilters, true
+}
+
+// parseAppealFilters reads the appeal list filters from the query string
+func parseAppealFilters(r *http.Request) (*models.AppealFilters, error) {
 	filters := &models.AppealFilters{
 		Page:  1,
 		Limit: 20,
@@ -161,10 +374,44 @@ func (h *AppealHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if districtIDStr := r.URL.Query().Get("district_id"); districtIDStr != "" {
+		if districtID, err := strconv.ParseInt(districtIDStr, 10, 64); err == nil {
+			filters.DistrictID = &districtID
+		}
+	}
+
+	if assigneeIDStr := r.URL.Query().Get("assignee_id"); assigneeIDStr != "" {
+		if assigneeID, err := strconv.ParseInt(assigneeIDStr, 10, 64); err == nil {
+			filters.AssigneeID = &assigneeID
+		}
+	}
+
+	// mine=true limits the list to appeals assigned to the current user
+	if mineStr := r.URL.Query().Get("mine"); mineStr != "" {
+		if mine, err := strconv.ParseBool(mineStr); err == nil && mine {
+			if currentUserID, ok := middleware.GetUserID(r.Context()); ok {
+				filters.AssigneeID = &currentUserID
+			}
+		}
+	}
+
+	if unclaimedStr := r.URL.Query().Get("unclaimed"); unclaimedStr != "" {
+		if unclaimed, err := strconv.ParseBool(unclaimedStr); err == nil {
+			filters.Unclaimed = &unclaimed
+		}
+	}
+
 	if search := r.URL.Query().Get("search"); search != "" {
 		filters.Search = &search
 	}
 
+	// search_comments=true also searches public comments
+	if searchCommentsStr := r.URL.Query().Get("search_comments"); searchCommentsStr != "" {
+		if searchComments, err := strconv.ParseBool(searchCommentsStr); err == nil {
+			filters.SearchComments = searchComments
+		}
+	}
+
 	if fromDateStr := r.URL.Query().Get("from_date"); fromDateStr != "" {
 		if fromDate, err := time.Parse(time.RFC3339, fromDateStr); err == nil {
 			filters.FromDate = &fromDate
@@ -177,33 +424,88 @@ func (h *AppealHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if overdueStr := r.URL.Query().Get("overdue"); overdueStr != "" {
+		overdue, err := strconv.ParseBool(overdueStr)
+		if err != nil {
+			return nil, fmt.Errorf("overdue: %w", err)
+		}
+		filters.Overdue = &overdue
+	}
+
+	// tags_any= and tags_all= take comma-separated tag IDs
+	if tagsAny := r.URL.Query().Get("tags_any"); tagsAny != "" {
+		filters.TagsAny = parseIDList(tagsAny)
+	}
+
+	if tagsAll := r.URL.Query().Get("tags_all"); tagsAll != "" {
+		filters.TagsAll = parseIDList(tagsAll)
+	}
+
+	// cf.<key>=value filters by a category's custom field, e.g. cf.pole_number=17
+	for param, values := range r.URL.Query() {
+		if key, ok := strings.CutPrefix(param, "cf."); ok && key != "" && values[0] != "" {
+			if filters.CustomFields == nil {
+				filters.CustomFields = make(map[string]string)
+			}
+			filters.CustomFields[key] = values[0]
+		}
+	}
+
+	if err := parseSpatialFilters(r, filters); err != nil {
+		return nil, err
+	}
+
+	// cursor= (even empty, for the first page) switches to keyset pagination
+	if r.URL.Query().Has("cursor") {
+		cursor := r.URL.Query().Get("cursor")
+		filters.Cursor = &cursor
+	}
+
 	filters.SortBy = r.URL.Query().Get("sort_by")
 	filters.SortOrder = r.URL.Query().Get("sort_order")
 
-	// All authenticated users can see all appeals
-	// Executors see all appeals (like dispatcher), not just assigned ones
-	// They can then assign appeals to themselves
+	return filters, nil
+}
 
-	appeals, total, err := h.appealRepo.List(r.Context(), filters)
-	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to list appeals", err)
-		return
+// parseIDList reads comma-separated IDs, skipping malformed ones
+func parseIDList(s string) []int64 {
+	ids := make([]int64, 0)
+	for _, part := range strings.Split(s, ",") {
+		if id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64); err == nil {
+			ids = append(ids, id)
+		}
 	}
+	return ids
+}
 
-	totalPages := int(total) / filters.Limit
-	if int(total)%filters.Limit > 0 {
-		totalPages++
+// parseSpatialFilters reads near=lat,lng with radius_m=, and bbox=minLng,minLat,maxLng,maxLat.
+// Unlike the other filters, malformed values are rejected: ignoring them would return
+// every appeal instead of the few the map asked for.
+func parseSpatialFilters(r *http.Request, filters *models.AppealFilters) error {
+	query := r.URL.Query()
+
+	if nearStr := query.Get("near"); nearStr != "" {
+		near, err := geo.ParsePoint(nearStr)
+		if err != nil {
+			return fmt.Errorf("near: %w", err)
+		}
+		radius, err := strconv.ParseFloat(query.Get("radius_m"), 64)
+		if err != nil || radius <= 0 || radius > maxNearRadiusMeters {
+			return fmt.Errorf("radius_m must be a number between 0 and %d when near is given", maxNearRadiusMeters)
+		}
+		filters.Near = &near
+		filters.RadiusMeters = radius
 	}
 
-	response := models.PaginatedResponse{
-		Items:      appeals,
-		Total:      total,
-		Page:       filters.Page,
-		Limit:      filters.Limit,
-		TotalPages: totalPages,
+	if bboxStr := query.Get("bbox"); bboxStr != "" {
+		bbox, err := geo.ParseBoundingBox(bboxStr)
+		if err != nil {
+			return fmt.Errorf("bbox: %w", err)
+		}
+		filters.BBox = &bbox
 	}
 
-	respondJSON(w, http.StatusOK, response)
+	return nil
 }
 
 // Update updates an appeal (only for appeal creator)
@@ -265,6 +567,7 @@ func (h *AppealHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if req.Description != nil && isCreator {
 		appeal.Description = *req.Description
 	}
+	categoryChanged := req.CategoryID != nil && (appeal.CategoryID == nil || *req.CategoryID != *appeal.CategoryID)
 	if req.CategoryID != nil {
 		appeal.CategoryID = req.CategoryID
 	}
@@ -278,6 +581,36 @@ func (h *AppealHandler) Update(w http.ResponseWriter, r *http.Request) {
 		appeal.Longitude = *req.Longitude
 	}
 
+	// A moved appeal must stay in the city and may now lie in another district
+	if req.Latitude != nil || req.Longitude != nil {
+		if err := h.service.CheckCityBoundary(r.Context(), appeal.Latitude, appeal.Longitude); err != nil {
+			respondStatusError(w, err, "Failed to update appeal")
+			return
+		}
+		districtID, err := h.service.ResolveDistrict(r.Context(), appeal.Latitude, appeal.Longitude)
+		if err != nil {
+			log.Printf("Failed to resolve district of appeal %d: %v", id, err)
+		} else {
+			appeal.DistrictID = districtID
+		}
+	}
+
+	// New values must fit the category; a new category without new values keeps
+	// only the old values that still fit it
+	if appeal.CategoryID != nil && (req.CustomFields != nil || categoryChanged) {
+		var customFields map[string]interface{}
+		if req.CustomFields != nil {
+			customFields, err = h.service.ValidateAppealCustomFields(r.Context(), *appeal.CategoryID, req.CustomFields)
+		} else {
+			customFields, err = h.service.RecategorizeCustomFields(r.Context(), *appeal.CategoryID, appeal.CustomFields)
+		}
+		if err != nil {
+			respondStatusError(w, err, "Failed to update appeal")
+			return
+		}
+		appeal.CustomFields = customFields
+	}
+
 	if err := h.appealRepo.Update(r.Context(), appeal); err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to update appeal", err)
 		return
@@ -309,48 +642,18 @@ func (h *AppealHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get existing appeal
-	appeal, err := h.appealRepo.GetByID(r.Context(), id)
+	// Role permissions and allowed moves are enforced by the status transition table
+	appeal, err := h.service.UpdateStatus(r.Context(), id, userID, userRole, req.Status, req.Comment)
 	if err != nil {
-		if err == repository.ErrAppealNotFound {
-			respondError(w, http.StatusNotFound, "Appeal not found", err)
-		} else {
-			respondError(w, http.StatusInternalServerError, "Failed to get appeal", err)
-		}
-		return
-	}
-
-	// Check permissions based on role
-	canUpdate := false
-	switch userRole {
-	case models.RoleAdmin, models.RoleDispatcher:
-		canUpdate = true
-	case models.RoleExecutor:
-		// Executors can update appeals if they belong to the service assigned to the appeal
-		if appeal.ServiceID != nil {
-			// Check if executor belongs to the service (would need to query user_services table)
-			// For now, allow executors to update any appeal (can be refined later)
-			canUpdate = true
-		}
-	}
-
-	if !canUpdate {
-		respondError(w, http.StatusForbidden, "You don't have permission to update this appeal status")
+		respondStatusError(w, err, "Failed to update status")
 		return
 	}
+	oldStatus := appeal.Status
 
-	// Log the update attempt
 	if req.Comment != nil && *req.Comment != "" {
-		log.Printf("Updating appeal %d status from handler: %s -> %s with comment: %s", id, appeal.Status, req.Status, *req.Comment)
+		log.Printf("Updated appeal %d status from handler: %s -> %s with comment: %s", id, oldStatus, req.Status, *req.Comment)
 	} else {
-		log.Printf("Updating appeal %d status from handler: %s -> %s (no comment)", id, appeal.Status, req.Status)
-	}
-
-	oldStatus := appeal.Status
-	if err := h.appealRepo.UpdateStatus(r.Context(), id, req.Status, userID, req.Comment); err != nil {
-		log.Printf("Error updating status for appeal %d: %v", id, err)
-		respondError(w, http.StatusInternalServerError, "Failed to update status", err)
-		return
+		log.Printf("Updated appeal %d status from handler: %s -> %s (no comment)", id, oldStatus, req.Status)
 	}
 
 	// Send notification to appeal creator about status change
@@ -363,6 +666,7 @@ func (h *AppealHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 				log.Printf("Failed to send status change notification: %v", err)
 			}
 		}
+		h.notifyLinkedStatusChanged(r, id, oldStatus)
 	}
 
 	respondJSON(w, http.StatusOK, map[string]string{
@@ -405,7 +709,7 @@ func (h *AppealHandler) UpdatePriority(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.appealRepo.UpdatePriority(r.Context(), id, req.Priority, userID); err != nil {
+	if err := h.appealRepo.UpdatePriority(r.Context(), id, req.Priority, &userID); err != nil {
 		if err == repository.ErrAppealNotFound {
 			respondError(w, http.StatusNotFound, "Appeal not found", err)
 			return
@@ -449,12 +753,8 @@ func (h *AppealHandler) Assign(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.appealRepo.Assign(r.Context(), id, req.ServiceID, req.Priority, userID); err != nil {
-		if err == repository.ErrAppealNotFound {
-			respondError(w, http.StatusNotFound, "Appeal not found", err)
-			return
-		}
-		respondError(w, http.StatusInternalServerError, "Failed to assign appeal", err)
+	if err := h.service.AssignAppeal(r.Context(), id, req.ServiceID, req.Priority, userID, userRole); err != nil {
+		respondStatusError(w, err, "Failed to assign appeal")
 		return
 	}
 
@@ -465,6 +765,12 @@ func (h *AppealHandler) Assign(w http.ResponseWriter, r *http.Request) {
 			if err := h.notificationService.SendAppealAssigned(r.Context(), appeal); err != nil {
 				log.Printf("Failed to send assignment notification: %v", err)
 			}
+			// The service's strategy may have picked an executor right away
+			if appeal.AssigneeID != nil {
+				if err := h.notificationService.SendExecutorAssigned(r.Context(), appeal, *appeal.AssigneeID); err != nil {
+					log.Printf("Failed to send executor assignment notification: %v", err)
+				}
+			}
 		}
 	}
 
@@ -473,6 +779,196 @@ func (h *AppealHandler) Assign(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// Claim assigns the appeal to the current executor (executor)
+func (h *AppealHandler) Claim(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r.Context())
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid appeal ID", err)
+		return
+	}
+
+	appeal, err := h.service.ClaimAppeal(r.Context(), id, userID)
+	if err != nil {
+		respondStatusError(w, err, "Failed to claim appeal")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, appeal)
+}
+
+// AssignExecutor sets or clears the executor responsible for an appeal (dispatcher, admin)
+func (h *AppealHandler) AssignExecutor(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r.Context())
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid appeal ID", err)
+		return
+	}
+
+	var req models.AssignExecutorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	appeal, changed, err := h.service.AssignExecutor(r.Context(), id, req.ExecutorID, userID)
+	if err != nil {
+		respondStatusError(w, err, "Failed to assign executor")
+		return
+	}
+
+	if changed && req.ExecutorID != nil && h.notificationService != nil {
+		if err := h.notificationService.SendExecutorAssigned(r.Context(), appeal, *req.ExecutorID); err != nil {
+			log.Printf("Failed to send executor assignment notification: %v", err)
+		}
+	}
+
+	respondJSON(w, http.StatusOK, appeal)
+}
+
+// Confirm lets the author accept the executor's result and close the appeal
+func (h *AppealHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r.Context())
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid appeal ID", err)
+		return
+	}
+
+	closed, err := h.service.ConfirmCompletion(r.Context(), id, userID)
+	if err != nil {
+		respondStatusError(w, err, "Failed to confirm appeal")
+		return
+	}
+
+	// Appeals merged into the closed one are closed together with it
+	if h.notificationService != nil {
+		h.notifyLinkedStatusChanged(r, closed.ID, closed.Status)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Appeal closed successfully",
+	})
+}
+
+// Reopen lets the author reject the executor's result and send the appeal back to the service
+func (h *AppealHandler) Reopen(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r.Context())
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid appeal ID", err)
+		return
+	}
+
+	var req models.ReopenAppealRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	reopened, err := h.service.ReopenAppeal(r.Context(), id, userID, req.Comment)
+	if err != nil {
+		respondStatusError(w, err, "Failed to reopen appeal")
+		return
+	}
+
+	if h.notificationService != nil {
+		if err := h.notificationService.SendAppealReopened(r.Context(), reopened, req.Comment); err != nil {
+			log.Printf("Failed to send reopen notification: %v", err)
+		}
+		h.notifyLinkedStatusChanged(r, reopened.ID, reopened.Status)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Appeal reopened successfully",
+	})
+}
+
+// Merge folds other appeals into this one (dispatcher, admin)
+func (h *AppealHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	h.handleMerge(w, r, true)
+}
+
+// Unmerge detaches appeals from this one (dispatcher, admin)
+func (h *AppealHandler) Unmerge(w http.ResponseWriter, r *http.Request) {
+	h.handleMerge(w, r, false)
+}
+
+func (h *AppealHandler) handleMerge(w http.ResponseWriter, r *http.Request, merge bool) {
+	userID, _ := middleware.GetUserID(r.Context())
+
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid appeal ID", err)
+		return
+	}
+
+	var req models.MergeAppealsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if merge {
+		err = h.service.MergeAppeals(r.Context(), id, req.AppealIDs, userID)
+	} else {
+		err = h.service.UnmergeAppeals(r.Context(), id, req.AppealIDs, userID)
+	}
+	if err != nil {
+		respondStatusError(w, err, "Failed to update linked appeals")
+		return
+	}
+
+	appeal, err := h.appealRepo.GetByID(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get appeal", err)
+		return
+	}
+	appeal.LinkedAppeals, err = h.appealRepo.GetChildren(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get linked appeals", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, appeal)
+}
+
+// notifyLinkedStatusChanged notifies authors of appeals merged into parentID
+// that their appeal changed status together with the master appeal
+func (h *AppealHandler) notifyLinkedStatusChanged(r *http.Request, parentID int64, oldStatus models.AppealStatus) {
+	children, err := h.appealRepo.GetChildren(r.Context(), parentID)
+	if err != nil {
+		log.Printf("Failed to get linked appeals for notifications: %v", err)
+		return
+	}
+
+	for _, child := range children {
+		if err := h.notificationService.SendStatusChanged(r.Context(), child, oldStatus); err != nil {
+			log.Printf("Failed to send status change notification for linked appeal %d: %v", child.ID, err)
+		}
+	}
+}
+
 // GetStatistics returns appeal statistics (admin/dispatcher only)
 func (h *AppealHandler) GetStatistics(w http.ResponseWriter, r *http.Request) {
 	var fromDate, toDate *time.Time
@@ -568,3 +1064,43 @@ func (h *AppealHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, history)
 }
+
+// respondStatusError maps errors from status-changing service calls to HTTP responses
+func respondStatusError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrAppealNotFound):
+		respondError(w, http.StatusNotFound, "Appeal not found", err)
+	case errors.Is(err, service.ErrUnknownStatus):
+		respondError(w, http.StatusBadRequest, "Unknown appeal status", err)
+	case errors.Is(err, service.ErrInvalidStatusTransition):
+		respondError(w, http.StatusConflict, err.Error(), err)
+	case errors.Is(err, repository.ErrStatusChanged):
+		respondError(w, http.StatusConflict, "Appeal status was changed by someone else, reload and try again", err)
+	case errors.Is(err, service.ErrNotAppealService):
+		respondError(w, http.StatusForbidden, "You don't have permission to update this appeal status", err)
+	case errors.Is(err, service.ErrInvalidMerge), errors.Is(err, service.ErrAppealMerged):
+		respondError(w, http.StatusConflict, err.Error(), err)
+	case errors.Is(err, service.ErrNotAppealAuthor):
+		respondError(w, http.StatusForbidden, "Only the author of the appeal can do this", err)
+	case errors.Is(err, service.ErrConfirmationWindowClosed):
+		respondError(w, http.StatusConflict, "Confirmation window is over", err)
+	case errors.Is(err, service.ErrAppealNotOpen):
+		respondError(w, http.StatusConflict, "Appeal is already resolved", err)
+	case errors.Is(err, service.ErrAppealAlreadyClaimed):
+		respondError(w, http.StatusConflict, "Appeal is already assigned to another executor", err)
+	case errors.Is(err, service.ErrAppealWithoutService):
+		respondError(w, http.StatusConflict, "Appeal is not assigned to a service yet", err)
+	case errors.Is(err, service.ErrOutsideCityBoundary):
+		respondError(w, http.StatusBadRequest, "The location is outside the city boundary", err)
+	case errors.Is(err, service.ErrNotServiceExecutor):
+		respondError(w, http.StatusBadRequest, "Executor does not belong to the appeal's service", err)
+	case errors.Is(err, service.ErrInvalidCustomFields):
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+	case errors.Is(err, repository.ErrCategoryNotFound):
+		respondError(w, http.StatusBadRequest, "Category not found", err)
+	case errors.Is(err, repository.ErrPhotosNotClaimable):
+		respondError(w, http.StatusBadRequest, "Photos must be your own staged uploads", err)
+	default:
+		respondError(w, http.StatusInternalServerError, message, err)
+	}
+}
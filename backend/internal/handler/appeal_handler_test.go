@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"citizen-appeals/internal/models"
+)
+
+func TestParseAppealFilters_Overdue(t *testing.T) {
+	filters, err := parseAppealFilters(httptest.NewRequest("GET", "/api/appeals?overdue=true", nil))
+	assert.NoError(t, err)
+	if assert.NotNil(t, filters.Overdue) {
+		assert.True(t, *filters.Overdue)
+	}
+
+	_, err = parseAppealFilters(httptest.NewRequest("GET", "/api/appeals?overdue=maybe", nil))
+	assert.Error(t, err, "a malformed overdue flag is rejected instead of ignored")
+}
+
+func TestParseSpatialFilters(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/appeals?near=50.4501,30.5234&radius_m=500&bbox=30.40,50.38,30.65,50.52", nil)
+	filters := &models.AppealFilters{}
+
+	assert.NoError(t, parseSpatialFilters(req, filters))
+	if assert.NotNil(t, filters.Near) {
+		assert.Equal(t, 50.4501, filters.Near.Lat)
+		assert.Equal(t, 30.5234, filters.Near.Lng)
+	}
+	assert.Equal(t, 500.0, filters.RadiusMeters)
+	if assert.NotNil(t, filters.BBox) {
+		assert.Equal(t, 30.40, filters.BBox.MinLng)
+		assert.Equal(t, 50.52, filters.BBox.MaxLat)
+	}
+
+	invalid := []string{
+		"/api/appeals?near=50.4501,30.5234",
+		"/api/appeals?near=50.4501,30.5234&radius_m=-1",
+		"/api/appeals?near=50.4501,30.5234&radius_m=100000",
+		"/api/appeals?near=50.4501&radius_m=100",
+		"/api/appeals?bbox=30.65,50.38,30.40,50.52",
+	}
+	for _, url := range invalid {
+		err := parseSpatialFilters(httptest.NewRequest("GET", url, nil), &models.AppealFilters{})
+		assert.Error(t, err, url)
+	}
+}
+
+func TestParseAppealFilters_Search(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/appeals?search=%D1%8F%D0%BC%D0%B8&search_comments=true", nil)
+
+	filters, err := parseAppealFilters(req)
+	assert.NoError(t, err)
+	if assert.NotNil(t, filters.Search) {
+		assert.Equal(t, "ями", *filters.Search)
+	}
+	assert.True(t, filters.SearchComments)
+
+	filters, err = parseAppealFilters(httptest.NewRequest("GET", "/api/appeals?search=%D1%8F%D0%BC%D0%B8", nil))
+	assert.NoError(t, err)
+	assert.False(t, filters.SearchComments, "comments are only searched on request")
+}
+
+func TestParseAppealFilters_Cursor(t *testing.T) {
+	filters, err := parseAppealFilters(httptest.NewRequest("GET", "/api/appeals?page=3", nil))
+	assert.NoError(t, err)
+	assert.Nil(t, filters.Cursor, "offset pagination without a cursor")
+
+	filters, err = parseAppealFilters(httptest.NewRequest("GET", "/api/appeals?cursor=", nil))
+	assert.NoError(t, err)
+	if assert.NotNil(t, filters.Cursor, "an empty cursor asks for the first page") {
+		assert.Equal(t, "", *filters.Cursor)
+	}
+
+	filters, err = parseAppealFilters(httptest.NewRequest("GET", "/api/appeals?cursor=eyJvIjoiYSJ9&sort_by=priority", nil))
+	assert.NoError(t, err)
+	if assert.NotNil(t, filters.Cursor) {
+		assert.Equal(t, "eyJvIjoiYSJ9", *filters.Cursor)
+	}
+}
+
+func TestParseAppealFilters_Tags(t *testing.T) {
+	filters, err := parseAppealFilters(httptest.NewRequest("GET", "/api/appeals?tags_any=3,%204,x&tags_all=7", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 4}, filters.TagsAny, "malformed IDs are skipped")
+	assert.Equal(t, []int64{7}, filters.TagsAll)
+
+	filters, err = parseAppealFilters(httptest.NewRequest("GET", "/api/appeals", nil))
+	assert.NoError(t, err)
+	assert.Nil(t, filters.TagsAny)
+	assert.Nil(t, filters.TagsAll)
+}
+
+func TestParseAppealFilters_CustomFields(t *testing.T) {
+	filters, err := parseAppealFilters(httptest.NewRequest("GET", "/api/appeals?cf.pole_number=17&cf.dangerous=true&cf.=x&cf.lamps=", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"pole_number": "17", "dangerous": "true"}, filters.CustomFields)
+
+	filters, err = parseAppealFilters(httptest.NewRequest("GET", "/api/appeals", nil))
+	assert.NoError(t, err)
+	assert.Nil(t, filters.CustomFields)
+}
@@ -2,13 +2,16 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
+	"citizen-appeals/internal/middleware"
 	"citizen-appeals/internal/models"
 	"citizen-appeals/internal/repository"
+	"citizen-appeals/internal/service"
 )
 
 type CategoryHandler struct {
@@ -36,6 +39,47 @@ func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, categories)
 }
 
+// Tree retrieves top-level categories with their subcategories
+func (h *CategoryHandler) Tree(w http.ResponseWriter, r *http.Request) {
+	includeInactive := r.URL.Query().Get("include_inactive") == "true"
+
+	categories, err := h.categoryRepo.List(r.Context(), includeInactive)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list categories", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, service.BuildCategoryTree(categories))
+}
+
+// Subcategories retrieves the subcategories of a category
+func (h *CategoryHandler) Subcategories(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid category ID", err)
+		return
+	}
+
+	if _, err := h.categoryRepo.GetByID(r.Context(), id); err != nil {
+		if err == repository.ErrCategoryNotFound {
+			respondError(w, http.StatusNotFound, "Category not found", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to get category", err)
+		return
+	}
+
+	includeInactive := r.URL.Query().Get("include_inactive") == "true"
+	subcategories, err := h.categoryRepo.ListSubcategories(r.Context(), id, includeInactive)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list subcategories", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, subcategories)
+}
+
 // GetByID retrieves a category by ID
 func (h *CategoryHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -71,11 +115,26 @@ func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := service.ValidateCustomFieldSet(req.CustomFields); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
 	category := &models.Category{
-		Name:            req.Name,
-		Description:     req.Description,
-		DefaultPriority: req.DefaultPriority,
-		IsActive:        true,
+		Name:             req.Name,
+		Description:      req.Description,
+		DefaultPriority:  req.DefaultPriority,
+		InheritsPriority: req.DefaultPriority == 0,
+		IsActive:         true,
+		CustomFields:     req.CustomFields,
+	}
+
+	var parentID int64
+	if req.ParentID != nil {
+		parentID = *req.ParentID
+	}
+	if !h.placeCategory(w, r, category, parentID) {
+		return
 	}
 
 	if err := h.categoryRepo.Create(r.Context(), category); err != nil {
@@ -125,10 +184,31 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 	if req.DefaultPriority != nil {
 		category.DefaultPriority = *req.DefaultPriority
+		category.InheritsPriority = *req.DefaultPriority == 0
 	}
 	if req.IsActive != nil {
 		category.IsActive = *req.IsActive
 	}
+	// Values already stored on appeals stay as they are; they are checked against
+	// the new field set the next time they are edited
+	if req.CustomFields != nil {
+		if err := service.ValidateCustomFieldSet(*req.CustomFields); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+		category.CustomFields = *req.CustomFields
+	}
+
+	var parentID int64
+	if category.ParentID != nil {
+		parentID = *category.ParentID
+	}
+	if req.ParentID != nil {
+		parentID = *req.ParentID
+	}
+	if !h.placeCategory(w, r, category, parentID) {
+		return
+	}
 
 	if err := h.categoryRepo.Update(r.Context(), category); err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to update category", err)
@@ -138,7 +218,8 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, category)
 }
 
-// Delete soft deletes a category (admin only)
+// Delete archives a category with its subcategories (admin only). Open appeals block it
+// unless ?reassign_to= names the category to move them to.
 func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -147,16 +228,77 @@ func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.categoryRepo.Delete(r.Context(), id); err != nil {
-		if err == repository.ErrCategoryNotFound {
+	reassignTo, err := parseReassignTo(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid reassign_to", err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r.Context())
+	moved, err := h.categoryRepo.Delete(r.Context(), id, reassignTo, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrCategoryNotFound):
 			respondError(w, http.StatusNotFound, "Category not found", err)
-			return
+		case errors.Is(err, repository.ErrCategoryInUse):
+			respondError(w, http.StatusConflict, "Category has open appeals; pass reassign_to to move them to another category", err)
+		case errors.Is(err, repository.ErrInvalidReassignTarget):
+			respondError(w, http.StatusBadRequest, "Appeals can only be moved to another active category", err)
+		default:
+			respondError(w, http.StatusInternalServerError, "Failed to delete category", err)
 		}
-		respondError(w, http.StatusInternalServerError, "Failed to delete category", err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{
-		"message": "Category deleted successfully",
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message":    "Category deleted successfully",
+		"reassigned": moved,
 	})
 }
+
+// parseReassignTo reads the optional ?reassign_to= target of a deletion
+func parseReassignTo(r *http.Request) (*int64, error) {
+	value := r.URL.Query().Get("reassign_to")
+	if value == "" {
+		return nil, nil
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// placeCategory puts the category under the parent with the ID, or at the top level for 0,
+// writing the error response if it cannot go there
+func (h *CategoryHandler) placeCategory(w http.ResponseWriter, r *http.Request, category *models.Category, parentID int64) bool {
+	var parent *models.Category
+	hasSubcategories := false
+	if parentID != 0 {
+		var err error
+		parent, err = h.categoryRepo.GetByID(r.Context(), parentID)
+		if err != nil {
+			if err == repository.ErrCategoryNotFound {
+				respondError(w, http.StatusBadRequest, "Parent category not found", err)
+				return false
+			}
+			respondError(w, http.StatusInternalServerError, "Failed to get parent category", err)
+			return false
+		}
+
+		// A new category has no subcategories yet
+		if category.ID != 0 {
+			hasSubcategories, err = h.categoryRepo.HasSubcategories(r.Context(), category.ID)
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, "Failed to check subcategories", err)
+				return false
+			}
+		}
+	}
+
+	if err := service.SetCategoryParent(category, parent, hasSubcategories); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return false
+	}
+	return true
+}
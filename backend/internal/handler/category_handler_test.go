@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReassignTo(t *testing.T) {
+	target, err := parseReassignTo(httptest.NewRequest("DELETE", "/api/services/3?reassign_to=7", nil))
+	if assert.NoError(t, err) && assert.NotNil(t, target) {
+		assert.Equal(t, int64(7), *target)
+	}
+
+	target, err = parseReassignTo(httptest.NewRequest("DELETE", "/api/services/3", nil))
+	assert.NoError(t, err)
+	assert.Nil(t, target)
+
+	_, err = parseReassignTo(httptest.NewRequest("DELETE", "/api/services/3?reassign_to=seven", nil))
+	assert.Error(t, err)
+}
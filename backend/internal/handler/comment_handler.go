@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -11,6 +12,7 @@ import (
 	"citizen-appeals/internal/models"
 	"citizen-appeals/internal/repository"
 	"citizen-appeals/internal/service"
+	"citizen-appeals/pkg/storage"
 )
 
 type CommentHandler struct {
@@ -18,18 +20,30 @@ type CommentHandler struct {
 	appealRepo          *repository.AppealRepository
 	validator           *validator.Validate
 	notificationService *service.NotificationService
+	storage             storage.Storage
 }
 
 func NewCommentHandler(
 	commentRepo *repository.CommentRepository,
 	appealRepo *repository.AppealRepository,
 	notificationService *service.NotificationService,
+	storage storage.Storage,
 ) *CommentHandler {
 	return &CommentHandler{
 		commentRepo:         commentRepo,
 		appealRepo:          appealRepo,
 		validator:           validator.New(),
 		notificationService: notificationService,
+		storage:             storage,
+	}
+}
+
+// setPhotoURLs fills in the URLs of the comments' photos
+func (h *CommentHandler) setPhotoURLs(comments ...*models.Comment) {
+	for _, comment := range comments {
+		for i := range comment.Photos {
+			setPhotoURLs(h.storage, &comment.Photos[i])
+		}
 	}
 }
 
@@ -80,7 +94,11 @@ func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
 		IsInternal: req.IsInternal,
 	}
 
-	if err := h.commentRepo.Create(r.Context(), comment); err != nil {
+	if err := h.commentRepo.Create(r.Context(), comment, req.PhotoIDs); err != nil {
+		if errors.Is(err, repository.ErrPhotosNotClaimable) {
+			respondError(w, http.StatusBadRequest, "Photos must be your own staged uploads", err)
+			return
+		}
 		respondError(w, http.StatusInternalServerError, "Failed to create comment", err)
 		return
 	}
@@ -91,6 +109,7 @@ func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, "Failed to get created comment", err)
 		return
 	}
+	h.setPhotoURLs(fullComment)
 
 	// Send notification to appeal creator (if not internal comment or if comment is from different user)
 	if h.notificationService != nil && !req.IsInternal {
@@ -130,6 +149,7 @@ func (h *CommentHandler) GetByAppealID(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, "Failed to get comments", err)
 		return
 	}
+	h.setPhotoURLs(comments...)
 
 	respondJSON(w, http.StatusOK, comments)
 }
@@ -199,6 +219,7 @@ func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, "Failed to get updated comment", err)
 		return
 	}
+	h.setPhotoURLs(updatedComment)
 
 	respondJSON(w, http.StatusOK, updatedComment)
 }
@@ -0,0 +1,326 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+	"citizen-appeals/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxDistrictImportSize limits the size of an imported GeoJSON file
+const maxDistrictImportSize = 20 << 20
+
+type DistrictHandler struct {
+	districtRepo *repository.DistrictRepository
+	validator    *validator.Validate
+}
+
+func NewDistrictHandler(districtRepo *repository.DistrictRepository) *DistrictHandler {
+	return &DistrictHandler{
+		districtRepo: districtRepo,
+		validator:    validator.New(),
+	}
+}
+
+// List retrieves all districts; ?geometry=true includes the polygons
+func (h *DistrictHandler) List(w http.ResponseWriter, r *http.Request) {
+	withGeometry, _ := strconv.ParseBool(r.URL.Query().Get("geometry"))
+
+	districts, err := h.districtRepo.List(r.Context(), false, withGeometry)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list districts", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, districts)
+}
+
+// GetByID retrieves a district with its geometry
+func (h *DistrictHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid district ID", err)
+		return
+	}
+
+	district, err := h.districtRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrDistrictNotFound) {
+			respondError(w, http.StatusNotFound, "District not found", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to get district", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, district)
+}
+
+// Create creates a new district (admin only)
+func (h *DistrictHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateDistrictRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	district := &models.District{Name: req.Name, IsActive: true}
+	if err := service.SetDistrictGeometry(district, req.Geometry); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if err := h.districtRepo.Create(r.Context(), district); err != nil {
+		if errors.Is(err, repository.ErrDistrictExists) {
+			respondError(w, http.StatusConflict, err.Error(), err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to create district", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, district)
+}
+
+// Update updates a district (admin only)
+func (h *DistrictHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid district ID", err)
+		return
+	}
+
+	district, err := h.districtRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrDistrictNotFound) {
+			respondError(w, http.StatusNotFound, "District not found", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to get district", err)
+		return
+	}
+
+	var req models.UpdateDistrictRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if req.Name != nil {
+		district.Name = *req.Name
+	}
+	if len(req.Geometry) > 0 {
+		if err := service.SetDistrictGeometry(district, req.Geometry); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+	}
+	if req.IsActive != nil {
+		district.IsActive = *req.IsActive
+	}
+
+	if err := h.districtRepo.Update(r.Context(), district); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDistrictNotFound):
+			respondError(w, http.StatusNotFound, "District not found", err)
+		case errors.Is(err, repository.ErrDistrictExists):
+			respondError(w, http.StatusConflict, err.Error(), err)
+		default:
+			respondError(w, http.StatusInternalServerError, "Failed to update district", err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, district)
+}
+
+// Delete deletes a district (admin only)
+func (h *DistrictHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid district ID", err)
+		return
+	}
+
+	if err := h.districtRepo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrDistrictNotFound) {
+			respondError(w, http.StatusNotFound, "District not found", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to delete district", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "District deleted successfully"})
+}
+
+// Import creates or replaces districts from a GeoJSON FeatureCollection (admin only).
+// Features are matched to existing districts by their "name" property.
+func (h *DistrictHandler) Import(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxDistrictImportSize))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	districts, err := service.DistrictsFromGeoJSON(data)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	var result models.ImportDistrictsResponse
+	for _, district := range districts {
+		existing, err := h.districtRepo.GetByName(r.Context(), district.Name)
+		switch {
+		case err == nil:
+			if err := service.SetDistrictGeometry(existing, district.Geometry); err != nil {
+				respondError(w, http.StatusBadRequest, err.Error(), err)
+				return
+			}
+			if err := h.districtRepo.Update(r.Context(), existing); err != nil {
+				respondError(w, http.StatusInternalServerError, "Failed to update district "+district.Name, err)
+				return
+			}
+			result.Updated++
+		case errors.Is(err, repository.ErrDistrictNotFound):
+			if err := h.districtRepo.Create(r.Context(), district); err != nil {
+				respondError(w, http.StatusInternalServerError, "Failed to create district "+district.Name, err)
+				return
+			}
+			result.Created++
+		default:
+			respondError(w, http.StatusInternalServerError, "Failed to get district", err)
+			return
+		}
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// ListRoutingRules retrieves routing rules, optionally of one district (?district_id=)
+func (h *DistrictHandler) ListRoutingRules(w http.ResponseWriter, r *http.Request) {
+	var districtID *int64
+	if districtIDStr := r.URL.Query().Get("district_id"); districtIDStr != "" {
+		id, err := strconv.ParseInt(districtIDStr, 10, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid district ID", err)
+			return
+		}
+		districtID = &id
+	}
+
+	rules, err := h.districtRepo.ListRoutingRules(r.Context(), districtID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list routing rules", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rules)
+}
+
+// CreateRoutingRule creates a new routing rule (admin only)
+func (h *DistrictHandler) CreateRoutingRule(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateRoutingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	rule := &models.RoutingRule{
+		CategoryID: req.CategoryID,
+		DistrictID: req.DistrictID,
+		ServiceID:  req.ServiceID,
+	}
+
+	if err := h.districtRepo.CreateRoutingRule(r.Context(), rule); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRoutingRuleExists):
+			respondError(w, http.StatusConflict, err.Error(), err)
+		case errors.Is(err, repository.ErrRoutingRuleTarget):
+			respondError(w, http.StatusBadRequest, err.Error(), err)
+		default:
+			respondError(w, http.StatusInternalServerError, "Failed to create routing rule", err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, rule)
+}
+
+// UpdateRoutingRule changes the service of a routing rule (admin only)
+func (h *DistrictHandler) UpdateRoutingRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid routing rule ID", err)
+		return
+	}
+
+	var req models.UpdateRoutingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if err := h.districtRepo.UpdateRoutingRule(r.Context(), id, req.ServiceID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrRoutingRuleNotFound):
+			respondError(w, http.StatusNotFound, "Routing rule not found", err)
+		case errors.Is(err, repository.ErrRoutingRuleTarget):
+			respondError(w, http.StatusBadRequest, err.Error(), err)
+		default:
+			respondError(w, http.StatusInternalServerError, "Failed to update routing rule", err)
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Routing rule updated successfully"})
+}
+
+// DeleteRoutingRule deletes a routing rule (admin only)
+func (h *DistrictHandler) DeleteRoutingRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid routing rule ID", err)
+		return
+	}
+
+	if err := h.districtRepo.DeleteRoutingRule(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrRoutingRuleNotFound) {
+			respondError(w, http.StatusNotFound, "Routing rule not found", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to delete routing rule", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Routing rule deleted successfully"})
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+	"citizen-appeals/internal/service"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type HotspotHandler struct {
+	hotspotRepo *repository.HotspotRepository
+	detector    *service.HotspotDetector
+	validator   *validator.Validate
+}
+
+func NewHotspotHandler(hotspotRepo *repository.HotspotRepository, detector *service.HotspotDetector) *HotspotHandler {
+	return &HotspotHandler{
+		hotspotRepo: hotspotRepo,
+		detector:    detector,
+		validator:   validator.New(),
+	}
+}
+
+// List retrieves stored hotspots (admin only).
+// Query params: category_id (omit for the all-categories run), window_days.
+func (h *HotspotHandler) List(w http.ResponseWriter, r *http.Request) {
+	var categoryID *int64
+	if raw := r.URL.Query().Get("category_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid category_id", err)
+			return
+		}
+		categoryID = &id
+	}
+
+	var windowDays *int
+	if raw := r.URL.Query().Get("window_days"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days < 1 {
+			respondError(w, http.StatusBadRequest, "Invalid window_days", err)
+			return
+		}
+		windowDays = &days
+	}
+
+	hotspots, err := h.hotspotRepo.List(r.Context(), categoryID, windowDays)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list hotspots", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, hotspots)
+}
+
+// Recompute runs hotspot detection for the given scope right away (admin only)
+func (h *HotspotHandler) Recompute(w http.ResponseWriter, r *http.Request) {
+	var req models.RecomputeHotspotsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	windowDays := 0
+	if req.WindowDays != nil {
+		windowDays = *req.WindowDays
+	}
+
+	hotspots, err := h.detector.Recompute(r.Context(), req.CategoryID, windowDays)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to recompute hotspots", err)
+		return
+	}
+
+	// Read back so the response carries category names like List does
+	if len(hotspots) > 0 {
+		hotspots, err = h.hotspotRepo.List(r.Context(), req.CategoryID, &hotspots[0].WindowDays)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to list hotspots", err)
+			return
+		}
+	}
+
+	respondJSON(w, http.StatusOK, hotspots)
+}
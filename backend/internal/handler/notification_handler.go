@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"citizen-appeals/internal/middleware"
+	"citizen-appeals/internal/models"
 	"citizen-appeals/internal/repository"
 )
 
@@ -35,6 +37,13 @@ func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// cursor= (even empty, for the first page) pages by keyset and answers with a
+	// paginated response; without it the plain list is kept for older clients
+	if r.URL.Query().Has("cursor") {
+		h.listByCursor(w, r, userID, r.URL.Query().Get("cursor"), limit)
+		return
+	}
+
 	offset := (page - 1) * limit
 	notifications, err := h.repo.GetByUserID(r.Context(), userID, limit, offset)
 	if err != nil {
@@ -45,6 +54,39 @@ func (h *NotificationHandler) List(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, notifications)
 }
 
+// listByCursor responds with a page of the user's notifications after (or before) the cursor
+func (h *NotificationHandler) listByCursor(w http.ResponseWriter, r *http.Request, userID int64, cursor string, limit int) {
+	notifications, cursors, err := h.repo.GetPageByUserID(r.Context(), userID, cursor, limit)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			respondError(w, http.StatusBadRequest, "Invalid cursor", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to get notifications", err)
+		return
+	}
+
+	total, err := h.repo.CountByUserID(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get notifications", err)
+		return
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	respondJSON(w, http.StatusOK, models.PaginatedResponse{
+		Items:      notifications,
+		Total:      total,
+		Limit:      limit,
+		TotalPages: totalPages,
+		NextCursor: cursors.Next,
+		PrevCursor: cursors.Prev,
+	})
+}
+
 // GetUnreadCount returns the count of unread notifications
 func (h *NotificationHandler) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
 	userID, _ := middleware.GetUserID(r.Context())
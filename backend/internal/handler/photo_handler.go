@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"io"
 	"log"
 	"net/http"
@@ -10,25 +11,32 @@ import (
 	"citizen-appeals/internal/middleware"
 	"citizen-appeals/internal/models"
 	"citizen-appeals/internal/repository"
+	"citizen-appeals/internal/service"
 	"citizen-appeals/pkg/storage"
 )
 
 const (
 	maxPhotosPerAppeal = 5
 	maxPhotoSize       = 5 * 1024 * 1024 // 5MB
+	// maxStagedPhotosPerUser limits photos a user may hold unattached at a time
+	maxStagedPhotosPerUser = 20
 )
 
 type PhotoHandler struct {
-	photoRepo  *repository.PhotoRepository
-	appealRepo *repository.AppealRepository
-	storage    storage.Storage
+	photoRepo     *repository.PhotoRepository
+	appealRepo    *repository.AppealRepository
+	commentRepo   *repository.CommentRepository
+	appealService *service.AppealService
+	storage       storage.Storage
 }
 
-func NewPhotoHandler(photoRepo *repository.PhotoRepository, appealRepo *repository.AppealRepository, storage storage.Storage) *PhotoHandler {
+func NewPhotoHandler(photoRepo *repository.PhotoRepository, appealRepo *repository.AppealRepository, commentRepo *repository.CommentRepository, appealService *service.AppealService, storage storage.Storage) *PhotoHandler {
 	return &PhotoHandler{
-		photoRepo:  photoRepo,
-		appealRepo: appealRepo,
-		storage:    storage,
+		photoRepo:     photoRepo,
+		appealRepo:    appealRepo,
+		commentRepo:   commentRepo,
+		appealService: appealService,
+		storage:       storage,
 	}
 }
 
@@ -124,41 +132,198 @@ func (h *PhotoHandler) Upload(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Save file to storage
-		filePath, mimeType, fileSize, err := h.storage.Save(file, fileHeader, appealID, isResultPhoto)
+		saved, err := h.storage.Save(file, fileHeader, appealID, isResultPhoto)
 		file.Close()
 		if err != nil {
-			respondError(w, http.StatusInternalServerError, "Failed to save file", err)
+			respondSaveError(w, err)
 			return
 		}
 
 		// Create photo record in database
-		photo := &models.Photo{
-			AppealID:      &appealID,
-			FilePath:      filePath,
-			FileName:      fileHeader.Filename,
-			FileSize:      fileSize,
-			MimeType:      mimeType,
-			IsResultPhoto: isResultPhoto,
-		}
+		photo := newPhoto(saved, fileHeader.Filename, userID)
+		photo.AppealID = &appealID
+		photo.IsResultPhoto = isResultPhoto
 
 		if err := h.photoRepo.Create(r.Context(), photo); err != nil {
 			// Clean up file if database insert fails
-			h.storage.Delete(filePath)
+			h.deleteFiles(photo)
+			respondError(w, http.StatusInternalServerError, "Failed to save photo record", err)
+			return
+		}
+
+		uploadedPhotos = append(uploadedPhotos, h.uploadResponse(photo))
+	}
+
+	// Photo-count priority rules can only be checked once the author's photos are in
+	if !isResultPhoto && h.appealService != nil {
+		if err := h.appealService.ReevaluatePriority(r.Context(), appealID, currentCount+len(uploadedPhotos)); err != nil {
+			log.Printf("Failed to re-evaluate priority for appeal %d: %v", appealID, err)
+		}
+	}
+
+	respondJSON(w, http.StatusCreated, uploadedPhotos)
+}
+
+// UploadStaged uploads photos before their appeal or comment exists. The returned IDs
+// go into photo_ids of the create request; photos left unclaimed expire.
+func (h *PhotoHandler) UploadStaged(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r.Context())
+
+	currentCount, err := h.photoRepo.CountStaged(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to count photos", err)
+		return
+	}
+
+	if err := r.ParseMultipartForm(maxPhotoSize * maxPhotosPerAppeal); err != nil {
+		respondError(w, http.StatusBadRequest, "Failed to parse form", err)
+		return
+	}
+
+	files := r.MultipartForm.File["photos"]
+	if len(files) == 0 {
+		respondError(w, http.StatusBadRequest, "No files provided")
+		return
+	}
+
+	if len(files) > maxPhotosPerAppeal || currentCount+len(files) > maxStagedPhotosPerUser {
+		respondError(w, http.StatusBadRequest, "Too many photos. Attach or wait for earlier uploads to expire")
+		return
+	}
+
+	uploadedPhotos := make([]*models.UploadPhotoResponse, 0, len(files))
+	for _, fileHeader := range files {
+		if err := storage.ValidateFile(fileHeader, maxPhotoSize, maxPhotosPerAppeal, len(uploadedPhotos)); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error(), err)
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to open file", err)
+			return
+		}
+
+		saved, err := h.storage.SaveStaged(file, fileHeader, userID)
+		file.Close()
+		if err != nil {
+			respondSaveError(w, err)
+			return
+		}
+
+		photo := newPhoto(saved, fileHeader.Filename, userID)
+		if err := h.photoRepo.Create(r.Context(), photo); err != nil {
+			h.deleteFiles(photo)
 			respondError(w, http.StatusInternalServerError, "Failed to save photo record", err)
 			return
 		}
 
-		uploadedPhotos = append(uploadedPhotos, &models.UploadPhotoResponse{
-			ID:       photo.ID,
-			FileName: photo.FileName,
-			FileSize: photo.FileSize,
-			URL:      h.storage.GetURL(filePath),
-		})
+		uploadedPhotos = append(uploadedPhotos, h.uploadResponse(photo))
 	}
 
 	respondJSON(w, http.StatusCreated, uploadedPhotos)
 }
 
+// respondSaveError maps storage errors of an upload to HTTP responses
+func respondSaveError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, storage.ErrFileTooLarge):
+		respondError(w, http.StatusBadRequest, "File is too large", err)
+	case errors.Is(err, storage.ErrInvalidFileType):
+		respondError(w, http.StatusBadRequest, "File is not a supported image", err)
+	default:
+		respondError(w, http.StatusInternalServerError, "Failed to save file", err)
+	}
+}
+
+// newPhoto makes the photo record of a saved file
+func newPhoto(saved *storage.SavedFile, fileName string, userID int64) *models.Photo {
+	photo := &models.Photo{
+		FilePath:   saved.Path,
+		FileName:   fileName,
+		FileSize:   saved.Size,
+		MimeType:   saved.MimeType,
+		UploadedBy: &userID,
+	}
+	if saved.Width > 0 {
+		photo.Width, photo.Height = &saved.Width, &saved.Height
+	}
+	if saved.ThumbPath != "" {
+		photo.ThumbPath = &saved.ThumbPath
+	}
+	if saved.MediumPath != "" {
+		photo.MediumPath = &saved.MediumPath
+	}
+	return photo
+}
+
+// setPhotoURLs fills in the URLs of a photo and its variants
+func setPhotoURLs(s storage.Storage, photo *models.Photo) {
+	photo.URL = s.GetURL(photo.FilePath)
+	if photo.ThumbPath != nil {
+		photo.ThumbURL = s.GetURL(*photo.ThumbPath)
+	}
+	if photo.MediumPath != nil {
+		photo.MediumURL = s.GetURL(*photo.MediumPath)
+	}
+}
+
+func (h *PhotoHandler) uploadResponse(photo *models.Photo) *models.UploadPhotoResponse {
+	setPhotoURLs(h.storage, photo)
+	return &models.UploadPhotoResponse{
+		ID:        photo.ID,
+		FileName:  photo.FileName,
+		FileSize:  photo.FileSize,
+		URL:       photo.URL,
+		Width:     photo.Width,
+		Height:    photo.Height,
+		ThumbURL:  photo.ThumbURL,
+		MediumURL: photo.MediumURL,
+	}
+}
+
+// deleteFiles removes the files of a photo, logging failures
+func (h *PhotoHandler) deleteFiles(photo *models.Photo) {
+	for _, path := range photo.StoredFiles() {
+		if err := h.storage.Delete(path); err != nil && err != storage.ErrFileNotFound {
+			log.Printf("Warning: failed to delete file from storage: %v", err)
+		}
+	}
+}
+
+// isStagedPhotoOwner reports whether a photo is staged, and if so, whether the user may
+// see or remove it: only its uploader and admins can.
+func isStagedPhotoOwner(photo *models.Photo, userID int64, userRole models.UserRole) (staged, allowed bool) {
+	if photo.AppealID != nil {
+		return false, false
+	}
+	return true, userRole == models.RoleAdmin || (photo.UploadedBy != nil && *photo.UploadedBy == userID)
+}
+
+// citizenCommentAccess reports whether a citizen may see and remove a photo attached to
+// a comment: internal comments are hidden from citizens, and only the author may remove
+// photos of their comment.
+func citizenCommentAccess(comment *models.Comment, userID int64) (canView, canDelete bool) {
+	if comment.IsInternal {
+		return false, false
+	}
+	return true, comment.UserID == userID
+}
+
+// commentAccess checks a citizen's access to a photo attached to a comment.
+// Other roles and photos of the appeal itself are not restricted here.
+func (h *PhotoHandler) commentAccess(r *http.Request, photo *models.Photo, userID int64, userRole models.UserRole) (canView, canDelete bool, err error) {
+	if userRole != models.RoleCitizen || photo.CommentID == nil {
+		return true, true, nil
+	}
+	comment, err := h.commentRepo.GetByID(r.Context(), *photo.CommentID)
+	if err != nil {
+		return false, false, err
+	}
+	canView, canDelete = citizenCommentAccess(comment, userID)
+	return canView, canDelete, nil
+}
+
 // Get retrieves a photo by ID
 func (h *PhotoHandler) Get(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -182,16 +347,33 @@ func (h *PhotoHandler) Get(w http.ResponseWriter, r *http.Request) {
 	userID, _ := middleware.GetUserID(r.Context())
 	userRole, _ := middleware.GetUserRole(r.Context())
 
-	appeal, err := h.appealRepo.GetByID(r.Context(), *photo.AppealID)
-	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to get appeal", err)
-		return
-	}
+	if staged, allowed := isStagedPhotoOwner(photo, userID, userRole); staged {
+		if !allowed {
+			respondError(w, http.StatusForbidden, "You can only view your own uploads")
+			return
+		}
+	} else {
+		appeal, err := h.appealRepo.GetByID(r.Context(), *photo.AppealID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get appeal", err)
+			return
+		}
 
-	// Check permissions
-	if userRole == models.RoleCitizen && appeal.UserID != userID {
-		respondError(w, http.StatusForbidden, "You can only view photos of your own appeals")
-		return
+		// Check permissions
+		if userRole == models.RoleCitizen && appeal.UserID != userID {
+			respondError(w, http.StatusForbidden, "You can only view photos of your own appeals")
+			return
+		}
+
+		canView, _, err := h.commentAccess(r, photo, userID, userRole)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get comment", err)
+			return
+		}
+		if !canView {
+			respondError(w, http.StatusForbidden, "You don't have permission to view this photo")
+			return
+		}
 	}
 
 	// Get file from storage
@@ -257,13 +439,18 @@ func (h *PhotoHandler) List(w http.ResponseWriter, r *http.Request) {
 	// Add URLs to photos
 	response := make([]map[string]interface{}, len(photos))
 	for i, photo := range photos {
+		setPhotoURLs(h.storage, photo)
 		response[i] = map[string]interface{}{
 			"id":              photo.ID,
 			"file_name":       photo.FileName,
 			"file_size":       photo.FileSize,
 			"mime_type":       photo.MimeType,
 			"is_result_photo": photo.IsResultPhoto,
-			"url":             h.storage.GetURL(photo.FilePath),
+			"url":             photo.URL,
+			"thumb_url":       photo.ThumbURL,
+			"medium_url":      photo.MediumURL,
+			"width":           photo.Width,
+			"height":          photo.Height,
 			"uploaded_at":     photo.UploadedAt,
 		}
 	}
@@ -295,26 +482,38 @@ func (h *PhotoHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	userID, _ := middleware.GetUserID(r.Context())
 	userRole, _ := middleware.GetUserRole(r.Context())
 
-	appeal, err := h.appealRepo.GetByID(r.Context(), *photo.AppealID)
-	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to get appeal", err)
-		return
-	}
+	// The uploader may drop a staged photo before submitting
+	staged, canDelete := isStagedPhotoOwner(photo, userID, userRole)
+	if !staged {
+		appeal, err := h.appealRepo.GetByID(r.Context(), *photo.AppealID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to get appeal", err)
+			return
+		}
 
-	canDelete := false
-	if userRole == models.RoleCitizen {
-		// Citizens can only delete their own photos from their own appeals
-		if appeal.UserID == userID && !photo.IsResultPhoto {
+		if userRole == models.RoleCitizen {
+			// Citizens can only delete their own photos from their own appeals
+			if appeal.UserID == userID && !photo.IsResultPhoto {
+				canDelete = true
+			}
+		} else if userRole == models.RoleExecutor {
+			// Executors can delete result photos from appeals assigned to their service
+			if appeal.ServiceID != nil && photo.IsResultPhoto {
+				canDelete = true
+			}
+		} else if userRole == models.RoleDispatcher || userRole == models.RoleAdmin {
+			// Dispatchers and admins can delete any photo
 			canDelete = true
 		}
-	} else if userRole == models.RoleExecutor {
-		// Executors can delete result photos from appeals assigned to their service
-		if appeal.ServiceID != nil && photo.IsResultPhoto {
-			canDelete = true
+
+		// Photos of a comment belong to its author
+		if canDelete {
+			_, canDelete, err = h.commentAccess(r, photo, userID, userRole)
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, "Failed to get comment", err)
+				return
+			}
 		}
-	} else if userRole == models.RoleDispatcher || userRole == models.RoleAdmin {
-		// Dispatchers and admins can delete any photo
-		canDelete = true
 	}
 
 	if !canDelete {
@@ -322,11 +521,8 @@ func (h *PhotoHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Delete file from storage
-	if err := h.storage.Delete(photo.FilePath); err != nil && err != storage.ErrFileNotFound {
-		log.Printf("Warning: failed to delete file from storage: %v", err)
-		// Continue with database deletion even if file deletion fails
-	}
+	// Delete files from storage; continue with database deletion even if that fails
+	h.deleteFiles(photo)
 
 	// Delete photo record
 	if err := h.photoRepo.Delete(r.Context(), id); err != nil {
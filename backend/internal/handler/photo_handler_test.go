@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"citizen-appeals/internal/models"
+)
+
+func TestCitizenCommentAccess(t *testing.T) {
+	comment := &models.Comment{UserID: 7}
+
+	canView, canDelete := citizenCommentAccess(comment, 7)
+	assert.True(t, canView)
+	assert.True(t, canDelete, "the author may remove photos of their comment")
+
+	canView, canDelete = citizenCommentAccess(comment, 8)
+	assert.True(t, canView)
+	assert.False(t, canDelete, "the appeal author may not remove photos of someone else's comment")
+
+	internal := &models.Comment{UserID: 7, IsInternal: true}
+	canView, canDelete = citizenCommentAccess(internal, 7)
+	assert.False(t, canView, "internal comments are hidden from citizens")
+	assert.False(t, canDelete)
+}
+
+func TestIsStagedPhotoOwner(t *testing.T) {
+	uploader, appealID := int64(5), int64(9)
+	staged := &models.Photo{ID: 1, UploadedBy: &uploader}
+
+	isStaged, allowed := isStagedPhotoOwner(staged, 5, models.RoleCitizen)
+	assert.True(t, isStaged)
+	assert.True(t, allowed)
+
+	_, allowed = isStagedPhotoOwner(staged, 6, models.RoleDispatcher)
+	assert.False(t, allowed, "only the uploader and admins see staged photos")
+
+	_, allowed = isStagedPhotoOwner(staged, 6, models.RoleAdmin)
+	assert.True(t, allowed)
+
+	isStaged, _ = isStagedPhotoOwner(&models.Photo{ID: 2, AppealID: &appealID, UploadedBy: &uploader}, 5, models.RoleCitizen)
+	assert.False(t, isStaged)
+}
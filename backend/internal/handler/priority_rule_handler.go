@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+	"citizen-appeals/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type PriorityRuleHandler struct {
+	ruleRepo  *repository.PriorityRuleRepository
+	validator *validator.Validate
+}
+
+func NewPriorityRuleHandler(ruleRepo *repository.PriorityRuleRepository) *PriorityRuleHandler {
+	return &PriorityRuleHandler{
+		ruleRepo:  ruleRepo,
+		validator: validator.New(),
+	}
+}
+
+// List retrieves all priority rules (admin only)
+func (h *PriorityRuleHandler) List(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.ruleRepo.List(r.Context(), false)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list priority rules", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rules)
+}
+
+// Create creates a new priority rule (admin only)
+func (h *PriorityRuleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreatePriorityRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	rule := &models.PriorityRule{
+		Name:       req.Name,
+		Type:       req.Type,
+		Params:     req.Params,
+		Priority:   req.Priority,
+		CategoryID: req.CategoryID,
+		IsActive:   true,
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := service.ValidatePriorityRule(rule); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if err := h.ruleRepo.Create(r.Context(), rule); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create priority rule", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, rule)
+}
+
+// Update updates a priority rule (admin only)
+func (h *PriorityRuleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid priority rule ID", err)
+		return
+	}
+
+	rule, err := h.ruleRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrPriorityRuleNotFound) {
+			respondError(w, http.StatusNotFound, "Priority rule not found", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to get priority rule", err)
+		return
+	}
+
+	var req models.UpdatePriorityRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if req.Name != nil {
+		rule.Name = *req.Name
+	}
+	if req.Params != nil {
+		rule.Params = *req.Params
+	}
+	if req.Priority != nil {
+		rule.Priority = *req.Priority
+	}
+	if req.IsActive != nil {
+		rule.IsActive = *req.IsActive
+	}
+
+	if err := service.ValidatePriorityRule(rule); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if err := h.ruleRepo.Update(r.Context(), rule); err != nil {
+		if errors.Is(err, repository.ErrPriorityRuleNotFound) {
+			respondError(w, http.StatusNotFound, "Priority rule not found", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to update priority rule", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rule)
+}
+
+// Delete deletes a priority rule (admin only)
+func (h *PriorityRuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid priority rule ID", err)
+		return
+	}
+
+	if err := h.ruleRepo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrPriorityRuleNotFound) {
+			respondError(w, http.StatusNotFound, "Priority rule not found", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to delete priority rule", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Priority rule deleted successfully"})
+}
@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"citizen-appeals/internal/middleware"
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+	"citizen-appeals/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// reportDeliveryLimit is how many recent delivery attempts are listed per subscription
+const reportDeliveryLimit = 50
+
+type ReportSubscriptionHandler struct {
+	repo      *repository.ReportSubscriptionRepository
+	scheduler *service.ReportScheduler
+	validator *validator.Validate
+}
+
+func NewReportSubscriptionHandler(repo *repository.ReportSubscriptionRepository, scheduler *service.ReportScheduler) *ReportSubscriptionHandler {
+	return &ReportSubscriptionHandler{
+		repo:      repo,
+		scheduler: scheduler,
+		validator: validator.New(),
+	}
+}
+
+// List retrieves all report subscriptions (admin only)
+func (h *ReportSubscriptionHandler) List(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.repo.List(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list report subscriptions", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, subs)
+}
+
+// Create creates a new report subscription (admin only)
+func (h *ReportSubscriptionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateReportSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r.Context())
+	sub := &models.ReportSubscription{
+		Name:       req.Name,
+		ReportType: req.ReportType,
+		ServiceID:  req.ServiceID,
+		DateRange:  req.DateRange,
+		Recipients: req.Recipients,
+		Schedule:   req.Schedule,
+		IsActive:   true,
+		CreatedBy:  &userID,
+	}
+	if sub.DateRange == "" {
+		sub.DateRange = models.ReportLast7Days
+	}
+	if req.IsActive != nil {
+		sub.IsActive = *req.IsActive
+	}
+
+	if !h.schedule(w, sub) {
+		return
+	}
+
+	if err := h.repo.Create(r.Context(), sub); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create report subscription", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, sub)
+}
+
+// Update updates a report subscription and reschedules it from now (admin only)
+func (h *ReportSubscriptionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateReportSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if req.Name != nil {
+		sub.Name = *req.Name
+	}
+	if req.ServiceID != nil {
+		sub.ServiceID = req.ServiceID
+	}
+	if req.DateRange != nil {
+		sub.DateRange = *req.DateRange
+	}
+	if req.Recipients != nil {
+		sub.Recipients = req.Recipients
+	}
+	if req.Schedule != nil {
+		sub.Schedule = *req.Schedule
+	}
+	if req.IsActive != nil {
+		sub.IsActive = *req.IsActive
+	}
+
+	if !h.schedule(w, sub) {
+		return
+	}
+
+	if err := h.repo.Update(r.Context(), sub); err != nil {
+		if errors.Is(err, repository.ErrReportSubscriptionNotFound) {
+			respondError(w, http.StatusNotFound, "Report subscription not found", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to update report subscription", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, sub)
+}
+
+// Delete deletes a report subscription (admin only)
+func (h *ReportSubscriptionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid report subscription ID", err)
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrReportSubscriptionNotFound) {
+			respondError(w, http.StatusNotFound, "Report subscription not found", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to delete report subscription", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Report subscription deleted successfully"})
+}
+
+// ListDeliveries retrieves the latest delivery attempts of a subscription (admin only)
+func (h *ReportSubscriptionHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.repo.ListDeliveries(r.Context(), sub.ID, reportDeliveryLimit)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list report deliveries", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, deliveries)
+}
+
+// Send emails a subscription's report right away without changing its schedule (admin only)
+func (h *ReportSubscriptionHandler) Send(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.load(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.scheduler.Send(r.Context(), sub, time.Now(), 1)
+	if err != nil {
+		respondError(w, http.StatusBadGateway, "Failed to send report", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, delivery)
+}
+
+// load retrieves the subscription named in the URL, writing the error response if it cannot
+func (h *ReportSubscriptionHandler) load(w http.ResponseWriter, r *http.Request) (*models.ReportSubscription, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid report subscription ID", err)
+		return nil, false
+	}
+
+	sub, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrReportSubscriptionNotFound) {
+			respondError(w, http.StatusNotFound, "Report subscription not found", err)
+			return nil, false
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to get report subscription", err)
+		return nil, false
+	}
+
+	return sub, true
+}
+
+// schedule validates the subscription and sets its first run from now,
+// writing the error response if it is invalid
+func (h *ReportSubscriptionHandler) schedule(w http.ResponseWriter, sub *models.ReportSubscription) bool {
+	if err := service.ValidateReportSubscription(sub); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return false
+	}
+
+	next, err := service.NextReportRun(sub.Schedule, time.Now())
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return false
+	}
+	sub.NextRunAt = next
+	sub.FailedAttempts = 0
+	return true
+}
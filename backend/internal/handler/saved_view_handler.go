@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"citizen-appeals/internal/middleware"
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+	"citizen-appeals/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type SavedViewHandler struct {
+	repo      *repository.SavedViewRepository
+	service   *service.SavedViewService
+	validator *validator.Validate
+}
+
+func NewSavedViewHandler(repo *repository.SavedViewRepository, service *service.SavedViewService) *SavedViewHandler {
+	return &SavedViewHandler{
+		repo:      repo,
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+// List retrieves the current user's views and the views shared with them, with live counts
+func (h *SavedViewHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r.Context())
+	role, _ := middleware.GetUserRole(r.Context())
+
+	views, err := h.service.List(r.Context(), userID, role)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list saved views", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, views)
+}
+
+// GetByID retrieves a saved view with its live count
+func (h *SavedViewHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid saved view ID", err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r.Context())
+	role, _ := middleware.GetUserRole(r.Context())
+
+	view, err := h.service.Get(r.Context(), id, userID, role)
+	if err != nil {
+		respondSavedViewError(w, err, "Failed to get saved view")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, view)
+}
+
+// Create saves a view for the current user
+func (h *SavedViewHandler) Create(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r.Context())
+	view := &models.SavedView{UserID: userID}
+	if err := service.ApplySavedViewRequest(view, req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if err := h.repo.Create(r.Context(), view); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create saved view", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, view)
+}
+
+// Update replaces the settings of a view (owner only)
+func (h *SavedViewHandler) Update(w http.ResponseWriter, r *http.Request) {
+	view, ok := h.loadOwned(w, r)
+	if !ok {
+		return
+	}
+
+	req, ok := h.decode(w, r)
+	if !ok {
+		return
+	}
+
+	if err := service.ApplySavedViewRequest(view, req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if err := h.repo.Update(r.Context(), view); err != nil {
+		respondSavedViewError(w, err, "Failed to update saved view")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, view)
+}
+
+// Delete deletes a view (owner only)
+func (h *SavedViewHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	view, ok := h.loadOwned(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), view.ID); err != nil {
+		respondSavedViewError(w, err, "Failed to delete saved view")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Saved view deleted successfully"})
+}
+
+// Subscribe notifies the current user about new appeals matching the view
+func (h *SavedViewHandler) Subscribe(w http.ResponseWriter, r *http.Request) {
+	h.setSubscription(w, r, true)
+}
+
+// Unsubscribe stops notifications about the view
+func (h *SavedViewHandler) Unsubscribe(w http.ResponseWriter, r *http.Request) {
+	h.setSubscription(w, r, false)
+}
+
+func (h *SavedViewHandler) setSubscription(w http.ResponseWriter, r *http.Request, subscribe bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid saved view ID", err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r.Context())
+	role, _ := middleware.GetUserRole(r.Context())
+
+	view, err := h.repo.GetVisible(r.Context(), id, userID, role)
+	if err != nil {
+		respondSavedViewError(w, err, "Failed to get saved view")
+		return
+	}
+
+	if err := h.repo.Subscribe(r.Context(), view.ID, userID, subscribe); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update saved view subscription", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]bool{"subscribed": subscribe})
+}
+
+// decode reads and validates a view request, writing the error response if it is invalid
+func (h *SavedViewHandler) decode(w http.ResponseWriter, r *http.Request) (*models.SavedViewRequest, bool) {
+	var req models.SavedViewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return nil, false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return nil, false
+	}
+
+	return &req, true
+}
+
+// loadOwned retrieves the view named in the URL if the current user owns it,
+// writing the error response otherwise
+func (h *SavedViewHandler) loadOwned(w http.ResponseWriter, r *http.Request) (*models.SavedView, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid saved view ID", err)
+		return nil, false
+	}
+
+	userID, _ := middleware.GetUserID(r.Context())
+	role, _ := middleware.GetUserRole(r.Context())
+
+	view, err := h.repo.GetVisible(r.Context(), id, userID, role)
+	if err == nil {
+		err = service.CheckSavedViewOwner(view, userID)
+	}
+	if err != nil {
+		respondSavedViewError(w, err, "Failed to get saved view")
+		return nil, false
+	}
+
+	return view, true
+}
+
+func respondSavedViewError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrSavedViewNotFound):
+		respondError(w, http.StatusNotFound, "Saved view not found", err)
+	case errors.Is(err, service.ErrNotSavedViewOwner):
+		respondError(w, http.StatusForbidden, "Only the owner can change a saved view", err)
+	default:
+		respondError(w, http.StatusInternalServerError, message, err)
+	}
+}
@@ -3,11 +3,13 @@ package handler
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
 	"time"
 
+	"citizen-appeals/internal/middleware"
 	"citizen-appeals/internal/models"
 	"citizen-appeals/internal/repository"
 
@@ -101,12 +103,16 @@ func (h *ServiceHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	service := &models.Service{
-		Name:          req.Name,
-		Description:   req.Description,
-		ContactPerson: req.ContactPerson,
-		ContactPhone:  req.ContactPhone,
-		ContactEmail:  req.ContactEmail,
-		IsActive:      true,
+		Name:               req.Name,
+		Description:        req.Description,
+		ContactPerson:      req.ContactPerson,
+		ContactPhone:       req.ContactPhone,
+		ContactEmail:       req.ContactEmail,
+		IsActive:           true,
+		AssignmentStrategy: models.AssignmentManual,
+	}
+	if req.AssignmentStrategy != nil {
+		service.AssignmentStrategy = *req.AssignmentStrategy
 	}
 
 	if err := h.serviceRepo.Create(r.Context(), service); err != nil {
@@ -189,6 +195,9 @@ func (h *ServiceHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if req.IsActive != nil {
 		service.IsActive = *req.IsActive
 	}
+	if req.AssignmentStrategy != nil {
+		service.AssignmentStrategy = *req.AssignmentStrategy
+	}
 
 	if err := h.serviceRepo.Update(r.Context(), service); err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to update service", err)
@@ -260,7 +269,8 @@ func (h *ServiceHandler) syncClassificationService() {
 	}
 }
 
-// Delete soft deletes a service (admin only)
+// Delete archives a service (admin only). Open appeals block it unless ?reassign_to=
+// names the service to move them to.
 func (h *ServiceHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.ParseInt(idStr, 10, 64)
@@ -269,17 +279,31 @@ func (h *ServiceHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.serviceRepo.Delete(r.Context(), id); err != nil {
-		if err == repository.ErrServiceNotFound {
+	reassignTo, err := parseReassignTo(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid reassign_to", err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r.Context())
+	moved, err := h.serviceRepo.Delete(r.Context(), id, reassignTo, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrServiceNotFound):
 			respondError(w, http.StatusNotFound, "Service not found", err)
-			return
+		case errors.Is(err, repository.ErrServiceInUse):
+			respondError(w, http.StatusConflict, "Service has open appeals; pass reassign_to to move them to another service", err)
+		case errors.Is(err, repository.ErrInvalidReassignTarget):
+			respondError(w, http.StatusBadRequest, "Appeals can only be moved to another active service", err)
+		default:
+			respondError(w, http.StatusInternalServerError, "Failed to delete service", err)
 		}
-		respondError(w, http.StatusInternalServerError, "Failed to delete service", err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{
-		"message": "Service deleted successfully",
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message":    "Service deleted successfully",
+		"reassigned": moved,
 	})
 }
 
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type SLAPolicyHandler struct {
+	slaRepo   *repository.SLAPolicyRepository
+	validator *validator.Validate
+}
+
+func NewSLAPolicyHandler(slaRepo *repository.SLAPolicyRepository) *SLAPolicyHandler {
+	return &SLAPolicyHandler{
+		slaRepo:   slaRepo,
+		validator: validator.New(),
+	}
+}
+
+// List retrieves all SLA policies (dispatcher, admin)
+func (h *SLAPolicyHandler) List(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.slaRepo.List(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list SLA policies", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, policies)
+}
+
+// Create creates a new SLA policy (admin only)
+func (h *SLAPolicyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateSLAPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if req.ResolutionHours < req.ResponseHours {
+		respondError(w, http.StatusBadRequest, "Resolution time cannot be shorter than response time")
+		return
+	}
+
+	policy := &models.SLAPolicy{
+		CategoryID:      req.CategoryID,
+		Priority:        req.Priority,
+		ResponseHours:   req.ResponseHours,
+		ResolutionHours: req.ResolutionHours,
+	}
+
+	if err := h.slaRepo.Create(r.Context(), policy); err != nil {
+		if errors.Is(err, repository.ErrSLAPolicyExists) {
+			respondError(w, http.StatusConflict, "SLA policy for this category and priority already exists", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to create SLA policy", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, policy)
+}
+
+// Update updates SLA targets of a policy (admin only)
+func (h *SLAPolicyHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid SLA policy ID", err)
+		return
+	}
+
+	policy, err := h.slaRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrSLAPolicyNotFound) {
+			respondError(w, http.StatusNotFound, "SLA policy not found", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to get SLA policy", err)
+		return
+	}
+
+	var req models.UpdateSLAPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if req.ResponseHours != nil {
+		policy.ResponseHours = *req.ResponseHours
+	}
+	if req.ResolutionHours != nil {
+		policy.ResolutionHours = *req.ResolutionHours
+	}
+
+	if policy.ResolutionHours < policy.ResponseHours {
+		respondError(w, http.StatusBadRequest, "Resolution time cannot be shorter than response time")
+		return
+	}
+
+	if err := h.slaRepo.Update(r.Context(), policy); err != nil {
+		if errors.Is(err, repository.ErrSLAPolicyNotFound) {
+			respondError(w, http.StatusNotFound, "SLA policy not found", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to update SLA policy", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, policy)
+}
+
+// Delete deletes an SLA policy (admin only)
+func (h *SLAPolicyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid SLA policy ID", err)
+		return
+	}
+
+	if err := h.slaRepo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrSLAPolicyNotFound) {
+			respondError(w, http.StatusNotFound, "SLA policy not found", err)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to delete SLA policy", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "SLA policy deleted successfully"})
+}
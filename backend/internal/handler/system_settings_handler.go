@@ -2,24 +2,37 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"sync"
 
 	"citizen-appeals/internal/models"
+	"citizen-appeals/pkg/geo"
 )
 
+// cityBoundaryFile is the name of the GeoJSON file with the city boundary,
+// kept in the same directory as the settings file.
+const cityBoundaryFile = "city_boundary.geojson"
+
+// maxCityBoundarySize limits the size of an uploaded boundary
+const maxCityBoundarySize = 10 << 20
+
 // SystemSettingsHandler handles reading and updating system-wide settings
-// that are stored in a JSON file on disk.
+// that are stored in a JSON file on disk, and the city boundary polygon
+// stored as GeoJSON next to it.
 type SystemSettingsHandler struct {
-	filePath string
-	mu       sync.RWMutex
+	filePath     string
+	boundaryPath string
+	mu           sync.RWMutex
 }
 
 func NewSystemSettingsHandler(filePath string) *SystemSettingsHandler {
 	return &SystemSettingsHandler{
-		filePath: filePath,
+		filePath:     filePath,
+		boundaryPath: filepath.Join(filepath.Dir(filePath), cityBoundaryFile),
 	}
 }
 
@@ -37,11 +50,21 @@ func (h *SystemSettingsHandler) load() (*models.SystemSettings, error) {
 		if os.IsNotExist(err) {
 			// Return sensible defaults if file does not exist yet
 			return &models.SystemSettings{
-				CityName:            "Київ",
-				MapCenterLat:        50.4501,
-				MapCenterLng:        30.5234,
-				MapZoom:             13,
-				ConfidenceThreshold: 0.8,
+				CityName:                     "Київ",
+				MapCenterLat:                 50.4501,
+				MapCenterLng:                 30.5234,
+				MapZoom:                      13,
+				ConfidenceThreshold:          0.8,
+				DuplicateRadiusMeters:        models.DefaultDuplicateRadiusMeters,
+				DuplicateSimilarityThreshold: models.DefaultDuplicateSimilarityThreshold,
+				ConfirmationWindowDays:       models.DefaultConfirmationWindowDays,
+				HotspotRadiusMeters:          models.DefaultHotspotRadiusMeters,
+				HotspotMinAppeals:            models.DefaultHotspotMinAppeals,
+				HotspotWindowDays:            models.DefaultHotspotWindowDays,
+				SpikeWindowMinutes:           models.DefaultSpikeWindowMinutes,
+				SpikeBaselineDays:            models.DefaultSpikeBaselineDays,
+				SpikeThreshold:               models.DefaultSpikeThreshold,
+				SpikeMinAppeals:              models.DefaultSpikeMinAppeals,
 			}, nil
 		}
 		return nil, err
@@ -56,6 +79,36 @@ func (h *SystemSettingsHandler) load() (*models.SystemSettings, error) {
 	if settings.ConfidenceThreshold == 0 {
 		settings.ConfidenceThreshold = 0.8
 	}
+	if settings.DuplicateRadiusMeters == 0 {
+		settings.DuplicateRadiusMeters = models.DefaultDuplicateRadiusMeters
+	}
+	if settings.DuplicateSimilarityThreshold == 0 {
+		settings.DuplicateSimilarityThreshold = models.DefaultDuplicateSimilarityThreshold
+	}
+	if settings.ConfirmationWindowDays == 0 {
+		settings.ConfirmationWindowDays = models.DefaultConfirmationWindowDays
+	}
+	if settings.HotspotRadiusMeters == 0 {
+		settings.HotspotRadiusMeters = models.DefaultHotspotRadiusMeters
+	}
+	if settings.HotspotMinAppeals == 0 {
+		settings.HotspotMinAppeals = models.DefaultHotspotMinAppeals
+	}
+	if settings.HotspotWindowDays == 0 {
+		settings.HotspotWindowDays = models.DefaultHotspotWindowDays
+	}
+	if settings.SpikeWindowMinutes == 0 {
+		settings.SpikeWindowMinutes = models.DefaultSpikeWindowMinutes
+	}
+	if settings.SpikeBaselineDays == 0 {
+		settings.SpikeBaselineDays = models.DefaultSpikeBaselineDays
+	}
+	if settings.SpikeThreshold == 0 {
+		settings.SpikeThreshold = models.DefaultSpikeThreshold
+	}
+	if settings.SpikeMinAppeals == 0 {
+		settings.SpikeMinAppeals = models.DefaultSpikeMinAppeals
+	}
 
 	return &settings, nil
 }
@@ -112,6 +165,72 @@ func (h *SystemSettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
 	if req.ConfidenceThreshold > 1.0 {
 		req.ConfidenceThreshold = 1.0
 	}
+	// Settings the client did not send keep their current values
+	if current, err := h.load(); err == nil {
+		if req.DuplicateRadiusMeters <= 0 {
+			req.DuplicateRadiusMeters = current.DuplicateRadiusMeters
+		}
+		if req.DuplicateSimilarityThreshold <= 0 {
+			req.DuplicateSimilarityThreshold = current.DuplicateSimilarityThreshold
+		}
+		if req.ConfirmationWindowDays <= 0 {
+			req.ConfirmationWindowDays = current.ConfirmationWindowDays
+		}
+		if req.HotspotRadiusMeters <= 0 {
+			req.HotspotRadiusMeters = current.HotspotRadiusMeters
+		}
+		if req.HotspotMinAppeals <= 0 {
+			req.HotspotMinAppeals = current.HotspotMinAppeals
+		}
+		if req.HotspotWindowDays <= 0 {
+			req.HotspotWindowDays = current.HotspotWindowDays
+		}
+		if req.SpikeWindowMinutes <= 0 {
+			req.SpikeWindowMinutes = current.SpikeWindowMinutes
+		}
+		if req.SpikeBaselineDays <= 0 {
+			req.SpikeBaselineDays = current.SpikeBaselineDays
+		}
+		if req.SpikeThreshold <= 0 {
+			req.SpikeThreshold = current.SpikeThreshold
+		}
+		if req.SpikeMinAppeals <= 0 {
+			req.SpikeMinAppeals = current.SpikeMinAppeals
+		}
+	}
+	if req.DuplicateRadiusMeters <= 0 {
+		req.DuplicateRadiusMeters = models.DefaultDuplicateRadiusMeters
+	}
+	if req.DuplicateSimilarityThreshold <= 0 {
+		req.DuplicateSimilarityThreshold = models.DefaultDuplicateSimilarityThreshold
+	}
+	if req.ConfirmationWindowDays <= 0 {
+		req.ConfirmationWindowDays = models.DefaultConfirmationWindowDays
+	}
+	if req.HotspotRadiusMeters <= 0 {
+		req.HotspotRadiusMeters = models.DefaultHotspotRadiusMeters
+	}
+	if req.HotspotMinAppeals <= 0 {
+		req.HotspotMinAppeals = models.DefaultHotspotMinAppeals
+	}
+	if req.HotspotWindowDays <= 0 {
+		req.HotspotWindowDays = models.DefaultHotspotWindowDays
+	}
+	if req.SpikeWindowMinutes <= 0 {
+		req.SpikeWindowMinutes = models.DefaultSpikeWindowMinutes
+	}
+	if req.SpikeBaselineDays <= 0 {
+		req.SpikeBaselineDays = models.DefaultSpikeBaselineDays
+	}
+	if req.SpikeThreshold <= 0 {
+		req.SpikeThreshold = models.DefaultSpikeThreshold
+	}
+	if req.SpikeMinAppeals <= 0 {
+		req.SpikeMinAppeals = models.DefaultSpikeMinAppeals
+	}
+	if req.DuplicateSimilarityThreshold > 1.0 {
+		req.DuplicateSimilarityThreshold = 1.0
+	}
 
 	if err := h.save(&req); err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to save system settings", err)
@@ -120,3 +239,88 @@ func (h *SystemSettingsHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	respondJSON(w, http.StatusOK, req)
 }
+
+// GetCityBoundary returns the configured city boundary, or nil when there is none
+// (public method for internal use)
+func (h *SystemSettingsHandler) GetCityBoundary() (geo.Area, error) {
+	data, err := h.loadBoundary()
+	if err != nil || data == nil {
+		return nil, err
+	}
+	return geo.ParseArea(data)
+}
+
+func (h *SystemSettingsHandler) loadBoundary() ([]byte, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	data, err := os.ReadFile(h.boundaryPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// GetBoundary returns the city boundary as GeoJSON (any authenticated user, so the map can draw it).
+func (h *SystemSettingsHandler) GetBoundary(w http.ResponseWriter, r *http.Request) {
+	data, err := h.loadBoundary()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to load city boundary", err)
+		return
+	}
+	if data == nil {
+		respondError(w, http.StatusNotFound, "City boundary is not configured")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, json.RawMessage(data))
+}
+
+// UpdateBoundary uploads or replaces the city boundary (admin-only, routed in main.go).
+// The body is a GeoJSON Polygon, MultiPolygon, Feature or FeatureCollection.
+func (h *SystemSettingsHandler) UpdateBoundary(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCityBoundarySize))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	area, err := geo.ParseArea(data)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(h.boundaryPath), 0o755); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to save city boundary", err)
+		return
+	}
+	if err := os.WriteFile(h.boundaryPath, data, 0o644); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to save city boundary", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"polygons": len(area),
+		"bounds":   area.Bounds(),
+	})
+}
+
+// DeleteBoundary removes the city boundary, so appeals are accepted anywhere (admin-only).
+func (h *SystemSettingsHandler) DeleteBoundary(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := os.Remove(h.boundaryPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		respondError(w, http.StatusInternalServerError, "Failed to delete city boundary", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "City boundary deleted successfully"})
+}
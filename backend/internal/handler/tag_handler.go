@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"citizen-appeals/internal/middleware"
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+	"citizen-appeals/internal/service"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type TagHandler struct {
+	repo      *repository.TagRepository
+	service   *service.TagService
+	validator *validator.Validate
+}
+
+func NewTagHandler(repo *repository.TagRepository, service *service.TagService) *TagHandler {
+	return &TagHandler{
+		repo:      repo,
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+// List retrieves all tags with their appeal counts
+func (h *TagHandler) List(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.repo.List(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list tags", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tags)
+}
+
+// Create creates a curated tag (admin only)
+func (h *TagHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r.Context())
+	tag := &models.Tag{
+		Name:      strings.Join(strings.Fields(req.Name), " "),
+		Color:     req.Color,
+		IsCurated: true,
+		CreatedBy: &userID,
+	}
+
+	if tag.Name == "" {
+		respondError(w, http.StatusBadRequest, "Tag name is required")
+		return
+	}
+
+	if err := h.repo.Create(r.Context(), tag); err != nil {
+		respondTagError(w, err, "Failed to create tag")
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, tag)
+}
+
+// Update renames or recolors a tag, or moves it between curated and free-form (admin only)
+func (h *TagHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid tag ID", err)
+		return
+	}
+
+	var req models.UpdateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	tag, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		respondTagError(w, err, "Failed to get tag")
+		return
+	}
+
+	if req.Name != nil {
+		tag.Name = strings.Join(strings.Fields(*req.Name), " ")
+	}
+	if req.Color != nil {
+		tag.Color = req.Color
+		if *req.Color == "" {
+			tag.Color = nil
+		}
+	}
+	if req.IsCurated != nil {
+		tag.IsCurated = *req.IsCurated
+	}
+
+	if tag.Name == "" {
+		respondError(w, http.StatusBadRequest, "Tag name is required")
+		return
+	}
+
+	if err := h.repo.Update(r.Context(), tag); err != nil {
+		respondTagError(w, err, "Failed to update tag")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tag)
+}
+
+// Delete deletes a tag and removes it from all appeals (admin only)
+func (h *TagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid tag ID", err)
+		return
+	}
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		respondTagError(w, err, "Failed to delete tag")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Tag deleted successfully"})
+}
+
+// Tag adds tags to several appeals at once (dispatcher, admin)
+func (h *TagHandler) Tag(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeBulk(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r.Context())
+	result, err := h.service.Tag(r.Context(), req, userID)
+	if err != nil {
+		respondTagError(w, err, "Failed to tag appeals")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// Untag removes tags from several appeals at once (dispatcher, admin)
+func (h *TagHandler) Untag(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.decodeBulk(w, r)
+	if !ok {
+		return
+	}
+
+	userID, _ := middleware.GetUserID(r.Context())
+	result, err := h.service.Untag(r.Context(), req, userID)
+	if err != nil {
+		respondTagError(w, err, "Failed to untag appeals")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// decodeBulk reads and validates a bulk tag request, writing the error response if it is invalid
+func (h *TagHandler) decodeBulk(w http.ResponseWriter, r *http.Request) (*models.BulkTagRequest, bool) {
+	var req models.BulkTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return nil, false
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error(), err)
+		return nil, false
+	}
+
+	return &req, true
+}
+
+func respondTagError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrTagNotFound):
+		respondError(w, http.StatusNotFound, "Tag not found", err)
+	case errors.Is(err, repository.ErrTagExists):
+		respondError(w, http.StatusConflict, "Tag with this name already exists", err)
+	case errors.Is(err, service.ErrNoTags):
+		respondError(w, http.StatusBadRequest, "At least one tag is required", err)
+	default:
+		respondError(w, http.StatusInternalServerError, message, err)
+	}
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"citizen-appeals/internal/middleware"
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// volumeAlertListLimit caps how many alerts List returns
+const volumeAlertListLimit = 100
+
+type VolumeAlertHandler struct {
+	alertRepo *repository.VolumeAlertRepository
+}
+
+func NewVolumeAlertHandler(alertRepo *repository.VolumeAlertRepository) *VolumeAlertHandler {
+	return &VolumeAlertHandler{
+		alertRepo: alertRepo,
+	}
+}
+
+// List retrieves the latest volume alerts (dispatcher, admin).
+// Query params: status (open, grouped, dismissed).
+func (h *VolumeAlertHandler) List(w http.ResponseWriter, r *http.Request) {
+	var status *models.VolumeAlertStatus
+	if raw := r.URL.Query().Get("status"); raw != "" {
+		s := models.VolumeAlertStatus(raw)
+		switch s {
+		case models.VolumeAlertOpen, models.VolumeAlertGrouped, models.VolumeAlertDismissed:
+		default:
+			respondError(w, http.StatusBadRequest, "Invalid status")
+			return
+		}
+		status = &s
+	}
+
+	alerts, err := h.alertRepo.List(r.Context(), status, volumeAlertListLimit)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list volume alerts", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, alerts)
+}
+
+// Group merges the appeals of an open alert into one incident (dispatcher, admin)
+func (h *VolumeAlertHandler) Group(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r.Context())
+
+	alert, ok := h.getOpenAlert(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.GroupVolumeAlertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if err := h.alertRepo.Group(r.Context(), alert.ID, req.MasterAppealID, userID); err != nil {
+		respondVolumeAlertError(w, err, "Failed to group appeals")
+		return
+	}
+
+	h.respondAlert(w, r, alert.ID)
+}
+
+// Dismiss closes an open alert without grouping its appeals (dispatcher, admin)
+func (h *VolumeAlertHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
+	userID, _ := middleware.GetUserID(r.Context())
+
+	alert, ok := h.getOpenAlert(w, r)
+	if !ok {
+		return
+	}
+
+	h.resolve(w, r, alert.ID, models.VolumeAlertDismissed, nil, userID)
+}
+
+func (h *VolumeAlertHandler) getOpenAlert(w http.ResponseWriter, r *http.Request) (*models.VolumeAlert, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid volume alert ID", err)
+		return nil, false
+	}
+
+	alert, err := h.alertRepo.GetByID(r.Context(), id)
+	if err != nil {
+		respondVolumeAlertError(w, err, "Failed to get volume alert")
+		return nil, false
+	}
+	if alert.Status != models.VolumeAlertOpen {
+		respondVolumeAlertError(w, repository.ErrVolumeAlertResolved, "")
+		return nil, false
+	}
+
+	return alert, true
+}
+
+func (h *VolumeAlertHandler) resolve(w http.ResponseWriter, r *http.Request, id int64, status models.VolumeAlertStatus, incidentAppealID *int64, userID int64) {
+	if err := h.alertRepo.Resolve(r.Context(), id, status, incidentAppealID, userID); err != nil {
+		respondVolumeAlertError(w, err, "Failed to resolve volume alert")
+		return
+	}
+
+	h.respondAlert(w, r, id)
+}
+
+func (h *VolumeAlertHandler) respondAlert(w http.ResponseWriter, r *http.Request, id int64) {
+	alert, err := h.alertRepo.GetByID(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get volume alert", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, alert)
+}
+
+func respondVolumeAlertError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrVolumeAlertNotFound):
+		respondError(w, http.StatusNotFound, "Volume alert not found", err)
+	case errors.Is(err, repository.ErrVolumeAlertResolved):
+		respondError(w, http.StatusConflict, "Volume alert is already resolved", err)
+	case errors.Is(err, repository.ErrNothingToGroup), errors.Is(err, repository.ErrInvalidIncidentMaster),
+		errors.Is(err, repository.ErrMergeConflict):
+		respondError(w, http.StatusConflict, err.Error(), err)
+	case errors.Is(err, repository.ErrAppealNotFound):
+		respondError(w, http.StatusNotFound, "Master appeal not found", err)
+	default:
+		respondError(w, http.StatusInternalServerError, message, err)
+	}
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"citizen-appeals/pkg/geo"
 )
 
 type AppealStatus string
@@ -13,6 +15,8 @@ const (
 	StatusCompleted  AppealStatus = "completed"
 	StatusClosed     AppealStatus = "closed"
 	StatusRejected   AppealStatus = "rejected"
+	// StatusReopened is set when the author rejects the executor's result
+	StatusReopened AppealStatus = "reopened"
 )
 
 type Appeal struct {
@@ -20,6 +24,8 @@ type Appeal struct {
 	UserID      int64        `json:"user_id" db:"user_id"`
 	CategoryID  *int64       `json:"category_id" db:"category_id"`
 	ServiceID   *int64       `json:"service_id" db:"service_id"`
+	AssigneeID  *int64       `json:"assignee_id" db:"assignee_id"`
+	DistrictID  *int64       `json:"district_id" db:"district_id"`
 	Status      AppealStatus `json:"status" db:"status"`
 	Title       string       `json:"title" db:"title"`
 	Description string       `json:"description" db:"description"`
@@ -30,13 +36,73 @@ type Appeal struct {
 	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
 	ClosedAt    *time.Time   `json:"closed_at" db:"closed_at"`
+	// When the executor reported the work as done
+	CompletedAt *time.Time `json:"completed_at" db:"completed_at"`
+	ReopenCount int        `json:"reopen_count" db:"reopen_count"`
+
+	// SLA deadlines (computed from the matching SLA policy on creation)
+	ResponseDueAt *time.Time `json:"response_due_at" db:"response_due_at"`
+	DueAt         *time.Time `json:"due_at" db:"due_at"`
+
+	// Duplicate detection: the most similar open appeal found on creation and how close it was (0..1)
+	DuplicateOfID  *int64   `json:"duplicate_of_id" db:"duplicate_of_id"`
+	DuplicateScore *float64 `json:"duplicate_score" db:"duplicate_score"`
+	// Master appeal this one was merged into (NULL for standalone and master appeals)
+	ParentID *int64 `json:"parent_id" db:"parent_id"`
+
+	// Values of the category's custom fields, by field key
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty" db:"custom_fields"`
+
+	// Number of citizens who attached their report to this appeal instead of creating a new one
+	SupportersCount int `json:"supporters_count" db:"-"`
+
+	// Distance from the point of a "near" search, in meters
+	DistanceMeters *float64 `json:"distance_meters,omitempty" db:"-"`
+
+	// How the appeal matched a full-text search
+	SearchMatch *AppealSearchMatch `json:"search_match,omitempty" db:"-"`
 
 	// Joined fields
 	User     *User     `json:"user,omitempty" db:"-"`
 	Category *Category `json:"category,omitempty" db:"-"`
 	Service  *Service  `json:"service,omitempty" db:"-"`
+	Assignee *User     `json:"assignee,omitempty" db:"-"`
+	District *District `json:"district,omitempty" db:"-"`
 	Photos   []Photo   `json:"photos,omitempty" db:"-"`
 	Comments []Comment `json:"comments,omitempty" db:"-"`
+	Tags     []*Tag    `json:"tags,omitempty" db:"-"`
+
+	// Appeals merged into this one (filled for a single appeal only)
+	LinkedAppeals []*Appeal `json:"linked_appeals,omitempty" db:"-"`
+	// Priority rules that fired for this appeal
+	PriorityRules []*AppealPriorityRule `json:"priority_rules,omitempty" db:"-"`
+
+	// Returned on creation only
+	PossibleDuplicates []DuplicateCandidate `json:"possible_duplicates,omitempty" db:"-"`
+}
+
+// AppealSearchMatch describes how an appeal matched a full-text search. Title, Snippet and
+// CommentSnippet are HTML-escaped text with the matched words wrapped in <mark>.
+type AppealSearchMatch struct {
+	Rank    float64 `json:"rank"`
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"`
+	// Best matching public comment, when comments were searched and one matched
+	CommentSnippet *string `json:"comment_snippet,omitempty"`
+}
+
+// DuplicateCandidate is an open appeal that likely describes the same problem
+type DuplicateCandidate struct {
+	AppealID       int64        `json:"appeal_id"`
+	Title          string       `json:"title"`
+	Status         AppealStatus `json:"status"`
+	Address        string       `json:"address"`
+	Latitude       float64      `json:"latitude"`
+	Longitude      float64      `json:"longitude"`
+	CreatedAt      time.Time    `json:"created_at"`
+	DistanceMeters float64      `json:"distance_meters"`
+	Similarity     float64      `json:"similarity"`
+	Score          float64      `json:"score"`
 }
 
 type CreateAppealRequest struct {
@@ -47,7 +113,19 @@ type CreateAppealRequest struct {
 	Latitude    float64 `json:"latitude" validate:"required,min=-90,max=90"`
 	Longitude   float64 `json:"longitude" validate:"required,min=-180,max=180"`
 	Priority    *int    `json:"priority" validate:"omitempty,min=1,max=3"`
-	PhotoIDs    []int64 `json:"photo_ids"`
+	// Staged photos of the author to attach to the appeal
+	PhotoIDs []int64 `json:"photo_ids" validate:"max=5,unique,dive,gt=0"`
+	// When set, the citizen joins this existing appeal, with the photos, instead of creating a new one
+	AttachToAppealID *int64 `json:"attach_to_appeal_id"`
+	// Values of the category's custom fields, by field key
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+type FindDuplicatesRequest struct {
+	CategoryID  int64   `json:"category_id" validate:"required"`
+	Description string  `json:"description" validate:"required"`
+	Latitude    float64 `json:"latitude" validate:"required,min=-90,max=90"`
+	Longitude   float64 `json:"longitude" validate:"required,min=-180,max=180"`
 }
 
 type UpdateAppealRequest struct {
@@ -57,6 +135,8 @@ type UpdateAppealRequest struct {
 	Address     *string  `json:"address"`
 	Latitude    *float64 `json:"latitude" validate:"omitempty,min=-90,max=90"`
 	Longitude   *float64 `json:"longitude" validate:"omitempty,min=-180,max=180"`
+	// Replaces all custom field values when given
+	CustomFields map[string]interface{} `json:"custom_fields"`
 }
 
 type AssignAppealRequest struct {
@@ -64,11 +144,24 @@ type AssignAppealRequest struct {
 	Priority  *int  `json:"priority" validate:"omitempty,min=1,max=3"`
 }
 
+// AssignExecutorRequest sets the executor responsible for an appeal; null clears it
+type AssignExecutorRequest struct {
+	ExecutorID *int64 `json:"executor_id"`
+}
+
 type UpdateStatusRequest struct {
 	Status  AppealStatus `json:"status" validate:"required"`
 	Comment *string      `json:"comment"`
 }
 
+type MergeAppealsRequest struct {
+	AppealIDs []int64 `json:"appeal_ids" validate:"required,min=1,dive,min=1"`
+}
+
+type ReopenAppealRequest struct {
+	Comment string `json:"comment" validate:"required,min=10"`
+}
+
 type UpdatePriorityRequest struct {
 	Priority int `json:"priority" validate:"required,min=1,max=3"`
 }
@@ -78,11 +171,34 @@ type AppealFilters struct {
 	CategoryID *int64        `json:"category_id"`
 	ServiceID  *int64        `json:"service_id"`
 	UserID     *int64        `json:"user_id"`
+	AssigneeID *int64        `json:"assignee_id"`
+	DistrictID *int64        `json:"district_id"`
+	Unclaimed  *bool         `json:"unclaimed"`
 	FromDate   *time.Time    `json:"from_date"`
 	ToDate     *time.Time    `json:"to_date"`
 	Search     *string       `json:"search"`
+	Overdue    *bool         `json:"overdue"`
 	Page       int           `json:"page"`
 	Limit      int           `json:"limit"`
 	SortBy     string        `json:"sort_by"`
 	SortOrder  string        `json:"sort_order"`
+
+	// Near limits the list to appeals within RadiusMeters of a point;
+	// BBox limits it to a rectangle of the map
+	Near         *geo.Point       `json:"near"`
+	RadiusMeters float64          `json:"radius_m"`
+	BBox         *geo.BoundingBox `json:"bbox"`
+
+	// SearchComments extends the full-text search to public comments
+	SearchComments bool `json:"search_comments"`
+
+	// TagsAny keeps appeals with at least one of the tags, TagsAll those with every one of them
+	TagsAny []int64 `json:"tags_any,omitempty"`
+	TagsAll []int64 `json:"tags_all,omitempty"`
+
+	// CustomFields keeps appeals whose custom field values equal these, compared as text
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+
+	// Cursor switches the list from Page to keyset pagination; "" is the first page
+	Cursor *string `json:"cursor"`
 }
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// AppealExportFormat is a spreadsheet format of the appeal export
+type AppealExportFormat string
+
+const (
+	ExportCSV  AppealExportFormat = "csv"
+	ExportXLSX AppealExportFormat = "xlsx"
+)
+
+// AppealExportRow is an appeal with everything a spreadsheet row needs. Appeal has
+// its author (with contacts), category (with its custom fields), service, district
+// and assignee joined.
+type AppealExportRow struct {
+	Appeal *Appeal
+	// Last status change after creation; nil when the status never changed
+	LastStatusChangeAt *time.Time
+	// Who made it; nil for system actions
+	LastStatusChangeBy *string
+}
@@ -5,7 +5,7 @@ import "time"
 type AppealHistory struct {
 	ID        int64        `json:"id" db:"id"`
 	AppealID  int64        `json:"appeal_id" db:"appeal_id"`
-	UserID    int64        `json:"user_id" db:"user_id"`
+	UserID    *int64       `json:"user_id" db:"user_id"` // NULL for system actions
 	OldStatus *AppealStatus `json:"old_status" db:"old_status"`
 	NewStatus AppealStatus `json:"new_status" db:"new_status"`
 	Action    string       `json:"action" db:"action"`
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// AppealMapPoint is the part of an appeal the map needs to draw a marker
+type AppealMapPoint struct {
+	ID           int64        `json:"id"`
+	Title        string       `json:"title"`
+	Status       AppealStatus `json:"status"`
+	Priority     int          `json:"priority"`
+	CategoryID   *int64       `json:"category_id"`
+	CategoryName *string      `json:"category_name"`
+	Latitude     float64      `json:"latitude"`
+	Longitude    float64      `json:"longitude"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// AppealClusterCell counts appeals of one status in one cell of the cluster grid
+type AppealClusterCell struct {
+	CellX  int64
+	CellY  int64
+	Status AppealStatus
+	Count  int64
+	// Mean location of the appeals in the cell
+	Latitude  float64
+	Longitude float64
+	// Smallest appeal ID in the cell, used when the cell holds a single appeal
+	AppealID int64
+}
+
+// MapFeatureCollection is a GeoJSON FeatureCollection of appeal points or clusters.
+// Zoom and Center are foreign members telling the client how the feed was built.
+type MapFeatureCollection struct {
+	Type     string       `json:"type"`
+	Features []MapFeature `json:"features"`
+	Zoom     int          `json:"zoom"`
+	Center   [2]float64   `json:"center"`
+	// Truncated is set when there were more appeals than the feed returns
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// MapFeature is a GeoJSON Feature with a Point geometry
+type MapFeature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   MapPoint               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// MapPoint is a GeoJSON Point; coordinates are [longitude, latitude]
+type MapPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
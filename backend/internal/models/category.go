@@ -5,24 +5,65 @@ import (
 )
 
 type Category struct {
-	ID              int64     `json:"id" db:"id"`
-	Name            string    `json:"name" db:"name"`
-	Description     string    `json:"description" db:"description"`
+	ID          int64  `json:"id" db:"id"`
+	Name        string `json:"name" db:"name"`
+	Description string `json:"description" db:"description"`
+	// A subcategory without its own default priority takes the parent's
 	DefaultPriority int       `json:"default_priority" db:"default_priority"`
 	IsActive        bool      `json:"is_active" db:"is_active"`
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+
+	// Extra fields that appeals of the category fill in
+	CustomFields []CustomField `json:"custom_fields" db:"custom_fields"`
+
+	// Top-level category of a subcategory; categories nest one level deep
+	ParentID *int64 `json:"parent_id,omitempty" db:"parent_id"`
+	// InheritsPriority tells that DefaultPriority is the parent's
+	InheritsPriority bool `json:"inherits_priority" db:"-"`
+	// Subcategories, filled in for the category tree
+	Children []*Category `json:"children,omitempty" db:"-"`
 }
 
 type CreateCategoryRequest struct {
-	Name            string `json:"name" validate:"required,min=3,max=100"`
-	Description     string `json:"description"`
-	DefaultPriority int    `json:"default_priority" validate:"required,min=1,max=3"`
+	Name        string `json:"name" validate:"required,min=3,max=100"`
+	Description string `json:"description"`
+	// Required for a top-level category; a subcategory without one takes the parent's
+	DefaultPriority int           `json:"default_priority" validate:"omitempty,min=1,max=3"`
+	CustomFields    []CustomField `json:"custom_fields" validate:"dive"`
+	ParentID        *int64        `json:"parent_id"`
 }
 
 type UpdateCategoryRequest struct {
-	Name            *string `json:"name" validate:"omitempty,min=3,max=100"`
-	Description     *string `json:"description"`
-	DefaultPriority *int    `json:"default_priority" validate:"omitempty,min=1,max=3"`
-	IsActive        *bool   `json:"is_active"`
+	Name        *string `json:"name" validate:"omitempty,min=3,max=100"`
+	Description *string `json:"description"`
+	// 0 makes a subcategory take the parent's priority again
+	DefaultPriority *int  `json:"default_priority" validate:"omitempty,min=0,max=3"`
+	IsActive        *bool `json:"is_active"`
+	// Replaces the whole field set when given
+	CustomFields *[]CustomField `json:"custom_fields" validate:"omitempty,dive"`
+	// Moves the category under another parent; 0 makes it top-level
+	ParentID *int64 `json:"parent_id"`
+}
+
+// CustomFieldType is the kind of value a category-specific field holds
+type CustomFieldType string
+
+const (
+	CustomFieldText    CustomFieldType = "text"
+	CustomFieldNumber  CustomFieldType = "number"
+	CustomFieldEnum    CustomFieldType = "enum"
+	CustomFieldBoolean CustomFieldType = "boolean"
+)
+
+// CustomField is an extra field that appeals of a category fill in,
+// e.g. the pole number of a broken streetlight
+type CustomField struct {
+	// Key names the value in Appeal.CustomFields
+	Key      string          `json:"key" validate:"required,max=50"`
+	Label    string          `json:"label" validate:"required,max=100"`
+	Type     CustomFieldType `json:"type" validate:"required,oneof=text number enum boolean"`
+	Required bool            `json:"required"`
+	// Allowed values of an enum field
+	Options []string `json:"options,omitempty" validate:"dive,required,max=100"`
 }
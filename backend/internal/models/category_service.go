@@ -26,5 +26,7 @@ type CategoryServiceAssignment struct {
 type CategoryWithServices struct {
 	Category *Category        `json:"category"`
 	Services []*CategoryService `json:"services"`
+	// InheritsServices tells that a subcategory has no services of its own and uses the parent's
+	InheritsServices bool `json:"inherits_services"`
 }
 
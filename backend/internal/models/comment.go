@@ -18,7 +18,8 @@ type Comment struct {
 }
 
 type CreateCommentRequest struct {
-	Text       string  `json:"text" validate:"required,min=1"`
-	IsInternal bool    `json:"is_internal"`
-	PhotoIDs   []int64 `json:"photo_ids"`
+	Text       string `json:"text" validate:"required,min=1"`
+	IsInternal bool   `json:"is_internal"`
+	// Staged photos of the author to attach to the comment
+	PhotoIDs []int64 `json:"photo_ids" validate:"max=5,unique,dive,gt=0"`
 }
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// District is a city district with its boundary as GeoJSON
+type District struct {
+	ID        int64           `json:"id" db:"id"`
+	Name      string          `json:"name" db:"name"`
+	Geometry  json.RawMessage `json:"geometry,omitempty" db:"geometry"`
+	MinLat    float64         `json:"min_lat" db:"min_lat"`
+	MinLng    float64         `json:"min_lng" db:"min_lng"`
+	MaxLat    float64         `json:"max_lat" db:"max_lat"`
+	MaxLng    float64         `json:"max_lng" db:"max_lng"`
+	IsActive  bool            `json:"is_active" db:"is_active"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+type CreateDistrictRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=100"`
+	// GeoJSON Polygon, MultiPolygon or a Feature with one of them
+	Geometry json.RawMessage `json:"geometry" validate:"required"`
+}
+
+type UpdateDistrictRequest struct {
+	Name     *string         `json:"name" validate:"omitempty,min=2,max=100"`
+	Geometry json.RawMessage `json:"geometry"`
+	IsActive *bool           `json:"is_active"`
+}
+
+// ImportDistrictsResponse reports the result of a GeoJSON FeatureCollection import
+type ImportDistrictsResponse struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// RoutingRule sends appeals of a category in a district to a service.
+// A rule without a category applies to every category of the district.
+type RoutingRule struct {
+	ID         int64     `json:"id" db:"id"`
+	CategoryID *int64    `json:"category_id" db:"category_id"`
+	DistrictID int64     `json:"district_id" db:"district_id"`
+	ServiceID  int64     `json:"service_id" db:"service_id"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	Category *Category `json:"category,omitempty" db:"-"`
+	District *District `json:"district,omitempty" db:"-"`
+	Service  *Service  `json:"service,omitempty" db:"-"`
+}
+
+type CreateRoutingRuleRequest struct {
+	CategoryID *int64 `json:"category_id"`
+	DistrictID int64  `json:"district_id" validate:"required"`
+	ServiceID  int64  `json:"service_id" validate:"required"`
+}
+
+type UpdateRoutingRuleRequest struct {
+	ServiceID int64 `json:"service_id" validate:"required"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// HotspotTrend compares the newer and the older half of a hotspot's time window
+type HotspotTrend string
+
+const (
+	HotspotRising  HotspotTrend = "rising"
+	HotspotStable  HotspotTrend = "stable"
+	HotspotFalling HotspotTrend = "falling"
+)
+
+// Hotspot is a location with recurring appeals found by density-based clustering
+type Hotspot struct {
+	ID int64 `json:"id" db:"id"`
+	// Scope of the run: category filter (nil for all categories) and time window
+	CategoryID         *int64       `json:"category_id" db:"category_id"`
+	WindowDays         int          `json:"window_days" db:"window_days"`
+	CentroidLat        float64      `json:"centroid_lat" db:"centroid_lat"`
+	CentroidLng        float64      `json:"centroid_lng" db:"centroid_lng"`
+	RadiusMeters       float64      `json:"radius_meters" db:"radius_meters"`
+	AppealCount        int          `json:"appeal_count" db:"appeal_count"`
+	DominantCategoryID *int64       `json:"dominant_category_id" db:"dominant_category_id"`
+	RecentCount        int          `json:"recent_count" db:"recent_count"`
+	PreviousCount      int          `json:"previous_count" db:"previous_count"`
+	Trend              HotspotTrend `json:"trend" db:"trend"`
+	AppealIDs          []int64      `json:"appeal_ids" db:"appeal_ids"`
+	ComputedAt         time.Time    `json:"computed_at" db:"computed_at"`
+
+	// Joined fields
+	Category         *Category `json:"category,omitempty" db:"-"`
+	DominantCategory *Category `json:"dominant_category,omitempty" db:"-"`
+}
+
+// HotspotPoint is an appeal location considered by hotspot detection
+type HotspotPoint struct {
+	AppealID   int64
+	CategoryID *int64
+	Latitude   float64
+	Longitude  float64
+	CreatedAt  time.Time
+}
+
+type RecomputeHotspotsRequest struct {
+	CategoryID *int64 `json:"category_id"`
+	// Defaults to the window from the system settings
+	WindowDays *int `json:"window_days" validate:"omitempty,min=1,max=3650"`
+}
@@ -12,6 +12,10 @@ const (
 	NotificationStatusChanged   NotificationType = "status_changed"
 	NotificationCommentAdded    NotificationType = "comment_added"
 	NotificationAppealCompleted NotificationType = "appeal_completed"
+	NotificationSLABreached     NotificationType = "sla_breached"
+	NotificationAppealReopened  NotificationType = "appeal_reopened"
+	NotificationVolumeSpike     NotificationType = "volume_spike"
+	NotificationSavedViewMatch  NotificationType = "saved_view_match"
 )
 
 type Notification struct {
@@ -14,11 +14,38 @@ type Photo struct {
 	MimeType      string    `json:"mime_type" db:"mime_type"`
 	IsResultPhoto bool      `json:"is_result_photo" db:"is_result_photo"`
 	UploadedAt    time.Time `json:"uploaded_at" db:"uploaded_at"`
+	// Uploader of the photo; a staged photo (no appeal or comment yet) can only be claimed by them
+	UploadedBy *int64 `json:"uploaded_by,omitempty" db:"uploaded_by"`
+	// Dimensions and downscaled variants; unset for photos that were not processed
+	Width      *int    `json:"width,omitempty" db:"width"`
+	Height     *int    `json:"height,omitempty" db:"height"`
+	ThumbPath  *string `json:"thumb_path,omitempty" db:"thumb_path"`
+	MediumPath *string `json:"medium_path,omitempty" db:"medium_path"`
+
+	// Public URLs of the files, filled in by handlers
+	URL       string `json:"url,omitempty" db:"-"`
+	ThumbURL  string `json:"thumb_url,omitempty" db:"-"`
+	MediumURL string `json:"medium_url,omitempty" db:"-"`
+}
+
+// StoredFiles returns the paths of the photo file and its variants
+func (p *Photo) StoredFiles() []string {
+	files := []string{p.FilePath}
+	for _, path := range []*string{p.ThumbPath, p.MediumPath} {
+		if path != nil {
+			files = append(files, *path)
+		}
+	}
+	return files
 }
 
 type UploadPhotoResponse struct {
-	ID       int64  `json:"id"`
-	FileName string `json:"file_name"`
-	FileSize int64  `json:"file_size"`
-	URL      string `json:"url"`
+	ID        int64  `json:"id"`
+	FileName  string `json:"file_name"`
+	FileSize  int64  `json:"file_size"`
+	URL       string `json:"url"`
+	Width     *int   `json:"width,omitempty"`
+	Height    *int   `json:"height,omitempty"`
+	ThumbURL  string `json:"thumb_url,omitempty"`
+	MediumURL string `json:"medium_url,omitempty"`
 }
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+)
+
+// PriorityRuleType selects the condition a priority rule checks
+type PriorityRuleType string
+
+const (
+	// PriorityRuleKeyword fires when the description contains one of the keywords
+	PriorityRuleKeyword PriorityRuleType = "keyword"
+	// PriorityRulePhotoCount fires when the author attached at least MinPhotos photos
+	PriorityRulePhotoCount PriorityRuleType = "photo_count"
+	// PriorityRuleNearbyOpen fires when at least MinAppeals open appeals are within RadiusMeters
+	PriorityRuleNearbyOpen PriorityRuleType = "nearby_open"
+	// PriorityRuleTimeOfDay fires when the appeal is submitted between From and To (HH:MM, may wrap midnight)
+	PriorityRuleTimeOfDay PriorityRuleType = "time_of_day"
+)
+
+// PriorityRuleParams holds the condition parameters; which fields are used depends on the rule type
+type PriorityRuleParams struct {
+	// Keywords match whole words in their inflected forms; a keyword ending in "*" matches any word it begins
+	Keywords     []string `json:"keywords,omitempty"`
+	MinPhotos    int      `json:"min_photos,omitempty"`
+	RadiusMeters float64  `json:"radius_meters,omitempty"`
+	MinAppeals   int      `json:"min_appeals,omitempty"`
+	From         string   `json:"from,omitempty"`
+	To           string   `json:"to,omitempty"`
+}
+
+// PriorityRule raises an appeal's priority when its condition holds.
+// A rule without a category applies to every category.
+type PriorityRule struct {
+	ID         int64              `json:"id" db:"id"`
+	Name       string             `json:"name" db:"name"`
+	Type       PriorityRuleType   `json:"type" db:"rule_type"`
+	Params     PriorityRuleParams `json:"params" db:"params"`
+	Priority   int                `json:"priority" db:"priority"`
+	CategoryID *int64             `json:"category_id" db:"category_id"`
+	IsActive   bool               `json:"is_active" db:"is_active"`
+	CreatedAt  time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	Category *Category `json:"category,omitempty" db:"-"`
+}
+
+type CreatePriorityRuleRequest struct {
+	Name       string             `json:"name" validate:"required,min=3,max=100"`
+	Type       PriorityRuleType   `json:"type" validate:"required,oneof=keyword photo_count nearby_open time_of_day"`
+	Params     PriorityRuleParams `json:"params"`
+	Priority   int                `json:"priority" validate:"required,min=1,max=3"`
+	CategoryID *int64             `json:"category_id"`
+	IsActive   *bool              `json:"is_active"`
+}
+
+type UpdatePriorityRuleRequest struct {
+	Name     *string             `json:"name" validate:"omitempty,min=3,max=100"`
+	Params   *PriorityRuleParams `json:"params"`
+	Priority *int                `json:"priority" validate:"omitempty,min=1,max=3"`
+	IsActive *bool               `json:"is_active"`
+}
+
+// AppealPriorityRule records a rule that fired for an appeal
+type AppealPriorityRule struct {
+	RuleID   *int64           `json:"rule_id" db:"rule_id"`
+	Name     string           `json:"name" db:"rule_name"`
+	Type     PriorityRuleType `json:"type" db:"rule_type"`
+	Priority int              `json:"priority" db:"priority"`
+	FiredAt  time.Time        `json:"fired_at" db:"fired_at"`
+}
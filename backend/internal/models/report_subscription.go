@@ -0,0 +1,88 @@
+package models
+
+import "time"
+
+// ReportType selects which statistics a report subscription sends
+type ReportType string
+
+const (
+	// ReportStatistics is the appeal statistics over the subscription's date range
+	ReportStatistics ReportType = "statistics"
+	// ReportAdminDashboard is the admin dashboard: service speed and trends
+	ReportAdminDashboard ReportType = "admin_dashboard"
+	// ReportServiceStatistics is the statistics of one service
+	ReportServiceStatistics ReportType = "service_statistics"
+)
+
+// ReportDateRange is the period a statistics report covers, relative to the run time
+type ReportDateRange string
+
+const (
+	ReportLastDay       ReportDateRange = "last_day"
+	ReportLast7Days     ReportDateRange = "last_7_days"
+	ReportLast30Days    ReportDateRange = "last_30_days"
+	ReportPreviousMonth ReportDateRange = "previous_month"
+	ReportMonthToDate   ReportDateRange = "month_to_date"
+)
+
+type ReportDeliveryStatus string
+
+const (
+	ReportDeliverySent   ReportDeliveryStatus = "sent"
+	ReportDeliveryFailed ReportDeliveryStatus = "failed"
+)
+
+// ReportSubscription emails a report to its recipients on a cron schedule
+type ReportSubscription struct {
+	ID         int64           `json:"id" db:"id"`
+	Name       string          `json:"name" db:"name"`
+	ReportType ReportType      `json:"report_type" db:"report_type"`
+	ServiceID  *int64          `json:"service_id" db:"service_id"`
+	DateRange  ReportDateRange `json:"date_range" db:"date_range"`
+	Recipients []string        `json:"recipients" db:"recipients"`
+	// Schedule is a 5-field cron expression in server local time
+	Schedule       string     `json:"schedule" db:"schedule"`
+	IsActive       bool       `json:"is_active" db:"is_active"`
+	NextRunAt      *time.Time `json:"next_run_at" db:"next_run_at"`
+	LastRunAt      *time.Time `json:"last_run_at" db:"last_run_at"`
+	FailedAttempts int        `json:"failed_attempts" db:"failed_attempts"`
+	CreatedBy      *int64     `json:"created_by" db:"created_by"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	Service *Service `json:"service,omitempty" db:"-"`
+}
+
+type CreateReportSubscriptionRequest struct {
+	Name       string          `json:"name" validate:"required,min=3,max=200"`
+	ReportType ReportType      `json:"report_type" validate:"required,oneof=statistics admin_dashboard service_statistics"`
+	ServiceID  *int64          `json:"service_id"`
+	DateRange  ReportDateRange `json:"date_range" validate:"omitempty,oneof=last_day last_7_days last_30_days previous_month month_to_date"`
+	Recipients []string        `json:"recipients" validate:"required,min=1,max=50,dive,email"`
+	Schedule   string          `json:"schedule" validate:"required,max=100"`
+	IsActive   *bool           `json:"is_active"`
+}
+
+type UpdateReportSubscriptionRequest struct {
+	Name       *string          `json:"name" validate:"omitempty,min=3,max=200"`
+	ServiceID  *int64           `json:"service_id"`
+	DateRange  *ReportDateRange `json:"date_range" validate:"omitempty,oneof=last_day last_7_days last_30_days previous_month month_to_date"`
+	Recipients []string         `json:"recipients" validate:"omitempty,min=1,max=50,dive,email"`
+	Schedule   *string          `json:"schedule" validate:"omitempty,max=100"`
+	IsActive   *bool            `json:"is_active"`
+}
+
+// ReportDelivery records one attempt to email a report
+type ReportDelivery struct {
+	ID             int64                `json:"id" db:"id"`
+	SubscriptionID int64                `json:"subscription_id" db:"subscription_id"`
+	Status         ReportDeliveryStatus `json:"status" db:"status"`
+	// Attempt is 1 for the first try of a run and grows with every retry
+	Attempt    int        `json:"attempt" db:"attempt"`
+	Recipients []string   `json:"recipients" db:"recipients"`
+	PeriodFrom *time.Time `json:"period_from" db:"period_from"`
+	PeriodTo   *time.Time `json:"period_to" db:"period_to"`
+	Error      *string    `json:"error" db:"error"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
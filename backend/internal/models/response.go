@@ -14,6 +14,17 @@ type PaginatedResponse struct {
 	Page       int         `json:"page"`
 	Limit      int         `json:"limit"`
 	TotalPages int         `json:"total_pages"`
+
+	// Keyset cursors of the neighbouring pages, passed back as ?cursor=.
+	// Page, Total and TotalPages are 0 when the list was requested by cursor.
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// PageCursors are the keyset cursors of the pages after and before a page, empty at the ends
+type PageCursors struct {
+	Next string
+	Prev string
 }
 
 // ErrorResponse represents an error
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// SavedView is a named appeal list filter with its sort order. It is private to its
+// owner unless shared with everyone of a role or with the members of a service.
+type SavedView struct {
+	ID     int64  `json:"id" db:"id"`
+	UserID int64  `json:"user_id" db:"user_id"`
+	Name   string `json:"name" db:"name"`
+	// Filters never carry pagination or sorting; those are kept in SortBy and SortOrder
+	Filters         AppealFilters `json:"filters" db:"filters"`
+	SortBy          string        `json:"sort_by" db:"sort_by"`
+	SortOrder       string        `json:"sort_order" db:"sort_order"`
+	SharedRole      *UserRole     `json:"shared_role" db:"shared_role"`
+	SharedServiceID *int64        `json:"shared_service_id" db:"shared_service_id"`
+	CreatedAt       time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	Owner         *User    `json:"owner,omitempty" db:"-"`
+	SharedService *Service `json:"shared_service,omitempty" db:"-"`
+
+	// Computed for the user the view is listed for: how many appeals match it now
+	// and whether the user is notified about new matching appeals
+	Count      *int64 `json:"count,omitempty" db:"-"`
+	Subscribed bool   `json:"subscribed" db:"-"`
+}
+
+// ListFilters returns the view's filters with its sort order, ready for a list or an export
+func (v *SavedView) ListFilters() *AppealFilters {
+	filters := v.Filters
+	filters.SortBy = v.SortBy
+	filters.SortOrder = v.SortOrder
+	return &filters
+}
+
+// SavedViewRequest creates a view or replaces all of its settings
+type SavedViewRequest struct {
+	Name            string        `json:"name" validate:"required,min=1,max=200"`
+	Filters         AppealFilters `json:"filters"`
+	SortBy          string        `json:"sort_by" validate:"omitempty,oneof=created_at status priority distance relevance"`
+	SortOrder       string        `json:"sort_order" validate:"omitempty,oneof=asc desc"`
+	SharedRole      *UserRole     `json:"shared_role" validate:"omitempty,oneof=dispatcher executor admin"`
+	SharedServiceID *int64        `json:"shared_service_id"`
+}
+
+// SavedViewSubscription is a user to notify when a new appeal matches the view
+type SavedViewSubscription struct {
+	View   *SavedView
+	UserID int64
+}
@@ -4,35 +4,59 @@ import (
 	"time"
 )
 
+// AssignmentStrategy selects how a service hands new appeals to its executors
+type AssignmentStrategy string
+
+const (
+	AssignmentManual     AssignmentStrategy = "manual"
+	AssignmentRoundRobin AssignmentStrategy = "round_robin"
+	AssignmentLeastOpen  AssignmentStrategy = "least_open"
+	AssignmentNearest    AssignmentStrategy = "nearest"
+)
+
 type Service struct {
-	ID            int64     `json:"id" db:"id"`
-	Name          string    `json:"name" db:"name"`
-	Description   string    `json:"description" db:"description"`
-	ContactPerson string    `json:"contact_person" db:"contact_person"`
-	ContactPhone  string    `json:"contact_phone" db:"contact_phone"`
-	ContactEmail  string    `json:"contact_email" db:"contact_email"`
-	IsActive      bool      `json:"is_active" db:"is_active"`
-	CreatedAt     time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	ID            int64  `json:"id" db:"id"`
+	Name          string `json:"name" db:"name"`
+	Description   string `json:"description" db:"description"`
+	ContactPerson string `json:"contact_person" db:"contact_person"`
+	ContactPhone  string `json:"contact_phone" db:"contact_phone"`
+	ContactEmail  string `json:"contact_email" db:"contact_email"`
+	IsActive      bool   `json:"is_active" db:"is_active"`
+	// AssignmentStrategy is applied when an appeal is routed to the service
+	AssignmentStrategy AssignmentStrategy `json:"assignment_strategy" db:"assignment_strategy"`
+	CreatedAt          time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at" db:"updated_at"`
 	// Keywords are stored in MongoDB, not PostgreSQL
 	Keywords string `json:"keywords,omitempty" db:"-"`
 }
 
 type CreateServiceRequest struct {
-	Name          string `json:"name" validate:"required,min=3,max=200"`
-	Description   string `json:"description"`
-	Keywords      string `json:"keywords"` // Keywords are stored in MongoDB
-	ContactPerson string `json:"contact_person" validate:"required"`
-	ContactPhone  string `json:"contact_phone" validate:"required"`
-	ContactEmail  string `json:"contact_email" validate:"required,email"`
+	Name               string              `json:"name" validate:"required,min=3,max=200"`
+	Description        string              `json:"description"`
+	Keywords           string              `json:"keywords"` // Keywords are stored in MongoDB
+	ContactPerson      string              `json:"contact_person" validate:"required"`
+	ContactPhone       string              `json:"contact_phone" validate:"required"`
+	ContactEmail       string              `json:"contact_email" validate:"required,email"`
+	AssignmentStrategy *AssignmentStrategy `json:"assignment_strategy" validate:"omitempty,oneof=manual round_robin least_open nearest"`
 }
 
 type UpdateServiceRequest struct {
-	Name          *string `json:"name" validate:"omitempty,min=3,max=200"`
-	Description   *string `json:"description"`
-	Keywords      *string `json:"keywords"` // Keywords are stored in MongoDB
-	ContactPerson *string `json:"contact_person"`
-	ContactPhone  *string `json:"contact_phone"`
-	ContactEmail  *string `json:"contact_email" validate:"omitempty,email"`
-	IsActive      *bool   `json:"is_active"`
+	Name               *string             `json:"name" validate:"omitempty,min=3,max=200"`
+	Description        *string             `json:"description"`
+	Keywords           *string             `json:"keywords"` // Keywords are stored in MongoDB
+	ContactPerson      *string             `json:"contact_person"`
+	ContactPhone       *string             `json:"contact_phone"`
+	ContactEmail       *string             `json:"contact_email" validate:"omitempty,email"`
+	IsActive           *bool               `json:"is_active"`
+	AssignmentStrategy *AssignmentStrategy `json:"assignment_strategy" validate:"omitempty,oneof=manual round_robin least_open nearest"`
+}
+
+// ExecutorWorkload describes an executor as a candidate for automatic assignment
+type ExecutorWorkload struct {
+	UserID      int64    `json:"user_id"`
+	FirstName   string   `json:"first_name"`
+	LastName    string   `json:"last_name"`
+	OpenAppeals int      `json:"open_appeals"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
 }
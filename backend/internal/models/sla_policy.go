@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+// SLABreachKind tells which SLA target an appeal missed
+type SLABreachKind string
+
+const (
+	// SLABreachResponse means the appeal was not taken into work (left 'new') in time
+	SLABreachResponse SLABreachKind = "response"
+	// SLABreachResolution means the appeal was not completed in time
+	SLABreachResolution SLABreachKind = "resolution"
+)
+
+// SLAPolicy defines response and resolution targets for a category and priority.
+// A policy without a category is the default for its priority.
+type SLAPolicy struct {
+	ID              int64     `json:"id" db:"id"`
+	CategoryID      *int64    `json:"category_id" db:"category_id"`
+	Priority        int       `json:"priority" db:"priority"`
+	ResponseHours   int       `json:"response_hours" db:"response_hours"`
+	ResolutionHours int       `json:"resolution_hours" db:"resolution_hours"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+
+	// Joined fields
+	Category *Category `json:"category,omitempty" db:"-"`
+}
+
+type CreateSLAPolicyRequest struct {
+	CategoryID      *int64 `json:"category_id"`
+	Priority        int    `json:"priority" validate:"required,min=1,max=3"`
+	ResponseHours   int    `json:"response_hours" validate:"required,min=1"`
+	ResolutionHours int    `json:"resolution_hours" validate:"required,min=1"`
+}
+
+type UpdateSLAPolicyRequest struct {
+	ResponseHours   *int `json:"response_hours" validate:"omitempty,min=1"`
+	ResolutionHours *int `json:"resolution_hours" validate:"omitempty,min=1"`
+}
+
+// SLABreach is an open appeal that missed one of its SLA deadlines
+type SLABreach struct {
+	Appeal *Appeal       `json:"appeal"`
+	Kind   SLABreachKind `json:"kind"`
+}
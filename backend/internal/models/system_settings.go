@@ -1,5 +1,19 @@
 package models
 
+// Defaults for duplicate detection settings
+const (
+	DefaultDuplicateRadiusMeters        = 100.0
+	DefaultDuplicateSimilarityThreshold = 0.3
+	DefaultConfirmationWindowDays       = 14
+	DefaultHotspotRadiusMeters          = 150.0
+	DefaultHotspotMinAppeals            = 5
+	DefaultHotspotWindowDays            = 365
+	DefaultSpikeWindowMinutes           = 60
+	DefaultSpikeBaselineDays            = 14
+	DefaultSpikeThreshold               = 3.0
+	DefaultSpikeMinAppeals              = 10
+)
+
 // SystemSettings represents configurable system-wide settings
 // that are stored in a JSON file (e.g. map center and zoom).
 type SystemSettings struct {
@@ -8,4 +22,25 @@ type SystemSettings struct {
 	MapCenterLng        float64 `json:"map_center_lng"`
 	MapZoom             int     `json:"map_zoom"`
 	ConfidenceThreshold float64 `json:"confidence_threshold"`
+
+	// Duplicate detection: search radius around a new appeal and minimal description similarity (0..1)
+	DuplicateRadiusMeters        float64 `json:"duplicate_radius_meters"`
+	DuplicateSimilarityThreshold float64 `json:"duplicate_similarity_threshold"`
+
+	// Days the author has to confirm or reopen a completed appeal before it is closed automatically
+	ConfirmationWindowDays int `json:"confirmation_window_days"`
+
+	// Hotspot detection: appeals within the radius of each other form a hotspot once there
+	// are at least HotspotMinAppeals of them in the last HotspotWindowDays
+	HotspotRadiusMeters float64 `json:"hotspot_radius_meters"`
+	HotspotMinAppeals   int     `json:"hotspot_min_appeals"`
+	HotspotWindowDays   int     `json:"hotspot_window_days"`
+
+	// Volume spike alerts: an alert is raised when a category (or a category in a district)
+	// gets at least SpikeMinAppeals appeals in the last SpikeWindowMinutes and SpikeThreshold
+	// times more than usual, the usual rate being the average over the previous SpikeBaselineDays
+	SpikeWindowMinutes int     `json:"spike_window_minutes"`
+	SpikeBaselineDays  int     `json:"spike_baseline_days"`
+	SpikeThreshold     float64 `json:"spike_threshold"`
+	SpikeMinAppeals    int     `json:"spike_min_appeals"`
 }
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+)
+
+// Tag labels appeals across categories and services, e.g. "зима" or "запит депутата".
+// Curated tags are maintained by admins; free-form ones are created by staff while tagging.
+type Tag struct {
+	ID        int64     `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Color     *string   `json:"color" db:"color"`
+	IsCurated bool      `json:"is_curated" db:"is_curated"`
+	CreatedBy *int64    `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// Number of appeals with the tag (filled for the tag list only)
+	AppealsCount int64 `json:"appeals_count" db:"-"`
+}
+
+type CreateTagRequest struct {
+	Name  string  `json:"name" validate:"required,min=1,max=50"`
+	Color *string `json:"color" validate:"omitempty,hexcolor,len=7"`
+}
+
+type UpdateTagRequest struct {
+	Name      *string `json:"name" validate:"omitempty,min=1,max=50"`
+	Color     *string `json:"color" validate:"omitempty,hexcolor,len=7"`
+	IsCurated *bool   `json:"is_curated"`
+}
+
+// BulkTagRequest adds or removes tags on several appeals at once. Tags are given by ID
+// or by name; when tagging, unknown names become new free-form tags.
+type BulkTagRequest struct {
+	AppealIDs []int64  `json:"appeal_ids" validate:"required,min=1,max=500"`
+	TagIDs    []int64  `json:"tag_ids"`
+	TagNames  []string `json:"tag_names" validate:"dive,min=1,max=50"`
+}
+
+// BulkTagResult reports how many appeal–tag pairs a bulk request changed
+type BulkTagResult struct {
+	Changed int64 `json:"changed"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+type VolumeAlertStatus string
+
+const (
+	VolumeAlertOpen      VolumeAlertStatus = "open"
+	VolumeAlertGrouped   VolumeAlertStatus = "grouped"
+	VolumeAlertDismissed VolumeAlertStatus = "dismissed"
+)
+
+// VolumeAlert is raised when a category, optionally within one district, gets far more
+// appeals than its rolling baseline
+type VolumeAlert struct {
+	ID         int64  `json:"id" db:"id"`
+	CategoryID int64  `json:"category_id" db:"category_id"`
+	DistrictID *int64 `json:"district_id" db:"district_id"`
+	// The recent window in which the spike was seen
+	WindowStart time.Time `json:"window_start" db:"window_start"`
+	WindowEnd   time.Time `json:"window_end" db:"window_end"`
+	RecentCount int       `json:"recent_count" db:"recent_count"`
+	// Appeals usually received in a window of the same length
+	BaselineCount    float64           `json:"baseline_count" db:"baseline_count"`
+	AppealIDs        []int64           `json:"appeal_ids" db:"appeal_ids"`
+	Status           VolumeAlertStatus `json:"status" db:"status"`
+	IncidentAppealID *int64            `json:"incident_appeal_id" db:"incident_appeal_id"`
+	ResolvedBy       *int64            `json:"resolved_by" db:"resolved_by"`
+	ResolvedAt       *time.Time        `json:"resolved_at" db:"resolved_at"`
+	CreatedAt        time.Time         `json:"created_at" db:"created_at"`
+
+	// Joined fields
+	Category *Category `json:"category,omitempty" db:"-"`
+	District *District `json:"district,omitempty" db:"-"`
+}
+
+// VolumeCount is the number of appeals of a category in a district over some period;
+// DistrictID is nil for appeals outside every district
+type VolumeCount struct {
+	CategoryID int64
+	DistrictID *int64
+	Count      int
+}
+
+type GroupVolumeAlertRequest struct {
+	// Defaults to the earliest appeal of the alert that can still be merged into
+	MasterAppealID *int64 `json:"master_appeal_id"`
+}
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
 	"citizen-appeals/internal/models"
+	"citizen-appeals/pkg/geo"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -16,8 +18,63 @@ import (
 
 var (
 	ErrAppealNotFound = errors.New("appeal not found")
+	// ErrAppealClaimed is returned when an executor claims an appeal another executor owns
+	ErrAppealClaimed = errors.New("appeal is claimed by another executor")
+	// ErrStatusChanged is returned when the status of an appeal changed after the caller read it
+	ErrStatusChanged = errors.New("appeal status changed meanwhile")
+	// ErrMergeConflict is returned when a merge would nest appeals: the master is merged
+	// itself, or a child is merged elsewhere or has linked appeals of its own
+	ErrMergeConflict = errors.New("appeals cannot be merged")
 )
 
+// slaBreachCondition matches open appeals that missed their response deadline
+// (still 'new') or their resolution deadline (not completed yet).
+const slaBreachCondition = `COALESCE(
+	(a.status = 'new' AND a.response_due_at < NOW())
+	OR (a.status NOT IN ('completed', 'closed', 'rejected') AND a.due_at < NOW()),
+	false)`
+
+// statusTimestampsSet keeps lifecycle timestamps in line with a status change to $1:
+// completed_at marks the executor's result, closed_at the final closure.
+// Going back to work clears completed_at; a reopen is counted.
+const statusTimestampsSet = `
+	closed_at = CASE WHEN $1 = 'closed' THEN NOW() ELSE closed_at END,
+	completed_at = CASE
+		WHEN $1 = 'completed' THEN NOW()
+		WHEN $1 = 'closed' THEN COALESCE(completed_at, NOW())
+		WHEN $1 = 'rejected' THEN completed_at
+		ELSE NULL
+	END,
+	reopen_count = reopen_count + CASE WHEN $1 = 'reopened' THEN 1 ELSE 0 END`
+
+// slaDeadlinesSet recomputes the SLA deadlines of an appeal for a new priority, given as
+// an SQL expression, the way they are set on creation: from created_at, by the category's
+// own policy or else the default one, none without a policy. Columns of the appeal must
+// be qualified in the expression, since sla_policies has a priority column too.
+func slaDeadlinesSet(priority string) string {
+	policy := func(hours string) string {
+		return fmt.Sprintf(`(
+		SELECT appeals.created_at + make_interval(hours => p.%s)
+		FROM sla_policies p
+		WHERE p.priority = %s AND (p.category_id = appeals.category_id OR p.category_id IS NULL)
+		ORDER BY p.category_id NULLS LAST
+		LIMIT 1
+	)`, hours, priority)
+	}
+	return fmt.Sprintf("response_due_at = %s,\n\tdue_at = %s", policy("response_hours"), policy("resolution_hours"))
+}
+
+// appealStatusLabels are Ukrainian status names used in history entries
+var appealStatusLabels = map[models.AppealStatus]string{
+	models.StatusNew:        "Нове",
+	models.StatusAssigned:   "Призначене",
+	models.StatusInProgress: "В роботі",
+	models.StatusCompleted:  "Виконане",
+	models.StatusClosed:     "Закрите",
+	models.StatusRejected:   "Відхилене",
+	models.StatusReopened:   "Повторно відкрите",
+}
+
 type AppealRepository struct {
 	db *pgxpool.Pool
 }
@@ -34,18 +91,25 @@ func getStringValue(s *string) string {
 	return *s
 }
 
-// Create creates a new appeal
-func (r *AppealRepository) Create(ctx context.Context, appeal *models.Appeal) error {
+// Create creates a new appeal and attaches the author's staged photos to it in one transaction
+func (r *AppealRepository) Create(ctx context.Context, appeal *models.Appeal, photoIDs []int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 		INSERT INTO appeals (
 			user_id, category_id, title, description, address,
-			latitude, longitude, priority, status
+			latitude, longitude, priority, status,
+			response_due_at, due_at, duplicate_of_id, duplicate_score, district_id, custom_fields
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, COALESCE($15, '{}'::JSONB))
 		RETURNING id, created_at, updated_at
 	`
 
-	err := r.db.QueryRow(
+	err = tx.QueryRow(
 		ctx,
 		query,
 		appeal.UserID,
@@ -57,13 +121,23 @@ func (r *AppealRepository) Create(ctx context.Context, appeal *models.Appeal) er
 		appeal.Longitude,
 		appeal.Priority,
 		appeal.Status,
+		appeal.ResponseDueAt,
+		appeal.DueAt,
+		appeal.DuplicateOfID,
+		appeal.DuplicateScore,
+		appeal.DistrictID,
+		appeal.CustomFields,
 	).Scan(&appeal.ID, &appeal.CreatedAt, &appeal.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to create appeal: %w", err)
 	}
 
-	return nil
+	if err := claimStagedPhotos(ctx, tx, photoIDs, appeal.UserID, appeal.ID, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 // GetByID retrieves an appeal by ID with all related data
@@ -72,14 +146,20 @@ func (r *AppealRepository) GetByID(ctx context.Context, id int64) (*models.Appea
 		SELECT
 			a.id, a.user_id, a.category_id, a.service_id,
 			a.status, a.title, a.description, a.address, a.latitude, a.longitude,
-			a.priority, a.created_at, a.updated_at, a.closed_at,
+			a.priority, a.created_at, a.updated_at, a.closed_at, a.completed_at, a.reopen_count,
+			a.response_due_at, a.due_at, a.duplicate_of_id, a.duplicate_score, a.parent_id, a.custom_fields,
+			(SELECT COUNT(*) FROM appeal_supporters sp WHERE sp.appeal_id = a.id),
 			u.id, u.email, u.first_name, u.last_name, u.phone, u.role,
-			c.id, c.name, c.description,
-			s.id, s.name, s.description
+			c.id, c.name, c.description, c.custom_fields,
+			s.id, s.name, s.description,
+			e.id, e.first_name, e.last_name, e.phone,
+			d.id, d.name
 		FROM appeals a
 		LEFT JOIN users u ON a.user_id = u.id
 		LEFT JOIN categories c ON a.category_id = c.id
 		LEFT JOIN services s ON a.service_id = s.id
+		LEFT JOIN users e ON a.assignee_id = e.id
+		LEFT JOIN districts d ON a.district_id = d.id
 		WHERE a.id = $1
 	`
 
@@ -88,18 +168,27 @@ func (r *AppealRepository) GetByID(ctx context.Context, id int64) (*models.Appea
 
 	var categoryID, serviceID *int64
 	var categoryName, categoryDesc, serviceName, serviceDesc *string
+	var categoryFields []models.CustomField
 
 	// Use nullable types for JOIN fields that can be NULL (category, service)
 	var categoryIDVal, serviceIDVal *int64
+	var assigneeID *int64
+	var assigneeFirstName, assigneeLastName, assigneePhone *string
+	var districtID *int64
+	var districtName *string
 
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&appeal.ID, &appeal.UserID, &categoryID, &serviceID,
 		&appeal.Status, &appeal.Title, &appeal.Description, &appeal.Address,
 		&appeal.Latitude, &appeal.Longitude, &appeal.Priority,
-		&appeal.CreatedAt, &appeal.UpdatedAt, &appeal.ClosedAt,
+		&appeal.CreatedAt, &appeal.UpdatedAt, &appeal.ClosedAt, &appeal.CompletedAt, &appeal.ReopenCount,
+		&appeal.ResponseDueAt, &appeal.DueAt, &appeal.DuplicateOfID, &appeal.DuplicateScore, &appeal.ParentID, &appeal.CustomFields,
+		&appeal.SupportersCount,
 		&user.ID, &user.Email, &user.FirstName, &user.LastName, &user.Phone, &user.Role,
-		&categoryIDVal, &categoryName, &categoryDesc,
+		&categoryIDVal, &categoryName, &categoryDesc, &categoryFields,
 		&serviceIDVal, &serviceName, &serviceDesc,
+		&assigneeID, &assigneeFirstName, &assigneeLastName, &assigneePhone,
+		&districtID, &districtName,
 	)
 
 	if err != nil {
@@ -112,6 +201,8 @@ func (r *AppealRepository) GetByID(ctx context.Context, id int64) (*models.Appea
 	// Set appeal IDs
 	appeal.CategoryID = categoryID
 	appeal.ServiceID = serviceID
+	appeal.AssigneeID = assigneeID
+	appeal.DistrictID = districtID
 
 	// Set user (should always exist since user_id is NOT NULL)
 	appeal.User = &user
@@ -124,6 +215,7 @@ func (r *AppealRepository) GetByID(ctx context.Context, id int64) (*models.Appea
 		if categoryDesc != nil {
 			category.Description = *categoryDesc
 		}
+		category.CustomFields = categoryFields
 		appeal.Category = &category
 	}
 
@@ -138,11 +230,103 @@ func (r *AppealRepository) GetByID(ctx context.Context, id int64) (*models.Appea
 		appeal.Service = &service
 	}
 
+	// Set assigned executor if exists
+	if assigneeID != nil {
+		appeal.Assignee = &models.User{
+			ID:        *assigneeID,
+			FirstName: getStringValue(assigneeFirstName),
+			LastName:  getStringValue(assigneeLastName),
+			Phone:     getStringValue(assigneePhone),
+			Role:      models.RoleExecutor,
+		}
+	}
+
+	// Set district if exists
+	if districtID != nil && districtName != nil {
+		appeal.District = &models.District{ID: *districtID, Name: *districtName}
+	}
+
+	if err := r.attachTags(ctx, &appeal); err != nil {
+		return nil, err
+	}
+
 	return &appeal, nil
 }
 
-// List retrieves appeals with filters and pagination
-func (r *AppealRepository) List(ctx context.Context, filters *models.AppealFilters) ([]*models.Appeal, int64, error) {
+// appealSortKeys returns the order of the filters' sort_by and sort_order.
+// Every order ends with the id, so rows never tie and cursors stay stable.
+func appealSortKeys(filters *models.AppealFilters, columns appealFilterColumns) []keysetKey {
+	createdAt := keysetKey{Column: "a.created_at", Desc: true, Kind: keysetTime}
+	id := keysetKey{Column: "a.id", Desc: true, Kind: keysetInt}
+
+	// A search is ordered by relevance unless another order is asked for
+	if appealSearchText(filters) != "" && (filters.SortBy == "" || filters.SortBy == "relevance") {
+		rank := keysetKey{Column: "search_rank", Expr: columns.SearchRank, Desc: true, Kind: keysetFloat}
+		return []keysetKey{rank, createdAt, id}
+	}
+
+	desc := filters.SortOrder != "asc"
+	switch filters.SortBy {
+	case "priority":
+		return []keysetKey{{Column: "a.priority", Desc: desc, Kind: keysetInt}, createdAt, id}
+	case "created_at":
+		createdAt.Desc, id.Desc = desc, desc
+	case "status":
+		return []keysetKey{{Column: "a.status", Desc: desc, Kind: keysetText}, createdAt, id}
+	case "distance":
+		// Nearest first unless asked otherwise; meaningless without a point
+		if filters.Near != nil {
+			distance := keysetKey{Column: "distance", Expr: columns.Distance, Desc: filters.SortOrder == "desc", Kind: keysetFloat}
+			return []keysetKey{distance, createdAt, id}
+		}
+	}
+	return []keysetKey{createdAt, id}
+}
+
+// appealSortValues returns the values of keys for an appeal read by List
+func appealSortValues(keys []keysetKey, appeal *models.Appeal) []interface{} {
+	values := make([]interface{}, len(keys))
+	for i, k := range keys {
+		switch k.Column {
+		case "search_rank":
+			values[i] = appeal.SearchMatch.Rank
+		case "a.priority":
+			values[i] = appeal.Priority
+		case "a.created_at":
+			values[i] = appeal.CreatedAt
+		case "a.status":
+			values[i] = appeal.Status
+		case "distance":
+			values[i] = *appeal.DistanceMeters
+		case "a.id":
+			values[i] = appeal.ID
+		}
+	}
+	return values
+}
+
+// appealSearchText returns the full-text search of the filters, "" without one
+func appealSearchText(filters *models.AppealFilters) string {
+	if filters.Search == nil {
+		return ""
+	}
+	return strings.TrimSpace(*filters.Search)
+}
+
+// appealFilterColumns are SQL expressions of values the filters compute per appeal.
+// Each is NULL when its filter is not set.
+type appealFilterColumns struct {
+	// Distance to the "near" point, in meters
+	Distance string
+	// Relevance to the full-text search; public comments add half of their best rank
+	SearchRank string
+	// Highlighted title, description and best matching public comment
+	SearchTitle, SearchSnippet, SearchComment string
+}
+
+// appealFilterClause builds the WHERE clause (over appeals aliased as "a") for the filters
+// and the columns the filters compute.
+func appealFilterClause(filters *models.AppealFilters) (string, []interface{}, appealFilterColumns) {
 	whereConditions := []string{"1=1"}
 	args := []interface{}{}
 	argCount := 1
@@ -154,8 +338,10 @@ func (r *AppealRepository) List(ctx context.Context, filters *models.AppealFilte
 		argCount++
 	}
 
+	// A category covers its subcategories
 	if filters.CategoryID != nil {
-		whereConditions = append(whereConditions, fmt.Sprintf("a.category_id = $%d", argCount))
+		whereConditions = append(whereConditions, fmt.Sprintf(
+			"a.category_id IN (SELECT sc.id FROM categories sc WHERE sc.id = $%d OR sc.parent_id = $%d)", argCount, argCount))
 		args = append(args, *filters.CategoryID)
 		argCount++
 	}
@@ -172,6 +358,26 @@ func (r *AppealRepository) List(ctx context.Context, filters *models.AppealFilte
 		argCount++
 	}
 
+	if filters.AssigneeID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.assignee_id = $%d", argCount))
+		args = append(args, *filters.AssigneeID)
+		argCount++
+	}
+
+	if filters.DistrictID != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.district_id = $%d", argCount))
+		args = append(args, *filters.DistrictID)
+		argCount++
+	}
+
+	if filters.Unclaimed != nil {
+		if *filters.Unclaimed {
+			whereConditions = append(whereConditions, "a.assignee_id IS NULL")
+		} else {
+			whereConditions = append(whereConditions, "a.assignee_id IS NOT NULL")
+		}
+	}
+
 	if filters.FromDate != nil {
 		whereConditions = append(whereConditions, fmt.Sprintf("a.created_at >= $%d", argCount))
 		args = append(args, *filters.FromDate)
@@ -184,41 +390,134 @@ func (r *AppealRepository) List(ctx context.Context, filters *models.AppealFilte
 		argCount++
 	}
 
-	if filters.Search != nil && *filters.Search != "" {
-		whereConditions = append(whereConditions, fmt.Sprintf("(a.title ILIKE $%d OR a.description ILIKE $%d)", argCount, argCount))
-		searchTerm := "%" + *filters.Search + "%"
-		args = append(args, searchTerm)
+	// Custom field values compare as text, so "12", 12 and "true", true match alike
+	for _, key := range sortedKeys(filters.CustomFields) {
+		whereConditions = append(whereConditions, fmt.Sprintf("a.custom_fields ->> $%d = $%d", argCount, argCount+1))
+		args = append(args, key, filters.CustomFields[key])
+		argCount += 2
+	}
+
+	if len(filters.TagsAny) > 0 {
+		whereConditions = append(whereConditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM appeal_tags at WHERE at.appeal_id = a.id AND at.tag_id = ANY($%d))", argCount))
+		args = append(args, filters.TagsAny)
 		argCount++
 	}
 
-	whereClause := strings.Join(whereConditions, " AND ")
+	if len(filters.TagsAll) > 0 {
+		whereConditions = append(whereConditions, fmt.Sprintf(
+			"(SELECT COUNT(DISTINCT at.tag_id) FROM appeal_tags at WHERE at.appeal_id = a.id AND at.tag_id = ANY($%d)) = $%d",
+			argCount, argCount+1))
+		args = append(args, filters.TagsAll, distinctCount(filters.TagsAll))
+		argCount += 2
+	}
 
-	// Count total
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM appeals a WHERE %s", whereClause)
-	var total int64
-	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count appeals: %w", err)
+	columns := appealFilterColumns{
+		Distance:      "NULL::DOUBLE PRECISION",
+		SearchRank:    "NULL::DOUBLE PRECISION",
+		SearchTitle:   "NULL::TEXT",
+		SearchSnippet: "NULL::TEXT",
+		SearchComment: "NULL::TEXT",
+	}
+
+	// Full-text search; the stemming functions come from migration 016
+	if search := appealSearchText(filters); search != "" {
+		query := fmt.Sprintf("ukrainian_tsquery($%d)", argCount)
+		condition := "a.search_vector @@ " + query
+		columns.SearchRank = fmt.Sprintf("ts_rank(a.search_vector, %s)::DOUBLE PRECISION", query)
+
+		if filters.SearchComments {
+			publicComments := "FROM comments cm WHERE cm.appeal_id = a.id AND NOT cm.is_internal AND cm.search_vector @@ " + query
+			condition = fmt.Sprintf("(%s OR EXISTS (SELECT 1 %s))", condition, publicComments)
+			columns.SearchRank = fmt.Sprintf("(%s + COALESCE((SELECT MAX(ts_rank(cm.search_vector, %s)) %s), 0) / 2)::DOUBLE PRECISION",
+				columns.SearchRank, query, publicComments)
+			columns.SearchComment = fmt.Sprintf("(SELECT ukrainian_headline(cm.text, $%d, 'MaxWords=30, MinWords=10') %s ORDER BY ts_rank(cm.search_vector, %s) DESC LIMIT 1)",
+				argCount, publicComments, query)
+		}
+
+		columns.SearchTitle = fmt.Sprintf("ukrainian_headline(a.title, $%d, 'HighlightAll=true')", argCount)
+		columns.SearchSnippet = fmt.Sprintf("ukrainian_headline(a.description, $%d, 'MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=\" … \"')", argCount)
+
+		whereConditions = append(whereConditions, condition)
+		args = append(args, search)
+		argCount++
 	}
 
-	// Build ORDER BY clause
-	orderBy := "a.created_at DESC"
-	if filters.SortBy != "" {
-		order := "DESC"
-		if filters.SortOrder == "asc" {
-			order = "ASC"
+	if filters.Overdue != nil {
+		if *filters.Overdue {
+			whereConditions = append(whereConditions, slaBreachCondition)
+		} else {
+			whereConditions = append(whereConditions, "NOT "+slaBreachCondition)
 		}
-		switch filters.SortBy {
-		case "priority":
-			orderBy = fmt.Sprintf("a.priority %s, a.created_at DESC", order)
-		case "created_at":
-			orderBy = fmt.Sprintf("a.created_at %s", order)
-		case "status":
-			orderBy = fmt.Sprintf("a.status %s, a.created_at DESC", order)
+	}
+
+	// Spatial filters: a latitude/longitude range lets idx_appeals_location do the
+	// work, the exact distance is only computed for rows inside the range
+	if filters.BBox != nil {
+		whereConditions = append(whereConditions, fmt.Sprintf(
+			"a.latitude BETWEEN $%d AND $%d AND a.longitude BETWEEN $%d AND $%d",
+			argCount, argCount+1, argCount+2, argCount+3,
+		))
+		args = append(args, filters.BBox.MinLat, filters.BBox.MaxLat, filters.BBox.MinLng, filters.BBox.MaxLng)
+		argCount += 4
+	}
+
+	if filters.Near != nil {
+		box := geo.BoundingBoxAround(*filters.Near, filters.RadiusMeters)
+		whereConditions = append(whereConditions, fmt.Sprintf(
+			"a.latitude BETWEEN $%d AND $%d AND a.longitude BETWEEN $%d AND $%d",
+			argCount, argCount+1, argCount+2, argCount+3,
+		))
+		args = append(args, box.MinLat, box.MaxLat, box.MinLng, box.MaxLng)
+		argCount += 4
+
+		columns.Distance = distanceSQL(argCount, argCount+1)
+		whereConditions = append(whereConditions, fmt.Sprintf("%s <= $%d", columns.Distance, argCount+2))
+		args = append(args, filters.Near.Lat, filters.Near.Lng, filters.RadiusMeters)
+	}
+
+	return strings.Join(whereConditions, " AND "), args, columns
+}
+
+// sortedKeys returns the keys of m in order, so the same filters build the same query
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// distinctCount returns how many different IDs there are
+func distinctCount(ids []int64) int64 {
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		seen[id] = true
+	}
+	return int64(len(seen))
+}
+
+// List retrieves appeals with filters and pagination, with the cursors of the neighbouring pages.
+// With filters.Cursor set it pages by keyset instead of offset and does not count the
+// total, which would scan every matching row on each page; the total is then 0.
+func (r *AppealRepository) List(ctx context.Context, filters *models.AppealFilters) ([]*models.Appeal, int64, models.PageCursors, error) {
+	whereClause, args, columns := appealFilterClause(filters)
+	argCount := len(args) + 1
+
+	// Count total
+	var total int64
+	if filters.Cursor == nil {
+		countQuery := fmt.Sprintf("SELECT COUNT(*) FROM appeals a WHERE %s", whereClause)
+		err := r.db.QueryRow(ctx, countQuery, args...).Scan(&total)
+		if err != nil {
+			return nil, 0, models.PageCursors{}, fmt.Errorf("failed to count appeals: %w", err)
 		}
 	}
 
-	// Pagination
+	keys := appealSortKeys(filters, columns)
+
+	// Pagination: by page number, or by cursor after (or before) a known row
 	if filters.Limit <= 0 {
 		filters.Limit = 20
 	}
@@ -226,275 +525,1276 @@ func (r *AppealRepository) List(ctx context.Context, filters *models.AppealFilte
 		filters.Page = 1
 	}
 	offset := (filters.Page - 1) * filters.Limit
+	limit := filters.Limit
+	fromCursor, backward := false, false
+	if filters.Cursor != nil {
+		offset, limit = 0, filters.Limit+1
+		if *filters.Cursor != "" {
+			cursorArgs, back, err := decodeKeysetCursor(keys, *filters.Cursor)
+			if err != nil {
+				return nil, 0, models.PageCursors{}, err
+			}
+			whereClause += " AND " + keysetCondition(keys, back, argCount)
+			args = append(args, cursorArgs...)
+			argCount += len(cursorArgs)
+			fromCursor, backward = true, back
+		}
+	}
+	orderBy := keysetOrder(keys, backward)
 
 	// Get appeals
 	query := fmt.Sprintf(`
 		SELECT
-			a.id, a.user_id, a.category_id, a.service_id,
+			a.id, a.user_id, a.category_id, a.service_id, a.assignee_id, a.district_id,
 			a.status, a.title, a.description, a.address, a.latitude, a.longitude,
-			a.priority, a.created_at, a.updated_at, a.closed_at,
+			a.priority, a.created_at, a.updated_at, a.closed_at, a.completed_at, a.reopen_count,
+			a.response_due_at, a.due_at, a.duplicate_of_id, a.duplicate_score, a.parent_id, a.custom_fields,
 			u.first_name, u.last_name,
 			c.name AS category_name,
-			s.name AS service_name
+			s.name AS service_name,
+			d.name AS district_name,
+			%s AS distance,
+			%s AS search_rank, %s, %s, %s
 		FROM appeals a
 		LEFT JOIN users u ON a.user_id = u.id
 		LEFT JOIN categories c ON a.category_id = c.id
 		LEFT JOIN services s ON a.service_id = s.id
+		LEFT JOIN districts d ON a.district_id = d.id
 		WHERE %s
 		ORDER BY %s
 		LIMIT $%d OFFSET $%d
-	`, whereClause, orderBy, argCount, argCount+1)
+	`, columns.Distance, columns.SearchRank, columns.SearchTitle, columns.SearchSnippet, columns.SearchComment,
+		whereClause, orderBy, argCount, argCount+1)
+
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, models.PageCursors{}, fmt.Errorf("failed to list appeals: %w", err)
+	}
+	defer rows.Close()
+
+	appeals := make([]*models.Appeal, 0)
+	for rows.Next() {
+		var appeal models.Appeal
+		var firstName, lastName string
+		var categoryName, serviceName, districtName *string
+		var searchRank *float64
+		var searchTitle, searchSnippet, searchComment *string
+
+		err := rows.Scan(
+			&appeal.ID, &appeal.UserID, &appeal.CategoryID, &appeal.ServiceID, &appeal.AssigneeID, &appeal.DistrictID,
+			&appeal.Status, &appeal.Title, &appeal.Description, &appeal.Address,
+			&appeal.Latitude, &appeal.Longitude, &appeal.Priority,
+			&appeal.CreatedAt, &appeal.UpdatedAt, &appeal.ClosedAt, &appeal.CompletedAt, &appeal.ReopenCount,
+			&appeal.ResponseDueAt, &appeal.DueAt, &appeal.DuplicateOfID, &appeal.DuplicateScore, &appeal.ParentID, &appeal.CustomFields,
+			&firstName, &lastName,
+			&categoryName, &serviceName, &districtName,
+			&appeal.DistanceMeters,
+			&searchRank, &searchTitle, &searchSnippet, &searchComment,
+		)
+		if err != nil {
+			return nil, 0, models.PageCursors{}, fmt.Errorf("failed to scan appeal: %w", err)
+		}
+
+		if searchRank != nil {
+			appeal.SearchMatch = &models.AppealSearchMatch{
+				Rank:           *searchRank,
+				Title:          getStringValue(searchTitle),
+				Snippet:        getStringValue(searchSnippet),
+				CommentSnippet: searchComment,
+			}
+		}
+
+		// Set user info
+		appeal.User = &models.User{
+			ID:        appeal.UserID,
+			FirstName: firstName,
+			LastName:  lastName,
+		}
+
+		// Set category if exists
+		if categoryName != nil {
+			appeal.Category = &models.Category{
+				ID:   *appeal.CategoryID,
+				Name: *categoryName,
+			}
+		}
+
+		// Set service if exists
+		if serviceName != nil {
+			appeal.Service = &models.Service{
+				ID:   *appeal.ServiceID,
+				Name: *serviceName,
+			}
+		}
+
+		// Set district if exists
+		if districtName != nil {
+			appeal.District = &models.District{
+				ID:   *appeal.DistrictID,
+				Name: *districtName,
+			}
+		}
 
-	args = append(args, filters.Limit, offset)
+		appeals = append(appeals, &appeal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, models.PageCursors{}, fmt.Errorf("failed to list appeals: %w", err)
+	}
+	rows.Close()
+
+	if err := r.attachTags(ctx, appeals...); err != nil {
+		return nil, 0, models.PageCursors{}, err
+	}
+
+	cursorAt := func(i int, backward bool) string {
+		return encodeKeysetCursor(keys, appealSortValues(keys, appeals[i]), backward)
+	}
+
+	var cursors models.PageCursors
+	if filters.Cursor == nil {
+		// Cursors let a client that started by page number continue by cursor
+		if len(appeals) > 0 && offset+len(appeals) < int(total) {
+			cursors.Next = cursorAt(len(appeals)-1, false)
+		}
+		if len(appeals) > 0 && offset > 0 {
+			cursors.Prev = cursorAt(0, true)
+		}
+		return appeals, total, cursors, nil
+	}
+
+	kept, cursors := keysetPage(len(appeals), filters.Limit, fromCursor, backward, cursorAt)
+	appeals = appeals[:kept]
+	if backward {
+		for i, j := 0, len(appeals)-1; i < j; i, j = i+1, j-1 {
+			appeals[i], appeals[j] = appeals[j], appeals[i]
+		}
+	}
+
+	return appeals, total, cursors, nil
+}
+
+// attachTags loads the tags of the appeals, ordered by name
+func (r *AppealRepository) attachTags(ctx context.Context, appeals ...*models.Appeal) error {
+	if len(appeals) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*models.Appeal, len(appeals))
+	ids := make([]int64, 0, len(appeals))
+	for _, appeal := range appeals {
+		byID[appeal.ID] = appeal
+		ids = append(ids, appeal.ID)
+	}
+
+	query := `
+		SELECT ` + tagColumns + `, at.appeal_id
+		FROM appeal_tags at
+		JOIN tags t ON at.tag_id = t.id
+		WHERE at.appeal_id = ANY($1)
+		ORDER BY lower(t.name)
+	`
+
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get appeal tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var appealID int64
+		tag, err := scanTag(scanWithExtra{rows, &appealID})
+		if err != nil {
+			return fmt.Errorf("failed to scan appeal tag: %w", err)
+		}
+		byID[appealID].Tags = append(byID[appealID].Tags, tag)
+	}
+
+	return rows.Err()
+}
+
+// Count returns how many appeals match the filters
+func (r *AppealRepository) Count(ctx context.Context, filters *models.AppealFilters) (int64, error) {
+	whereClause, args, _ := appealFilterClause(filters)
+
+	var count int64
+	err := r.db.QueryRow(ctx, fmt.Sprintf("SELECT COUNT(*) FROM appeals a WHERE %s", whereClause), args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count appeals: %w", err)
+	}
+
+	return count, nil
+}
+
+// Matches reports whether the appeal passes the filters
+func (r *AppealRepository) Matches(ctx context.Context, filters *models.AppealFilters, appealID int64) (bool, error) {
+	whereClause, args, _ := appealFilterClause(filters)
+	query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM appeals a WHERE %s AND a.id = $%d)", whereClause, len(args)+1)
+
+	var matches bool
+	if err := r.db.QueryRow(ctx, query, append(args, appealID)...).Scan(&matches); err != nil {
+		return false, fmt.Errorf("failed to match appeal: %w", err)
+	}
+
+	return matches, nil
+}
+
+// Export streams all filtered appeals to fn in list order, with the author's contacts,
+// the assignee and the last status change. Iteration stops at the first error from fn.
+func (r *AppealRepository) Export(ctx context.Context, filters *models.AppealFilters, fn func(*models.AppealExportRow) error) error {
+	whereClause, args, columns := appealFilterClause(filters)
+
+	query := fmt.Sprintf(`
+		SELECT
+			a.id, a.user_id, a.category_id, a.service_id, a.assignee_id, a.district_id,
+			a.status, a.title, a.description, a.address, a.latitude, a.longitude,
+			a.priority, a.created_at, a.updated_at, a.closed_at, a.completed_at, a.parent_id, a.custom_fields,
+			u.first_name, u.last_name, u.email, u.phone,
+			c.name, c.custom_fields, s.name, d.name,
+			asg.first_name, asg.last_name,
+			lsc.created_at, lu.first_name, lu.last_name,
+			%s AS distance,
+			%s AS search_rank
+		FROM appeals a
+		LEFT JOIN users u ON a.user_id = u.id
+		LEFT JOIN categories c ON a.category_id = c.id
+		LEFT JOIN services s ON a.service_id = s.id
+		LEFT JOIN districts d ON a.district_id = d.id
+		LEFT JOIN users asg ON a.assignee_id = asg.id
+		LEFT JOIN LATERAL (
+			SELECT h.created_at, h.user_id
+			FROM appeal_history h
+			WHERE h.appeal_id = a.id AND h.old_status IS NOT NULL AND h.old_status <> h.new_status
+			ORDER BY h.created_at DESC, h.id DESC
+			LIMIT 1
+		) lsc ON true
+		LEFT JOIN users lu ON lsc.user_id = lu.id
+		WHERE %s
+		ORDER BY %s
+	`, columns.Distance, columns.SearchRank, whereClause, keysetOrder(appealSortKeys(filters, columns), false))
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export appeals: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var appeal models.Appeal
+		var author models.User
+		var categoryName, serviceName, districtName *string
+		var categoryFields []models.CustomField
+		var assigneeFirstName, assigneeLastName *string
+		var changedByFirstName, changedByLastName *string
+		var row models.AppealExportRow
+
+		err := rows.Scan(
+			&appeal.ID, &appeal.UserID, &appeal.CategoryID, &appeal.ServiceID, &appeal.AssigneeID, &appeal.DistrictID,
+			&appeal.Status, &appeal.Title, &appeal.Description, &appeal.Address,
+			&appeal.Latitude, &appeal.Longitude, &appeal.Priority,
+			&appeal.CreatedAt, &appeal.UpdatedAt, &appeal.ClosedAt, &appeal.CompletedAt, &appeal.ParentID, &appeal.CustomFields,
+			&author.FirstName, &author.LastName, &author.Email, &author.Phone,
+			&categoryName, &categoryFields, &serviceName, &districtName,
+			&assigneeFirstName, &assigneeLastName,
+			&row.LastStatusChangeAt, &changedByFirstName, &changedByLastName,
+			&appeal.DistanceMeters,
+			nil, // search_rank is only selected to order by
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan appeal: %w", err)
+		}
+
+		author.ID = appeal.UserID
+		appeal.User = &author
+		if categoryName != nil {
+			appeal.Category = &models.Category{ID: *appeal.CategoryID, Name: *categoryName, CustomFields: categoryFields}
+		}
+		if serviceName != nil {
+			appeal.Service = &models.Service{ID: *appeal.ServiceID, Name: *serviceName}
+		}
+		if districtName != nil {
+			appeal.District = &models.District{ID: *appeal.DistrictID, Name: *districtName}
+		}
+		if appeal.AssigneeID != nil && assigneeFirstName != nil && assigneeLastName != nil {
+			appeal.Assignee = &models.User{ID: *appeal.AssigneeID, FirstName: *assigneeFirstName, LastName: *assigneeLastName}
+		}
+		if changedByFirstName != nil && changedByLastName != nil {
+			name := *changedByFirstName + " " + *changedByLastName
+			row.LastStatusChangeBy = &name
+		}
+		row.Appeal = &appeal
+
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// ListMapPoints retrieves the locations of filtered appeals for the map, newest first.
+// It returns at most limit points and whether more appeals matched.
+func (r *AppealRepository) ListMapPoints(ctx context.Context, filters *models.AppealFilters, limit int) ([]*models.AppealMapPoint, bool, error) {
+	whereClause, args, _ := appealFilterClause(filters)
+
+	query := fmt.Sprintf(`
+		SELECT a.id, a.title, a.status, a.priority, a.category_id, c.name,
+		       a.latitude, a.longitude, a.created_at
+		FROM appeals a
+		LEFT JOIN categories c ON a.category_id = c.id
+		WHERE %s
+		ORDER BY a.created_at DESC
+		LIMIT $%d
+	`, whereClause, len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list map points: %w", err)
+	}
+	defer rows.Close()
+
+	points := make([]*models.AppealMapPoint, 0)
+	for rows.Next() {
+		var p models.AppealMapPoint
+		err := rows.Scan(
+			&p.ID, &p.Title, &p.Status, &p.Priority, &p.CategoryID, &p.CategoryName,
+			&p.Latitude, &p.Longitude, &p.CreatedAt,
+		)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to scan map point: %w", err)
+		}
+		points = append(points, &p)
+	}
+
+	if len(points) > limit {
+		return points[:limit], true, nil
+	}
+	return points, false, nil
+}
+
+// ListClusterCells groups filtered appeals into square grid cells of cellSize degrees
+// and counts them per status.
+func (r *AppealRepository) ListClusterCells(ctx context.Context, filters *models.AppealFilters, cellSize float64) ([]*models.AppealClusterCell, error) {
+	whereClause, args, _ := appealFilterClause(filters)
+	cellArg := len(args) + 1
+
+	query := fmt.Sprintf(`
+		SELECT FLOOR(a.longitude / $%[1]d)::BIGINT AS cell_x,
+		       FLOOR(a.latitude / $%[1]d)::BIGINT AS cell_y,
+		       a.status, COUNT(*), AVG(a.latitude), AVG(a.longitude), MIN(a.id)
+		FROM appeals a
+		WHERE %[2]s
+		GROUP BY cell_x, cell_y, a.status
+		ORDER BY cell_x, cell_y
+	`, cellArg, whereClause)
+	args = append(args, cellSize)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cluster appeals: %w", err)
+	}
+	defer rows.Close()
+
+	cells := make([]*models.AppealClusterCell, 0)
+	for rows.Next() {
+		var c models.AppealClusterCell
+		if err := rows.Scan(&c.CellX, &c.CellY, &c.Status, &c.Count, &c.Latitude, &c.Longitude, &c.AppealID); err != nil {
+			return nil, fmt.Errorf("failed to scan cluster cell: %w", err)
+		}
+		cells = append(cells, &c)
+	}
+
+	return cells, nil
+}
+
+// distanceSQL is the haversine distance in meters between an appeal and the point
+// given by the latitude and longitude parameters.
+func distanceSQL(latArg, lngArg int) string {
+	return fmt.Sprintf(`(2 * %[3]f * ASIN(LEAST(1, SQRT(
+		POWER(SIN(RADIANS(a.latitude - $%[1]d::DOUBLE PRECISION) / 2), 2) +
+		COS(RADIANS($%[1]d::DOUBLE PRECISION)) * COS(RADIANS(a.latitude)) *
+		POWER(SIN(RADIANS(a.longitude - $%[2]d::DOUBLE PRECISION) / 2), 2)))))`, latArg, lngArg, geo.EarthRadiusMeters)
+}
+
+// Update updates an appeal
+func (r *AppealRepository) Update(ctx context.Context, appeal *models.Appeal) error {
+	query := `
+		UPDATE appeals
+		SET title = $1, description = $2, category_id = $3, address = $4,
+		    latitude = $5, longitude = $6, service_id = $7, district_id = $8,
+		    custom_fields = COALESCE($10, custom_fields), updated_at = NOW()
+		WHERE id = $9
+	`
+
+	result, err := r.db.Exec(
+		ctx,
+		query,
+		appeal.Title,
+		appeal.Description,
+		appeal.CategoryID,
+		appeal.Address,
+		appeal.Latitude,
+		appeal.Longitude,
+		appeal.ServiceID,
+		appeal.DistrictID,
+		appeal.ID,
+		appeal.CustomFields,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to update appeal: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrAppealNotFound
+	}
+
+	return nil
+}
+
+// UpdateStatus moves an appeal from oldStatus, the status the caller validated the move
+// against, to newStatus and records history. If the status is no longer oldStatus it
+// returns ErrStatusChanged, so a concurrent change cannot let an invalid move through.
+func (r *AppealRepository) UpdateStatus(ctx context.Context, appealID int64, oldStatus, newStatus models.AppealStatus, userID int64, comment *string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Get current status
+	var currentStatus models.AppealStatus
+	err = tx.QueryRow(ctx, "SELECT status FROM appeals WHERE id = $1 FOR UPDATE", appealID).Scan(&currentStatus)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAppealNotFound
+		}
+		return fmt.Errorf("failed to get current status: %w", err)
+	}
+	if currentStatus != oldStatus {
+		return ErrStatusChanged
+	}
+
+	// Only record history if status actually changed
+	if oldStatus == newStatus {
+		log.Printf("Status unchanged for appeal %d: %s, skipping history record", appealID, oldStatus)
+		// Still update updated_at timestamp
+		updateQuery := `UPDATE appeals SET updated_at = NOW() WHERE id = $1`
+		_, err = tx.Exec(ctx, updateQuery, appealID)
+		if err != nil {
+			return fmt.Errorf("failed to update timestamp: %w", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		return nil
+	}
+
+	// Update status together with completed_at / closed_at / reopen_count
+	updateQuery := fmt.Sprintf(`
+		UPDATE appeals
+		SET status = $1, updated_at = NOW(), %s
+		WHERE id = $2 AND status = $3
+	`, statusTimestampsSet)
+
+	result, err := tx.Exec(ctx, updateQuery, newStatus, appealID, oldStatus)
+	if err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrStatusChanged
+	}
+
+	// Record history
+	historyQuery := `
+		INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action, comment)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	// Translate status to Ukrainian for action message
+	oldLabel := appealStatusLabels[oldStatus]
+	if oldLabel == "" {
+		oldLabel = string(oldStatus)
+	}
+	newLabel := appealStatusLabels[newStatus]
+	if newLabel == "" {
+		newLabel = string(newStatus)
+	}
+	action := fmt.Sprintf("Статус змінено з %s на %s", oldLabel, newLabel)
+	result, err = tx.Exec(ctx, historyQuery, appealID, userID, oldStatus, newStatus, action, comment)
+	if err != nil {
+		log.Printf("Error inserting history: %v", err)
+		return fmt.Errorf("failed to record history: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		log.Printf("Warning: History insert affected 0 rows for appeal %d", appealID)
+	} else {
+		log.Printf("History recorded for appeal %d: %s -> %s", appealID, oldStatus, newStatus)
+	}
+
+	// Appeals merged into this one follow its status
+	if err := cascadeStatusToChildren(ctx, tx, appealID, newStatus, userID, comment); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Assign assigns an appeal to a service
+func (r *AppealRepository) Assign(ctx context.Context, appealID, serviceID int64, priority *int, userID int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Get current status and executor before updating
+	var oldStatus models.AppealStatus
+	var oldServiceID, oldAssigneeID *int64
+	err = tx.QueryRow(ctx, "SELECT status, service_id, assignee_id FROM appeals WHERE id = $1", appealID).
+		Scan(&oldStatus, &oldServiceID, &oldAssigneeID)
+	if err != nil {
+		return fmt.Errorf("failed to get current status: %w", err)
+	}
+
+	// Update appeal with service assignment and set status to 'assigned'.
+	// The executor only stays when the appeal remains within the same service.
+	// A new priority moves the SLA deadlines along with it.
+	deadlinesSet := ""
+	if priority != nil {
+		deadlinesSet = ",\n\t\t    " + slaDeadlinesSet("$2::INT")
+	}
+	query := fmt.Sprintf(`
+		UPDATE appeals
+		SET service_id = $1, priority = COALESCE($2, priority),
+		    status = 'assigned', updated_at = NOW(), completed_at = NULL,
+		    assignee_id = CASE WHEN service_id = $1 THEN assignee_id ELSE NULL END%s
+		WHERE id = $3
+	`, deadlinesSet)
+
+	result, err := tx.Exec(ctx, query, serviceID, priority, appealID)
+	if err != nil {
+		return fmt.Errorf("failed to assign appeal: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrAppealNotFound
+	}
+
+	// Record history only if status actually changed
+	if oldStatus != models.StatusAssigned {
+		// Translate status to Ukrainian for action message
+		oldLabel := appealStatusLabels[oldStatus]
+		if oldLabel == "" {
+			oldLabel = string(oldStatus)
+		}
+		action := fmt.Sprintf("Звернення призначено до служби. Статус змінено з %s на Призначене", oldLabel)
+
+	historyQuery := `
+		INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
+			VALUES ($1, $2, $3, 'assigned', $4)
+	`
+		_, err = tx.Exec(ctx, historyQuery, appealID, userID, oldStatus, action)
+	if err != nil {
+			log.Printf("Error inserting history: %v", err)
+		return fmt.Errorf("failed to record history: %w", err)
+		}
+	} else {
+		// Status already assigned, just record assignment without status change
+		historyQuery := `
+			INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
+			VALUES ($1, $2, $3, 'assigned', 'Звернення призначено до служби')
+		`
+		_, err = tx.Exec(ctx, historyQuery, appealID, userID, oldStatus)
+		if err != nil {
+			log.Printf("Error inserting history: %v", err)
+			return fmt.Errorf("failed to record history: %w", err)
+		}
+	}
+
+	if oldAssigneeID != nil && (oldServiceID == nil || *oldServiceID != serviceID) {
+		oldName, err := executorName(ctx, tx, oldAssigneeID)
+		if err != nil {
+			return err
+		}
+		historyQuery := `
+			INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
+			VALUES ($1, $2, 'assigned', 'assigned', $3)
+		`
+		action := fmt.Sprintf("Виконавця змінено з %s на —", oldName)
+		if _, err := tx.Exec(ctx, historyQuery, appealID, userID, action); err != nil {
+			return fmt.Errorf("failed to record history: %w", err)
+		}
+	}
+
+	// Appeals merged into this one are routed to the same service
+	_, err = tx.Exec(ctx, `UPDATE appeals SET service_id = $1, updated_at = NOW() WHERE parent_id = $2`, serviceID, appealID)
+	if err != nil {
+		return fmt.Errorf("failed to assign linked appeals: %w", err)
+	}
+	if err := cascadeStatusToChildren(ctx, tx, appealID, models.StatusAssigned, userID, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SetAssignee sets (or clears, when assigneeID is nil) the executor responsible
+// for an appeal and records the change in history. Returns false when the
+// appeal already had this executor.
+func (r *AppealRepository) SetAssignee(ctx context.Context, appealID int64, assigneeID *int64, userID int64) (bool, error) {
+	return r.setAssignee(ctx, appealID, assigneeID, userID, false)
+}
+
+// Claim makes the executor the assignee of an appeal that has none. The update only
+// applies while the appeal is unclaimed, so of two executors claiming at once the second
+// gets ErrAppealClaimed. Returns false when the executor already owned the appeal.
+func (r *AppealRepository) Claim(ctx context.Context, appealID, executorID int64) (bool, error) {
+	return r.setAssignee(ctx, appealID, &executorID, executorID, true)
+}
+
+func (r *AppealRepository) setAssignee(ctx context.Context, appealID int64, assigneeID *int64, userID int64, claim bool) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status models.AppealStatus
+	var oldAssigneeID *int64
+	err = tx.QueryRow(ctx, "SELECT status, assignee_id FROM appeals WHERE id = $1 FOR UPDATE", appealID).
+		Scan(&status, &oldAssigneeID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, ErrAppealNotFound
+		}
+		return false, fmt.Errorf("failed to get current assignee: %w", err)
+	}
+
+	if (oldAssigneeID == nil && assigneeID == nil) ||
+		(oldAssigneeID != nil && assigneeID != nil && *oldAssigneeID == *assigneeID) {
+		return false, nil
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE appeals SET assignee_id = $1, updated_at = NOW()
+		WHERE id = $2 AND (NOT $3 OR assignee_id IS NULL OR assignee_id = $1)
+	`, assigneeID, appealID, claim)
+	if err != nil {
+		return false, fmt.Errorf("failed to update assignee: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return false, ErrAppealClaimed
+	}
+
+	oldName, err := executorName(ctx, tx, oldAssigneeID)
+	if err != nil {
+		return false, err
+	}
+	newName, err := executorName(ctx, tx, assigneeID)
+	if err != nil {
+		return false, err
+	}
+
+	historyQuery := `
+		INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
+		VALUES ($1, $2, $3, $3, $4)
+	`
+	action := fmt.Sprintf("Виконавця змінено з %s на %s", oldName, newName)
+	if _, err := tx.Exec(ctx, historyQuery, appealID, userID, status, action); err != nil {
+		return false, fmt.Errorf("failed to record history: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// AutoAssign gives an unclaimed appeal of the service to the executor picked by an
+// assignment strategy. The history entry is a system action naming the strategy.
+// Returns false when the appeal got an executor or left the service in the meantime.
+func (r *AppealRepository) AutoAssign(ctx context.Context, appealID, serviceID, executorID int64, strategy models.AssignmentStrategy) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status models.AppealStatus
+	err = tx.QueryRow(ctx, `
+		UPDATE appeals
+		SET assignee_id = $1, updated_at = NOW()
+		WHERE id = $2 AND service_id = $3 AND assignee_id IS NULL
+		RETURNING status
+	`, executorID, appealID, serviceID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to auto-assign appeal: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE services SET last_assigned_user_id = $1 WHERE id = $2`, executorID, serviceID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update service rotation: %w", err)
+	}
+
+	name, err := executorName(ctx, tx, &executorID)
+	if err != nil {
+		return false, err
+	}
+
+	historyQuery := `
+		INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
+		VALUES ($1, NULL, $2, $2, $3)
+	`
+	action := fmt.Sprintf("Виконавця призначено автоматично (стратегія %s): %s", strategy, name)
+	if _, err := tx.Exec(ctx, historyQuery, appealID, status, action); err != nil {
+		return false, fmt.Errorf("failed to record history: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// executorName returns the display name of a user for history entries, "—" for none
+func executorName(ctx context.Context, tx pgx.Tx, userID *int64) (string, error) {
+	if userID == nil {
+		return "—", nil
+	}
+	var firstName, lastName string
+	err := tx.QueryRow(ctx, "SELECT first_name, last_name FROM users WHERE id = $1", *userID).Scan(&firstName, &lastName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "—", nil
+		}
+		return "", fmt.Errorf("failed to get executor: %w", err)
+	}
+	return strings.TrimSpace(firstName + " " + lastName), nil
+}
+
+// cascadeStatusToChildren moves appeals merged into parentID to the parent's new status
+// and records the change in their history. Must run inside the parent's transaction.
+func cascadeStatusToChildren(ctx context.Context, tx pgx.Tx, parentID int64, newStatus models.AppealStatus, userID int64, comment *string) error {
+	query := `
+		WITH children AS (
+			SELECT id, status AS old_status
+			FROM appeals
+			WHERE parent_id = $2 AND status <> $1
+			FOR UPDATE
+		), updated AS (
+			UPDATE appeals a
+			SET status = $1, updated_at = NOW(), %s
+			FROM children c
+			WHERE a.id = c.id
+			RETURNING a.id, c.old_status
+		)
+		INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action, comment)
+		SELECT id, $3, old_status, $1, $4, $5
+		FROM updated
+	`
+
+	query = fmt.Sprintf(query, statusTimestampsSet)
+	action := fmt.Sprintf("Статус змінено разом з основним зверненням #%d", parentID)
+	if _, err := tx.Exec(ctx, query, newStatus, parentID, userID, action, comment); err != nil {
+		return fmt.Errorf("failed to update linked appeals: %w", err)
+	}
+
+	return nil
+}
+
+// GetChildren returns appeals merged into the given master appeal
+func (r *AppealRepository) GetChildren(ctx context.Context, parentID int64) ([]*models.Appeal, error) {
+	query := `
+		SELECT id, user_id, category_id, service_id, status, title, address,
+		       latitude, longitude, priority, created_at, updated_at, parent_id
+		FROM appeals
+		WHERE parent_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get linked appeals: %w", err)
+	}
+	defer rows.Close()
+
+	appeals := make([]*models.Appeal, 0)
+	for rows.Next() {
+		var appeal models.Appeal
+		err := rows.Scan(
+			&appeal.ID, &appeal.UserID, &appeal.CategoryID, &appeal.ServiceID, &appeal.Status,
+			&appeal.Title, &appeal.Address, &appeal.Latitude, &appeal.Longitude, &appeal.Priority,
+			&appeal.CreatedAt, &appeal.UpdatedAt, &appeal.ParentID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan linked appeal: %w", err)
+		}
+		appeals = append(appeals, &appeal)
+	}
+
+	return appeals, nil
+}
+
+// Merge links child appeals to a master appeal. Children take over the master's
+// status and service; history is recorded on both sides.
+func (r *AppealRepository) Merge(ctx context.Context, parentID int64, childIDs []int64, userID int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := mergeAppeals(ctx, tx, parentID, childIDs, userID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// mergeAppeals does the work of Merge within a transaction
+func mergeAppeals(ctx context.Context, tx pgx.Tx, parentID int64, childIDs []int64, userID int64) error {
+	// The one-level rule is checked on locked rows, each check in its own statement so it
+	// sees merges committed while waiting for the lock; otherwise concurrent merges of
+	// A into B and B into C could build a chain.
+	var parentStatus models.AppealStatus
+	var parentServiceID, parentParentID *int64
+	err := tx.QueryRow(ctx, "SELECT status, service_id, parent_id FROM appeals WHERE id = $1 FOR UPDATE", parentID).
+		Scan(&parentStatus, &parentServiceID, &parentParentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAppealNotFound
+		}
+		return fmt.Errorf("failed to get master appeal: %w", err)
+	}
+	if parentParentID != nil {
+		return fmt.Errorf("%w: appeal %d is itself merged into appeal %d", ErrMergeConflict, parentID, *parentParentID)
+	}
+
+	historyQuery := `
+		INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	for _, childID := range childIDs {
+		var oldStatus models.AppealStatus
+		var oldParentID *int64
+		err := tx.QueryRow(ctx, "SELECT status, parent_id FROM appeals WHERE id = $1 FOR UPDATE", childID).
+			Scan(&oldStatus, &oldParentID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrAppealNotFound
+			}
+			return fmt.Errorf("failed to get appeal: %w", err)
+		}
+		if oldParentID != nil {
+			return fmt.Errorf("%w: appeal %d is already merged into appeal %d", ErrMergeConflict, childID, *oldParentID)
+		}
+
+		var hasChildren bool
+		err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM appeals WHERE parent_id = $1)", childID).Scan(&hasChildren)
+		if err != nil {
+			return fmt.Errorf("failed to check linked appeals: %w", err)
+		}
+		if hasChildren {
+			return fmt.Errorf("%w: appeal %d has its own linked appeals", ErrMergeConflict, childID)
+		}
+
+		result, err := tx.Exec(ctx, `
+			UPDATE appeals
+			SET parent_id = $1, status = $2, service_id = COALESCE($3, service_id), updated_at = NOW()
+			WHERE id = $4 AND parent_id IS NULL
+		`, parentID, parentStatus, parentServiceID, childID)
+		if err != nil {
+			return fmt.Errorf("failed to merge appeal: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("%w: appeal %d is already merged", ErrMergeConflict, childID)
+		}
+
+		childAction := fmt.Sprintf("Об'єднано з основним зверненням #%d", parentID)
+		if _, err := tx.Exec(ctx, historyQuery, childID, userID, oldStatus, parentStatus, childAction); err != nil {
+			return fmt.Errorf("failed to record history: %w", err)
+		}
+
+		parentAction := fmt.Sprintf("Приєднано звернення #%d", childID)
+		if _, err := tx.Exec(ctx, historyQuery, parentID, userID, parentStatus, parentStatus, parentAction); err != nil {
+			return fmt.Errorf("failed to record history: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Unmerge detaches child appeals from a master appeal. Children keep their current status.
+func (r *AppealRepository) Unmerge(ctx context.Context, parentID int64, childIDs []int64, userID int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	historyQuery := `
+		INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
+		SELECT id, $2, status, status, $3
+		FROM appeals
+		WHERE id = $1
+	`
+
+	for _, childID := range childIDs {
+		result, err := tx.Exec(ctx, `
+			UPDATE appeals SET parent_id = NULL, updated_at = NOW()
+			WHERE id = $1 AND parent_id = $2
+		`, childID, parentID)
+		if err != nil {
+			return fmt.Errorf("failed to unmerge appeal: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return ErrAppealNotFound
+		}
+
+		childAction := fmt.Sprintf("Від'єднано від основного звернення #%d", parentID)
+		if _, err := tx.Exec(ctx, historyQuery, childID, userID, childAction); err != nil {
+			return fmt.Errorf("failed to record history: %w", err)
+		}
+
+		parentAction := fmt.Sprintf("Від'єднано звернення #%d", childID)
+		if _, err := tx.Exec(ctx, historyQuery, parentID, userID, parentAction); err != nil {
+			return fmt.Errorf("failed to record history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AutoCloseCompleted closes appeals that stayed 'completed' longer than windowDays
+// without an answer from the author. Returns the closed appeals.
+func (r *AppealRepository) AutoCloseCompleted(ctx context.Context, windowDays, limit int) ([]*models.Appeal, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM appeals
+			WHERE status = 'completed' AND completed_at < NOW() - make_interval(days => $1)
+			ORDER BY completed_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		), closed AS (
+			UPDATE appeals a
+			SET status = 'closed', closed_at = NOW(), updated_at = NOW()
+			FROM due
+			WHERE a.id = due.id
+			RETURNING a.id, a.user_id, a.category_id, a.service_id, a.title, a.status, a.parent_id
+		), history AS (
+			INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
+			SELECT id, NULL, 'completed', 'closed', $3
+			FROM closed
+		)
+		SELECT id, user_id, category_id, service_id, title, status, parent_id
+		FROM closed
+	`
 
-	rows, err := r.db.Query(ctx, query, args...)
+	action := fmt.Sprintf("Звернення закрито автоматично: автор не відповів протягом %d днів", windowDays)
+	rows, err := r.db.Query(ctx, query, windowDays, limit, action)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list appeals: %w", err)
+		return nil, fmt.Errorf("failed to auto-close appeals: %w", err)
 	}
 	defer rows.Close()
 
 	appeals := make([]*models.Appeal, 0)
 	for rows.Next() {
 		var appeal models.Appeal
-		var firstName, lastName string
-		var categoryName, serviceName *string
-
 		err := rows.Scan(
 			&appeal.ID, &appeal.UserID, &appeal.CategoryID, &appeal.ServiceID,
-			&appeal.Status, &appeal.Title, &appeal.Description, &appeal.Address,
-			&appeal.Latitude, &appeal.Longitude, &appeal.Priority,
-			&appeal.CreatedAt, &appeal.UpdatedAt, &appeal.ClosedAt,
-			&firstName, &lastName,
-			&categoryName, &serviceName,
+			&appeal.Title, &appeal.Status, &appeal.ParentID,
 		)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan appeal: %w", err)
+			return nil, fmt.Errorf("failed to scan closed appeal: %w", err)
 		}
+		appeals = append(appeals, &appeal)
+	}
 
-		// Set user info
-		appeal.User = &models.User{
-			ID:        appeal.UserID,
-			FirstName: firstName,
-			LastName:  lastName,
-		}
+	return appeals, rows.Err()
+}
 
-		// Set category if exists
-		if categoryName != nil {
-			appeal.Category = &models.Category{
-				ID:   *appeal.CategoryID,
-				Name: *categoryName,
-			}
-		}
+// FindOpenInArea returns open appeals of a category whose location falls into the box.
+// Used as a prefilter for duplicate detection; exact distance is checked by the caller.
+func (r *AppealRepository) FindOpenInArea(ctx context.Context, categoryID int64, box geo.BoundingBox, limit int) ([]*models.Appeal, error) {
+	query := `
+		SELECT id, user_id, category_id, status, title, description, address,
+		       latitude, longitude, created_at
+		FROM appeals
+		WHERE category_id = $1
+			AND parent_id IS NULL
+			AND status NOT IN ('completed', 'closed', 'rejected')
+			AND latitude BETWEEN $2 AND $3
+			AND longitude BETWEEN $4 AND $5
+		ORDER BY created_at DESC
+		LIMIT $6
+	`
 
-		// Set service if exists
-		if serviceName != nil {
-			appeal.Service = &models.Service{
-				ID:   *appeal.ServiceID,
-				Name: *serviceName,
-			}
-		}
+	rows, err := r.db.Query(ctx, query, categoryID, box.MinLat, box.MaxLat, box.MinLng, box.MaxLng, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find appeals in area: %w", err)
+	}
+	defer rows.Close()
 
+	appeals := make([]*models.Appeal, 0)
+	for rows.Next() {
+		var appeal models.Appeal
+		err := rows.Scan(
+			&appeal.ID, &appeal.UserID, &appeal.CategoryID, &appeal.Status, &appeal.Title,
+			&appeal.Description, &appeal.Address, &appeal.Latitude, &appeal.Longitude, &appeal.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan appeal: %w", err)
+		}
 		appeals = append(appeals, &appeal)
 	}
 
-	return appeals, total, nil
+	return appeals, nil
 }
 
-// Update updates an appeal
-func (r *AppealRepository) Update(ctx context.Context, appeal *models.Appeal) error {
+// ListOpenLocationsInArea returns the locations of open appeals (of any category) inside
+// the box, leaving out excludeID. Merged appeals are not counted separately.
+func (r *AppealRepository) ListOpenLocationsInArea(ctx context.Context, box geo.BoundingBox, excludeID int64) ([]geo.Point, error) {
 	query := `
-		UPDATE appeals
-		SET title = $1, description = $2, category_id = $3, address = $4,
-		    latitude = $5, longitude = $6, service_id = $7, updated_at = NOW()
-		WHERE id = $8
+		SELECT latitude, longitude
+		FROM appeals
+		WHERE id <> $1
+			AND parent_id IS NULL
+			AND status NOT IN ('completed', 'closed', 'rejected')
+			AND latitude BETWEEN $2 AND $3
+			AND longitude BETWEEN $4 AND $5
 	`
 
-	result, err := r.db.Exec(
-		ctx,
-		query,
-		appeal.Title,
-		appeal.Description,
-		appeal.CategoryID,
-		appeal.Address,
-		appeal.Latitude,
-		appeal.Longitude,
-		appeal.ServiceID,
-		appeal.ID,
-	)
-
+	rows, err := r.db.Query(ctx, query, excludeID, box.MinLat, box.MaxLat, box.MinLng, box.MaxLng)
 	if err != nil {
-		return fmt.Errorf("failed to update appeal: %w", err)
+		return nil, fmt.Errorf("failed to find appeal locations in area: %w", err)
 	}
+	defer rows.Close()
 
-	if result.RowsAffected() == 0 {
-		return ErrAppealNotFound
+	points := make([]geo.Point, 0)
+	for rows.Next() {
+		var p geo.Point
+		if err := rows.Scan(&p.Lat, &p.Lng); err != nil {
+			return nil, fmt.Errorf("failed to scan appeal location: %w", err)
+		}
+		points = append(points, p)
 	}
 
-	return nil
+	return points, nil
 }
 
-// UpdateStatus updates appeal status and records history
-func (r *AppealRepository) UpdateStatus(ctx context.Context, appealID int64, newStatus models.AppealStatus, userID int64, comment *string) error {
+// AddSupporter attaches a citizen's report to an existing appeal and records it in history.
+// The citizen's staged photos are attached to the appeal in the same transaction, even when
+// the citizen has already joined it. Returns false if the citizen has already joined this
+// appeal or is its author; the author is counted anyway.
+func (r *AppealRepository) AddSupporter(ctx context.Context, appealID, userID int64, description string, photoIDs []int64) (bool, error) {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
-	// Get current status
-	var oldStatus models.AppealStatus
-	err = tx.QueryRow(ctx, "SELECT status FROM appeals WHERE id = $1", appealID).Scan(&oldStatus)
-	if err != nil {
-		return fmt.Errorf("failed to get current status: %w", err)
+	if err := claimStagedPhotos(ctx, tx, photoIDs, userID, appealID, nil); err != nil {
+		return false, err
 	}
 
-	// Only record history if status actually changed
-	if oldStatus == newStatus {
-		log.Printf("Status unchanged for appeal %d: %s, skipping history record", appealID, oldStatus)
-		// Still update updated_at timestamp
-		updateQuery := `UPDATE appeals SET updated_at = NOW() WHERE id = $1`
-		_, err = tx.Exec(ctx, updateQuery, appealID)
-		if err != nil {
-			return fmt.Errorf("failed to update timestamp: %w", err)
-		}
+	result, err := tx.Exec(ctx, `
+		INSERT INTO appeal_supporters (appeal_id, user_id, description)
+		SELECT id, $2, NULLIF($3, '')
+		FROM appeals
+		WHERE id = $1 AND user_id <> $2
+		ON CONFLICT (appeal_id, user_id) DO NOTHING
+	`, appealID, userID, description)
+	if err != nil {
+		return false, fmt.Errorf("failed to add supporter: %w", err)
+	}
+	if result.RowsAffected() == 0 {
 		if err := tx.Commit(ctx); err != nil {
-			return fmt.Errorf("failed to commit transaction: %w", err)
+			return false, fmt.Errorf("failed to commit transaction: %w", err)
 		}
-		return nil
+		return false, nil
 	}
 
-	// Update status
-	// Set closed_at for both 'closed' and 'completed' statuses
-	updateQuery := `
-		UPDATE appeals
-		SET status = $1, updated_at = NOW()
-		WHERE id = $2
+	historyQuery := `
+		INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action, comment)
+		SELECT id, $2, status, status, 'Приєднано повторне звернення', NULLIF($3, '')
+		FROM appeals
+		WHERE id = $1
 	`
-	if newStatus == models.StatusClosed || newStatus == models.StatusCompleted {
-		updateQuery = `
-			UPDATE appeals
-			SET status = $1, updated_at = NOW(), closed_at = NOW()
-			WHERE id = $2
-		`
+	if _, err := tx.Exec(ctx, historyQuery, appealID, userID, description); err != nil {
+		return false, fmt.Errorf("failed to record history: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	result, err := tx.Exec(ctx, updateQuery, newStatus, appealID)
+	return true, nil
+}
+
+// GetSLABreaches returns open appeals whose response or resolution deadline
+// has passed and that were not escalated for that breach yet.
+// Merged appeals follow their master appeal and are not escalated on their own.
+func (r *AppealRepository) GetSLABreaches(ctx context.Context, limit int) ([]*models.SLABreach, error) {
+	query := `
+		SELECT a.id, a.user_id, a.category_id, a.service_id, a.status, a.title,
+		       a.priority, a.created_at, a.response_due_at, a.due_at, 'response' AS kind
+		FROM appeals a
+		WHERE a.status = 'new'
+			AND a.parent_id IS NULL
+			AND a.response_due_at < NOW()
+			AND a.response_escalated_at IS NULL
+		UNION ALL
+		SELECT a.id, a.user_id, a.category_id, a.service_id, a.status, a.title,
+		       a.priority, a.created_at, a.response_due_at, a.due_at, 'resolution' AS kind
+		FROM appeals a
+		WHERE a.status NOT IN ('completed', 'closed', 'rejected')
+			AND a.parent_id IS NULL
+			AND a.due_at < NOW()
+			AND a.due_escalated_at IS NULL
+		ORDER BY created_at ASC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
 	if err != nil {
-		return fmt.Errorf("failed to update status: %w", err)
+		return nil, fmt.Errorf("failed to get sla breaches: %w", err)
 	}
+	defer rows.Close()
 
-	if result.RowsAffected() == 0 {
-		return ErrAppealNotFound
+	breaches := make([]*models.SLABreach, 0)
+	for rows.Next() {
+		var appeal models.Appeal
+		var kind string
+		err := rows.Scan(
+			&appeal.ID, &appeal.UserID, &appeal.CategoryID, &appeal.ServiceID, &appeal.Status, &appeal.Title,
+			&appeal.Priority, &appeal.CreatedAt, &appeal.ResponseDueAt, &appeal.DueAt, &kind,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sla breach: %w", err)
+		}
+		breaches = append(breaches, &models.SLABreach{
+			Appeal: &appeal,
+			Kind:   models.SLABreachKind(kind),
+		})
 	}
 
-	// Record history
-	historyQuery := `
-		INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action, comment)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`
-	// Translate status to Ukrainian for action message
-	statusLabels := map[models.AppealStatus]string{
-		models.StatusNew:        "Нове",
-		models.StatusAssigned:   "Призначене",
-		models.StatusInProgress: "В роботі",
-		models.StatusCompleted:  "Виконане",
-		models.StatusClosed:     "Закрите",
-		models.StatusRejected:   "Відхилене",
-	}
-	oldLabel := statusLabels[oldStatus]
-	if oldLabel == "" {
-		oldLabel = string(oldStatus)
+	return breaches, nil
+}
+
+// EscalateSLABreach raises the priority of an appeal that missed an SLA deadline,
+// marks the breach as escalated and records it in history as a system action.
+// Returns false if the breach was already escalated (e.g. by a concurrent run).
+func (r *AppealRepository) EscalateSLABreach(ctx context.Context, appealID int64, kind models.SLABreachKind) (bool, error) {
+	escalatedColumn := "due_escalated_at"
+	action := "Порушено термін виконання (SLA), пріоритет підвищено"
+	if kind == models.SLABreachResponse {
+		escalatedColumn = "response_escalated_at"
+		action = "Порушено термін реагування (SLA), пріоритет підвищено"
 	}
-	newLabel := statusLabels[newStatus]
-	if newLabel == "" {
-		newLabel = string(newStatus)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	action := fmt.Sprintf("Статус змінено з %s на %s", oldLabel, newLabel)
-	result, err = tx.Exec(ctx, historyQuery, appealID, userID, oldStatus, newStatus, action, comment)
+	defer tx.Rollback(ctx)
+
+	// The deadlines follow the raised priority; the breach itself stays marked as escalated.
+	query := fmt.Sprintf(`
+		UPDATE appeals
+		SET priority = LEAST(priority + 1, 3), %[1]s = NOW(), updated_at = NOW(),
+		    %[2]s
+		WHERE id = $1 AND %[1]s IS NULL
+		RETURNING status
+	`, escalatedColumn, slaDeadlinesSet("LEAST(appeals.priority + 1, 3)"))
+
+	var status models.AppealStatus
+	err = tx.QueryRow(ctx, query, appealID).Scan(&status)
 	if err != nil {
-		log.Printf("Error inserting history: %v", err)
-		return fmt.Errorf("failed to record history: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to escalate appeal: %w", err)
 	}
-	if result.RowsAffected() == 0 {
-		log.Printf("Warning: History insert affected 0 rows for appeal %d", appealID)
-	} else {
-		log.Printf("History recorded for appeal %d: %s -> %s", appealID, oldStatus, newStatus)
+
+	historyQuery := `
+		INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
+		VALUES ($1, NULL, $2, $2, $3)
+	`
+	if _, err := tx.Exec(ctx, historyQuery, appealID, status, action); err != nil {
+		return false, fmt.Errorf("failed to record history: %w", err)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return nil
+	return true, nil
 }
 
-// Assign assigns an appeal to a service
-func (r *AppealRepository) Assign(ctx context.Context, appealID, serviceID int64, priority *int, userID int64) error {
+// UpdatePriority updates the priority of an appeal together with its SLA deadlines and
+// records the change in history.
+// A nil userID records it as a system action.
+func (r *AppealRepository) UpdatePriority(ctx context.Context, appealID int64, priority int, userID *int64) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
-	// Get current status before updating
-	var oldStatus models.AppealStatus
-	err = tx.QueryRow(ctx, "SELECT status FROM appeals WHERE id = $1", appealID).Scan(&oldStatus)
+	var oldPriority int
+	var status models.AppealStatus
+	err = tx.QueryRow(ctx, "SELECT priority, status FROM appeals WHERE id = $1 FOR UPDATE", appealID).
+		Scan(&oldPriority, &status)
 	if err != nil {
-		return fmt.Errorf("failed to get current status: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAppealNotFound
+		}
+		return fmt.Errorf("failed to get current priority: %w", err)
 	}
 
-	// Update appeal with service assignment and set status to 'assigned'
-	query := `
+	query := fmt.Sprintf(`
 		UPDATE appeals
-		SET service_id = $1, priority = COALESCE($2, priority),
-		    status = 'assigned', updated_at = NOW()
-		WHERE id = $3
-	`
-
-	result, err := tx.Exec(ctx, query, serviceID, priority, appealID)
-	if err != nil {
-		return fmt.Errorf("failed to assign appeal: %w", err)
-	}
-
-	if result.RowsAffected() == 0 {
-		return ErrAppealNotFound
+		SET priority = $1, updated_at = NOW(),
+		    %s
+		WHERE id = $2
+	`, slaDeadlinesSet("$1"))
+	if _, err := tx.Exec(ctx, query, priority, appealID); err != nil {
+		return fmt.Errorf("failed to update priority: %w", err)
 	}
 
-	// Record history only if status actually changed
-	if oldStatus != models.StatusAssigned {
-		// Translate status to Ukrainian for action message
-		statusLabels := map[models.AppealStatus]string{
-			models.StatusNew:        "Нове",
-			models.StatusAssigned:   "Призначене",
-			models.StatusInProgress: "В роботі",
-			models.StatusCompleted:  "Виконане",
-			models.StatusClosed:     "Закрите",
-			models.StatusRejected:   "Відхилене",
-		}
-		oldLabel := statusLabels[oldStatus]
-		if oldLabel == "" {
-			oldLabel = string(oldStatus)
-		}
-		action := fmt.Sprintf("Звернення призначено до служби. Статус змінено з %s на Призначене", oldLabel)
-
-	historyQuery := `
-		INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
-			VALUES ($1, $2, $3, 'assigned', $4)
-	`
-		_, err = tx.Exec(ctx, historyQuery, appealID, userID, oldStatus, action)
-	if err != nil {
-			log.Printf("Error inserting history: %v", err)
-		return fmt.Errorf("failed to record history: %w", err)
-		}
-	} else {
-		// Status already assigned, just record assignment without status change
+	if oldPriority != priority {
 		historyQuery := `
 			INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
-			VALUES ($1, $2, $3, 'assigned', 'Звернення призначено до служби')
+			VALUES ($1, $2, $3, $3, $4)
 		`
-		_, err = tx.Exec(ctx, historyQuery, appealID, userID, oldStatus)
-		if err != nil {
-			log.Printf("Error inserting history: %v", err)
+		action := fmt.Sprintf("Пріоритет змінено: %d → %d", oldPriority, priority)
+		if _, err := tx.Exec(ctx, historyQuery, appealID, userID, status, action); err != nil {
 			return fmt.Errorf("failed to record history: %w", err)
 		}
 	}
@@ -506,26 +1806,6 @@ func (r *AppealRepository) Assign(ctx context.Context, appealID, serviceID int64
 	return nil
 }
 
-// UpdatePriority updates only the priority of an appeal
-func (r *AppealRepository) UpdatePriority(ctx context.Context, appealID int64, priority int, userID int64) error {
-	query := `
-		UPDATE appeals
-		SET priority = $1, updated_at = NOW()
-		WHERE id = $2
-	`
-
-	result, err := r.db.Exec(ctx, query, priority, appealID)
-	if err != nil {
-		return fmt.Errorf("failed to update priority: %w", err)
-	}
-
-	if result.RowsAffected() == 0 {
-		return ErrAppealNotFound
-	}
-
-	return nil
-}
-
 // Delete soft deletes an appeal (not implemented, for future use)
 func (r *AppealRepository) Delete(ctx context.Context, id int64) error {
 	// For now, we don't implement soft delete
@@ -540,10 +1820,11 @@ func (r *AppealRepository) GetStatistics(ctx context.Context, fromDate, toDate *
 	args := []interface{}{}
 	argCount := 1
 
-	// Build WHERE clause for queries with JOIN (using alias 'a')
-	whereClauseWithAlias := "1=1"
+	// Build WHERE clause for queries with JOIN (using alias 'a').
+	// Appeals merged into a master appeal are one incident and counted once.
+	whereClauseWithAlias := "a.parent_id IS NULL"
 	// Build WHERE clause for simple queries without JOIN
-	simpleWhereClause := "1=1"
+	simpleWhereClause := "parent_id IS NULL"
 
 	if fromDate != nil {
 		whereClauseWithAlias += fmt.Sprintf(" AND a.created_at >= $%d", argCount)
@@ -595,9 +1876,9 @@ func (r *AppealRepository) GetStatistics(ctx context.Context, fromDate, toDate *
 	// Average processing time (for completed appeals) - in days
 	var avgTime *float64
 	avgQuery := fmt.Sprintf(`
-		SELECT AVG(EXTRACT(EPOCH FROM (closed_at - created_at))/86400)
+		SELECT AVG(EXTRACT(EPOCH FROM (completed_at - created_at))/86400)
 		FROM appeals
-		WHERE status IN ('closed', 'completed') AND closed_at IS NOT NULL AND %s
+		WHERE status IN ('closed', 'completed') AND completed_at IS NOT NULL AND %s
 	`, simpleWhereClause)
 	err = r.db.QueryRow(ctx, avgQuery, args...).Scan(&avgTime)
 	if err != nil && err != pgx.ErrNoRows {
@@ -608,11 +1889,12 @@ func (r *AppealRepository) GetStatistics(ctx context.Context, fromDate, toDate *
 		stats["avg_processing_hours"] = *avgTime * 24 // Для сумісності зі старим API
 	}
 
-	// By category
+	// By category: subcategories count towards their parent
 	categoryQuery := fmt.Sprintf(`
 		SELECT c.name, COUNT(a.id)
 		FROM appeals a
-		LEFT JOIN categories c ON a.category_id = c.id
+		LEFT JOIN categories sc ON a.category_id = sc.id
+		LEFT JOIN categories c ON COALESCE(sc.parent_id, sc.id) = c.id
 		WHERE %s
 		GROUP BY c.name
 		ORDER BY COUNT(a.id) DESC
@@ -670,6 +1952,63 @@ func (r *AppealRepository) GetStatistics(ctx context.Context, fromDate, toDate *
 	}
 	stats["by_service"] = byService
 
+	// By district
+	districtQuery := fmt.Sprintf(`
+		SELECT d.name, COUNT(a.id)
+		FROM appeals a
+		LEFT JOIN districts d ON a.district_id = d.id
+		WHERE %s
+		GROUP BY d.name
+		ORDER BY COUNT(a.id) DESC
+	`, whereClauseWithAlias)
+	rows, err = r.db.Query(ctx, districtQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get district stats: %w", err)
+	}
+	defer rows.Close()
+
+	byDistrict := make(map[string]int64)
+	for rows.Next() {
+		var districtName *string
+		var count int64
+		if err := rows.Scan(&districtName, &count); err != nil {
+			return nil, err
+		}
+		name := "Поза районами"
+		if districtName != nil {
+			name = *districtName
+		}
+		byDistrict[name] = count
+	}
+	stats["by_district"] = byDistrict
+
+	// By tag; an appeal with several tags is counted under each of them
+	tagQuery := fmt.Sprintf(`
+		SELECT t.name, COUNT(a.id)
+		FROM appeals a
+		JOIN appeal_tags at ON at.appeal_id = a.id
+		JOIN tags t ON at.tag_id = t.id
+		WHERE %s
+		GROUP BY t.name
+		ORDER BY COUNT(a.id) DESC
+	`, whereClauseWithAlias)
+	rows, err = r.db.Query(ctx, tagQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag stats: %w", err)
+	}
+	defer rows.Close()
+
+	byTag := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var count int64
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, err
+		}
+		byTag[name] = count
+	}
+	stats["by_tag"] = byTag
+
 	// By executor - removed, appeals are assigned to services, not executors
 
 	// Daily trend (last 30 days)
@@ -746,7 +2085,7 @@ func (r *AppealRepository) GetStatistics(ctx context.Context, fromDate, toDate *
 	var totalCompleted int64
 	onTimeQuery := fmt.Sprintf(`
 		SELECT 
-			COUNT(*) FILTER (WHERE closed_at IS NOT NULL AND EXTRACT(EPOCH FROM (closed_at - created_at))/86400 <= 30) as on_time,
+			COUNT(*) FILTER (WHERE completed_at IS NOT NULL AND EXTRACT(EPOCH FROM (completed_at - created_at))/86400 <= 30) as on_time,
 			COUNT(*) FILTER (WHERE status IN ('closed', 'completed')) as total_completed
 		FROM appeals
 		WHERE status IN ('closed', 'completed') AND %s
@@ -906,6 +2245,14 @@ func (r *AppealRepository) GetDispatcherDashboard(ctx context.Context) (map[stri
 	}
 	dashboard["approaching_appeals"] = approachingAppeals
 
+	// Appeals that breached their SLA deadlines
+	var slaBreachCount int64
+	err = r.db.QueryRow(ctx, fmt.Sprintf("SELECT COUNT(*) FROM appeals a WHERE a.parent_id IS NULL AND %s", slaBreachCondition)).Scan(&slaBreachCount)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count sla breaches: %w", err)
+	}
+	dashboard["sla_breach_count"] = slaBreachCount
+
 	return dashboard, nil
 }
 
@@ -915,17 +2262,17 @@ func (r *AppealRepository) GetAdminDashboard(ctx context.Context) (map[string]in
 
 	// Top services by processing speed (average time from creation to closure)
 	// Include both 'closed' and 'completed' statuses
-	// Use closed_at if available, otherwise use updated_at for completed appeals
+	// Use completed_at if available, otherwise use updated_at
 	serviceSpeedQuery := `
 		SELECT 
 			s.name,
 			COUNT(a.id) as total_appeals,
-			AVG(EXTRACT(EPOCH FROM (COALESCE(a.closed_at, a.updated_at) - a.created_at))/3600) as avg_hours
+			AVG(EXTRACT(EPOCH FROM (COALESCE(a.completed_at, a.updated_at) - a.created_at))/3600) as avg_hours
 		FROM appeals a
 		INNER JOIN services s ON a.service_id = s.id
 		WHERE a.status IN ('closed', 'completed')
-			AND (a.closed_at IS NOT NULL OR a.updated_at IS NOT NULL)
-			AND COALESCE(a.closed_at, a.updated_at) >= NOW() - INTERVAL '90 days'
+			AND (a.completed_at IS NOT NULL OR a.updated_at IS NOT NULL)
+			AND COALESCE(a.completed_at, a.updated_at) >= NOW() - INTERVAL '90 days'
 		GROUP BY s.id, s.name
 		HAVING COUNT(a.id) >= 1
 		ORDER BY avg_hours ASC
@@ -1035,12 +2382,12 @@ func (r *AppealRepository) GetAdminDashboard(ctx context.Context) (map[string]in
 			COUNT(a.id) FILTER (WHERE a.status IN ('completed', 'closed')) as completed_count,
 			COUNT(a.id) FILTER (WHERE a.status NOT IN ('closed', 'rejected') 
 				AND a.created_at < NOW() - INTERVAL '30 days') as overdue_count,
-			AVG(EXTRACT(EPOCH FROM (COALESCE(a.closed_at, a.updated_at) - a.created_at))/3600) 
+			AVG(EXTRACT(EPOCH FROM (COALESCE(a.completed_at, a.updated_at) - a.created_at))/3600) 
 				FILTER (WHERE a.status IN ('closed', 'completed') 
-					AND (a.closed_at IS NOT NULL OR a.updated_at IS NOT NULL)) as avg_hours,
+					AND (a.completed_at IS NOT NULL OR a.updated_at IS NOT NULL)) as avg_hours,
 			COUNT(a.id) FILTER (WHERE a.status IN ('closed', 'completed') 
-				AND a.closed_at IS NOT NULL 
-				AND EXTRACT(EPOCH FROM (a.closed_at - a.created_at))/86400 <= 30) as on_time_count,
+				AND a.completed_at IS NOT NULL 
+				AND EXTRACT(EPOCH FROM (a.completed_at - a.created_at))/86400 <= 30) as on_time_count,
 			COUNT(a.id) FILTER (WHERE a.status IN ('closed', 'completed')) as total_completed
 		FROM services s
 		LEFT JOIN appeals a ON s.id = a.service_id
@@ -1135,6 +2482,8 @@ func (r *AppealRepository) GetExecutorDashboard(ctx context.Context, userID int6
 
 	if len(serviceIDs) == 0 {
 		dashboard["active_appeals"] = []map[string]interface{}{}
+		dashboard["my_appeals"] = []map[string]interface{}{}
+		dashboard["unclaimed_appeals"] = []map[string]interface{}{}
 		dashboard["my_avg_processing_time"] = 0.0
 		dashboard["service_avg_processing_time"] = 0.0
 		return dashboard, nil
@@ -1151,7 +2500,7 @@ func (r *AppealRepository) GetExecutorDashboard(ctx context.Context, userID int6
 	}
 
 	activeQuery := fmt.Sprintf(`
-		SELECT id, title, status, created_at, service_id, priority
+		SELECT id, title, status, created_at, service_id, priority, assignee_id
 		FROM appeals
 		WHERE service_id IN (%s)
 			AND status NOT IN ('closed', 'rejected')
@@ -1165,13 +2514,15 @@ func (r *AppealRepository) GetExecutorDashboard(ctx context.Context, userID int6
 	defer rows.Close()
 
 	activeAppeals := []map[string]interface{}{}
+	myAppeals := []map[string]interface{}{}
+	unclaimedAppeals := []map[string]interface{}{}
 	for rows.Next() {
 		var id, serviceID int64
 		var title, status string
 		var createdAt time.Time
 		var priority int
-		var serviceIDPtr *int64
-		err := rows.Scan(&id, &title, &status, &createdAt, &serviceIDPtr, &priority)
+		var serviceIDPtr, assigneeID *int64
+		err := rows.Scan(&id, &title, &status, &createdAt, &serviceIDPtr, &priority, &assigneeID)
 		if err != nil {
 			continue
 		}
@@ -1179,7 +2530,7 @@ func (r *AppealRepository) GetExecutorDashboard(ctx context.Context, userID int6
 			serviceID = *serviceIDPtr
 		}
 		daysSinceCreation := int(time.Since(createdAt).Hours() / 24)
-		activeAppeals = append(activeAppeals, map[string]interface{}{
+		item := map[string]interface{}{
 			"id":                  id,
 			"title":               title,
 			"status":              status,
@@ -1187,20 +2538,32 @@ func (r *AppealRepository) GetExecutorDashboard(ctx context.Context, userID int6
 			"service_id":          serviceID,
 			"service_name":        serviceNames[serviceID],
 			"priority":            priority,
+			"assignee_id":         assigneeID,
 			"days_since_creation": daysSinceCreation,
-		})
+		}
+		activeAppeals = append(activeAppeals, item)
+
+		// Split the service queue into my own work and appeals nobody has claimed yet
+		switch {
+		case assigneeID != nil && *assigneeID == userID:
+			myAppeals = append(myAppeals, item)
+		case assigneeID == nil && status != string(models.StatusCompleted):
+			unclaimedAppeals = append(unclaimedAppeals, item)
+		}
 	}
 	dashboard["active_appeals"] = activeAppeals
+	dashboard["my_appeals"] = myAppeals
+	dashboard["unclaimed_appeals"] = unclaimedAppeals
 
 	// My average processing time (appeals where I changed status to completed/closed) - in days
 	myAvgQuery := `
-		SELECT AVG(EXTRACT(EPOCH FROM (a.closed_at - a.created_at))/86400)
+		SELECT AVG(EXTRACT(EPOCH FROM (a.completed_at - a.created_at))/86400)
 		FROM appeals a
 		INNER JOIN appeal_history ah ON a.id = ah.appeal_id
 		WHERE ah.user_id = $1
 			AND ah.new_status IN ('completed', 'closed')
-			AND a.closed_at IS NOT NULL
-			AND a.closed_at >= NOW() - INTERVAL '90 days'
+			AND a.completed_at IS NOT NULL
+			AND a.completed_at >= NOW() - INTERVAL '90 days'
 	`
 	var myAvgDays *float64
 	err = r.db.QueryRow(ctx, myAvgQuery, userID).Scan(&myAvgDays)
@@ -1215,12 +2578,12 @@ func (r *AppealRepository) GetExecutorDashboard(ctx context.Context, userID int6
 
 	// Service average processing time - in days
 	serviceAvgQuery := fmt.Sprintf(`
-		SELECT AVG(EXTRACT(EPOCH FROM (closed_at - created_at))/86400)
+		SELECT AVG(EXTRACT(EPOCH FROM (completed_at - created_at))/86400)
 		FROM appeals
 		WHERE service_id IN (%s)
 			AND status IN ('closed', 'completed')
-			AND closed_at IS NOT NULL
-			AND closed_at >= NOW() - INTERVAL '90 days'
+			AND completed_at IS NOT NULL
+			AND completed_at >= NOW() - INTERVAL '90 days'
 	`, strings.Join(placeholders, ","))
 	var serviceAvgDays *float64
 	err = r.db.QueryRow(ctx, serviceAvgQuery, queryArgs...).Scan(&serviceAvgDays)
@@ -1341,22 +2704,28 @@ func (r *AppealRepository) GetServiceStatistics(ctx context.Context, serviceID i
 			COUNT(a.id) FILTER (WHERE a.status = 'rejected') as rejected_count,
 			COUNT(a.id) FILTER (WHERE a.status NOT IN ('closed', 'rejected') 
 				AND a.created_at < NOW() - INTERVAL '30 days') as overdue_count,
-			AVG(EXTRACT(EPOCH FROM (COALESCE(a.closed_at, a.updated_at) - a.created_at))/86400) 
+			AVG(EXTRACT(EPOCH FROM (COALESCE(a.completed_at, a.updated_at) - a.created_at))/86400) 
 				FILTER (WHERE a.status IN ('closed', 'completed') 
-					AND (a.closed_at IS NOT NULL OR a.updated_at IS NOT NULL)) as avg_days,
+					AND (a.completed_at IS NOT NULL OR a.updated_at IS NOT NULL)) as avg_days,
 			COUNT(a.id) FILTER (WHERE a.status IN ('closed', 'completed') 
-				AND a.closed_at IS NOT NULL 
-				AND EXTRACT(EPOCH FROM (a.closed_at - a.created_at))/86400 <= 30) as on_time_count,
-			COUNT(a.id) FILTER (WHERE a.status IN ('closed', 'completed')) as total_completed
+				AND a.completed_at IS NOT NULL 
+				AND EXTRACT(EPOCH FROM (a.completed_at - a.created_at))/86400 <= 30) as on_time_count,
+			COUNT(a.id) FILTER (WHERE a.status IN ('closed', 'completed')) as total_completed,
+			COUNT(a.id) FILTER (WHERE a.status = 'reopened') as reopened_count,
+			COUNT(a.id) FILTER (WHERE a.reopen_count > 0) as reopened_appeals,
+			COALESCE(SUM(a.reopen_count), 0) as total_reopens,
+			COUNT(a.id) FILTER (WHERE a.completed_at IS NOT NULL OR a.reopen_count > 0) as ever_completed
 		FROM appeals a
 		WHERE a.service_id = $1
 	`
 	var totalAppeals, newCount, assignedCount, inProgressCount, completedCount, rejectedCount, overdueCount int64
 	var avgDays *float64
 	var onTimeCount, totalCompleted int64
+	var reopenedCount, reopenedAppeals, totalReopens, everCompleted int64
 	err = r.db.QueryRow(ctx, overallStatsQuery, serviceID).Scan(
 		&totalAppeals, &newCount, &assignedCount, &inProgressCount, &completedCount, &rejectedCount,
 		&overdueCount, &avgDays, &onTimeCount, &totalCompleted,
+		&reopenedCount, &reopenedAppeals, &totalReopens, &everCompleted,
 	)
 	if err != nil && err != pgx.ErrNoRows {
 		return nil, fmt.Errorf("failed to get overall stats: %w", err)
@@ -1372,6 +2741,12 @@ func (r *AppealRepository) GetServiceStatistics(ctx context.Context, serviceID i
 		onTimePercentage = float64(onTimeCount) / float64(totalCompleted) * 100
 	}
 
+	// Share of appeals whose result the author rejected at least once
+	reopenRate := 0.0
+	if everCompleted > 0 {
+		reopenRate = float64(reopenedAppeals) / float64(everCompleted) * 100
+	}
+
 	stats["overall"] = map[string]interface{}{
 		"total_appeals":      totalAppeals,
 		"new_count":          newCount,
@@ -1384,6 +2759,10 @@ func (r *AppealRepository) GetServiceStatistics(ctx context.Context, serviceID i
 		"on_time_count":      onTimeCount,
 		"total_completed":    totalCompleted,
 		"on_time_percentage": onTimePercentage,
+		"reopened_count":     reopenedCount,
+		"reopened_appeals":   reopenedAppeals,
+		"total_reopens":      totalReopens,
+		"reopen_rate":        reopenRate,
 	}
 
 	// Monthly trend (last 6 months)
@@ -1445,11 +2824,12 @@ func (r *AppealRepository) GetServiceStatistics(ctx context.Context, serviceID i
 	}
 	stats["status_distribution"] = statusDistribution
 
-	// Category distribution
+	// Category distribution: subcategories count towards their parent
 	categoryDistQuery := `
 		SELECT c.name, COUNT(*) as count
 		FROM appeals a
-		INNER JOIN categories c ON a.category_id = c.id
+		INNER JOIN categories sc ON a.category_id = sc.id
+		INNER JOIN categories c ON COALESCE(sc.parent_id, sc.id) = c.id
 		WHERE a.service_id = $1
 		GROUP BY c.id, c.name
 		ORDER BY count DESC
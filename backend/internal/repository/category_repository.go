@@ -12,6 +12,10 @@ import (
 
 var (
 	ErrCategoryNotFound = errors.New("category not found")
+	ErrCategoryInUse    = errors.New("category has open appeals")
+	// ErrInvalidReassignTarget is returned when open appeals cannot move to the chosen
+	// category or service: it is unknown, inactive or being deleted itself
+	ErrInvalidReassignTarget = errors.New("invalid reassignment target")
 )
 
 type CategoryRepository struct {
@@ -22,11 +26,44 @@ func NewCategoryRepository(db *pgxpool.Pool) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
+// categoryColumns selects a category joined with its parent as p: the default
+// priority of a subcategory without its own is the parent's
+const categoryColumns = `c.id, c.name, c.description, COALESCE(c.default_priority, p.default_priority),
+	c.default_priority IS NULL, c.is_active, c.created_at, c.updated_at, c.custom_fields, c.parent_id`
+
+func scanCategory(row pgx.Row) (*models.Category, error) {
+	var category models.Category
+	err := row.Scan(
+		&category.ID,
+		&category.Name,
+		&category.Description,
+		&category.DefaultPriority,
+		&category.InheritsPriority,
+		&category.IsActive,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+		&category.CustomFields,
+		&category.ParentID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// ownPriority is the default priority stored for a category; NULL makes it inherit the parent's
+func ownPriority(category *models.Category) *int {
+	if category.InheritsPriority {
+		return nil
+	}
+	return &category.DefaultPriority
+}
+
 // Create creates a new category
 func (r *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
 	query := `
-		INSERT INTO categories (name, description, default_priority, is_active)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO categories (name, description, default_priority, is_active, custom_fields, parent_id)
+		VALUES ($1, $2, $3, $4, COALESCE($5, '[]'::JSONB), $6)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -35,8 +72,10 @@ func (r *CategoryRepository) Create(ctx context.Context, category *models.Catego
 		query,
 		category.Name,
 		category.Description,
-		category.DefaultPriority,
+		ownPriority(category),
 		category.IsActive,
+		category.CustomFields,
+		category.ParentID,
 	).Scan(&category.ID, &category.CreatedAt, &category.UpdatedAt)
 
 	if err != nil {
@@ -49,22 +88,13 @@ func (r *CategoryRepository) Create(ctx context.Context, category *models.Catego
 // GetByID retrieves a category by ID
 func (r *CategoryRepository) GetByID(ctx context.Context, id int64) (*models.Category, error) {
 	query := `
-		SELECT id, name, description, default_priority, is_active, created_at, updated_at
-		FROM categories
-		WHERE id = $1
+		SELECT ` + categoryColumns + `
+		FROM categories c
+		LEFT JOIN categories p ON c.parent_id = p.id
+		WHERE c.id = $1
 	`
 
-	var category models.Category
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&category.ID,
-		&category.Name,
-		&category.Description,
-		&category.DefaultPriority,
-		&category.IsActive,
-		&category.CreatedAt,
-		&category.UpdatedAt,
-	)
-
+	category, err := scanCategory(r.db.QueryRow(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrCategoryNotFound
@@ -72,19 +102,29 @@ func (r *CategoryRepository) GetByID(ctx context.Context, id int64) (*models.Cat
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
 
-	return &category, nil
+	return category, nil
 }
 
-// List retrieves all active categories
+// List retrieves all active categories, top-level and subcategories alike
 func (r *CategoryRepository) List(ctx context.Context, includeInactive bool) ([]*models.Category, error) {
+	return r.list(ctx, "c.is_active = true OR $1", includeInactive)
+}
+
+// ListSubcategories retrieves the active subcategories of a category
+func (r *CategoryRepository) ListSubcategories(ctx context.Context, parentID int64, includeInactive bool) ([]*models.Category, error) {
+	return r.list(ctx, "(c.is_active = true OR $1) AND c.parent_id = $2", includeInactive, parentID)
+}
+
+func (r *CategoryRepository) list(ctx context.Context, where string, args ...interface{}) ([]*models.Category, error) {
 	query := `
-		SELECT id, name, description, default_priority, is_active, created_at, updated_at
-		FROM categories
-		WHERE is_active = true OR $1
-		ORDER BY name
+		SELECT ` + categoryColumns + `
+		FROM categories c
+		LEFT JOIN categories p ON c.parent_id = p.id
+		WHERE ` + where + `
+		ORDER BY c.name
 	`
 
-	rows, err := r.db.Query(ctx, query, includeInactive)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list categories: %w", err)
 	}
@@ -92,23 +132,24 @@ func (r *CategoryRepository) List(ctx context.Context, includeInactive bool) ([]
 
 	categories := make([]*models.Category, 0)
 	for rows.Next() {
-		var category models.Category
-		err := rows.Scan(
-			&category.ID,
-			&category.Name,
-			&category.Description,
-			&category.DefaultPriority,
-			&category.IsActive,
-			&category.CreatedAt,
-			&category.UpdatedAt,
-		)
+		category, err := scanCategory(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan category: %w", err)
 		}
-		categories = append(categories, &category)
+		categories = append(categories, category)
 	}
 
-	return categories, nil
+	return categories, rows.Err()
+}
+
+// HasSubcategories reports whether any category, active or not, has the category as its parent
+func (r *CategoryRepository) HasSubcategories(ctx context.Context, id int64) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM categories WHERE parent_id = $1)", id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check subcategories: %w", err)
+	}
+	return exists, nil
 }
 
 // Update updates a category
@@ -116,7 +157,7 @@ func (r *CategoryRepository) Update(ctx context.Context, category *models.Catego
 	query := `
 		UPDATE categories
 		SET name = $1, description = $2, default_priority = $3,
-		    is_active = $4, updated_at = NOW()
+		    is_active = $4, custom_fields = COALESCE($6, '[]'::JSONB), parent_id = $7, updated_at = NOW()
 		WHERE id = $5
 	`
 
@@ -125,9 +166,11 @@ func (r *CategoryRepository) Update(ctx context.Context, category *models.Catego
 		query,
 		category.Name,
 		category.Description,
-		category.DefaultPriority,
+		ownPriority(category),
 		category.IsActive,
 		category.ID,
+		category.CustomFields,
+		category.ParentID,
 	)
 
 	if err != nil {
@@ -141,18 +184,106 @@ func (r *CategoryRepository) Update(ctx context.Context, category *models.Catego
 	return nil
 }
 
-// Delete soft deletes a category
-func (r *CategoryRepository) Delete(ctx context.Context, id int64) error {
-	query := `UPDATE categories SET is_active = false, updated_at = NOW() WHERE id = $1`
+// Delete archives a category together with its subcategories. Closed and rejected appeals
+// keep the category, so historical statistics stay intact. Appeals still in work block the
+// deletion unless reassignTo names an active category to move them to; they move in the same
+// transaction, each with a history entry. It returns how many appeals were moved.
+func (r *CategoryRepository) Delete(ctx context.Context, id int64, reassignTo *int64, userID int64) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-	result, err := r.db.Exec(ctx, query, id)
+	var name string
+	err = tx.QueryRow(ctx, "SELECT name FROM categories WHERE id = $1 FOR UPDATE", id).Scan(&name)
 	if err != nil {
-		return fmt.Errorf("failed to delete category: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrCategoryNotFound
+		}
+		return 0, fmt.Errorf("failed to get category: %w", err)
 	}
 
-	if result.RowsAffected() == 0 {
-		return ErrCategoryNotFound
+	var moved int64
+	if reassignTo == nil {
+		var open int64
+		err := tx.QueryRow(ctx, `
+			SELECT COUNT(*)
+			FROM appeals a
+			JOIN categories c ON a.category_id = c.id
+			WHERE (c.id = $1 OR c.parent_id = $1) AND a.status NOT IN ('closed', 'rejected')
+		`, id).Scan(&open)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count open appeals: %w", err)
+		}
+		if open > 0 {
+			return 0, fmt.Errorf("%w (%d)", ErrCategoryInUse, open)
+		}
+	} else {
+		var targetName string
+		err := tx.QueryRow(ctx, `
+			SELECT name FROM categories
+			WHERE id = $2 AND is_active = true AND id <> $1 AND parent_id IS DISTINCT FROM $1
+		`, id, *reassignTo).Scan(&targetName)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return 0, ErrInvalidReassignTarget
+			}
+			return 0, fmt.Errorf("failed to get target category: %w", err)
+		}
+
+		// The history entry names the category or subcategory each appeal leaves
+		rows, err := tx.Query(ctx, "SELECT id, name FROM categories WHERE id = $1 OR parent_id = $1", id)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get subcategories: %w", err)
+		}
+		var sourceIDs []int64
+		var actions []string
+		for rows.Next() {
+			var sourceID int64
+			var sourceName string
+			if err := rows.Scan(&sourceID, &sourceName); err != nil {
+				rows.Close()
+				return 0, fmt.Errorf("failed to scan category: %w", err)
+			}
+			sourceIDs = append(sourceIDs, sourceID)
+			actions = append(actions, categoryReassignAction(sourceName, targetName))
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("failed to get subcategories: %w", err)
+		}
+
+		query := `
+			WITH moved AS (
+				UPDATE appeals a
+				SET category_id = $2, updated_at = NOW()
+				FROM categories c
+				WHERE a.category_id = c.id
+				  AND (c.id = $1 OR c.parent_id = $1)
+				  AND a.status NOT IN ('closed', 'rejected')
+				RETURNING a.id, a.status, c.id AS source_id
+			), history AS (
+				INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
+				SELECT moved.id, $3, moved.status, moved.status, act.action
+				FROM moved
+				JOIN unnest($4::BIGINT[], $5::TEXT[]) AS act(source_id, action) ON act.source_id = moved.source_id
+			)
+			SELECT COUNT(*) FROM moved
+		`
+		if err := tx.QueryRow(ctx, query, id, *reassignTo, userID, sourceIDs, actions).Scan(&moved); err != nil {
+			return 0, fmt.Errorf("failed to reassign appeals: %w", err)
+		}
 	}
 
-	return nil
+	_, err = tx.Exec(ctx, "UPDATE categories SET is_active = false, updated_at = NOW() WHERE id = $1 OR parent_id = $1", id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete category: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return moved, nil
 }
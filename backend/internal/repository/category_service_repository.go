@@ -21,14 +21,23 @@ func NewCategoryServiceRepository(db *pgxpool.Pool) *CategoryServiceRepository {
 	return &CategoryServiceRepository{db: db}
 }
 
-// GetByCategoryID retrieves all services assigned to a category
+// GetByCategoryID retrieves all services assigned to a category. A subcategory without
+// services of its own gets the parent's, which keep the parent's category ID.
 func (r *CategoryServiceRepository) GetByCategoryID(ctx context.Context, categoryID int64) ([]*models.CategoryService, error) {
 	query := `
 		SELECT cs.id, cs.category_id, cs.service_id, cs.created_at,
 		       s.id, s.name, s.description, s.contact_person, s.contact_phone, s.contact_email, s.is_active
 		FROM category_services cs
 		JOIN services s ON cs.service_id = s.id
-		WHERE cs.category_id = $1 AND s.is_active = true
+		WHERE cs.category_id = (
+			SELECT CASE
+				WHEN c.parent_id IS NULL OR EXISTS (SELECT 1 FROM category_services own WHERE own.category_id = c.id)
+				THEN c.id
+				ELSE c.parent_id
+			END
+			FROM categories c
+			WHERE c.id = $1
+		) AND s.is_active = true
 		ORDER BY cs.id ASC
 	`
 
@@ -63,9 +72,10 @@ func (r *CategoryServiceRepository) GetByCategoryID(ctx context.Context, categor
 func (r *CategoryServiceRepository) GetByServiceID(ctx context.Context, serviceID int64) ([]*models.CategoryService, error) {
 	query := `
 		SELECT cs.id, cs.category_id, cs.service_id, cs.created_at,
-		       c.id, c.name, c.description, c.default_priority, c.is_active
+		       c.id, c.name, c.description, COALESCE(c.default_priority, p.default_priority), c.is_active
 		FROM category_services cs
 		JOIN categories c ON cs.category_id = c.id
+		LEFT JOIN categories p ON c.parent_id = p.id
 		WHERE cs.service_id = $1 AND c.is_active = true
 		ORDER BY cs.id ASC
 	`
@@ -113,8 +123,9 @@ func (r *CategoryServiceRepository) GetAllWithDetails(ctx context.Context) ([]*m
 		}
 
 		result = append(result, &models.CategoryWithServices{
-			Category: category,
-			Services: services,
+			Category:         category,
+			Services:         services,
+			InheritsServices: len(services) > 0 && services[0].CategoryID != category.ID,
 		})
 	}
 
@@ -22,15 +22,21 @@ func NewCommentRepository(db *pgxpool.Pool) *CommentRepository {
 	return &CommentRepository{db: db}
 }
 
-// Create creates a new comment
-func (r *CommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+// Create creates a new comment and attaches the author's staged photos to it in one transaction
+func (r *CommentRepository) Create(ctx context.Context, comment *models.Comment, photoIDs []int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
 		INSERT INTO comments (appeal_id, user_id, text, is_internal)
 		VALUES ($1, $2, $3, $4)
 		RETURNING id, created_at
 	`
 
-	err := r.db.QueryRow(
+	err = tx.QueryRow(
 		ctx,
 		query,
 		comment.AppealID,
@@ -43,7 +49,11 @@ func (r *CommentRepository) Create(ctx context.Context, comment *models.Comment)
 		return fmt.Errorf("failed to create comment: %w", err)
 	}
 
-	return nil
+	if err := claimStagedPhotos(ctx, tx, photoIDs, comment.UserID, comment.AppealID, &comment.ID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 // GetByID retrieves a comment by ID with user information
@@ -73,6 +83,9 @@ func (r *CommentRepository) GetByID(ctx context.Context, id int64) (*models.Comm
 	}
 
 	comment.User = &user
+	if err := r.attachPhotos(ctx, []*models.Comment{&comment}); err != nil {
+		return nil, err
+	}
 	return &comment, nil
 }
 
@@ -128,10 +141,53 @@ func (r *CommentRepository) GetByAppealID(ctx context.Context, appealID int64, u
 		comment.User = &user
 		comments = append(comments, &comment)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read comments: %w", err)
+	}
+
+	if err := r.attachPhotos(ctx, comments); err != nil {
+		return nil, err
+	}
 
 	return comments, nil
 }
 
+// attachPhotos fills in the photos of the comments
+func (r *CommentRepository) attachPhotos(ctx context.Context, comments []*models.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*models.Comment, len(comments))
+	ids := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		byID[comment.ID] = comment
+		ids = append(ids, comment.ID)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+photoColumns+`
+		FROM photos
+		WHERE comment_id = ANY($1)
+		ORDER BY uploaded_at ASC
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get comment photos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		photo, err := scanPhoto(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan photo: %w", err)
+		}
+		comment := byID[*photo.CommentID]
+		comment.Photos = append(comment.Photos, *photo)
+	}
+
+	return rows.Err()
+}
+
 // Update updates a comment
 func (r *CommentRepository) Update(ctx context.Context, comment *models.Comment) error {
 	query := `
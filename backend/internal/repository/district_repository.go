@@ -0,0 +1,356 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/pkg/geo"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrDistrictNotFound    = errors.New("district not found")
+	ErrDistrictExists      = errors.New("district with this name already exists")
+	ErrRoutingRuleNotFound = errors.New("routing rule not found")
+	ErrRoutingRuleExists   = errors.New("routing rule for this category and district already exists")
+	ErrRoutingRuleTarget   = errors.New("category, district or service of the routing rule does not exist")
+)
+
+type DistrictRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewDistrictRepository(db *pgxpool.Pool) *DistrictRepository {
+	return &DistrictRepository{db: db}
+}
+
+// Create creates a new district; the bounding box must already be set
+func (r *DistrictRepository) Create(ctx context.Context, district *models.District) error {
+	query := `
+		INSERT INTO districts (name, geometry, min_lat, min_lng, max_lat, max_lng, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		district.Name,
+		district.Geometry,
+		district.MinLat,
+		district.MinLng,
+		district.MaxLat,
+		district.MaxLng,
+		district.IsActive,
+	).Scan(&district.ID, &district.CreatedAt, &district.UpdatedAt)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDistrictExists
+		}
+		return fmt.Errorf("failed to create district: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a district with its geometry
+func (r *DistrictRepository) GetByID(ctx context.Context, id int64) (*models.District, error) {
+	return r.getOne(ctx, `WHERE id = $1`, id)
+}
+
+// GetByName retrieves a district with its geometry by name
+func (r *DistrictRepository) GetByName(ctx context.Context, name string) (*models.District, error) {
+	return r.getOne(ctx, `WHERE name = $1`, name)
+}
+
+func (r *DistrictRepository) getOne(ctx context.Context, where string, arg interface{}) (*models.District, error) {
+	query := `
+		SELECT id, name, geometry, min_lat, min_lng, max_lat, max_lng, is_active, created_at, updated_at
+		FROM districts
+	` + where
+
+	var district models.District
+	err := r.db.QueryRow(ctx, query, arg).Scan(
+		&district.ID,
+		&district.Name,
+		&district.Geometry,
+		&district.MinLat,
+		&district.MinLng,
+		&district.MaxLat,
+		&district.MaxLng,
+		&district.IsActive,
+		&district.CreatedAt,
+		&district.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrDistrictNotFound
+		}
+		return nil, fmt.Errorf("failed to get district: %w", err)
+	}
+
+	return &district, nil
+}
+
+// List retrieves districts ordered by name. Geometry is only loaded when asked for,
+// since the polygons can be large.
+func (r *DistrictRepository) List(ctx context.Context, activeOnly, withGeometry bool) ([]*models.District, error) {
+	query := `
+		SELECT id, name, CASE WHEN $2 THEN geometry END, min_lat, min_lng, max_lat, max_lng,
+		       is_active, created_at, updated_at
+		FROM districts
+		WHERE is_active = true OR NOT $1
+		ORDER BY name
+	`
+
+	rows, err := r.db.Query(ctx, query, activeOnly, withGeometry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list districts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDistricts(rows)
+}
+
+// ListCandidates retrieves active districts whose bounding box contains the point.
+// The caller still has to check the polygons.
+func (r *DistrictRepository) ListCandidates(ctx context.Context, point geo.Point) ([]*models.District, error) {
+	query := `
+		SELECT id, name, geometry, min_lat, min_lng, max_lat, max_lng, is_active, created_at, updated_at
+		FROM districts
+		WHERE is_active = true
+		  AND $1 BETWEEN min_lat AND max_lat
+		  AND $2 BETWEEN min_lng AND max_lng
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query, point.Lat, point.Lng)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find districts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanDistricts(rows)
+}
+
+func scanDistricts(rows pgx.Rows) ([]*models.District, error) {
+	districts := make([]*models.District, 0)
+	for rows.Next() {
+		var district models.District
+		err := rows.Scan(
+			&district.ID,
+			&district.Name,
+			&district.Geometry,
+			&district.MinLat,
+			&district.MinLng,
+			&district.MaxLat,
+			&district.MaxLng,
+			&district.IsActive,
+			&district.CreatedAt,
+			&district.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan district: %w", err)
+		}
+		districts = append(districts, &district)
+	}
+
+	return districts, nil
+}
+
+// Update updates a district's name, geometry, bounding box and status
+func (r *DistrictRepository) Update(ctx context.Context, district *models.District) error {
+	query := `
+		UPDATE districts
+		SET name = $1, geometry = $2, min_lat = $3, min_lng = $4, max_lat = $5, max_lng = $6,
+		    is_active = $7, updated_at = NOW()
+		WHERE id = $8
+	`
+
+	result, err := r.db.Exec(
+		ctx,
+		query,
+		district.Name,
+		district.Geometry,
+		district.MinLat,
+		district.MinLng,
+		district.MaxLat,
+		district.MaxLng,
+		district.IsActive,
+		district.ID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDistrictExists
+		}
+		return fmt.Errorf("failed to update district: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrDistrictNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a district; its appeals lose the district and its routing rules are removed
+func (r *DistrictRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM districts WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete district: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrDistrictNotFound
+	}
+
+	return nil
+}
+
+// CreateRoutingRule creates a new routing rule
+func (r *DistrictRepository) CreateRoutingRule(ctx context.Context, rule *models.RoutingRule) error {
+	query := `
+		INSERT INTO routing_rules (category_id, district_id, service_id)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query, rule.CategoryID, rule.DistrictID, rule.ServiceID).
+		Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrRoutingRuleExists
+		}
+		if isForeignKeyViolation(err) {
+			return ErrRoutingRuleTarget
+		}
+		return fmt.Errorf("failed to create routing rule: %w", err)
+	}
+
+	return nil
+}
+
+// ListRoutingRules retrieves routing rules, optionally of one district
+func (r *DistrictRepository) ListRoutingRules(ctx context.Context, districtID *int64) ([]*models.RoutingRule, error) {
+	query := `
+		SELECT rr.id, rr.category_id, rr.district_id, rr.service_id, rr.created_at, rr.updated_at,
+		       c.name, d.name, s.name
+		FROM routing_rules rr
+		LEFT JOIN categories c ON rr.category_id = c.id
+		JOIN districts d ON rr.district_id = d.id
+		JOIN services s ON rr.service_id = s.id
+		WHERE $1::BIGINT IS NULL OR rr.district_id = $1
+		ORDER BY d.name, c.name NULLS FIRST
+	`
+
+	rows, err := r.db.Query(ctx, query, districtID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list routing rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]*models.RoutingRule, 0)
+	for rows.Next() {
+		var rule models.RoutingRule
+		var categoryName *string
+		var districtName, serviceName string
+		err := rows.Scan(
+			&rule.ID,
+			&rule.CategoryID,
+			&rule.DistrictID,
+			&rule.ServiceID,
+			&rule.CreatedAt,
+			&rule.UpdatedAt,
+			&categoryName,
+			&districtName,
+			&serviceName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan routing rule: %w", err)
+		}
+		if rule.CategoryID != nil && categoryName != nil {
+			rule.Category = &models.Category{ID: *rule.CategoryID, Name: *categoryName}
+		}
+		rule.District = &models.District{ID: rule.DistrictID, Name: districtName}
+		rule.Service = &models.Service{ID: rule.ServiceID, Name: serviceName}
+		rules = append(rules, &rule)
+	}
+
+	return rules, nil
+}
+
+// UpdateRoutingRule changes the service a rule routes to
+func (r *DistrictRepository) UpdateRoutingRule(ctx context.Context, id, serviceID int64) error {
+	result, err := r.db.Exec(ctx, `UPDATE routing_rules SET service_id = $1, updated_at = NOW() WHERE id = $2`, serviceID, id)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrRoutingRuleTarget
+		}
+		return fmt.Errorf("failed to update routing rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrRoutingRuleNotFound
+	}
+
+	return nil
+}
+
+// DeleteRoutingRule deletes a routing rule
+func (r *DistrictRepository) DeleteRoutingRule(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM routing_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete routing rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrRoutingRuleNotFound
+	}
+
+	return nil
+}
+
+// FindRoute returns the service for a category in a district. A rule for the category
+// wins over a rule for its parent category, which wins over the district-wide rule;
+// nil means no rule matches.
+func (r *DistrictRepository) FindRoute(ctx context.Context, categoryID *int64, districtID int64) (*int64, error) {
+	query := `
+		SELECT rr.service_id
+		FROM routing_rules rr
+		JOIN services s ON rr.service_id = s.id
+		WHERE rr.district_id = $1
+		  AND (rr.category_id IS NULL OR rr.category_id = $2
+		       OR rr.category_id = (SELECT c.parent_id FROM categories c WHERE c.id = $2))
+		  AND s.is_active = true
+		ORDER BY rr.category_id = $2 DESC NULLS LAST
+		LIMIT 1
+	`
+
+	var serviceID int64
+	err := r.db.QueryRow(ctx, query, districtID, categoryID).Scan(&serviceID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find routing rule: %w", err)
+	}
+
+	return &serviceID, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" // unique_violation
+}
+
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503" // foreign_key_violation
+}
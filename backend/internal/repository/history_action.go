@@ -0,0 +1,18 @@
+package repository
+
+import "fmt"
+
+// categoryReassignAction is the history entry of an appeal moved off an archived category
+func categoryReassignAction(from, to string) string {
+	return fmt.Sprintf("Категорію змінено: «%s» → «%s» (стару архівовано)", from, to)
+}
+
+// serviceReassignAction is the history entry of an appeal moved off an archived service;
+// the appeal loses its executor, who works for the archived service
+func serviceReassignAction(from, to string, assigneeRemoved bool) string {
+	action := fmt.Sprintf("Службу змінено: «%s» → «%s» (стару архівовано)", from, to)
+	if assigneeRemoved {
+		action += "; виконавця знято"
+	}
+	return action
+}
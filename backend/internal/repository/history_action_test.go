@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryReassignAction(t *testing.T) {
+	action := categoryReassignAction("Благоустрій та Довкілля", "Інфраструктура та Мережі")
+	assert.Equal(t, "Категорію змінено: «Благоустрій та Довкілля» → «Інфраструктура та Мережі» (стару архівовано)", action)
+	assert.LessOrEqual(t, utf8.RuneCountInString(action), 100, "seeded names fit the old VARCHAR(100) column")
+
+	action = categoryReassignAction("Прибирання та вивезення сміття", "Інфраструктура та Мережі")
+	assert.Contains(t, action, "«Прибирання та вивезення сміття»", "names the subcategory the appeal leaves")
+}
+
+func TestServiceReassignAction(t *testing.T) {
+	assert.Equal(t,
+		"Службу змінено: «Громадський транспорт» → «Муніципальна варта» (стару архівовано)",
+		serviceReassignAction("Громадський транспорт", "Муніципальна варта", false))
+
+	action := serviceReassignAction("Громадський транспорт", "Муніципальна варта", true)
+	assert.Equal(t, "Службу змінено: «Громадський транспорт» → «Муніципальна варта» (стару архівовано); виконавця знято", action)
+	assert.LessOrEqual(t, utf8.RuneCountInString(action), 100)
+}
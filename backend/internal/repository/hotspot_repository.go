@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type HotspotRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewHotspotRepository(db *pgxpool.Pool) *HotspotRepository {
+	return &HotspotRepository{db: db}
+}
+
+// ListPoints retrieves locations of appeals created since the given time, optionally
+// of one category. Appeals merged into a master appeal are one incident and skipped.
+func (r *HotspotRepository) ListPoints(ctx context.Context, categoryID *int64, since time.Time) ([]*models.HotspotPoint, error) {
+	query := `
+		SELECT id, category_id, latitude, longitude, created_at
+		FROM appeals
+		WHERE parent_id IS NULL
+		  AND created_at >= $1
+		  AND ($2::BIGINT IS NULL OR category_id = $2)
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query, since, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hotspot points: %w", err)
+	}
+	defer rows.Close()
+
+	points := make([]*models.HotspotPoint, 0)
+	for rows.Next() {
+		var p models.HotspotPoint
+		if err := rows.Scan(&p.AppealID, &p.CategoryID, &p.Latitude, &p.Longitude, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan hotspot point: %w", err)
+		}
+		points = append(points, &p)
+	}
+
+	return points, nil
+}
+
+// Replace stores the hotspots of a run, replacing earlier results of the same scope
+func (r *HotspotRepository) Replace(ctx context.Context, categoryID *int64, windowDays int, hotspots []*models.Hotspot) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM hotspots
+		WHERE COALESCE(category_id, 0) = COALESCE($1::BIGINT, 0) AND window_days = $2
+	`, categoryID, windowDays)
+	if err != nil {
+		return fmt.Errorf("failed to delete old hotspots: %w", err)
+	}
+
+	query := `
+		INSERT INTO hotspots (
+			category_id, window_days, centroid_lat, centroid_lng, radius_meters, appeal_count,
+			dominant_category_id, recent_count, previous_count, trend, appeal_ids, computed_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`
+	for _, h := range hotspots {
+		err := tx.QueryRow(
+			ctx,
+			query,
+			h.CategoryID,
+			h.WindowDays,
+			h.CentroidLat,
+			h.CentroidLng,
+			h.RadiusMeters,
+			h.AppealCount,
+			h.DominantCategoryID,
+			h.RecentCount,
+			h.PreviousCount,
+			h.Trend,
+			h.AppealIDs,
+			h.ComputedAt,
+		).Scan(&h.ID)
+		if err != nil {
+			return fmt.Errorf("failed to save hotspot: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves stored hotspots, largest first. Without a window the results of
+// every window are returned.
+func (r *HotspotRepository) List(ctx context.Context, categoryID *int64, windowDays *int) ([]*models.Hotspot, error) {
+	query := `
+		SELECT h.id, h.category_id, h.window_days, h.centroid_lat, h.centroid_lng, h.radius_meters,
+		       h.appeal_count, h.dominant_category_id, h.recent_count, h.previous_count, h.trend,
+		       h.appeal_ids, h.computed_at, c.name, dc.name
+		FROM hotspots h
+		LEFT JOIN categories c ON h.category_id = c.id
+		LEFT JOIN categories dc ON h.dominant_category_id = dc.id
+		WHERE COALESCE(h.category_id, 0) = COALESCE($1::BIGINT, 0)
+		  AND ($2::INT IS NULL OR h.window_days = $2)
+		ORDER BY h.appeal_count DESC, h.id
+	`
+
+	rows, err := r.db.Query(ctx, query, categoryID, windowDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hotspots: %w", err)
+	}
+	defer rows.Close()
+
+	hotspots := make([]*models.Hotspot, 0)
+	for rows.Next() {
+		var h models.Hotspot
+		var categoryName, dominantName *string
+		err := rows.Scan(
+			&h.ID,
+			&h.CategoryID,
+			&h.WindowDays,
+			&h.CentroidLat,
+			&h.CentroidLng,
+			&h.RadiusMeters,
+			&h.AppealCount,
+			&h.DominantCategoryID,
+			&h.RecentCount,
+			&h.PreviousCount,
+			&h.Trend,
+			&h.AppealIDs,
+			&h.ComputedAt,
+			&categoryName,
+			&dominantName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan hotspot: %w", err)
+		}
+		if h.CategoryID != nil && categoryName != nil {
+			h.Category = &models.Category{ID: *h.CategoryID, Name: *categoryName}
+		}
+		if h.DominantCategoryID != nil && dominantName != nil {
+			h.DominantCategory = &models.Category{ID: *h.DominantCategoryID, Name: *dominantName}
+		}
+		hotspots = append(hotspots, &h)
+	}
+
+	return hotspots, nil
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/pkg/pagination"
+)
+
+// ErrInvalidCursor is returned when a page is asked for with a cursor that
+// is malformed or was made for another sort order
+var ErrInvalidCursor = pagination.ErrInvalidCursor
+
+type keysetKind int
+
+const (
+	keysetTime keysetKind = iota
+	keysetInt
+	keysetFloat
+	keysetText
+)
+
+// keysetKey is one column of a keyset-paginated order. The last key must be unique
+// (the row id), so that every row has a distinct position.
+type keysetKey struct {
+	// Column is used in ORDER BY and may be a select alias;
+	// Expr is the same value for WHERE, it defaults to Column
+	Column, Expr string
+	Desc         bool
+	Kind         keysetKind
+}
+
+func (k keysetKey) expr() string {
+	if k.Expr != "" {
+		return k.Expr
+	}
+	return k.Column
+}
+
+// keysetOrder builds the ORDER BY clause for keys; a backward page reads the order in reverse
+func keysetOrder(keys []keysetKey, backward bool) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		direction := "ASC"
+		if k.Desc != backward {
+			direction = "DESC"
+		}
+		parts[i] = k.Column + " " + direction
+	}
+	return strings.Join(parts, ", ")
+}
+
+// keysetSignature identifies the order of keys, so a cursor is only used with the order it was made for
+func keysetSignature(keys []keysetKey) string {
+	h := fnv.New32a()
+	h.Write([]byte(keysetOrder(keys, false)))
+	return strconv.FormatUint(uint64(h.Sum32()), 36)
+}
+
+// keysetCondition matches rows after the cursor position in $argStart, $argStart+1, ...
+// (one argument per key). When all keys go the same direction it is a row comparison,
+// (k1, k2) > (v1, v2), which PostgreSQL can answer from a matching index; mixed
+// directions need (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+func keysetCondition(keys []keysetKey, backward bool, argStart int) string {
+	if sameDirection(keys) {
+		columns := make([]string, len(keys))
+		values := make([]string, len(keys))
+		for i, k := range keys {
+			columns[i] = k.expr()
+			values[i] = fmt.Sprintf("$%d", argStart+i)
+		}
+		op := ">"
+		if keys[0].Desc != backward {
+			op = "<"
+		}
+		return fmt.Sprintf("((%s) %s (%s))", strings.Join(columns, ", "), op, strings.Join(values, ", "))
+	}
+
+	alternatives := make([]string, len(keys))
+	for i, k := range keys {
+		conditions := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, fmt.Sprintf("%s = $%d", keys[j].expr(), argStart+j))
+		}
+		op := ">"
+		if k.Desc != backward {
+			op = "<"
+		}
+		conditions = append(conditions, fmt.Sprintf("%s %s $%d", k.expr(), op, argStart+i))
+		alternatives[i] = "(" + strings.Join(conditions, " AND ") + ")"
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// sameDirection reports whether all keys are sorted the same way
+func sameDirection(keys []keysetKey) bool {
+	for _, k := range keys[1:] {
+		if k.Desc != keys[0].Desc {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeKeysetCursor makes a cursor at the row with the given key values
+func encodeKeysetCursor(keys []keysetKey, values []interface{}, backward bool) string {
+	cursor := pagination.Cursor{Order: keysetSignature(keys), Values: make([]string, len(values)), Backward: backward}
+	for i, v := range values {
+		switch v := v.(type) {
+		case time.Time:
+			cursor.Values[i] = v.UTC().Format(time.RFC3339Nano)
+		case float64:
+			cursor.Values[i] = strconv.FormatFloat(v, 'g', -1, 64)
+		default:
+			cursor.Values[i] = fmt.Sprint(v)
+		}
+	}
+	return cursor.Encode()
+}
+
+// decodeKeysetCursor parses a cursor for keys into query arguments, one per key
+func decodeKeysetCursor(keys []keysetKey, s string) ([]interface{}, bool, error) {
+	cursor, err := pagination.Decode(s, keysetSignature(keys), len(keys))
+	if err != nil {
+		return nil, false, err
+	}
+
+	args := make([]interface{}, len(keys))
+	for i, k := range keys {
+		value := cursor.Values[i]
+		switch k.Kind {
+		case keysetTime:
+			args[i], err = time.Parse(time.RFC3339Nano, value)
+		case keysetInt:
+			args[i], err = strconv.ParseInt(value, 10, 64)
+		case keysetFloat:
+			args[i], err = strconv.ParseFloat(value, 64)
+		default:
+			args[i] = value
+		}
+		if err != nil {
+			return nil, false, ErrInvalidCursor
+		}
+	}
+	return args, cursor.Backward, nil
+}
+
+// keysetPage works out the cursors around a page fetched in cursor mode with one row
+// more than limit. It returns how many rows to keep; rows of a backward page must
+// also be reversed. cursorAt makes a cursor at the kept row i (in fetched order).
+func keysetPage(fetched, limit int, fromCursor, backward bool, cursorAt func(i int, backward bool) string) (int, models.PageCursors) {
+	kept := fetched
+	more := fetched > limit
+	if more {
+		kept = limit
+	}
+
+	var cursors models.PageCursors
+	if kept == 0 {
+		return 0, cursors
+	}
+	// A backward page is fetched in reverse: row 0 ends the page, row kept-1 starts it
+	if backward {
+		cursors.Next = cursorAt(0, false)
+		if more {
+			cursors.Prev = cursorAt(kept-1, true)
+		}
+	} else {
+		if more {
+			cursors.Next = cursorAt(kept-1, false)
+		}
+		if fromCursor {
+			cursors.Prev = cursorAt(0, true)
+		}
+	}
+	return kept, cursors
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeysetCondition(t *testing.T) {
+	createdAt := keysetKey{Column: "a.created_at", Desc: true, Kind: keysetTime}
+	id := keysetKey{Column: "a.id", Desc: true, Kind: keysetInt}
+
+	assert.Equal(t, "((a.created_at, a.id) < ($3, $4))", keysetCondition([]keysetKey{createdAt, id}, false, 3),
+		"keys going one way are compared as a row")
+	assert.Equal(t, "((a.created_at, a.id) > ($3, $4))", keysetCondition([]keysetKey{createdAt, id}, true, 3),
+		"a backward page reads the order in reverse")
+
+	priority := keysetKey{Column: "a.priority", Kind: keysetInt}
+	assert.Equal(t, "((a.priority > $1) OR (a.priority = $1 AND a.id < $2))", keysetCondition([]keysetKey{priority, id}, false, 1),
+		"mixed directions need one alternative per key")
+
+	rank := keysetKey{Column: "search_rank", Expr: "ts_rank(a.search_vector, $1)", Desc: true, Kind: keysetFloat}
+	assert.Equal(t, "((ts_rank(a.search_vector, $1), a.created_at, a.id) < ($2, $3, $4))",
+		keysetCondition([]keysetKey{rank, createdAt, id}, false, 2), "select aliases are compared by their expression")
+}
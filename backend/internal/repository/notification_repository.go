@@ -93,6 +93,78 @@ func (r *NotificationRepository) GetByUserID(ctx context.Context, userID int64,
 	}
 	defer rows.Close()
 
+	return scanNotifications(rows)
+}
+
+// notificationSortKeys is the order of a user's notifications: newest first
+var notificationSortKeys = []keysetKey{
+	{Column: "sent_at", Desc: true, Kind: keysetTime},
+	{Column: "id", Desc: true, Kind: keysetInt},
+}
+
+// GetPageByUserID retrieves a page of a user's notifications after (or before) the cursor,
+// with the cursors of the neighbouring pages. An empty cursor is the first page.
+func (r *NotificationRepository) GetPageByUserID(ctx context.Context, userID int64, cursor string, limit int) ([]*models.Notification, models.PageCursors, error) {
+	condition := "user_id = $1"
+	args := []interface{}{userID}
+	backward := false
+	if cursor != "" {
+		cursorArgs, back, err := decodeKeysetCursor(notificationSortKeys, cursor)
+		if err != nil {
+			return nil, models.PageCursors{}, err
+		}
+		condition += " AND " + keysetCondition(notificationSortKeys, back, 2)
+		args = append(args, cursorArgs...)
+		backward = back
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, user_id, appeal_id, type, title, message, is_read, sent_at
+		FROM notifications
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d
+	`, condition, keysetOrder(notificationSortKeys, backward), len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, models.PageCursors{}, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	defer rows.Close()
+
+	notifications, err := scanNotifications(rows)
+	if err != nil {
+		return nil, models.PageCursors{}, err
+	}
+
+	kept, cursors := keysetPage(len(notifications), limit, cursor != "", backward, func(i int, backward bool) string {
+		n := notifications[i]
+		return encodeKeysetCursor(notificationSortKeys, []interface{}{n.SentAt, n.ID}, backward)
+	})
+	notifications = notifications[:kept]
+	if backward {
+		for i, j := 0, len(notifications)-1; i < j; i, j = i+1, j-1 {
+			notifications[i], notifications[j] = notifications[j], notifications[i]
+		}
+	}
+
+	return notifications, cursors, nil
+}
+
+// CountByUserID returns the number of a user's notifications
+func (r *NotificationRepository) CountByUserID(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM notifications WHERE user_id = $1", userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+// scanNotifications reads notification rows selected in GetByID column order
+func scanNotifications(rows pgx.Rows) ([]*models.Notification, error) {
 	notifications := make([]*models.Notification, 0)
 	for rows.Next() {
 		var notification models.Notification
@@ -111,6 +183,9 @@ func (r *NotificationRepository) GetByUserID(ctx context.Context, userID int64,
 		}
 		notifications = append(notifications, &notification)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
 
 	return notifications, nil
 }
@@ -4,14 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"citizen-appeals/internal/models"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"citizen-appeals/internal/models"
 )
 
 var (
 	ErrPhotoNotFound = errors.New("photo not found")
+	// ErrPhotosNotClaimable is returned when listed photos are not staged uploads of the user
+	ErrPhotosNotClaimable = errors.New("photos are not staged uploads of the user")
 )
 
 type PhotoRepository struct {
@@ -22,11 +25,42 @@ func NewPhotoRepository(db *pgxpool.Pool) *PhotoRepository {
 	return &PhotoRepository{db: db}
 }
 
-// Create creates a new photo record
+const photoColumns = `id, appeal_id, comment_id, file_path, file_name, file_size, mime_type, is_result_photo, uploaded_at, uploaded_by,
+	width, height, thumb_path, medium_path`
+
+func scanPhoto(row pgx.Row) (*models.Photo, error) {
+	var photo models.Photo
+	err := row.Scan(
+		&photo.ID,
+		&photo.AppealID,
+		&photo.CommentID,
+		&photo.FilePath,
+		&photo.FileName,
+		&photo.FileSize,
+		&photo.MimeType,
+		&photo.IsResultPhoto,
+		&photo.UploadedAt,
+		&photo.UploadedBy,
+		&photo.Width,
+		&photo.Height,
+		&photo.ThumbPath,
+		&photo.MediumPath,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &photo, nil
+}
+
+// Create creates a new photo record. Without an appeal or comment it is a staged photo
+// of the uploader.
 func (r *PhotoRepository) Create(ctx context.Context, photo *models.Photo) error {
 	query := `
-		INSERT INTO photos (appeal_id, comment_id, file_path, file_name, file_size, mime_type, is_result_photo)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO photos (
+			appeal_id, comment_id, file_path, file_name, file_size, mime_type, is_result_photo, uploaded_by,
+			width, height, thumb_path, medium_path
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 		RETURNING id, uploaded_at
 	`
 
@@ -40,6 +74,11 @@ func (r *PhotoRepository) Create(ctx context.Context, photo *models.Photo) error
 		photo.FileSize,
 		photo.MimeType,
 		photo.IsResultPhoto,
+		photo.UploadedBy,
+		photo.Width,
+		photo.Height,
+		photo.ThumbPath,
+		photo.MediumPath,
 	).Scan(&photo.ID, &photo.UploadedAt)
 
 	if err != nil {
@@ -51,25 +90,7 @@ func (r *PhotoRepository) Create(ctx context.Context, photo *models.Photo) error
 
 // GetByID retrieves a photo by ID
 func (r *PhotoRepository) GetByID(ctx context.Context, id int64) (*models.Photo, error) {
-	query := `
-		SELECT id, appeal_id, comment_id, file_path, file_name, file_size, mime_type, is_result_photo, uploaded_at
-		FROM photos
-		WHERE id = $1
-	`
-
-	var photo models.Photo
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&photo.ID,
-		&photo.AppealID,
-		&photo.CommentID,
-		&photo.FilePath,
-		&photo.FileName,
-		&photo.FileSize,
-		&photo.MimeType,
-		&photo.IsResultPhoto,
-		&photo.UploadedAt,
-	)
-
+	photo, err := scanPhoto(r.db.QueryRow(ctx, "SELECT "+photoColumns+" FROM photos WHERE id = $1", id))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrPhotoNotFound
@@ -77,57 +98,35 @@ func (r *PhotoRepository) GetByID(ctx context.Context, id int64) (*models.Photo,
 		return nil, fmt.Errorf("failed to get photo: %w", err)
 	}
 
-	return &photo, nil
+	return photo, nil
 }
 
-// GetByAppealID retrieves all photos for an appeal
+// GetByAppealID retrieves the photos of an appeal; photos of its comments are not included
 func (r *PhotoRepository) GetByAppealID(ctx context.Context, appealID int64) ([]*models.Photo, error) {
 	query := `
-		SELECT id, appeal_id, comment_id, file_path, file_name, file_size, mime_type, is_result_photo, uploaded_at
+		SELECT ` + photoColumns + `
 		FROM photos
-		WHERE appeal_id = $1
+		WHERE appeal_id = $1 AND comment_id IS NULL
 		ORDER BY uploaded_at ASC
 	`
 
-	rows, err := r.db.Query(ctx, query, appealID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get photos: %w", err)
-	}
-	defer rows.Close()
-
-	photos := make([]*models.Photo, 0)
-	for rows.Next() {
-		var photo models.Photo
-		err := rows.Scan(
-			&photo.ID,
-			&photo.AppealID,
-			&photo.CommentID,
-			&photo.FilePath,
-			&photo.FileName,
-			&photo.FileSize,
-			&photo.MimeType,
-			&photo.IsResultPhoto,
-			&photo.UploadedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan photo: %w", err)
-		}
-		photos = append(photos, &photo)
-	}
-
-	return photos, nil
+	return r.list(ctx, query, appealID)
 }
 
 // GetByCommentID retrieves all photos for a comment
 func (r *PhotoRepository) GetByCommentID(ctx context.Context, commentID int64) ([]*models.Photo, error) {
 	query := `
-		SELECT id, appeal_id, comment_id, file_path, file_name, file_size, mime_type, is_result_photo, uploaded_at
+		SELECT ` + photoColumns + `
 		FROM photos
 		WHERE comment_id = $1
 		ORDER BY uploaded_at ASC
 	`
 
-	rows, err := r.db.Query(ctx, query, commentID)
+	return r.list(ctx, query, commentID)
+}
+
+func (r *PhotoRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.Photo, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get photos: %w", err)
 	}
@@ -135,30 +134,19 @@ func (r *PhotoRepository) GetByCommentID(ctx context.Context, commentID int64) (
 
 	photos := make([]*models.Photo, 0)
 	for rows.Next() {
-		var photo models.Photo
-		err := rows.Scan(
-			&photo.ID,
-			&photo.AppealID,
-			&photo.CommentID,
-			&photo.FilePath,
-			&photo.FileName,
-			&photo.FileSize,
-			&photo.MimeType,
-			&photo.IsResultPhoto,
-			&photo.UploadedAt,
-		)
+		photo, err := scanPhoto(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan photo: %w", err)
 		}
-		photos = append(photos, &photo)
+		photos = append(photos, photo)
 	}
 
-	return photos, nil
+	return photos, rows.Err()
 }
 
-// CountByAppealID counts photos for an appeal
+// CountByAppealID counts the photos of an appeal, not counting photos of its comments
 func (r *PhotoRepository) CountByAppealID(ctx context.Context, appealID int64) (int, error) {
-	query := `SELECT COUNT(*) FROM photos WHERE appeal_id = $1`
+	query := `SELECT COUNT(*) FROM photos WHERE appeal_id = $1 AND comment_id IS NULL`
 
 	var count int
 	err := r.db.QueryRow(ctx, query, appealID).Scan(&count)
@@ -169,6 +157,37 @@ func (r *PhotoRepository) CountByAppealID(ctx context.Context, appealID int64) (
 	return count, nil
 }
 
+// CountStaged counts the staged photos of a user
+func (r *PhotoRepository) CountStaged(ctx context.Context, userID int64) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM photos
+		WHERE uploaded_by = $1 AND appeal_id IS NULL AND comment_id IS NULL
+	`
+
+	var count int
+	if err := r.db.QueryRow(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count staged photos: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteStagedBefore deletes up to limit staged photos uploaded before the time and
+// returns them, so their files can be removed
+func (r *PhotoRepository) DeleteStagedBefore(ctx context.Context, before time.Time, limit int) ([]*models.Photo, error) {
+	query := `
+		DELETE FROM photos
+		WHERE id IN (
+			SELECT id FROM photos
+			WHERE appeal_id IS NULL AND comment_id IS NULL AND uploaded_at < $1
+			ORDER BY uploaded_at
+			LIMIT $2
+		)
+		RETURNING ` + photoColumns
+
+	return r.list(ctx, query, before, limit)
+}
+
 // Delete deletes a photo record
 func (r *PhotoRepository) Delete(ctx context.Context, id int64) error {
 	query := `DELETE FROM photos WHERE id = $1`
@@ -197,3 +216,26 @@ func (r *PhotoRepository) DeleteByAppealID(ctx context.Context, appealID int64)
 	return nil
 }
 
+// claimStagedPhotos attaches staged photos of the owner to an appeal, or to a comment of
+// the appeal, inside the caller's transaction. Every photo must be a staged upload of the
+// owner; otherwise nothing is attached and ErrPhotosNotClaimable is returned.
+func claimStagedPhotos(ctx context.Context, tx pgx.Tx, photoIDs []int64, ownerID, appealID int64, commentID *int64) error {
+	if len(photoIDs) == 0 {
+		return nil
+	}
+
+	result, err := tx.Exec(ctx, `
+		UPDATE photos
+		SET appeal_id = $3, comment_id = $4
+		WHERE id = ANY($1) AND uploaded_by = $2 AND appeal_id IS NULL AND comment_id IS NULL
+	`, photoIDs, ownerID, appealID, commentID)
+	if err != nil {
+		return fmt.Errorf("failed to claim photos: %w", err)
+	}
+
+	if result.RowsAffected() != distinctCount(photoIDs) {
+		return ErrPhotosNotClaimable
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrPriorityRuleNotFound = errors.New("priority rule not found")
+)
+
+type PriorityRuleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPriorityRuleRepository(db *pgxpool.Pool) *PriorityRuleRepository {
+	return &PriorityRuleRepository{db: db}
+}
+
+// Create creates a new priority rule
+func (r *PriorityRuleRepository) Create(ctx context.Context, rule *models.PriorityRule) error {
+	query := `
+		INSERT INTO priority_rules (name, rule_type, params, priority, category_id, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		rule.Name,
+		rule.Type,
+		rule.Params,
+		rule.Priority,
+		rule.CategoryID,
+		rule.IsActive,
+	).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create priority rule: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a priority rule by ID
+func (r *PriorityRuleRepository) GetByID(ctx context.Context, id int64) (*models.PriorityRule, error) {
+	query := `
+		SELECT id, name, rule_type, params, priority, category_id, is_active, created_at, updated_at
+		FROM priority_rules
+		WHERE id = $1
+	`
+
+	var rule models.PriorityRule
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&rule.ID,
+		&rule.Name,
+		&rule.Type,
+		&rule.Params,
+		&rule.Priority,
+		&rule.CategoryID,
+		&rule.IsActive,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPriorityRuleNotFound
+		}
+		return nil, fmt.Errorf("failed to get priority rule: %w", err)
+	}
+
+	return &rule, nil
+}
+
+// List retrieves priority rules, optionally only the active ones
+func (r *PriorityRuleRepository) List(ctx context.Context, activeOnly bool) ([]*models.PriorityRule, error) {
+	query := `
+		SELECT p.id, p.name, p.rule_type, p.params, p.priority, p.category_id, p.is_active,
+		       p.created_at, p.updated_at, c.name
+		FROM priority_rules p
+		LEFT JOIN categories c ON p.category_id = c.id
+		WHERE p.is_active = true OR NOT $1
+		ORDER BY p.priority DESC, p.id
+	`
+
+	rows, err := r.db.Query(ctx, query, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list priority rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]*models.PriorityRule, 0)
+	for rows.Next() {
+		var rule models.PriorityRule
+		var categoryName *string
+		err := rows.Scan(
+			&rule.ID,
+			&rule.Name,
+			&rule.Type,
+			&rule.Params,
+			&rule.Priority,
+			&rule.CategoryID,
+			&rule.IsActive,
+			&rule.CreatedAt,
+			&rule.UpdatedAt,
+			&categoryName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan priority rule: %w", err)
+		}
+		if rule.CategoryID != nil && categoryName != nil {
+			rule.Category = &models.Category{
+				ID:   *rule.CategoryID,
+				Name: *categoryName,
+			}
+		}
+		rules = append(rules, &rule)
+	}
+
+	return rules, nil
+}
+
+// Update updates a priority rule
+func (r *PriorityRuleRepository) Update(ctx context.Context, rule *models.PriorityRule) error {
+	query := `
+		UPDATE priority_rules
+		SET name = $1, params = $2, priority = $3, is_active = $4, updated_at = NOW()
+		WHERE id = $5
+	`
+
+	result, err := r.db.Exec(ctx, query, rule.Name, rule.Params, rule.Priority, rule.IsActive, rule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update priority rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrPriorityRuleNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes a priority rule; appeals keep the record of it having fired
+func (r *PriorityRuleRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM priority_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete priority rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrPriorityRuleNotFound
+	}
+
+	return nil
+}
+
+// RecordFired stores the rules that fired for an appeal. A rule is recorded once per appeal.
+func (r *PriorityRuleRepository) RecordFired(ctx context.Context, appealID int64, rules []*models.PriorityRule) error {
+	query := `
+		INSERT INTO appeal_priority_rules (appeal_id, rule_id, rule_name, rule_type, priority)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (appeal_id, rule_id) DO NOTHING
+	`
+
+	for _, rule := range rules {
+		if _, err := r.db.Exec(ctx, query, appealID, rule.ID, rule.Name, rule.Type, rule.Priority); err != nil {
+			return fmt.Errorf("failed to record fired rule %d: %w", rule.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// GetFired retrieves the rules that fired for an appeal
+func (r *PriorityRuleRepository) GetFired(ctx context.Context, appealID int64) ([]*models.AppealPriorityRule, error) {
+	query := `
+		SELECT rule_id, rule_name, rule_type, priority, fired_at
+		FROM appeal_priority_rules
+		WHERE appeal_id = $1
+		ORDER BY fired_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, appealID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fired rules: %w", err)
+	}
+	defer rows.Close()
+
+	fired := make([]*models.AppealPriorityRule, 0)
+	for rows.Next() {
+		var rule models.AppealPriorityRule
+		if err := rows.Scan(&rule.RuleID, &rule.Name, &rule.Type, &rule.Priority, &rule.FiredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan fired rule: %w", err)
+		}
+		fired = append(fired, &rule)
+	}
+
+	return fired, nil
+}
@@ -0,0 +1,285 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrReportSubscriptionNotFound = errors.New("report subscription not found")
+)
+
+type ReportSubscriptionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReportSubscriptionRepository(db *pgxpool.Pool) *ReportSubscriptionRepository {
+	return &ReportSubscriptionRepository{db: db}
+}
+
+const reportSubscriptionColumns = `
+	s.id, s.name, s.report_type, s.service_id, s.date_range, s.recipients, s.schedule, s.is_active,
+	s.next_run_at, s.last_run_at, s.failed_attempts, s.created_by, s.created_at, s.updated_at, sv.name
+`
+
+func scanReportSubscription(row pgx.Row) (*models.ReportSubscription, error) {
+	var sub models.ReportSubscription
+	var serviceName *string
+	err := row.Scan(
+		&sub.ID,
+		&sub.Name,
+		&sub.ReportType,
+		&sub.ServiceID,
+		&sub.DateRange,
+		&sub.Recipients,
+		&sub.Schedule,
+		&sub.IsActive,
+		&sub.NextRunAt,
+		&sub.LastRunAt,
+		&sub.FailedAttempts,
+		&sub.CreatedBy,
+		&sub.CreatedAt,
+		&sub.UpdatedAt,
+		&serviceName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if sub.ServiceID != nil && serviceName != nil {
+		sub.Service = &models.Service{
+			ID:   *sub.ServiceID,
+			Name: *serviceName,
+		}
+	}
+	return &sub, nil
+}
+
+// Create creates a new report subscription
+func (r *ReportSubscriptionRepository) Create(ctx context.Context, sub *models.ReportSubscription) error {
+	query := `
+		INSERT INTO report_subscriptions (name, report_type, service_id, date_range, recipients, schedule, is_active, next_run_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		sub.Name,
+		sub.ReportType,
+		sub.ServiceID,
+		sub.DateRange,
+		sub.Recipients,
+		sub.Schedule,
+		sub.IsActive,
+		sub.NextRunAt,
+		sub.CreatedBy,
+	).Scan(&sub.ID, &sub.CreatedAt, &sub.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create report subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a report subscription by ID
+func (r *ReportSubscriptionRepository) GetByID(ctx context.Context, id int64) (*models.ReportSubscription, error) {
+	query := `
+		SELECT ` + reportSubscriptionColumns + `
+		FROM report_subscriptions s
+		LEFT JOIN services sv ON s.service_id = sv.id
+		WHERE s.id = $1
+	`
+
+	sub, err := scanReportSubscription(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReportSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get report subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+// List retrieves all report subscriptions
+func (r *ReportSubscriptionRepository) List(ctx context.Context) ([]*models.ReportSubscription, error) {
+	query := `
+		SELECT ` + reportSubscriptionColumns + `
+		FROM report_subscriptions s
+		LEFT JOIN services sv ON s.service_id = sv.id
+		ORDER BY s.name, s.id
+	`
+
+	return r.list(ctx, query)
+}
+
+// ListDue retrieves active subscriptions whose next run is at or before now
+func (r *ReportSubscriptionRepository) ListDue(ctx context.Context, now time.Time) ([]*models.ReportSubscription, error) {
+	query := `
+		SELECT ` + reportSubscriptionColumns + `
+		FROM report_subscriptions s
+		LEFT JOIN services sv ON s.service_id = sv.id
+		WHERE s.is_active AND s.next_run_at IS NOT NULL AND s.next_run_at <= $1
+		ORDER BY s.next_run_at, s.id
+	`
+
+	return r.list(ctx, query, now)
+}
+
+func (r *ReportSubscriptionRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.ReportSubscription, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := make([]*models.ReportSubscription, 0)
+	for rows.Next() {
+		sub, err := scanReportSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report subscription: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+
+	return subs, rows.Err()
+}
+
+// Update updates a report subscription's settings and its next run
+func (r *ReportSubscriptionRepository) Update(ctx context.Context, sub *models.ReportSubscription) error {
+	query := `
+		UPDATE report_subscriptions
+		SET name = $1, service_id = $2, date_range = $3, recipients = $4, schedule = $5,
+		    is_active = $6, next_run_at = $7, failed_attempts = $8, updated_at = NOW()
+		WHERE id = $9
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		sub.Name,
+		sub.ServiceID,
+		sub.DateRange,
+		sub.Recipients,
+		sub.Schedule,
+		sub.IsActive,
+		sub.NextRunAt,
+		sub.FailedAttempts,
+		sub.ID,
+	).Scan(&sub.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrReportSubscriptionNotFound
+		}
+		return fmt.Errorf("failed to update report subscription: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a report subscription together with its delivery log
+func (r *ReportSubscriptionRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM report_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete report subscription: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrReportSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// ScheduleNext stores the outcome of a run: when to run next and how many attempts have failed.
+// lastRunAt is only updated when it is not nil.
+func (r *ReportSubscriptionRepository) ScheduleNext(ctx context.Context, id int64, nextRunAt *time.Time, lastRunAt *time.Time, failedAttempts int) error {
+	query := `
+		UPDATE report_subscriptions
+		SET next_run_at = $1, last_run_at = COALESCE($2, last_run_at), failed_attempts = $3
+		WHERE id = $4
+	`
+
+	if _, err := r.db.Exec(ctx, query, nextRunAt, lastRunAt, failedAttempts, id); err != nil {
+		return fmt.Errorf("failed to schedule report subscription: %w", err)
+	}
+
+	return nil
+}
+
+// RecordDelivery stores a delivery attempt
+func (r *ReportSubscriptionRepository) RecordDelivery(ctx context.Context, delivery *models.ReportDelivery) error {
+	query := `
+		INSERT INTO report_deliveries (subscription_id, status, attempt, recipients, period_from, period_to, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		delivery.SubscriptionID,
+		delivery.Status,
+		delivery.Attempt,
+		delivery.Recipients,
+		delivery.PeriodFrom,
+		delivery.PeriodTo,
+		delivery.Error,
+	).Scan(&delivery.ID, &delivery.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to record report delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries retrieves the latest delivery attempts of a subscription, newest first
+func (r *ReportSubscriptionRepository) ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]*models.ReportDelivery, error) {
+	query := `
+		SELECT id, subscription_id, status, attempt, recipients, period_from, period_to, error, created_at
+		FROM report_deliveries
+		WHERE subscription_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, subscriptionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.ReportDelivery, 0)
+	for rows.Next() {
+		var d models.ReportDelivery
+		err := rows.Scan(
+			&d.ID,
+			&d.SubscriptionID,
+			&d.Status,
+			&d.Attempt,
+			&d.Recipients,
+			&d.PeriodFrom,
+			&d.PeriodTo,
+			&d.Error,
+			&d.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan report delivery: %w", err)
+		}
+		deliveries = append(deliveries, &d)
+	}
+
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrSavedViewNotFound = errors.New("saved view not found")
+)
+
+type SavedViewRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSavedViewRepository(db *pgxpool.Pool) *SavedViewRepository {
+	return &SavedViewRepository{db: db}
+}
+
+const savedViewColumns = `
+	v.id, v.user_id, v.name, v.filters, v.sort_by, v.sort_order, v.shared_role, v.shared_service_id,
+	v.created_at, v.updated_at, u.first_name, u.last_name, s.name
+`
+
+const savedViewJoins = `
+	FROM saved_views v
+	JOIN users u ON v.user_id = u.id
+	LEFT JOIN services s ON v.shared_service_id = s.id
+`
+
+// savedViewVisible matches views the user $1 with role $2 may use: their own, shared
+// with their role, or shared with a service they belong to
+const savedViewVisible = `(
+	v.user_id = $1
+	OR v.shared_role = $2
+	OR v.shared_service_id IN (SELECT us.service_id FROM user_services us WHERE us.user_id = $1)
+)`
+
+func scanSavedView(row pgx.Row) (*models.SavedView, error) {
+	var view models.SavedView
+	var ownerFirstName, ownerLastName string
+	var serviceName *string
+	err := row.Scan(
+		&view.ID,
+		&view.UserID,
+		&view.Name,
+		&view.Filters,
+		&view.SortBy,
+		&view.SortOrder,
+		&view.SharedRole,
+		&view.SharedServiceID,
+		&view.CreatedAt,
+		&view.UpdatedAt,
+		&ownerFirstName,
+		&ownerLastName,
+		&serviceName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	view.Owner = &models.User{
+		ID:        view.UserID,
+		FirstName: ownerFirstName,
+		LastName:  ownerLastName,
+	}
+	if view.SharedServiceID != nil && serviceName != nil {
+		view.SharedService = &models.Service{
+			ID:   *view.SharedServiceID,
+			Name: *serviceName,
+		}
+	}
+	return &view, nil
+}
+
+// Create creates a new saved view
+func (r *SavedViewRepository) Create(ctx context.Context, view *models.SavedView) error {
+	query := `
+		INSERT INTO saved_views (user_id, name, filters, sort_by, sort_order, shared_role, shared_service_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		view.UserID,
+		view.Name,
+		view.Filters,
+		view.SortBy,
+		view.SortOrder,
+		view.SharedRole,
+		view.SharedServiceID,
+	).Scan(&view.ID, &view.CreatedAt, &view.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create saved view: %w", err)
+	}
+
+	return nil
+}
+
+// GetVisible retrieves a saved view the user may use. Views of other users that are
+// not shared with them are reported as not found.
+func (r *SavedViewRepository) GetVisible(ctx context.Context, id, userID int64, role models.UserRole) (*models.SavedView, error) {
+	query := `
+		SELECT ` + savedViewColumns + savedViewJoins + `
+		WHERE ` + savedViewVisible + ` AND v.id = $3
+	`
+
+	view, err := scanSavedView(r.db.QueryRow(ctx, query, userID, role, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSavedViewNotFound
+		}
+		return nil, fmt.Errorf("failed to get saved view: %w", err)
+	}
+
+	return view, nil
+}
+
+// ListVisible retrieves the views the user may use: their own first, then shared ones
+func (r *SavedViewRepository) ListVisible(ctx context.Context, userID int64, role models.UserRole) ([]*models.SavedView, error) {
+	query := `
+		SELECT ` + savedViewColumns + `,
+			EXISTS (SELECT 1 FROM saved_view_subscriptions vs WHERE vs.view_id = v.id AND vs.user_id = $1)
+		` + savedViewJoins + `
+		WHERE ` + savedViewVisible + `
+		ORDER BY v.user_id <> $1, v.name, v.id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved views: %w", err)
+	}
+	defer rows.Close()
+
+	views := make([]*models.SavedView, 0)
+	for rows.Next() {
+		var subscribed bool
+		view, err := scanSavedView(scanWithExtra{rows, &subscribed})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved view: %w", err)
+		}
+		view.Subscribed = subscribed
+		views = append(views, view)
+	}
+
+	return views, rows.Err()
+}
+
+// Update replaces the settings of a saved view
+func (r *SavedViewRepository) Update(ctx context.Context, view *models.SavedView) error {
+	query := `
+		UPDATE saved_views
+		SET name = $2, filters = $3, sort_by = $4, sort_order = $5, shared_role = $6, shared_service_id = $7,
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		view.ID,
+		view.Name,
+		view.Filters,
+		view.SortBy,
+		view.SortOrder,
+		view.SharedRole,
+		view.SharedServiceID,
+	).Scan(&view.UpdatedAt)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrSavedViewNotFound
+		}
+		return fmt.Errorf("failed to update saved view: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a saved view with its subscriptions
+func (r *SavedViewRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, "DELETE FROM saved_views WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved view: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrSavedViewNotFound
+	}
+
+	return nil
+}
+
+// Subscribe turns notifications about new matching appeals on or off for the user
+func (r *SavedViewRepository) Subscribe(ctx context.Context, viewID, userID int64, subscribe bool) error {
+	query := `
+		INSERT INTO saved_view_subscriptions (view_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (view_id, user_id) DO NOTHING
+	`
+	if !subscribe {
+		query = "DELETE FROM saved_view_subscriptions WHERE view_id = $1 AND user_id = $2"
+	}
+
+	if _, err := r.db.Exec(ctx, query, viewID, userID); err != nil {
+		return fmt.Errorf("failed to update saved view subscription: %w", err)
+	}
+
+	return nil
+}
+
+// ListSubscriptions retrieves every subscription whose user may still use the view;
+// a view that stopped being shared with a subscriber no longer notifies them
+func (r *SavedViewRepository) ListSubscriptions(ctx context.Context) ([]*models.SavedViewSubscription, error) {
+	query := `
+		SELECT ` + savedViewColumns + `, vs.user_id
+		FROM saved_view_subscriptions vs
+		JOIN users su ON vs.user_id = su.id
+		JOIN saved_views v ON vs.view_id = v.id
+		JOIN users u ON v.user_id = u.id
+		LEFT JOIN services s ON v.shared_service_id = s.id
+		WHERE su.is_active AND (
+			v.user_id = vs.user_id
+			OR v.shared_role = su.role
+			OR v.shared_service_id IN (SELECT us.service_id FROM user_services us WHERE us.user_id = vs.user_id)
+		)
+		ORDER BY vs.user_id, v.id
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved view subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := make([]*models.SavedViewSubscription, 0)
+	for rows.Next() {
+		var userID int64
+		view, err := scanSavedView(scanWithExtra{rows, &userID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved view subscription: %w", err)
+		}
+		subs = append(subs, &models.SavedViewSubscription{View: view, UserID: userID})
+	}
+
+	return subs, rows.Err()
+}
+
+// scanWithExtra scans a row whose trailing column follows the columns a scan function reads
+type scanWithExtra struct {
+	row   pgx.Row
+	extra interface{}
+}
+
+func (s scanWithExtra) Scan(dest ...interface{}) error {
+	return s.row.Scan(append(dest, s.extra)...)
+}
@@ -12,6 +12,7 @@ import (
 
 var (
 	ErrServiceNotFound = errors.New("service not found")
+	ErrServiceInUse    = errors.New("service has open appeals")
 )
 
 type ServiceRepository struct {
@@ -25,8 +26,8 @@ func NewServiceRepository(db *pgxpool.Pool) *ServiceRepository {
 // Create creates a new service
 func (r *ServiceRepository) Create(ctx context.Context, service *models.Service) error {
 	query := `
-		INSERT INTO services (name, description, contact_person, contact_phone, contact_email, is_active)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO services (name, description, contact_person, contact_phone, contact_email, is_active, assignment_strategy)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -39,6 +40,7 @@ func (r *ServiceRepository) Create(ctx context.Context, service *models.Service)
 		service.ContactPhone,
 		service.ContactEmail,
 		service.IsActive,
+		service.AssignmentStrategy,
 	).Scan(&service.ID, &service.CreatedAt, &service.UpdatedAt)
 
 	if err != nil {
@@ -52,7 +54,7 @@ func (r *ServiceRepository) Create(ctx context.Context, service *models.Service)
 func (r *ServiceRepository) GetByID(ctx context.Context, id int64) (*models.Service, error) {
 	query := `
 		SELECT id, name, description, contact_person, contact_phone, contact_email,
-		       is_active, created_at, updated_at
+		       is_active, assignment_strategy, created_at, updated_at
 		FROM services
 		WHERE id = $1
 	`
@@ -66,6 +68,7 @@ func (r *ServiceRepository) GetByID(ctx context.Context, id int64) (*models.Serv
 		&service.ContactPhone,
 		&service.ContactEmail,
 		&service.IsActive,
+		&service.AssignmentStrategy,
 		&service.CreatedAt,
 		&service.UpdatedAt,
 	)
@@ -84,7 +87,7 @@ func (r *ServiceRepository) GetByID(ctx context.Context, id int64) (*models.Serv
 func (r *ServiceRepository) List(ctx context.Context, includeInactive bool) ([]*models.Service, error) {
 	query := `
 		SELECT id, name, description, contact_person, contact_phone, contact_email,
-		       is_active, created_at, updated_at
+		       is_active, assignment_strategy, created_at, updated_at
 		FROM services
 		WHERE is_active = true OR $1
 		ORDER BY name
@@ -107,6 +110,7 @@ func (r *ServiceRepository) List(ctx context.Context, includeInactive bool) ([]*
 			&service.ContactPhone,
 			&service.ContactEmail,
 			&service.IsActive,
+			&service.AssignmentStrategy,
 			&service.CreatedAt,
 			&service.UpdatedAt,
 		)
@@ -124,8 +128,8 @@ func (r *ServiceRepository) Update(ctx context.Context, service *models.Service)
 	query := `
 		UPDATE services
 		SET name = $1, description = $2, contact_person = $3, contact_phone = $4,
-		    contact_email = $5, is_active = $6, updated_at = NOW()
-		WHERE id = $7
+		    contact_email = $5, is_active = $6, assignment_strategy = $7, updated_at = NOW()
+		WHERE id = $8
 	`
 
 	result, err := r.db.Exec(
@@ -137,6 +141,7 @@ func (r *ServiceRepository) Update(ctx context.Context, service *models.Service)
 		service.ContactPhone,
 		service.ContactEmail,
 		service.IsActive,
+		service.AssignmentStrategy,
 		service.ID,
 	)
 
@@ -151,27 +156,111 @@ func (r *ServiceRepository) Update(ctx context.Context, service *models.Service)
 	return nil
 }
 
-// Delete soft deletes a service
-func (r *ServiceRepository) Delete(ctx context.Context, id int64) error {
-	query := `UPDATE services SET is_active = false, updated_at = NOW() WHERE id = $1`
+// Delete archives a service. Closed and rejected appeals keep the service, so historical
+// statistics stay intact. Appeals still in work block the deletion unless reassignTo names
+// an active service to move them to; they move in the same transaction, each with a history
+// entry, and lose their executor, who works for the archived service. It returns how many
+// appeals were moved.
+func (r *ServiceRepository) Delete(ctx context.Context, id int64, reassignTo *int64, userID int64) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-	result, err := r.db.Exec(ctx, query, id)
+	var name string
+	err = tx.QueryRow(ctx, "SELECT name FROM services WHERE id = $1 FOR UPDATE", id).Scan(&name)
 	if err != nil {
-		return fmt.Errorf("failed to delete service: %w", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrServiceNotFound
+		}
+		return 0, fmt.Errorf("failed to get service: %w", err)
 	}
 
-	if result.RowsAffected() == 0 {
-		return ErrServiceNotFound
+	var moved int64
+	if reassignTo == nil {
+		var open int64
+		err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM appeals
+			WHERE service_id = $1 AND status NOT IN ('closed', 'rejected')
+		`, id).Scan(&open)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count open appeals: %w", err)
+		}
+		if open > 0 {
+			return 0, fmt.Errorf("%w (%d)", ErrServiceInUse, open)
+		}
+	} else {
+		var targetName string
+		err := tx.QueryRow(ctx, `
+			SELECT name FROM services WHERE id = $2 AND is_active = true AND id <> $1
+		`, id, *reassignTo).Scan(&targetName)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return 0, ErrInvalidReassignTarget
+			}
+			return 0, fmt.Errorf("failed to get target service: %w", err)
+		}
+
+		query := `
+			WITH moved AS (
+				UPDATE appeals a
+				SET service_id = $2, assignee_id = NULL, updated_at = NOW()
+				FROM (
+					SELECT id, assignee_id FROM appeals
+					WHERE service_id = $1 AND status NOT IN ('closed', 'rejected')
+					FOR UPDATE
+				) old
+				WHERE a.id = old.id
+				RETURNING a.id, a.status, old.assignee_id
+			), history AS (
+				INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
+				SELECT id, $3, status, status,
+					CASE WHEN assignee_id IS NOT NULL THEN $5::TEXT ELSE $4::TEXT END
+				FROM moved
+			)
+			SELECT COUNT(*) FROM moved
+		`
+		err = tx.QueryRow(ctx, query, id, *reassignTo, userID,
+			serviceReassignAction(name, targetName, false),
+			serviceReassignAction(name, targetName, true),
+		).Scan(&moved)
+		if err != nil {
+			return 0, fmt.Errorf("failed to reassign appeals: %w", err)
+		}
 	}
 
-	return nil
+	if _, err := tx.Exec(ctx, "UPDATE services SET is_active = false, updated_at = NOW() WHERE id = $1", id); err != nil {
+		return 0, fmt.Errorf("failed to delete service: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return moved, nil
+}
+
+// GetLastAssignedUser returns the executor who got the service's previous automatic
+// assignment, nil if there was none yet
+func (r *ServiceRepository) GetLastAssignedUser(ctx context.Context, serviceID int64) (*int64, error) {
+	var userID *int64
+	err := r.db.QueryRow(ctx, `SELECT last_assigned_user_id FROM services WHERE id = $1`, serviceID).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrServiceNotFound
+		}
+		return nil, fmt.Errorf("failed to get last assigned user: %w", err)
+	}
+
+	return userID, nil
 }
 
 // GetByName retrieves a service by name (case-insensitive)
 func (r *ServiceRepository) GetByName(ctx context.Context, name string) (*models.Service, error) {
 	query := `
 		SELECT id, name, description, contact_person, contact_phone, contact_email,
-		       is_active, created_at, updated_at
+		       is_active, assignment_strategy, created_at, updated_at
 		FROM services
 		WHERE LOWER(name) = LOWER($1) AND is_active = true
 	`
@@ -185,6 +274,7 @@ func (r *ServiceRepository) GetByName(ctx context.Context, name string) (*models
 		&service.ContactPhone,
 		&service.ContactEmail,
 		&service.IsActive,
+		&service.AssignmentStrategy,
 		&service.CreatedAt,
 		&service.UpdatedAt,
 	)
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrSLAPolicyNotFound = errors.New("sla policy not found")
+	ErrSLAPolicyExists   = errors.New("sla policy for this category and priority already exists")
+)
+
+type SLAPolicyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSLAPolicyRepository(db *pgxpool.Pool) *SLAPolicyRepository {
+	return &SLAPolicyRepository{db: db}
+}
+
+// Create creates a new SLA policy
+func (r *SLAPolicyRepository) Create(ctx context.Context, policy *models.SLAPolicy) error {
+	query := `
+		INSERT INTO sla_policies (category_id, priority, response_hours, resolution_hours)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		policy.CategoryID,
+		policy.Priority,
+		policy.ResponseHours,
+		policy.ResolutionHours,
+	).Scan(&policy.ID, &policy.CreatedAt, &policy.UpdatedAt)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			return ErrSLAPolicyExists
+		}
+		return fmt.Errorf("failed to create sla policy: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an SLA policy by ID
+func (r *SLAPolicyRepository) GetByID(ctx context.Context, id int64) (*models.SLAPolicy, error) {
+	query := `
+		SELECT id, category_id, priority, response_hours, resolution_hours, created_at, updated_at
+		FROM sla_policies
+		WHERE id = $1
+	`
+
+	var policy models.SLAPolicy
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&policy.ID,
+		&policy.CategoryID,
+		&policy.Priority,
+		&policy.ResponseHours,
+		&policy.ResolutionHours,
+		&policy.CreatedAt,
+		&policy.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSLAPolicyNotFound
+		}
+		return nil, fmt.Errorf("failed to get sla policy: %w", err)
+	}
+
+	return &policy, nil
+}
+
+// List retrieves all SLA policies, defaults first
+func (r *SLAPolicyRepository) List(ctx context.Context) ([]*models.SLAPolicy, error) {
+	query := `
+		SELECT p.id, p.category_id, p.priority, p.response_hours, p.resolution_hours,
+		       p.created_at, p.updated_at, c.name
+		FROM sla_policies p
+		LEFT JOIN categories c ON p.category_id = c.id
+		ORDER BY p.category_id NULLS FIRST, c.name, p.priority DESC
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sla policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := make([]*models.SLAPolicy, 0)
+	for rows.Next() {
+		var policy models.SLAPolicy
+		var categoryName *string
+		err := rows.Scan(
+			&policy.ID,
+			&policy.CategoryID,
+			&policy.Priority,
+			&policy.ResponseHours,
+			&policy.ResolutionHours,
+			&policy.CreatedAt,
+			&policy.UpdatedAt,
+			&categoryName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sla policy: %w", err)
+		}
+		if policy.CategoryID != nil && categoryName != nil {
+			policy.Category = &models.Category{
+				ID:   *policy.CategoryID,
+				Name: *categoryName,
+			}
+		}
+		policies = append(policies, &policy)
+	}
+
+	return policies, nil
+}
+
+// Update updates SLA targets of a policy
+func (r *SLAPolicyRepository) Update(ctx context.Context, policy *models.SLAPolicy) error {
+	query := `
+		UPDATE sla_policies
+		SET response_hours = $1, resolution_hours = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+
+	result, err := r.db.Exec(ctx, query, policy.ResponseHours, policy.ResolutionHours, policy.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update sla policy: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrSLAPolicyNotFound
+	}
+
+	return nil
+}
+
+// Delete deletes an SLA policy
+func (r *SLAPolicyRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM sla_policies WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete sla policy: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrSLAPolicyNotFound
+	}
+
+	return nil
+}
+
+// FindForAppeal returns the policy that applies to an appeal: the category's own
+// policy for the priority if there is one, otherwise the default for the priority.
+// Returns ErrSLAPolicyNotFound when neither exists.
+func (r *SLAPolicyRepository) FindForAppeal(ctx context.Context, categoryID *int64, priority int) (*models.SLAPolicy, error) {
+	query := `
+		SELECT id, category_id, priority, response_hours, resolution_hours, created_at, updated_at
+		FROM sla_policies
+		WHERE priority = $1 AND (category_id = $2 OR category_id IS NULL)
+		ORDER BY category_id NULLS LAST
+		LIMIT 1
+	`
+
+	var policy models.SLAPolicy
+	err := r.db.QueryRow(ctx, query, priority, categoryID).Scan(
+		&policy.ID,
+		&policy.CategoryID,
+		&policy.Priority,
+		&policy.ResponseHours,
+		&policy.ResolutionHours,
+		&policy.CreatedAt,
+		&policy.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSLAPolicyNotFound
+		}
+		return nil, fmt.Errorf("failed to find sla policy: %w", err)
+	}
+
+	return &policy, nil
+}
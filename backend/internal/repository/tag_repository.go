@@ -0,0 +1,255 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagExists   = errors.New("tag with this name already exists")
+)
+
+type TagRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTagRepository(db *pgxpool.Pool) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+const tagColumns = `t.id, t.name, t.color, t.is_curated, t.created_by, t.created_at, t.updated_at`
+
+func scanTag(row pgx.Row) (*models.Tag, error) {
+	var tag models.Tag
+	err := row.Scan(
+		&tag.ID,
+		&tag.Name,
+		&tag.Color,
+		&tag.IsCurated,
+		&tag.CreatedBy,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// Create creates a new tag
+func (r *TagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	query := `
+		INSERT INTO tags (name, color, is_curated, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		tag.Name,
+		tag.Color,
+		tag.IsCurated,
+		tag.CreatedBy,
+	).Scan(&tag.ID, &tag.CreatedAt, &tag.UpdatedAt)
+
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrTagExists
+		}
+		return fmt.Errorf("failed to create tag: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a tag by ID
+func (r *TagRepository) GetByID(ctx context.Context, id int64) (*models.Tag, error) {
+	tag, err := scanTag(r.db.QueryRow(ctx, "SELECT "+tagColumns+" FROM tags t WHERE t.id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTagNotFound
+		}
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	return tag, nil
+}
+
+// List retrieves all tags with their appeal counts: curated ones first, then by name
+func (r *TagRepository) List(ctx context.Context) ([]*models.Tag, error) {
+	query := `
+		SELECT ` + tagColumns + `,
+			(SELECT COUNT(*) FROM appeal_tags at WHERE at.tag_id = t.id)
+		FROM tags t
+		ORDER BY t.is_curated DESC, lower(t.name)
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make([]*models.Tag, 0)
+	for rows.Next() {
+		var count int64
+		tag, err := scanTag(scanWithExtra{rows, &count})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tag.AppealsCount = count
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// Update updates a tag
+func (r *TagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	query := `
+		UPDATE tags
+		SET name = $2, color = $3, is_curated = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query, tag.ID, tag.Name, tag.Color, tag.IsCurated).Scan(&tag.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTagNotFound
+		}
+		if isUniqueViolation(err) {
+			return ErrTagExists
+		}
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a tag and removes it from all appeals
+func (r *TagRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.Exec(ctx, "DELETE FROM tags WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrTagNotFound
+	}
+
+	return nil
+}
+
+// FindIDsByNames returns the IDs of the tags with the names, ignoring case.
+// Unknown names are skipped.
+func (r *TagRepository) FindIDsByNames(ctx context.Context, names []string) ([]int64, error) {
+	ids := make([]int64, 0, len(names))
+	if len(names) == 0 {
+		return ids, nil
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT t.id FROM tags t
+		WHERE lower(t.name) IN (SELECT lower(n) FROM unnest($1::TEXT[]) n)
+	`, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// EnsureByNames returns the IDs of the tags with the names, creating the missing ones
+// as free-form tags of the user
+func (r *TagRepository) EnsureByNames(ctx context.Context, names []string, userID int64) ([]int64, error) {
+	if len(names) == 0 {
+		return []int64{}, nil
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO tags (name, created_by)
+		SELECT n, $2 FROM unnest($1::TEXT[]) n
+		ON CONFLICT ((lower(name))) DO NOTHING
+	`, names, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tags: %w", err)
+	}
+
+	return r.FindIDsByNames(ctx, names)
+}
+
+// TagAppeals adds the tags to the appeals and records the added tags in the history of
+// each appeal. Pairs that already exist and unknown appeals or tags are skipped.
+// It returns how many tags were added.
+func (r *TagRepository) TagAppeals(ctx context.Context, appealIDs, tagIDs []int64, userID int64) (int64, error) {
+	query := `
+		WITH added AS (
+			INSERT INTO appeal_tags (appeal_id, tag_id, created_by)
+			SELECT a.id, t.id, $3
+			FROM appeals a CROSS JOIN tags t
+			WHERE a.id = ANY($1) AND t.id = ANY($2)
+			ON CONFLICT (appeal_id, tag_id) DO NOTHING
+			RETURNING appeal_id, tag_id
+		), history AS (
+			INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
+			SELECT a.id, $3, a.status, a.status, 'Додано мітки: ' || string_agg(t.name, ', ' ORDER BY lower(t.name))
+			FROM added
+			JOIN appeals a ON added.appeal_id = a.id
+			JOIN tags t ON added.tag_id = t.id
+			GROUP BY a.id, a.status
+		)
+		SELECT COUNT(*) FROM added
+	`
+
+	var added int64
+	if err := r.db.QueryRow(ctx, query, appealIDs, tagIDs, userID).Scan(&added); err != nil {
+		return 0, fmt.Errorf("failed to tag appeals: %w", err)
+	}
+
+	return added, nil
+}
+
+// UntagAppeals removes the tags from the appeals and records the removed tags in the
+// history of each appeal. It returns how many tags were removed.
+func (r *TagRepository) UntagAppeals(ctx context.Context, appealIDs, tagIDs []int64, userID int64) (int64, error) {
+	query := `
+		WITH removed AS (
+			DELETE FROM appeal_tags
+			WHERE appeal_id = ANY($1) AND tag_id = ANY($2)
+			RETURNING appeal_id, tag_id
+		), history AS (
+			INSERT INTO appeal_history (appeal_id, user_id, old_status, new_status, action)
+			SELECT a.id, $3, a.status, a.status, 'Знято мітки: ' || string_agg(t.name, ', ' ORDER BY lower(t.name))
+			FROM removed
+			JOIN appeals a ON removed.appeal_id = a.id
+			JOIN tags t ON removed.tag_id = t.id
+			GROUP BY a.id, a.status
+		)
+		SELECT COUNT(*) FROM removed
+	`
+
+	var removed int64
+	if err := r.db.QueryRow(ctx, query, appealIDs, tagIDs, userID).Scan(&removed); err != nil {
+		return 0, fmt.Errorf("failed to untag appeals: %w", err)
+	}
+
+	return removed, nil
+}
@@ -61,6 +61,65 @@ func (r *UserServiceRepository) GetByServiceID(ctx context.Context, serviceID in
 	return users, nil
 }
 
+// IsServiceExecutor reports whether the user is an active executor assigned to the service
+func (r *UserServiceRepository) IsServiceExecutor(ctx context.Context, userID, serviceID int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM users u
+			INNER JOIN user_services us ON u.id = us.user_id
+			WHERE u.id = $1 AND us.service_id = $2 AND u.role = 'executor' AND u.is_active = true
+		)
+	`
+
+	var ok bool
+	if err := r.db.QueryRow(ctx, query, userID, serviceID).Scan(&ok); err != nil {
+		return false, fmt.Errorf("failed to check service executor: %w", err)
+	}
+
+	return ok, nil
+}
+
+// GetExecutorWorkload retrieves active executors of a service with the number of open
+// appeals assigned to each and their last known location: the appeal they last worked on
+func (r *UserServiceRepository) GetExecutorWorkload(ctx context.Context, serviceID int64) ([]*models.ExecutorWorkload, error) {
+	query := `
+		SELECT u.id, u.first_name, u.last_name,
+		       (SELECT COUNT(*) FROM appeals a
+		        WHERE a.assignee_id = u.id AND a.status NOT IN ('completed', 'closed', 'rejected')),
+		       loc.latitude, loc.longitude
+		FROM users u
+		INNER JOIN user_services us ON u.id = us.user_id
+		LEFT JOIN LATERAL (
+			SELECT a.latitude, a.longitude
+			FROM appeal_history ah
+			INNER JOIN appeals a ON a.id = ah.appeal_id
+			WHERE ah.user_id = u.id
+			ORDER BY ah.created_at DESC
+			LIMIT 1
+		) loc ON true
+		WHERE us.service_id = $1 AND u.role = 'executor' AND u.is_active = true
+		ORDER BY u.id
+	`
+
+	rows, err := r.db.Query(ctx, query, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get executor workload: %w", err)
+	}
+	defer rows.Close()
+
+	workload := make([]*models.ExecutorWorkload, 0)
+	for rows.Next() {
+		var w models.ExecutorWorkload
+		if err := rows.Scan(&w.UserID, &w.FirstName, &w.LastName, &w.OpenAppeals, &w.Latitude, &w.Longitude); err != nil {
+			return nil, fmt.Errorf("failed to scan executor workload: %w", err)
+		}
+		workload = append(workload, &w)
+	}
+
+	return workload, nil
+}
+
 // GetByUserID retrieves all services assigned to a user
 func (r *UserServiceRepository) GetByUserID(ctx context.Context, userID int64) ([]*models.Service, error) {
 	query := `
@@ -0,0 +1,374 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrVolumeAlertNotFound = errors.New("volume alert not found")
+	ErrVolumeAlertResolved = errors.New("volume alert is already resolved")
+	// ErrNothingToGroup is returned when no appeals of an alert can still be merged
+	ErrNothingToGroup = errors.New("no open appeals left to group")
+	// ErrInvalidIncidentMaster is returned for a master appeal that is resolved or merged itself
+	ErrInvalidIncidentMaster = errors.New("master appeal is resolved or merged into another appeal")
+)
+
+type VolumeAlertRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewVolumeAlertRepository(db *pgxpool.Pool) *VolumeAlertRepository {
+	return &VolumeAlertRepository{db: db}
+}
+
+// CountAppeals counts appeals created in [from, to) per category and district.
+// Appeals without a category and appeals merged into a master appeal are not counted.
+func (r *VolumeAlertRepository) CountAppeals(ctx context.Context, from, to time.Time) ([]*models.VolumeCount, error) {
+	query := `
+		SELECT category_id, district_id, COUNT(*)
+		FROM appeals
+		WHERE category_id IS NOT NULL
+		  AND parent_id IS NULL
+		  AND created_at >= $1 AND created_at < $2
+		GROUP BY category_id, district_id
+	`
+
+	rows, err := r.db.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count appeals: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make([]*models.VolumeCount, 0)
+	for rows.Next() {
+		var c models.VolumeCount
+		if err := rows.Scan(&c.CategoryID, &c.DistrictID, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan appeal count: %w", err)
+		}
+		counts = append(counts, &c)
+	}
+
+	return counts, nil
+}
+
+// ListAppealIDs retrieves IDs of appeals of a category created in [from, to), oldest first.
+// A nil districtID means appeals of every district.
+func (r *VolumeAlertRepository) ListAppealIDs(ctx context.Context, categoryID int64, districtID *int64, from, to time.Time) ([]int64, error) {
+	query := `
+		SELECT id
+		FROM appeals
+		WHERE category_id = $1
+		  AND ($2::BIGINT IS NULL OR district_id = $2)
+		  AND parent_id IS NULL
+		  AND created_at >= $3 AND created_at < $4
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, categoryID, districtID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spike appeals: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan appeal ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// ExistsSince reports whether an alert for the scope was raised after since
+func (r *VolumeAlertRepository) ExistsSince(ctx context.Context, categoryID int64, districtID *int64, since time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM volume_alerts
+			WHERE category_id = $1
+			  AND COALESCE(district_id, 0) = COALESCE($2::BIGINT, 0)
+			  AND created_at > $3
+		)
+	`
+
+	var exists bool
+	if err := r.db.QueryRow(ctx, query, categoryID, districtID, since).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check recent volume alerts: %w", err)
+	}
+
+	return exists, nil
+}
+
+// Create creates a new volume alert
+func (r *VolumeAlertRepository) Create(ctx context.Context, alert *models.VolumeAlert) error {
+	query := `
+		INSERT INTO volume_alerts (
+			category_id, district_id, window_start, window_end, recent_count, baseline_count, appeal_ids, status
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`
+
+	if alert.Status == "" {
+		alert.Status = models.VolumeAlertOpen
+	}
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		alert.CategoryID,
+		alert.DistrictID,
+		alert.WindowStart,
+		alert.WindowEnd,
+		alert.RecentCount,
+		alert.BaselineCount,
+		alert.AppealIDs,
+		alert.Status,
+	).Scan(&alert.ID, &alert.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create volume alert: %w", err)
+	}
+
+	return nil
+}
+
+const volumeAlertSelect = `
+	SELECT v.id, v.category_id, v.district_id, v.window_start, v.window_end, v.recent_count,
+	       v.baseline_count, v.appeal_ids, v.status, v.incident_appeal_id, v.resolved_by,
+	       v.resolved_at, v.created_at, c.name, d.name
+	FROM volume_alerts v
+	JOIN categories c ON v.category_id = c.id
+	LEFT JOIN districts d ON v.district_id = d.id
+`
+
+func scanVolumeAlert(row pgx.Row) (*models.VolumeAlert, error) {
+	var alert models.VolumeAlert
+	var categoryName string
+	var districtName *string
+	err := row.Scan(
+		&alert.ID,
+		&alert.CategoryID,
+		&alert.DistrictID,
+		&alert.WindowStart,
+		&alert.WindowEnd,
+		&alert.RecentCount,
+		&alert.BaselineCount,
+		&alert.AppealIDs,
+		&alert.Status,
+		&alert.IncidentAppealID,
+		&alert.ResolvedBy,
+		&alert.ResolvedAt,
+		&alert.CreatedAt,
+		&categoryName,
+		&districtName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	alert.Category = &models.Category{ID: alert.CategoryID, Name: categoryName}
+	if alert.DistrictID != nil && districtName != nil {
+		alert.District = &models.District{ID: *alert.DistrictID, Name: *districtName}
+	}
+	return &alert, nil
+}
+
+// GetByID retrieves a volume alert by ID
+func (r *VolumeAlertRepository) GetByID(ctx context.Context, id int64) (*models.VolumeAlert, error) {
+	alert, err := scanVolumeAlert(r.db.QueryRow(ctx, volumeAlertSelect+` WHERE v.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrVolumeAlertNotFound
+		}
+		return nil, fmt.Errorf("failed to get volume alert: %w", err)
+	}
+
+	return alert, nil
+}
+
+// List retrieves the latest volume alerts, optionally of one status
+func (r *VolumeAlertRepository) List(ctx context.Context, status *models.VolumeAlertStatus, limit int) ([]*models.VolumeAlert, error) {
+	query := volumeAlertSelect + `
+		WHERE $1::TEXT IS NULL OR v.status = $1
+		ORDER BY v.created_at DESC, v.id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := make([]*models.VolumeAlert, 0)
+	for rows.Next() {
+		alert, err := scanVolumeAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan volume alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+// Resolve closes an open alert as grouped into an incident or dismissed
+func (r *VolumeAlertRepository) Resolve(ctx context.Context, id int64, status models.VolumeAlertStatus, incidentAppealID *int64, userID int64) error {
+	query := `
+		UPDATE volume_alerts
+		SET status = $1, incident_appeal_id = $2, resolved_by = $3, resolved_at = NOW()
+		WHERE id = $4 AND status = $5
+	`
+
+	result, err := r.db.Exec(ctx, query, status, incidentAppealID, userID, id, models.VolumeAlertOpen)
+	if err != nil {
+		return fmt.Errorf("failed to resolve volume alert: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return ErrVolumeAlertResolved
+	}
+
+	return nil
+}
+
+// incidentAppeal is what decides whether an appeal can join an incident
+type incidentAppeal struct {
+	ParentID    *int64
+	Open        bool
+	HasChildren bool
+}
+
+// planIncident picks the master appeal and the appeals to merge into it: masterID if
+// given, else the first of appealIDs that can be merged. Appeals that were resolved,
+// merged elsewhere, deleted or have their own linked appeals are left out.
+func planIncident(appealIDs []int64, appeals map[int64]incidentAppeal, masterID *int64) (int64, []int64, error) {
+	if masterID != nil {
+		master, ok := appeals[*masterID]
+		if !ok {
+			return 0, nil, ErrAppealNotFound
+		}
+		if master.ParentID != nil || !master.Open {
+			return 0, nil, ErrInvalidIncidentMaster
+		}
+	}
+
+	candidates := make([]int64, 0, len(appealIDs))
+	seen := make(map[int64]bool, len(appealIDs))
+	for _, id := range appealIDs {
+		if seen[id] || (masterID != nil && *masterID == id) {
+			continue
+		}
+		seen[id] = true
+
+		appeal, ok := appeals[id]
+		if !ok || appeal.ParentID != nil || !appeal.Open || appeal.HasChildren {
+			continue
+		}
+		candidates = append(candidates, id)
+	}
+
+	if masterID == nil {
+		if len(candidates) == 0 {
+			return 0, nil, ErrNothingToGroup
+		}
+		masterID, candidates = &candidates[0], candidates[1:]
+	}
+	if len(candidates) == 0 {
+		return 0, nil, ErrNothingToGroup
+	}
+	return *masterID, candidates, nil
+}
+
+// Group merges the appeals of an open alert that can still be merged into one master
+// appeal (see planIncident) and resolves the alert as grouped into it. The alert and
+// its appeals are locked, so the choice holds until the merge is committed.
+func (r *VolumeAlertRepository) Group(ctx context.Context, id int64, masterID *int64, userID int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var appealIDs []int64
+	var status models.VolumeAlertStatus
+	err = tx.QueryRow(ctx, "SELECT appeal_ids, status FROM volume_alerts WHERE id = $1 FOR UPDATE", id).
+		Scan(&appealIDs, &status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrVolumeAlertNotFound
+		}
+		return fmt.Errorf("failed to get volume alert: %w", err)
+	}
+	if status != models.VolumeAlertOpen {
+		return ErrVolumeAlertResolved
+	}
+
+	lockIDs := appealIDs
+	if masterID != nil {
+		lockIDs = append([]int64{*masterID}, appealIDs...)
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT a.id, a.parent_id, a.status NOT IN ('completed', 'closed', 'rejected'),
+		       EXISTS (SELECT 1 FROM appeals c WHERE c.parent_id = a.id)
+		FROM appeals a
+		WHERE a.id = ANY($1)
+		ORDER BY a.id
+		FOR UPDATE
+	`, lockIDs)
+	if err != nil {
+		return fmt.Errorf("failed to lock appeals: %w", err)
+	}
+	appeals := make(map[int64]incidentAppeal, len(lockIDs))
+	for rows.Next() {
+		var appealID int64
+		var appeal incidentAppeal
+		if err := rows.Scan(&appealID, &appeal.ParentID, &appeal.Open, &appeal.HasChildren); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan appeal: %w", err)
+		}
+		appeals[appealID] = appeal
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to lock appeals: %w", err)
+	}
+
+	master, children, err := planIncident(appealIDs, appeals, masterID)
+	if err != nil {
+		return err
+	}
+
+	if err := mergeAppeals(ctx, tx, master, children, userID); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE volume_alerts
+		SET status = $1, incident_appeal_id = $2, resolved_by = $3, resolved_at = NOW()
+		WHERE id = $4
+	`
+	if _, err := tx.Exec(ctx, query, models.VolumeAlertGrouped, master, userID, id); err != nil {
+		return fmt.Errorf("failed to resolve volume alert: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanIncident(t *testing.T) {
+	parent := int64(99)
+	appeals := map[int64]incidentAppeal{
+		1: {Open: true},
+		2: {Open: true},
+		3: {Open: false},
+		4: {Open: true, ParentID: &parent},
+		5: {Open: true, HasChildren: true},
+		6: {Open: true},
+	}
+
+	master, children, err := planIncident([]int64{3, 1, 2, 4, 5, 7, 6, 2}, appeals, nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(1), master, "the first appeal that can be merged leads")
+		assert.Equal(t, []int64{2, 6}, children, "resolved, merged, linked and deleted appeals are left out")
+	}
+
+	chosen := int64(5)
+	master, children, err = planIncident([]int64{1, 5, 6}, appeals, &chosen)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(5), master, "a chosen master may already have linked appeals")
+		assert.Equal(t, []int64{1, 6}, children)
+	}
+
+	resolved := int64(3)
+	_, _, err = planIncident([]int64{1, 2}, appeals, &resolved)
+	assert.ErrorIs(t, err, ErrInvalidIncidentMaster)
+
+	merged := int64(4)
+	_, _, err = planIncident([]int64{1, 2}, appeals, &merged)
+	assert.ErrorIs(t, err, ErrInvalidIncidentMaster)
+
+	missing := int64(8)
+	_, _, err = planIncident([]int64{1, 2}, appeals, &missing)
+	assert.ErrorIs(t, err, ErrAppealNotFound)
+
+	_, _, err = planIncident([]int64{3, 4, 6}, appeals, nil)
+	assert.ErrorIs(t, err, ErrNothingToGroup, "one appeal is no incident")
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+)
+
+var (
+	// ErrAppealAlreadyClaimed is returned when an executor claims an appeal another executor owns.
+	ErrAppealAlreadyClaimed = errors.New("appeal is already assigned to another executor")
+	// ErrNotServiceExecutor is returned when the executor does not work for the appeal's service.
+	ErrNotServiceExecutor = errors.New("executor does not belong to the appeal's service")
+	// ErrAppealWithoutService is returned when an executor is assigned before the appeal is routed.
+	ErrAppealWithoutService = errors.New("appeal is not assigned to a service")
+	// ErrNotAppealService is returned when an executor acts on an appeal of a service they do not work for.
+	ErrNotAppealService = errors.New("appeal is not assigned to the executor's service")
+)
+
+// ClaimAppeal lets an executor take an unclaimed appeal of their service.
+// Claiming an appeal the executor already owns is a no-op. The claim is checked
+// again when the assignee is written, so concurrent claims cannot overwrite each other.
+func (s *AppealService) ClaimAppeal(ctx context.Context, appealID, executorID int64) (*models.Appeal, error) {
+	appeal, err := s.assignableAppeal(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkClaim(appeal, executorID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkServiceExecutor(ctx, executorID, *appeal.ServiceID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.Claim(ctx, appealID, executorID); err != nil {
+		if errors.Is(err, repository.ErrAppealClaimed) {
+			return nil, ErrAppealAlreadyClaimed
+		}
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, appealID)
+}
+
+// checkClaim reports whether the executor may claim the appeal: it must have no
+// executor yet, or have this one.
+func checkClaim(appeal *models.Appeal, executorID int64) error {
+	if appeal.AssigneeID != nil && *appeal.AssigneeID != executorID {
+		return ErrAppealAlreadyClaimed
+	}
+	return nil
+}
+
+// AssignExecutor sets the executor responsible for an appeal on behalf of a dispatcher.
+// A nil executorID takes the appeal back to the service queue.
+// The returned flag reports whether the assignee actually changed.
+func (s *AppealService) AssignExecutor(ctx context.Context, appealID int64, executorID *int64, userID int64) (*models.Appeal, bool, error) {
+	appeal, err := s.assignableAppeal(ctx, appealID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if executorID != nil {
+		if err := s.checkServiceExecutor(ctx, *executorID, *appeal.ServiceID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	changed, err := s.repo.SetAssignee(ctx, appealID, executorID, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	appeal, err = s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return appeal, changed, nil
+}
+
+// assignableAppeal loads an appeal that can get an executor: it must be routed
+// to a service and still be open.
+func (s *AppealService) assignableAppeal(ctx context.Context, appealID int64) (*models.Appeal, error) {
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+
+	if appeal.ServiceID == nil {
+		return nil, ErrAppealWithoutService
+	}
+	if !IsOpenStatus(appeal.Status) {
+		return nil, ErrAppealNotOpen
+	}
+
+	return appeal, nil
+}
+
+// checkServiceExecutor makes sure the user is an active executor of the service.
+func (s *AppealService) checkServiceExecutor(ctx context.Context, executorID, serviceID int64) error {
+	ok, err := s.userServiceRepo.IsServiceExecutor(ctx, executorID, serviceID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotServiceExecutor
+	}
+	return nil
+}
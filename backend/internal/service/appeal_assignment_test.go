@@ -0,0 +1,18 @@
+package service
+
+import (
+	"testing"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckClaim(t *testing.T) {
+	executorID, otherID := int64(7), int64(8)
+
+	assert.NoError(t, checkClaim(&models.Appeal{ID: 1}, executorID), "an unclaimed appeal can be claimed")
+	assert.NoError(t, checkClaim(&models.Appeal{ID: 1, AssigneeID: &executorID}, executorID),
+		"claiming an own appeal again is a no-op")
+	assert.ErrorIs(t, checkClaim(&models.Appeal{ID: 1, AssigneeID: &otherID}, executorID), ErrAppealAlreadyClaimed)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+)
+
+// autoCloseBatchSize limits how many appeals are closed per run
+const autoCloseBatchSize = 500
+
+// AppealAutoCloser periodically closes completed appeals whose authors did not
+// confirm or reopen them within the confirmation window.
+type AppealAutoCloser struct {
+	repo                 *repository.AppealRepository
+	notificationService  *NotificationService
+	systemSettingsLoader func(context.Context) (*models.SystemSettings, error)
+	interval             time.Duration
+}
+
+// NewAppealAutoCloser creates a new AppealAutoCloser instance.
+func NewAppealAutoCloser(
+	repo *repository.AppealRepository,
+	notificationService *NotificationService,
+	systemSettingsLoader func(context.Context) (*models.SystemSettings, error),
+	interval time.Duration,
+) *AppealAutoCloser {
+	return &AppealAutoCloser{
+		repo:                 repo,
+		notificationService:  notificationService,
+		systemSettingsLoader: systemSettingsLoader,
+		interval:             interval,
+	}
+}
+
+// Run closes unanswered appeals every interval until ctx is cancelled.
+func (c *AppealAutoCloser) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := c.CloseUnanswered(ctx); err != nil {
+			log.Printf("Auto-close failed: %v", err)
+		} else if n > 0 {
+			log.Printf("Auto-close: closed %d appeals", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CloseUnanswered closes appeals that stayed completed past the confirmation window
+// and notifies their authors. Returns how many appeals were closed.
+func (c *AppealAutoCloser) CloseUnanswered(ctx context.Context) (int, error) {
+	windowDays := confirmationWindowDays(ctx, c.systemSettingsLoader)
+
+	appeals, err := c.repo.AutoCloseCompleted(ctx, windowDays, autoCloseBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	if c.notificationService != nil {
+		for _, appeal := range appeals {
+			if err := c.notificationService.SendStatusChanged(ctx, appeal, models.StatusCompleted); err != nil {
+				log.Printf("Failed to send auto-close notification for appeal %d: %v", appeal.ID, err)
+			}
+		}
+	}
+
+	return len(appeals), nil
+}
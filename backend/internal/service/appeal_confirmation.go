@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"citizen-appeals/internal/models"
+)
+
+var (
+	// ErrNotAppealAuthor is returned when someone other than the author answers a completed appeal.
+	ErrNotAppealAuthor = errors.New("only the author of the appeal can do this")
+	// ErrConfirmationWindowClosed is returned when the author tries to reopen too late.
+	ErrConfirmationWindowClosed = errors.New("confirmation window is over")
+)
+
+// ConfirmCompletion lets the author accept the executor's result: completed -> closed.
+// A merged appeal confirms its master appeal, the same way ReopenAppeal does.
+// It returns the closed appeal as it was before the change.
+func (s *AppealService) ConfirmCompletion(ctx context.Context, appealID, userID int64) (*models.Appeal, error) {
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+
+	if appeal.UserID != userID {
+		return nil, ErrNotAppealAuthor
+	}
+
+	target := appeal
+	if appeal.ParentID != nil {
+		target, err = s.repo.GetByID(ctx, *appeal.ParentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ValidateStatusTransition(models.RoleCitizen, target.Status, models.StatusClosed); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateStatus(ctx, target.ID, target.Status, models.StatusClosed, userID, nil); err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// ReopenAppeal lets the author reject the executor's result: completed -> reopened,
+// which sends the appeal back to its service. A merged appeal reopens its master
+// appeal, since they describe one incident; the change then flows to all linked appeals.
+// It returns the reopened appeal as it was before the change.
+func (s *AppealService) ReopenAppeal(ctx context.Context, appealID, userID int64, comment string) (*models.Appeal, error) {
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+
+	if appeal.UserID != userID {
+		return nil, ErrNotAppealAuthor
+	}
+
+	target := appeal
+	if appeal.ParentID != nil {
+		target, err = s.repo.GetByID(ctx, *appeal.ParentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := ValidateStatusTransition(models.RoleCitizen, target.Status, models.StatusReopened); err != nil {
+		return nil, err
+	}
+
+	if target.CompletedAt != nil && time.Since(*target.CompletedAt) > s.confirmationWindow(ctx) {
+		return nil, ErrConfirmationWindowClosed
+	}
+
+	if err := s.repo.UpdateStatus(ctx, target.ID, target.Status, models.StatusReopened, userID, &comment); err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// confirmationWindow returns how long the author may answer a completed appeal.
+func (s *AppealService) confirmationWindow(ctx context.Context) time.Duration {
+	return time.Duration(confirmationWindowDays(ctx, s.systemSettingsLoader)) * 24 * time.Hour
+}
+
+// confirmationWindowDays reads the confirmation window from system settings.
+func confirmationWindowDays(ctx context.Context, loader func(context.Context) (*models.SystemSettings, error)) int {
+	if loader != nil {
+		settings, err := loader(ctx)
+		if err == nil && settings != nil && settings.ConfirmationWindowDays > 0 {
+			return settings.ConfirmationWindowDays
+		}
+	}
+	return models.DefaultConfirmationWindowDays
+}
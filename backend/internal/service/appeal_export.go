@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/pkg/xlsx"
+)
+
+// ErrUnknownExportFormat is returned for export formats other than csv and xlsx.
+var ErrUnknownExportFormat = errors.New("unknown export format")
+
+// exportTimeLayout is how dates appear in exported spreadsheets
+const exportTimeLayout = "02.01.2006 15:04"
+
+// AppealExportHeaders are the column titles of the appeal export
+var AppealExportHeaders = []string{
+	"№", "Назва", "Опис", "Статус", "Пріоритет", "Категорія", "Служба", "Район", "Адреса",
+	"Широта", "Довгота", "Автор", "Email автора", "Телефон автора", "Виконавець",
+	"Створено", "Оновлено", "Виконано", "Закрито",
+	"Остання зміна статусу", "Статус змінив", "Головне звернення", "Додаткові поля",
+}
+
+// AppealStatusLabels are the Ukrainian names of appeal statuses
+var AppealStatusLabels = map[models.AppealStatus]string{
+	models.StatusNew:        "Нове",
+	models.StatusAssigned:   "Призначене",
+	models.StatusInProgress: "В роботі",
+	models.StatusCompleted:  "Виконане",
+	models.StatusClosed:     "Закрите",
+	models.StatusRejected:   "Відхилене",
+	models.StatusReopened:   "Повторно відкрите",
+}
+
+var priorityLabels = map[int]string{1: "Низький", 2: "Середній", 3: "Високий"}
+
+// appealExportWriter is a spreadsheet being written row by row
+type appealExportWriter interface {
+	WriteHeader(titles []string) error
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+// ExportAppeals writes every appeal matching the filters to w as a spreadsheet,
+// ignoring pagination. Rows are streamed as they are read from the database.
+func (s *AppealService) ExportAppeals(ctx context.Context, w io.Writer, filters *models.AppealFilters, format models.AppealExportFormat) error {
+	out, err := newAppealExportWriter(w, format)
+	if err != nil {
+		return err
+	}
+
+	if err := out.WriteHeader(AppealExportHeaders); err != nil {
+		return err
+	}
+
+	err = s.repo.Export(ctx, filters, func(row *models.AppealExportRow) error {
+		return out.WriteRow(AppealExportValues(row))
+	})
+	if err != nil {
+		return err
+	}
+
+	return out.Close()
+}
+
+func newAppealExportWriter(w io.Writer, format models.AppealExportFormat) (appealExportWriter, error) {
+	switch format {
+	case models.ExportCSV:
+		return newCSVExportWriter(w)
+	case models.ExportXLSX:
+		return xlsx.NewWriter(w, "Звернення")
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownExportFormat, format)
+}
+
+// AppealExportValues converts an appeal into the cells of one export row, in the order
+// of AppealExportHeaders. Numbers stay numbers; missing values are nil.
+func AppealExportValues(row *models.AppealExportRow) []interface{} {
+	a := row.Appeal
+
+	status := AppealStatusLabels[a.Status]
+	if status == "" {
+		status = string(a.Status)
+	}
+	priority := priorityLabels[a.Priority]
+	if priority == "" {
+		priority = strconv.Itoa(a.Priority)
+	}
+
+	var category, service, district, author, email, phone, assignee interface{}
+	if a.Category != nil {
+		category = a.Category.Name
+	}
+	if a.Service != nil {
+		service = a.Service.Name
+	}
+	if a.District != nil {
+		district = a.District.Name
+	}
+	if a.User != nil {
+		author = strings.TrimSpace(a.User.FirstName + " " + a.User.LastName)
+		email = a.User.Email
+		phone = a.User.Phone
+	}
+	if a.Assignee != nil {
+		assignee = strings.TrimSpace(a.Assignee.FirstName + " " + a.Assignee.LastName)
+	}
+
+	var changedBy, parent, customFields interface{}
+	if row.LastStatusChangeBy != nil {
+		changedBy = *row.LastStatusChangeBy
+	}
+	if a.ParentID != nil {
+		parent = *a.ParentID
+	}
+	if len(a.CustomFields) > 0 {
+		var fields []models.CustomField
+		if a.Category != nil {
+			fields = a.Category.CustomFields
+		}
+		customFields = FormatCustomFields(fields, a.CustomFields)
+	}
+
+	return []interface{}{
+		a.ID, a.Title, a.Description, status, priority, category, service, district, a.Address,
+		a.Latitude, a.Longitude, author, email, phone, assignee,
+		exportTime(&a.CreatedAt), exportTime(&a.UpdatedAt), exportTime(a.CompletedAt), exportTime(a.ClosedAt),
+		exportTime(row.LastStatusChangeAt), changedBy, parent, customFields,
+	}
+}
+
+func exportTime(t *time.Time) interface{} {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return t.Local().Format(exportTimeLayout)
+}
+
+// csvExportWriter writes UTF-8 CSV that Excel opens with the right encoding
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer) (*csvExportWriter, error) {
+	// The byte order mark tells Excel the file is UTF-8
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	return &csvExportWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (c *csvExportWriter) WriteHeader(titles []string) error {
+	return c.w.Write(titles)
+}
+
+func (c *csvExportWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			record[i] = csvSafeText(v)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvExportWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// csvSafeText keeps spreadsheet programs from running text typed by citizens as a formula.
+// A leading tab or carriage return is escaped too, since some programs skip it and read
+// the formula that follows.
+func csvSafeText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
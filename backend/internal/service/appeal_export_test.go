@@ -0,0 +1,100 @@
+package service
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppealExportValues(t *testing.T) {
+	categoryID := int64(3)
+	created := time.Date(2024, 5, 10, 9, 30, 0, 0, time.Local)
+	changed := created.Add(26 * time.Hour)
+	depthFields := []models.CustomField{{Key: "depth_cm", Label: "Глибина, см", Type: models.CustomFieldNumber}}
+	changedBy := "Олена Диспетчер"
+
+	row := &models.AppealExportRow{
+		Appeal: &models.Appeal{
+			ID:          42,
+			Title:       "Яма на дорозі",
+			Description: "Глибока яма біля школи",
+			Status:      models.StatusInProgress,
+			Priority:    3,
+			CategoryID:  &categoryID,
+			Category:    &models.Category{ID: categoryID, Name: "Дороги", CustomFields: depthFields},
+			Address:     "вул. Хрещатик, 1",
+			Latitude:    50.4501,
+			Longitude:   30.5234,
+			User:        &models.User{FirstName: "Іван", LastName: "Петренко", Email: "ivan@example.com", Phone: "+380501234567"},
+			CreatedAt:   created,
+			UpdatedAt:   changed,
+
+			CustomFields: map[string]interface{}{"depth_cm": float64(15)},
+		},
+		LastStatusChangeAt: &changed,
+		LastStatusChangeBy: &changedBy,
+	}
+
+	values := AppealExportValues(row)
+	if !assert.Len(t, values, len(AppealExportHeaders)) {
+		return
+	}
+
+	assert.Equal(t, int64(42), values[0])
+	assert.Equal(t, "В роботі", values[3])
+	assert.Equal(t, "Високий", values[4])
+	assert.Equal(t, "Дороги", values[5])
+	assert.Nil(t, values[6], "no service")
+	assert.Equal(t, 50.4501, values[9])
+	assert.Equal(t, "Іван Петренко", values[11])
+	assert.Equal(t, "ivan@example.com", values[12])
+	assert.Nil(t, values[14], "no assignee")
+	assert.Equal(t, "10.05.2024 09:30", values[15])
+	assert.Nil(t, values[17], "not completed")
+	assert.Equal(t, "11.05.2024 11:30", values[19])
+	assert.Equal(t, "Олена Диспетчер", values[20])
+	assert.Nil(t, values[21], "not merged")
+	assert.Equal(t, "Глибина, см: 15", values[22])
+}
+
+func TestCSVExportWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := newAppealExportWriter(&buf, models.ExportCSV)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, w.WriteHeader([]string{"№", "Назва", "Широта", "Служба"}))
+	assert.NoError(t, w.WriteRow([]interface{}{int64(1), "Текст, з комою", 50.45, nil}))
+	assert.NoError(t, w.WriteRow([]interface{}{int64(2), "=HYPERLINK(\"http://evil\")", 50.5, "Водоканал"}))
+	assert.NoError(t, w.Close())
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "\ufeff№,Назва,Широта,Служба\n"), "BOM and Ukrainian headers")
+	assert.Contains(t, out, "1,\"Текст, з комою\",50.45,\n")
+	assert.Contains(t, out, `2,"'=HYPERLINK(""http://evil"")",50.5,Водоканал`, "formulas are neutralized")
+}
+
+func TestCSVSafeText(t *testing.T) {
+	tests := map[string]string{
+		"Яма на дорозі": "Яма на дорозі",
+		"=1+1":          "'=1+1",
+		"-2":            "'-2",
+		"\t=1+1":        "'\t=1+1",
+		"\r@SUM(A1)":    "'\r@SUM(A1)",
+		"":              "",
+	}
+	for input, want := range tests {
+		assert.Equal(t, want, csvSafeText(input), "%q", input)
+	}
+}
+
+func TestNewAppealExportWriter_UnknownFormat(t *testing.T) {
+	_, err := newAppealExportWriter(&bytes.Buffer{}, models.AppealExportFormat("pdf"))
+	assert.ErrorIs(t, err, ErrUnknownExportFormat)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"citizen-appeals/internal/models"
+)
+
+const (
+	// MaxMapPoints limits the plain (unclustered) feed
+	MaxMapPoints = 5000
+	// clusterCellsPerTile is how many grid cells fit across a 256px map tile,
+	// i.e. a cluster covers about 64px on screen at any zoom
+	clusterCellsPerTile = 4
+	minMapZoom          = 1
+	maxMapZoom          = 20
+	defaultMapZoom      = 13
+)
+
+// ClusterCellSize returns the side of a cluster grid cell in degrees for a zoom level.
+// A tile spans 360/2^zoom degrees of longitude.
+func ClusterCellSize(zoom int) float64 {
+	return 360 / math.Exp2(float64(clampZoom(zoom))) / clusterCellsPerTile
+}
+
+func clampZoom(zoom int) int {
+	if zoom < minMapZoom {
+		return minMapZoom
+	}
+	if zoom > maxMapZoom {
+		return maxMapZoom
+	}
+	return zoom
+}
+
+// BuildPointFeatures turns appeals into GeoJSON point features.
+func BuildPointFeatures(points []*models.AppealMapPoint) []models.MapFeature {
+	features := make([]models.MapFeature, 0, len(points))
+	for _, p := range points {
+		features = append(features, models.MapFeature{
+			Type:     "Feature",
+			ID:       p.ID,
+			Geometry: mapPoint(p.Latitude, p.Longitude),
+			Properties: map[string]interface{}{
+				"title":         p.Title,
+				"status":        p.Status,
+				"priority":      p.Priority,
+				"category_id":   p.CategoryID,
+				"category_name": p.CategoryName,
+				"created_at":    p.CreatedAt,
+			},
+		})
+	}
+	return features
+}
+
+// BuildClusterFeatures merges per-status grid cells into one feature per cell, placed
+// at the mean location of its appeals. A cell with a single appeal carries its ID.
+func BuildClusterFeatures(cells []*models.AppealClusterCell) []models.MapFeature {
+	type cluster struct {
+		count      int64
+		sumLat     float64
+		sumLng     float64
+		byStatus   map[models.AppealStatus]int64
+		firstID    int64
+		cellX      int64
+		cellY      int64
+		firstIndex int
+	}
+
+	type cellKey struct{ x, y int64 }
+	clusters := make(map[cellKey]*cluster)
+	for i, c := range cells {
+		key := cellKey{c.CellX, c.CellY}
+		cl, ok := clusters[key]
+		if !ok {
+			cl = &cluster{byStatus: make(map[models.AppealStatus]int64), firstID: c.AppealID, cellX: c.CellX, cellY: c.CellY, firstIndex: i}
+			clusters[key] = cl
+		}
+		cl.count += c.Count
+		cl.sumLat += c.Latitude * float64(c.Count)
+		cl.sumLng += c.Longitude * float64(c.Count)
+		cl.byStatus[c.Status] += c.Count
+		if c.AppealID < cl.firstID {
+			cl.firstID = c.AppealID
+		}
+	}
+
+	ordered := make([]*cluster, 0, len(clusters))
+	for _, cl := range clusters {
+		ordered = append(ordered, cl)
+	}
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].firstIndex < ordered[j].firstIndex })
+
+	features := make([]models.MapFeature, 0, len(ordered))
+	for _, cl := range ordered {
+		properties := map[string]interface{}{
+			"cluster":   cl.count > 1,
+			"count":     cl.count,
+			"by_status": cl.byStatus,
+		}
+		if cl.count == 1 {
+			properties["appeal_id"] = cl.firstID
+		}
+		features = append(features, models.MapFeature{
+			Type:       "Feature",
+			ID:         fmt.Sprintf("%d:%d", cl.cellX, cl.cellY),
+			Geometry:   mapPoint(cl.sumLat/float64(cl.count), cl.sumLng/float64(cl.count)),
+			Properties: properties,
+		})
+	}
+	return features
+}
+
+func mapPoint(lat, lng float64) models.MapPoint {
+	return models.MapPoint{Type: "Point", Coordinates: [2]float64{lng, lat}}
+}
+
+// AppealMap returns filtered appeals as a GeoJSON FeatureCollection, either as single
+// points or, in cluster mode, grouped on a grid that gets finer as the map zooms in.
+// A zero zoom means the zoom from the system settings.
+func (s *AppealService) AppealMap(ctx context.Context, filters *models.AppealFilters, cluster bool, zoom int) (*models.MapFeatureCollection, error) {
+	collection := &models.MapFeatureCollection{Type: "FeatureCollection", Zoom: zoom}
+
+	if s.systemSettingsLoader != nil {
+		settings, err := s.systemSettingsLoader(ctx)
+		if err != nil {
+			log.Printf("Failed to load system settings for the map: %v", err)
+		} else if settings != nil {
+			collection.Center = [2]float64{settings.MapCenterLng, settings.MapCenterLat}
+			if collection.Zoom == 0 {
+				collection.Zoom = settings.MapZoom
+			}
+		}
+	}
+	if collection.Zoom == 0 {
+		collection.Zoom = defaultMapZoom
+	}
+	collection.Zoom = clampZoom(collection.Zoom)
+
+	if cluster {
+		cells, err := s.repo.ListClusterCells(ctx, filters, ClusterCellSize(collection.Zoom))
+		if err != nil {
+			return nil, err
+		}
+		collection.Features = BuildClusterFeatures(cells)
+		return collection, nil
+	}
+
+	points, truncated, err := s.repo.ListMapPoints(ctx, filters, MaxMapPoints)
+	if err != nil {
+		return nil, err
+	}
+	collection.Features = BuildPointFeatures(points)
+	collection.Truncated = truncated
+	return collection, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterCellSize(t *testing.T) {
+	assert.Equal(t, 360.0/2/4, ClusterCellSize(1))
+	assert.InDelta(t, 360.0/8192/4, ClusterCellSize(13), 1e-12)
+	assert.Equal(t, ClusterCellSize(13)/2, ClusterCellSize(14), "every zoom level halves the cell")
+	assert.Equal(t, ClusterCellSize(1), ClusterCellSize(0), "zoom is clamped")
+	assert.Equal(t, ClusterCellSize(20), ClusterCellSize(25))
+}
+
+func TestBuildClusterFeatures(t *testing.T) {
+	cells := []*models.AppealClusterCell{
+		{CellX: 1, CellY: 1, Status: models.StatusNew, Count: 3, Latitude: 50.0, Longitude: 30.0, AppealID: 7},
+		{CellX: 1, CellY: 1, Status: models.StatusInProgress, Count: 1, Latitude: 50.4, Longitude: 30.4, AppealID: 4},
+		{CellX: 2, CellY: 1, Status: models.StatusCompleted, Count: 1, Latitude: 50.1, Longitude: 30.9, AppealID: 12},
+	}
+
+	features := BuildClusterFeatures(cells)
+	if !assert.Len(t, features, 2) {
+		return
+	}
+
+	first := features[0]
+	assert.Equal(t, "1:1", first.ID)
+	assert.Equal(t, true, first.Properties["cluster"])
+	assert.Equal(t, int64(4), first.Properties["count"])
+	assert.Equal(t, map[models.AppealStatus]int64{models.StatusNew: 3, models.StatusInProgress: 1}, first.Properties["by_status"])
+	assert.InDelta(t, 30.1, first.Geometry.Coordinates[0], 1e-9, "longitude is weighted by count")
+	assert.InDelta(t, 50.1, first.Geometry.Coordinates[1], 1e-9)
+	assert.NotContains(t, first.Properties, "appeal_id")
+
+	single := features[1]
+	assert.Equal(t, false, single.Properties["cluster"])
+	assert.Equal(t, int64(12), single.Properties["appeal_id"])
+	assert.Equal(t, [2]float64{30.9, 50.1}, single.Geometry.Coordinates)
+}
+
+func TestBuildPointFeatures(t *testing.T) {
+	features := BuildPointFeatures([]*models.AppealMapPoint{
+		{ID: 5, Title: "Яма", Status: models.StatusNew, Priority: 2, Latitude: 50.45, Longitude: 30.52},
+	})
+	if assert.Len(t, features, 1) {
+		assert.Equal(t, int64(5), features[0].ID)
+		assert.Equal(t, "Point", features[0].Geometry.Type)
+		assert.Equal(t, [2]float64{30.52, 50.45}, features[0].Geometry.Coordinates)
+		assert.Equal(t, models.StatusNew, features[0].Properties["status"])
+	}
+}
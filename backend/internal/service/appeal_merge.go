@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+)
+
+var (
+	// ErrInvalidMerge is returned when appeals cannot be merged into (or detached from) a master appeal.
+	ErrInvalidMerge = errors.New("invalid appeal merge")
+	// ErrAppealMerged is returned when a merged appeal is changed on its own instead of through its master.
+	ErrAppealMerged = errors.New("appeal is merged into another appeal")
+)
+
+// checkNotMerged refuses changes to an appeal merged into a master appeal: its status
+// follows the master's.
+func checkNotMerged(appeal *models.Appeal) error {
+	if appeal.ParentID != nil {
+		return fmt.Errorf("%w: change master appeal %d instead", ErrAppealMerged, *appeal.ParentID)
+	}
+	return nil
+}
+
+// checkMergeMaster checks that an appeal can take other appeals in.
+func checkMergeMaster(master *models.Appeal) error {
+	if master.ParentID != nil {
+		return fmt.Errorf("%w: appeal %d is itself merged into appeal %d", ErrInvalidMerge, master.ID, *master.ParentID)
+	}
+	if !IsOpenStatus(master.Status) {
+		return fmt.Errorf("%w: master appeal %d is already resolved", ErrInvalidMerge, master.ID)
+	}
+	return nil
+}
+
+// checkMergeChild checks that an appeal can be merged into the master appeal.
+// It returns false for an appeal already linked to the master, which needs no merge.
+// Whether the appeal has linked appeals of its own is checked separately.
+func checkMergeChild(masterID int64, child *models.Appeal) (bool, error) {
+	if child.ID == masterID {
+		return false, fmt.Errorf("%w: appeal cannot be merged into itself", ErrInvalidMerge)
+	}
+	if child.ParentID != nil {
+		if *child.ParentID == masterID {
+			return false, nil
+		}
+		return false, fmt.Errorf("%w: appeal %d is already merged into appeal %d", ErrInvalidMerge, child.ID, *child.ParentID)
+	}
+	if !IsOpenStatus(child.Status) {
+		return false, fmt.Errorf("%w: appeal %d is already resolved", ErrInvalidMerge, child.ID)
+	}
+	return true, nil
+}
+
+// MergeAppeals folds child appeals into a master appeal. Only one level is allowed:
+// a master cannot be merged into another appeal and a child cannot have children.
+func (s *AppealService) MergeAppeals(ctx context.Context, masterID int64, childIDs []int64, userID int64) error {
+	master, err := s.repo.GetByID(ctx, masterID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkMergeMaster(master); err != nil {
+		return err
+	}
+
+	toMerge := make([]int64, 0, len(childIDs))
+	seen := make(map[int64]bool)
+	for _, childID := range childIDs {
+		if seen[childID] {
+			continue
+		}
+		seen[childID] = true
+
+		if childID == masterID {
+			return fmt.Errorf("%w: appeal cannot be merged into itself", ErrInvalidMerge)
+		}
+
+		child, err := s.repo.GetByID(ctx, childID)
+		if err != nil {
+			return err
+		}
+
+		merge, err := checkMergeChild(masterID, child)
+		if err != nil {
+			return err
+		}
+		if !merge {
+			continue // already linked
+		}
+
+		children, err := s.repo.GetChildren(ctx, childID)
+		if err != nil {
+			return err
+		}
+		if len(children) > 0 {
+			return fmt.Errorf("%w: appeal %d has its own linked appeals", ErrInvalidMerge, childID)
+		}
+
+		toMerge = append(toMerge, childID)
+	}
+
+	if len(toMerge) == 0 {
+		return nil
+	}
+
+	// The checks above are repeated on locked rows, in case a concurrent merge got in between
+	if err := s.repo.Merge(ctx, masterID, toMerge, userID); err != nil {
+		if errors.Is(err, repository.ErrMergeConflict) {
+			return fmt.Errorf("%w: %v", ErrInvalidMerge, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// UnmergeAppeals detaches child appeals from a master appeal.
+func (s *AppealService) UnmergeAppeals(ctx context.Context, masterID int64, childIDs []int64, userID int64) error {
+	if _, err := s.repo.GetByID(ctx, masterID); err != nil {
+		return err
+	}
+
+	children, err := s.repo.GetChildren(ctx, masterID)
+	if err != nil {
+		return err
+	}
+
+	toUnmerge, err := selectUnmerge(masterID, childIDs, children)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Unmerge(ctx, masterID, toUnmerge, userID)
+}
+
+// selectUnmerge returns the distinct requested appeals to detach, all of which must be
+// among the master's children.
+func selectUnmerge(masterID int64, childIDs []int64, children []*models.Appeal) ([]int64, error) {
+	linked := make(map[int64]bool, len(children))
+	for _, child := range children {
+		linked[child.ID] = true
+	}
+
+	toUnmerge := make([]int64, 0, len(childIDs))
+	seen := make(map[int64]bool)
+	for _, childID := range childIDs {
+		if seen[childID] {
+			continue
+		}
+		seen[childID] = true
+
+		if !linked[childID] {
+			return nil, fmt.Errorf("%w: appeal %d is not merged into appeal %d", ErrInvalidMerge, childID, masterID)
+		}
+		toUnmerge = append(toUnmerge, childID)
+	}
+	return toUnmerge, nil
+}
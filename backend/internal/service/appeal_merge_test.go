@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"citizen-appeals/internal/models"
+)
+
+func TestCheckMergeMaster(t *testing.T) {
+	parent := int64(3)
+
+	assert.NoError(t, checkMergeMaster(&models.Appeal{ID: 1, Status: models.StatusInProgress}))
+	assert.ErrorIs(t, checkMergeMaster(&models.Appeal{ID: 1, Status: models.StatusNew, ParentID: &parent}), ErrInvalidMerge,
+		"a merged appeal cannot be a master")
+	assert.ErrorIs(t, checkMergeMaster(&models.Appeal{ID: 1, Status: models.StatusClosed}), ErrInvalidMerge,
+		"a closed master cannot take appeals in")
+	assert.ErrorIs(t, checkMergeMaster(&models.Appeal{ID: 1, Status: models.StatusRejected}), ErrInvalidMerge)
+}
+
+func TestCheckMergeChild(t *testing.T) {
+	master := int64(1)
+	other := int64(9)
+
+	merge, err := checkMergeChild(master, &models.Appeal{ID: 2, Status: models.StatusNew})
+	assert.NoError(t, err)
+	assert.True(t, merge)
+
+	_, err = checkMergeChild(master, &models.Appeal{ID: 1, Status: models.StatusNew})
+	assert.ErrorIs(t, err, ErrInvalidMerge, "an appeal cannot be merged into itself")
+
+	merge, err = checkMergeChild(master, &models.Appeal{ID: 2, Status: models.StatusNew, ParentID: &master})
+	assert.NoError(t, err)
+	assert.False(t, merge, "already linked appeals are skipped")
+
+	_, err = checkMergeChild(master, &models.Appeal{ID: 2, Status: models.StatusNew, ParentID: &other})
+	assert.ErrorIs(t, err, ErrInvalidMerge, "no nesting: an appeal merged elsewhere stays there")
+
+	_, err = checkMergeChild(master, &models.Appeal{ID: 2, Status: models.StatusCompleted})
+	assert.ErrorIs(t, err, ErrInvalidMerge, "resolved appeals are not merged")
+}
+
+func TestSelectUnmerge(t *testing.T) {
+	children := []*models.Appeal{{ID: 2}, {ID: 3}}
+
+	ids, err := selectUnmerge(1, []int64{3, 2, 3}, children)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 2}, ids)
+
+	_, err = selectUnmerge(1, []int64{2, 4}, children)
+	assert.ErrorIs(t, err, ErrInvalidMerge, "only linked appeals can be detached")
+}
+
+func TestCheckNotMerged(t *testing.T) {
+	master := int64(1)
+
+	assert.NoError(t, checkNotMerged(&models.Appeal{ID: 1}))
+	err := checkNotMerged(&models.Appeal{ID: 2, ParentID: &master})
+	if assert.ErrorIs(t, err, ErrAppealMerged) {
+		assert.Contains(t, err.Error(), "master appeal 1")
+	}
+}
@@ -2,11 +2,14 @@ package service
 
 import (
 	"context"
+	"errors"
 	"log"
+	"time"
 
 	"citizen-appeals/internal/models"
 	"citizen-appeals/internal/repository"
 	"citizen-appeals/pkg/classification"
+	"citizen-appeals/pkg/geo"
 )
 
 // AppealService contains business logic related to appeals.
@@ -14,39 +17,69 @@ import (
 type AppealService struct {
 	repo                 *repository.AppealRepository
 	serviceRepo          *repository.ServiceRepository
+	categoryRepo         *repository.CategoryRepository
+	slaRepo              *repository.SLAPolicyRepository
+	userServiceRepo      *repository.UserServiceRepository
+	priorityRuleRepo     *repository.PriorityRuleRepository
+	districtRepo         *repository.DistrictRepository
 	classifier           *classification.Classifier
 	systemSettingsLoader func(context.Context) (*models.SystemSettings, error)
+	cityBoundaryLoader   func(context.Context) (geo.Area, error)
 }
 
 // NewAppealService creates a new AppealService instance.
 func NewAppealService(
 	repo *repository.AppealRepository,
 	serviceRepo *repository.ServiceRepository,
+	categoryRepo *repository.CategoryRepository,
+	slaRepo *repository.SLAPolicyRepository,
+	userServiceRepo *repository.UserServiceRepository,
+	priorityRuleRepo *repository.PriorityRuleRepository,
+	districtRepo *repository.DistrictRepository,
 	classifier *classification.Classifier,
 	systemSettingsLoader func(context.Context) (*models.SystemSettings, error),
+	cityBoundaryLoader func(context.Context) (geo.Area, error),
 ) *AppealService {
 	return &AppealService{
 		repo:                 repo,
 		serviceRepo:          serviceRepo,
+		categoryRepo:         categoryRepo,
+		slaRepo:              slaRepo,
+		userServiceRepo:      userServiceRepo,
+		priorityRuleRepo:     priorityRuleRepo,
+		districtRepo:         districtRepo,
 		classifier:           classifier,
 		systemSettingsLoader: systemSettingsLoader,
+		cityBoundaryLoader:   cityBoundaryLoader,
 	}
 }
 
 // CreateAppeal encapsulates the logic of creating a new appeal:
-// - applies default / provided priority
-// - automatically assigns service ONLY through classification service
-// - persists the appeal via repository.
+// - rejects locations outside the city boundary
+// - validates the values of the category's custom fields
+// - applies the provided priority or the category default, then lets priority rules raise it
+// - computes SLA deadlines from the matching SLA policy
+// - looks for possible duplicates nearby and stores the best match score
+// - finds the district of the appeal's location
+// - routes the appeal by the (category, district) routing table, else through classification
+// - hands the appeal to an executor if the service uses automatic assignment
+// - persists the appeal via repository, claiming the author's staged photos.
 func (s *AppealService) CreateAppeal(
 	ctx context.Context,
 	userID int64,
 	req models.CreateAppealRequest,
 ) (*models.Appeal, error) {
-	priority := 2 // Default priority (середній)
-	if req.Priority != nil {
-		priority = *req.Priority
+	if err := s.CheckCityBoundary(ctx, req.Latitude, req.Longitude); err != nil {
+		return nil, err
 	}
 
+	customFields, err := s.ValidateAppealCustomFields(ctx, req.CategoryID, req.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+
+	priority := s.basePriority(ctx, req.CategoryID, req.Priority)
+
 	categoryID := req.CategoryID
 	appeal := &models.Appeal{
 		UserID:      userID,
@@ -58,13 +91,57 @@ func (s *AppealService) CreateAppeal(
 		Longitude:   req.Longitude,
 		Status:      models.StatusNew,
 		Priority:    priority,
+		CreatedAt:   time.Now(),
+
+		CustomFields: customFields,
+	}
+
+	// Staged photos count now; photos uploaded after creation trigger another check
+	var firedRules []*models.PriorityRule
+	appeal.Priority, firedRules = s.applyPriorityRules(ctx, appeal, len(req.PhotoIDs))
+
+	s.applySLADeadlines(ctx, appeal, appeal.CreatedAt)
+
+	// An unknown district only disables district routing
+	districtID, err := s.ResolveDistrict(ctx, req.Latitude, req.Longitude)
+	if err != nil {
+		log.Printf("Failed to resolve district of appeal: %v", err)
+	}
+	appeal.DistrictID = districtID
+
+	// Duplicate detection must not block submission
+	duplicates, err := s.FindDuplicates(ctx, req.CategoryID, req.Latitude, req.Longitude, req.Description)
+	if err != nil {
+		log.Printf("Duplicate detection error: %v", err)
+	} else if len(duplicates) > 0 {
+		best := duplicates[0]
+		appeal.DuplicateOfID = &best.AppealID
+		appeal.DuplicateScore = &best.Score
 	}
 
-	if err := s.repo.Create(ctx, appeal); err != nil {
+	if err := s.repo.Create(ctx, appeal, req.PhotoIDs); err != nil {
 		return nil, err
 	}
+	appeal.PossibleDuplicates = duplicates
+
+	if len(firedRules) > 0 {
+		if err := s.priorityRuleRepo.RecordFired(ctx, appeal.ID, firedRules); err != nil {
+			log.Printf("Failed to record fired priority rules for appeal %d: %v", appeal.ID, err)
+		}
+	}
+
+	// The routing table is more precise than classification, so it goes first
+	if s.routeByDistrict(ctx, appeal) {
+		s.assignExecutorAfterRouting(ctx, appeal)
+	} else {
+		s.routeByClassification(ctx, appeal)
+	}
 
-	// Assign service ONLY through classification service
+	return appeal, nil
+}
+
+// routeByClassification assigns a service suggested by the classification service.
+func (s *AppealService) routeByClassification(ctx context.Context, appeal *models.Appeal) {
 	if s.classifier != nil {
 		// Load confidence threshold from system settings
 		if s.systemSettingsLoader != nil {
@@ -75,7 +152,7 @@ func (s *AppealService) CreateAppeal(
 		}
 
 		// Use only description for classification (title is often too short/generic)
-		serviceName, confidence, err := s.classifier.ClassifyAppeal(ctx, req.Description)
+		serviceName, confidence, err := s.classifier.ClassifyAppeal(ctx, appeal.Description)
 		if err != nil {
 			log.Printf("Classification error: %v", err)
 		} else if serviceName != "" {
@@ -88,6 +165,7 @@ func (s *AppealService) CreateAppeal(
 					log.Printf("Failed to assign service from classification: %v", err)
 				} else {
 					log.Printf("Assigned service '%s' from classification (confidence: %.2f)", serviceName, confidence)
+					s.assignExecutorAfterRouting(ctx, appeal)
 				}
 			} else {
 				log.Printf("Service '%s' from classification not found in database", serviceName)
@@ -98,8 +176,44 @@ func (s *AppealService) CreateAppeal(
 	} else {
 		log.Printf("Classification service is disabled, service will not be assigned automatically")
 	}
+}
 
-	return appeal, nil
+// assignExecutorAfterRouting hands a freshly routed appeal to an executor if the
+// service uses automatic assignment.
+func (s *AppealService) assignExecutorAfterRouting(ctx context.Context, appeal *models.Appeal) {
+	executorID, err := s.autoAssignExecutor(ctx, appeal.ID)
+	if err != nil {
+		log.Printf("Automatic executor assignment failed for appeal %d: %v", appeal.ID, err)
+	}
+	appeal.AssigneeID = executorID
+}
+
+// applySLADeadlines sets response and resolution deadlines on a new appeal.
+// Appeals without a matching policy get no deadlines.
+func (s *AppealService) applySLADeadlines(ctx context.Context, appeal *models.Appeal, createdAt time.Time) {
+	if s.slaRepo == nil {
+		return
+	}
+
+	policy, err := s.slaRepo.FindForAppeal(ctx, appeal.CategoryID, appeal.Priority)
+	if err != nil {
+		if !errors.Is(err, repository.ErrSLAPolicyNotFound) {
+			log.Printf("Failed to find SLA policy for appeal: %v", err)
+		}
+		return
+	}
+
+	responseDue, due := slaDeadlines(policy, createdAt)
+	appeal.ResponseDueAt = &responseDue
+	appeal.DueAt = &due
+}
+
+// slaDeadlines returns the response and resolution deadlines a policy sets for an
+// appeal created at createdAt. Priority changes recompute them the same way in SQL.
+func slaDeadlines(policy *models.SLAPolicy, createdAt time.Time) (responseDue, due time.Time) {
+	responseDue = createdAt.Add(time.Duration(policy.ResponseHours) * time.Hour)
+	due = createdAt.Add(time.Duration(policy.ResolutionHours) * time.Hour)
+	return responseDue, due
 }
 
 // ClassifyText classifies text and returns suggested service name and confidence
@@ -118,3 +232,82 @@ func (s *AppealService) ClassifyText(ctx context.Context, text string) (string,
 
 	return s.classifier.ClassifyAppeal(ctx, text)
 }
+
+// UpdateStatus changes the status of an appeal after checking the move against
+// the status transition table for the given role. Executors may only change appeals
+// of a service they work for. Merged appeals follow their master and are not changed alone.
+// It returns the appeal as it was before the change so callers can compare statuses.
+func (s *AppealService) UpdateStatus(
+	ctx context.Context,
+	appealID int64,
+	userID int64,
+	role models.UserRole,
+	newStatus models.AppealStatus,
+	comment *string,
+) (*models.Appeal, error) {
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkNotMerged(appeal); err != nil {
+		return nil, err
+	}
+
+	if role == models.RoleExecutor {
+		if appeal.ServiceID == nil {
+			return nil, ErrNotAppealService
+		}
+		if err := s.checkServiceExecutor(ctx, userID, *appeal.ServiceID); err != nil {
+			if errors.Is(err, ErrNotServiceExecutor) {
+				return nil, ErrNotAppealService
+			}
+			return nil, err
+		}
+	}
+
+	if err := ValidateStatusTransition(role, appeal.Status, newStatus); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateStatus(ctx, appealID, appeal.Status, newStatus, userID, comment); err != nil {
+		return nil, err
+	}
+
+	return appeal, nil
+}
+
+// AssignAppeal routes an appeal to a service. Assignment moves the appeal to
+// 'assigned', so it is subject to the same transition table as status changes.
+func (s *AppealService) AssignAppeal(
+	ctx context.Context,
+	appealID int64,
+	serviceID int64,
+	priority *int,
+	userID int64,
+	role models.UserRole,
+) error {
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkNotMerged(appeal); err != nil {
+		return err
+	}
+
+	if err := ValidateStatusTransition(role, appeal.Status, models.StatusAssigned); err != nil {
+		return err
+	}
+
+	if err := s.repo.Assign(ctx, appealID, serviceID, priority, userID); err != nil {
+		return err
+	}
+
+	// The service's assignment strategy must not fail the routing itself
+	if _, err := s.autoAssignExecutor(ctx, appealID); err != nil {
+		log.Printf("Automatic executor assignment failed for appeal %d: %v", appealID, err)
+	}
+
+	return nil
+}
@@ -1,8 +1,10 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"citizen-appeals/internal/models"
@@ -210,3 +212,21 @@ func TestAppealService_ErrorHandling(t *testing.T) {
 		assert.Contains(t, repoError.Error(), "database")
 	}
 }
+
+func TestSLADeadlines(t *testing.T) {
+	createdAt := time.Date(2024, 5, 10, 9, 30, 0, 0, time.UTC)
+	policy := &models.SLAPolicy{Priority: 3, ResponseHours: 4, ResolutionHours: 72}
+
+	responseDue, due := slaDeadlines(policy, createdAt)
+	assert.Equal(t, time.Date(2024, 5, 10, 13, 30, 0, 0, time.UTC), responseDue)
+	assert.Equal(t, time.Date(2024, 5, 13, 9, 30, 0, 0, time.UTC), due)
+}
+
+func TestApplySLADeadlines_WithoutPolicies(t *testing.T) {
+	s := &AppealService{}
+	appeal := &models.Appeal{Priority: 2}
+
+	s.applySLADeadlines(context.Background(), appeal, time.Now())
+	assert.Nil(t, appeal.ResponseDueAt, "no deadlines without SLA policies")
+	assert.Nil(t, appeal.DueAt)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"citizen-appeals/internal/models"
+)
+
+var (
+	// ErrUnknownStatus is returned when a status value is not part of the appeal lifecycle.
+	ErrUnknownStatus = errors.New("unknown appeal status")
+	// ErrInvalidStatusTransition is returned when a role is not allowed to move an appeal
+	// from its current status to the requested one.
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+)
+
+// StatusTransitionError describes a rejected status change.
+// It wraps ErrInvalidStatusTransition so callers can use errors.Is.
+type StatusTransitionError struct {
+	Role models.UserRole
+	From models.AppealStatus
+	To   models.AppealStatus
+}
+
+func (e *StatusTransitionError) Error() string {
+	return fmt.Sprintf("role %s cannot change appeal status from %s to %s", e.Role, e.From, e.To)
+}
+
+func (e *StatusTransitionError) Unwrap() error {
+	return ErrInvalidStatusTransition
+}
+
+// knownStatuses lists every status of the appeal lifecycle.
+var knownStatuses = map[models.AppealStatus]bool{
+	models.StatusNew:        true,
+	models.StatusAssigned:   true,
+	models.StatusInProgress: true,
+	models.StatusCompleted:  true,
+	models.StatusClosed:     true,
+	models.StatusRejected:   true,
+	models.StatusReopened:   true,
+}
+
+// dispatcherTransitions are the moves available to dispatchers.
+// Admins get the same moves plus a few overrides (see adminTransitions).
+var dispatcherTransitions = map[models.AppealStatus][]models.AppealStatus{
+	models.StatusNew:        {models.StatusAssigned, models.StatusRejected},
+	models.StatusAssigned:   {models.StatusInProgress, models.StatusRejected},
+	models.StatusInProgress: {models.StatusAssigned, models.StatusCompleted},
+	models.StatusCompleted:  {models.StatusInProgress, models.StatusClosed},
+	models.StatusReopened:   {models.StatusAssigned, models.StatusInProgress},
+}
+
+// adminTransitions extends dispatcherTransitions with administrative overrides:
+// closing an appeal at any open stage and restoring a rejected one.
+var adminTransitions = map[models.AppealStatus][]models.AppealStatus{
+	models.StatusNew:        {models.StatusAssigned, models.StatusRejected, models.StatusClosed},
+	models.StatusAssigned:   {models.StatusInProgress, models.StatusRejected, models.StatusClosed},
+	models.StatusInProgress: {models.StatusAssigned, models.StatusCompleted, models.StatusRejected, models.StatusClosed},
+	models.StatusCompleted:  {models.StatusInProgress, models.StatusClosed},
+	models.StatusRejected:   {models.StatusNew},
+	models.StatusReopened:   {models.StatusAssigned, models.StatusInProgress, models.StatusClosed},
+}
+
+// executorTransitions only allow executors to move work forward.
+var executorTransitions = map[models.AppealStatus][]models.AppealStatus{
+	models.StatusAssigned:   {models.StatusInProgress},
+	models.StatusInProgress: {models.StatusCompleted},
+	models.StatusReopened:   {models.StatusInProgress},
+}
+
+// citizenTransitions let the author answer a completed appeal: confirm the result
+// or send it back. Authorship is checked by the service, not by this table.
+var citizenTransitions = map[models.AppealStatus][]models.AppealStatus{
+	models.StatusCompleted: {models.StatusClosed, models.StatusReopened},
+}
+
+// statusTransitions is the transition table per role: role -> from -> allowed targets.
+var statusTransitions = map[models.UserRole]map[models.AppealStatus][]models.AppealStatus{
+	models.RoleAdmin:      adminTransitions,
+	models.RoleDispatcher: dispatcherTransitions,
+	models.RoleExecutor:   executorTransitions,
+	models.RoleCitizen:    citizenTransitions,
+}
+
+// IsKnownStatus reports whether status is part of the appeal lifecycle.
+func IsKnownStatus(status models.AppealStatus) bool {
+	return knownStatuses[status]
+}
+
+// IsOpenStatus reports whether an appeal in this status still awaits resolution.
+func IsOpenStatus(status models.AppealStatus) bool {
+	switch status {
+	case models.StatusCompleted, models.StatusClosed, models.StatusRejected:
+		return false
+	}
+	return IsKnownStatus(status)
+}
+
+// CanTransition reports whether role may move an appeal from one status to another.
+func CanTransition(role models.UserRole, from, to models.AppealStatus) bool {
+	for _, allowed := range statusTransitions[role][from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateStatusTransition checks a requested status change against the transition table.
+// Setting the status an appeal already has is treated as a no-op and allowed.
+func ValidateStatusTransition(role models.UserRole, from, to models.AppealStatus) error {
+	if !IsKnownStatus(to) {
+		return fmt.Errorf("%w: %q", ErrUnknownStatus, to)
+	}
+	if from == to {
+		return nil
+	}
+	if !CanTransition(role, from, to) {
+		return &StatusTransitionError{Role: role, From: from, To: to}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var allStatuses = []models.AppealStatus{
+	models.StatusNew,
+	models.StatusAssigned,
+	models.StatusInProgress,
+	models.StatusCompleted,
+	models.StatusClosed,
+	models.StatusRejected,
+	models.StatusReopened,
+}
+
+var allRoles = []models.UserRole{
+	models.RoleCitizen,
+	models.RoleDispatcher,
+	models.RoleExecutor,
+	models.RoleAdmin,
+}
+
+type transition struct {
+	from models.AppealStatus
+	to   models.AppealStatus
+}
+
+// expectedTransitions is the full list of allowed moves per role.
+// Every role/from/to combination not listed here must be rejected.
+var expectedTransitions = map[models.UserRole][]transition{
+	models.RoleCitizen: {
+		{models.StatusCompleted, models.StatusClosed},
+		{models.StatusCompleted, models.StatusReopened},
+	},
+	models.RoleExecutor: {
+		{models.StatusAssigned, models.StatusInProgress},
+		{models.StatusInProgress, models.StatusCompleted},
+		{models.StatusReopened, models.StatusInProgress},
+	},
+	models.RoleDispatcher: {
+		{models.StatusNew, models.StatusAssigned},
+		{models.StatusNew, models.StatusRejected},
+		{models.StatusAssigned, models.StatusInProgress},
+		{models.StatusAssigned, models.StatusRejected},
+		{models.StatusInProgress, models.StatusAssigned},
+		{models.StatusInProgress, models.StatusCompleted},
+		{models.StatusCompleted, models.StatusInProgress},
+		{models.StatusCompleted, models.StatusClosed},
+		{models.StatusReopened, models.StatusAssigned},
+		{models.StatusReopened, models.StatusInProgress},
+	},
+	models.RoleAdmin: {
+		{models.StatusNew, models.StatusAssigned},
+		{models.StatusNew, models.StatusRejected},
+		{models.StatusNew, models.StatusClosed},
+		{models.StatusAssigned, models.StatusInProgress},
+		{models.StatusAssigned, models.StatusRejected},
+		{models.StatusAssigned, models.StatusClosed},
+		{models.StatusInProgress, models.StatusAssigned},
+		{models.StatusInProgress, models.StatusCompleted},
+		{models.StatusInProgress, models.StatusRejected},
+		{models.StatusInProgress, models.StatusClosed},
+		{models.StatusCompleted, models.StatusInProgress},
+		{models.StatusCompleted, models.StatusClosed},
+		{models.StatusRejected, models.StatusNew},
+		{models.StatusReopened, models.StatusAssigned},
+		{models.StatusReopened, models.StatusInProgress},
+		{models.StatusReopened, models.StatusClosed},
+	},
+}
+
+func TestStatusTransitions_FullMatrix(t *testing.T) {
+	for _, role := range allRoles {
+		allowed := make(map[transition]bool)
+		for _, tr := range expectedTransitions[role] {
+			allowed[tr] = true
+		}
+
+		for _, from := range allStatuses {
+			for _, to := range allStatuses {
+				tr := transition{from, to}
+				name := string(role) + "/" + string(from) + "->" + string(to)
+
+				assert.Equal(t, allowed[tr], CanTransition(role, from, to), name)
+
+				err := ValidateStatusTransition(role, from, to)
+				switch {
+				case from == to:
+					assert.NoError(t, err, name+": same status is a no-op")
+				case allowed[tr]:
+					assert.NoError(t, err, name)
+				default:
+					assert.ErrorIs(t, err, ErrInvalidStatusTransition, name)
+				}
+			}
+		}
+	}
+}
+
+func TestStatusTransitions_ClosedIsTerminal(t *testing.T) {
+	for _, role := range allRoles {
+		for _, to := range allStatuses {
+			if to == models.StatusClosed {
+				continue
+			}
+			assert.False(t, CanTransition(role, models.StatusClosed, to), "%s: closed -> %s", role, to)
+		}
+	}
+}
+
+func TestValidateStatusTransition_UnknownStatus(t *testing.T) {
+	err := ValidateStatusTransition(models.RoleAdmin, models.StatusNew, models.AppealStatus("archived"))
+	assert.ErrorIs(t, err, ErrUnknownStatus)
+	assert.NotErrorIs(t, err, ErrInvalidStatusTransition)
+
+	err = ValidateStatusTransition(models.RoleAdmin, models.StatusNew, models.AppealStatus(""))
+	assert.ErrorIs(t, err, ErrUnknownStatus)
+}
+
+func TestValidateStatusTransition_TypedError(t *testing.T) {
+	err := ValidateStatusTransition(models.RoleExecutor, models.StatusNew, models.StatusCompleted)
+
+	var transitionErr *StatusTransitionError
+	assert.True(t, errors.As(err, &transitionErr))
+	assert.Equal(t, models.RoleExecutor, transitionErr.Role)
+	assert.Equal(t, models.StatusNew, transitionErr.From)
+	assert.Equal(t, models.StatusCompleted, transitionErr.To)
+}
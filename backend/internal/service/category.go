@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"citizen-appeals/internal/models"
+)
+
+// ErrInvalidCategoryParent is returned when a category cannot go where it is placed
+var ErrInvalidCategoryParent = errors.New("invalid category parent")
+
+// SetCategoryParent places a category under the parent, or at the top level when parent
+// is nil. Categories nest one level deep: the parent must be a top-level category, active
+// unless the category is inactive too, and a category that has subcategories stays
+// top-level. A subcategory without its own default priority takes the parent's; a
+// top-level category needs its own.
+func SetCategoryParent(category, parent *models.Category, hasSubcategories bool) error {
+	if parent == nil {
+		if category.InheritsPriority {
+			return fmt.Errorf("%w: a top-level category needs its own default priority", ErrInvalidCategoryParent)
+		}
+		category.ParentID = nil
+		return nil
+	}
+
+	switch {
+	case parent.ID == category.ID:
+		return fmt.Errorf("%w: a category cannot be its own parent", ErrInvalidCategoryParent)
+	case parent.ParentID != nil:
+		return fmt.Errorf("%w: %q is a subcategory itself", ErrInvalidCategoryParent, parent.Name)
+	case !parent.IsActive && category.IsActive:
+		return fmt.Errorf("%w: %q is inactive", ErrInvalidCategoryParent, parent.Name)
+	case hasSubcategories:
+		return fmt.Errorf("%w: a category with subcategories stays top-level", ErrInvalidCategoryParent)
+	}
+
+	category.ParentID = &parent.ID
+	if category.InheritsPriority {
+		category.DefaultPriority = parent.DefaultPriority
+	}
+	return nil
+}
+
+// BuildCategoryTree nests subcategories under their parents, keeping the order of the list.
+// A subcategory whose parent is not in the list, e.g. an inactive one, stays at the top level.
+func BuildCategoryTree(categories []*models.Category) []*models.Category {
+	byID := make(map[int64]*models.Category, len(categories))
+	for _, category := range categories {
+		category.Children = nil
+		byID[category.ID] = category
+	}
+
+	roots := make([]*models.Category, 0)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			if parent, ok := byID[*category.ParentID]; ok {
+				parent.Children = append(parent.Children, category)
+				continue
+			}
+		}
+		roots = append(roots, category)
+	}
+	return roots
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetCategoryParent(t *testing.T) {
+	int64Ptr := func(v int64) *int64 { return &v }
+	parent := &models.Category{ID: 2, Name: "Благоустрій та Довкілля", DefaultPriority: 3, IsActive: true}
+
+	snow := &models.Category{ID: 10, Name: "Прибирання снігу", InheritsPriority: true, IsActive: true}
+	if assert.NoError(t, SetCategoryParent(snow, parent, false)) {
+		assert.Equal(t, int64Ptr(2), snow.ParentID)
+		assert.Equal(t, 3, snow.DefaultPriority, "takes the parent's priority")
+	}
+
+	graves := &models.Category{ID: 11, Name: "Кладовища", DefaultPriority: 1, IsActive: true}
+	if assert.NoError(t, SetCategoryParent(graves, parent, false)) {
+		assert.Equal(t, 1, graves.DefaultPriority, "keeps its own priority")
+	}
+
+	moved := &models.Category{ID: 12, Name: "Інше", DefaultPriority: 1, IsActive: true, ParentID: int64Ptr(2)}
+	if assert.NoError(t, SetCategoryParent(moved, nil, false)) {
+		assert.Nil(t, moved.ParentID)
+	}
+
+	archived := &models.Category{ID: 13, Name: "Старе", InheritsPriority: true}
+	assert.NoError(t, SetCategoryParent(archived, &models.Category{ID: 3, Name: "Архів", DefaultPriority: 2}, false),
+		"an inactive category may stay under an inactive parent")
+
+	tests := []struct {
+		name             string
+		category         *models.Category
+		parent           *models.Category
+		hasSubcategories bool
+	}{
+		{"top-level without priority", &models.Category{ID: 10, InheritsPriority: true, IsActive: true}, nil, false},
+		{"own parent", &models.Category{ID: 2, DefaultPriority: 2, IsActive: true}, parent, false},
+		{"parent is a subcategory", &models.Category{ID: 14, DefaultPriority: 2, IsActive: true}, snow, false},
+		{"inactive parent", &models.Category{ID: 15, DefaultPriority: 2, IsActive: true}, &models.Category{ID: 3, DefaultPriority: 2}, false},
+		{"category has subcategories", &models.Category{ID: 4, DefaultPriority: 2, IsActive: true}, parent, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, SetCategoryParent(tt.category, tt.parent, tt.hasSubcategories), ErrInvalidCategoryParent)
+		})
+	}
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	parentID := int64(2)
+	missingID := int64(99)
+	categories := []*models.Category{
+		{ID: 2, Name: "Благоустрій та Довкілля"},
+		{ID: 11, Name: "Кладовища", ParentID: &parentID},
+		{ID: 12, Name: "Осиротіла", ParentID: &missingID},
+		{ID: 10, Name: "Прибирання снігу", ParentID: &parentID},
+		{ID: 4, Name: "Транспорт"},
+	}
+
+	tree := BuildCategoryTree(categories)
+	if assert.Len(t, tree, 3) {
+		assert.Equal(t, int64(2), tree[0].ID)
+		assert.Equal(t, int64(12), tree[1].ID, "a subcategory of a missing parent stays at the top level")
+		assert.Equal(t, int64(4), tree[2].ID)
+		if assert.Len(t, tree[0].Children, 2) {
+			assert.Equal(t, "Кладовища", tree[0].Children[0].Name)
+			assert.Equal(t, "Прибирання снігу", tree[0].Children[1].Name)
+		}
+		assert.Empty(t, tree[2].Children)
+	}
+}
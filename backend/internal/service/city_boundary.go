@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"citizen-appeals/pkg/geo"
+)
+
+// ErrOutsideCityBoundary is returned when an appeal's location lies outside the city.
+var ErrOutsideCityBoundary = errors.New("location is outside the city boundary")
+
+// CheckCityBoundary rejects locations outside the configured city boundary.
+// Without a boundary every location is accepted; a boundary that cannot be loaded
+// is logged and ignored rather than blocking every submission.
+func (s *AppealService) CheckCityBoundary(ctx context.Context, latitude, longitude float64) error {
+	if s.cityBoundaryLoader == nil {
+		return nil
+	}
+
+	boundary, err := s.cityBoundaryLoader(ctx)
+	if err != nil {
+		log.Printf("Failed to load city boundary: %v", err)
+		return nil
+	}
+	if boundary == nil || boundary.Contains(geo.Point{Lat: latitude, Lng: longitude}) {
+		return nil
+	}
+	return ErrOutsideCityBoundary
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"citizen-appeals/pkg/geo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCityBoundary(t *testing.T) {
+	boundary, err := geo.ParseArea([]byte(`{"type": "Polygon", "coordinates": [[[30.24, 50.21], [30.83, 50.21], [30.83, 50.59], [30.24, 50.59], [30.24, 50.21]]]}`))
+	assert.NoError(t, err)
+
+	s := &AppealService{cityBoundaryLoader: func(context.Context) (geo.Area, error) { return boundary, nil }}
+	assert.NoError(t, s.CheckCityBoundary(context.Background(), 50.4501, 30.5234))
+	assert.ErrorIs(t, s.CheckCityBoundary(context.Background(), 49.8397, 24.0297), ErrOutsideCityBoundary, "Lviv is not in Kyiv")
+	assert.ErrorIs(t, s.CheckCityBoundary(context.Background(), 0, 0), ErrOutsideCityBoundary)
+
+	unset := &AppealService{cityBoundaryLoader: func(context.Context) (geo.Area, error) { return nil, nil }}
+	assert.NoError(t, unset.CheckCityBoundary(context.Background(), 0, 0), "no boundary accepts any location")
+
+	broken := &AppealService{cityBoundaryLoader: func(context.Context) (geo.Area, error) { return nil, errors.New("bad file") }}
+	assert.NoError(t, broken.CheckCityBoundary(context.Background(), 0, 0), "a broken boundary does not block submissions")
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"citizen-appeals/internal/models"
+)
+
+var (
+	// ErrInvalidCustomFieldSet is returned for a category field set that cannot be filled in
+	ErrInvalidCustomFieldSet = errors.New("invalid custom field set")
+	// ErrInvalidCustomFields is returned for appeal values that do not fit the category's fields
+	ErrInvalidCustomFields = errors.New("invalid custom fields")
+)
+
+// customFieldKey is what a field key looks like; keys also appear in filter query parameters
+var customFieldKey = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// maxCustomTextLength limits text values, which are short identifiers like a pole number
+const maxCustomTextLength = 500
+
+// ValidateCustomFieldSet checks the field set of a category: keys are unique lowercase
+// identifiers, types are known and enum fields list their options.
+func ValidateCustomFieldSet(fields []models.CustomField) error {
+	keys := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if !customFieldKey.MatchString(f.Key) {
+			return fmt.Errorf("%w: key %q must be lowercase latin letters, digits and underscores", ErrInvalidCustomFieldSet, f.Key)
+		}
+		if keys[f.Key] {
+			return fmt.Errorf("%w: key %q is repeated", ErrInvalidCustomFieldSet, f.Key)
+		}
+		keys[f.Key] = true
+
+		switch f.Type {
+		case models.CustomFieldText, models.CustomFieldNumber, models.CustomFieldBoolean:
+			if len(f.Options) > 0 {
+				return fmt.Errorf("%w: only enum fields have options (%q)", ErrInvalidCustomFieldSet, f.Key)
+			}
+		case models.CustomFieldEnum:
+			if len(f.Options) == 0 {
+				return fmt.Errorf("%w: enum field %q has no options", ErrInvalidCustomFieldSet, f.Key)
+			}
+		default:
+			return fmt.Errorf("%w: field %q has unknown type %q", ErrInvalidCustomFieldSet, f.Key, f.Type)
+		}
+	}
+	return nil
+}
+
+// ValidateCustomFields checks appeal values against the category's fields and returns
+// them normalized: text is trimmed, and empty or null values are dropped. Every required
+// field must have a value; keys the category does not define are rejected.
+func ValidateCustomFields(fields []models.CustomField, values map[string]interface{}) (map[string]interface{}, error) {
+	defined := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		defined[f.Key] = true
+	}
+	for key := range values {
+		if !defined[key] {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidCustomFields, key)
+		}
+	}
+
+	normalized := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		value, err := customFieldValue(f, values[f.Key])
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			if f.Required {
+				return nil, fmt.Errorf("%w: %q is required", ErrInvalidCustomFields, f.Label)
+			}
+			continue
+		}
+		normalized[f.Key] = value
+	}
+	return normalized, nil
+}
+
+// KeepValidCustomFields returns the values that still fit the fields, dropping the rest.
+// It is used when an appeal moves to another category: the new category's required
+// fields may stay empty, since staff recategorizing an appeal cannot fill them in.
+func KeepValidCustomFields(fields []models.CustomField, values map[string]interface{}) map[string]interface{} {
+	kept := make(map[string]interface{})
+	for _, f := range fields {
+		if value, err := customFieldValue(f, values[f.Key]); err == nil && value != nil {
+			kept[f.Key] = value
+		}
+	}
+	return kept
+}
+
+// customFieldValue checks one value as decoded from JSON; nil means no value
+func customFieldValue(f models.CustomField, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch f.Type {
+	case models.CustomFieldText, models.CustomFieldEnum:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q must be text", ErrInvalidCustomFields, f.Label)
+		}
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil, nil
+		}
+		if f.Type == models.CustomFieldEnum && !containsString(f.Options, s) {
+			return nil, fmt.Errorf("%w: %q must be one of %s", ErrInvalidCustomFields, f.Label, strings.Join(f.Options, ", "))
+		}
+		if len([]rune(s)) > maxCustomTextLength {
+			return nil, fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidCustomFields, f.Label, maxCustomTextLength)
+		}
+		return s, nil
+	case models.CustomFieldNumber:
+		n, ok := value.(float64)
+		if !ok || math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, fmt.Errorf("%w: %q must be a number", ErrInvalidCustomFields, f.Label)
+		}
+		return n, nil
+	case models.CustomFieldBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q must be true or false", ErrInvalidCustomFields, f.Label)
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("%w: field %q has unknown type %q", ErrInvalidCustomFields, f.Key, f.Type)
+}
+
+// FormatCustomFields renders values as "Label: value; ..." in the order of the fields.
+// Values of fields the category no longer defines follow under their keys.
+func FormatCustomFields(fields []models.CustomField, values map[string]interface{}) string {
+	parts := make([]string, 0, len(values))
+	shown := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if value, ok := values[f.Key]; ok && value != nil {
+			parts = append(parts, f.Label+": "+formatCustomFieldValue(value))
+			shown[f.Key] = true
+		}
+	}
+
+	rest := make([]string, 0)
+	for key, value := range values {
+		if !shown[key] && value != nil {
+			rest = append(rest, key+": "+formatCustomFieldValue(value))
+		}
+	}
+	sort.Strings(rest)
+
+	return strings.Join(append(parts, rest...), "; ")
+}
+
+func formatCustomFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return "так"
+		}
+		return "ні"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// categoryCustomFields returns the field set of a category
+func (s *AppealService) categoryCustomFields(ctx context.Context, categoryID int64) ([]models.CustomField, error) {
+	if s.categoryRepo == nil {
+		return nil, nil
+	}
+	category, err := s.categoryRepo.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return category.CustomFields, nil
+}
+
+// ValidateAppealCustomFields checks values against the fields of the category
+func (s *AppealService) ValidateAppealCustomFields(ctx context.Context, categoryID int64, values map[string]interface{}) (map[string]interface{}, error) {
+	fields, err := s.categoryCustomFields(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateCustomFields(fields, values)
+}
+
+// RecategorizeCustomFields keeps the values that fit the fields of the new category
+func (s *AppealService) RecategorizeCustomFields(ctx context.Context, categoryID int64, values map[string]interface{}) (map[string]interface{}, error) {
+	fields, err := s.categoryCustomFields(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return KeepValidCustomFields(fields, values), nil
+}
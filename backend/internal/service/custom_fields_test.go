@@ -0,0 +1,97 @@
+package service
+
+import (
+	"testing"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var streetlightFields = []models.CustomField{
+	{Key: "pole_number", Label: "Номер опори", Type: models.CustomFieldText, Required: true},
+	{Key: "lamps", Label: "Кількість ламп", Type: models.CustomFieldNumber},
+	{Key: "damage", Label: "Пошкодження", Type: models.CustomFieldEnum, Options: []string{"не світить", "блимає"}},
+	{Key: "dangerous", Label: "Загрожує падінням", Type: models.CustomFieldBoolean},
+}
+
+func TestValidateCustomFieldSet(t *testing.T) {
+	assert.NoError(t, ValidateCustomFieldSet(streetlightFields))
+	assert.NoError(t, ValidateCustomFieldSet(nil))
+
+	tests := []struct {
+		name   string
+		fields []models.CustomField
+	}{
+		{"bad key", []models.CustomField{{Key: "Pole Number", Label: "Опора", Type: models.CustomFieldText}}},
+		{"repeated key", []models.CustomField{
+			{Key: "pole", Label: "Опора", Type: models.CustomFieldText},
+			{Key: "pole", Label: "Ще опора", Type: models.CustomFieldNumber},
+		}},
+		{"enum without options", []models.CustomField{{Key: "tree", Label: "Дерево", Type: models.CustomFieldEnum}}},
+		{"options on text", []models.CustomField{{Key: "tree", Label: "Дерево", Type: models.CustomFieldText, Options: []string{"дуб"}}}},
+		{"unknown type", []models.CustomField{{Key: "at", Label: "Коли", Type: "date"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, ValidateCustomFieldSet(tt.fields), ErrInvalidCustomFieldSet)
+		})
+	}
+}
+
+func TestValidateCustomFields(t *testing.T) {
+	values, err := ValidateCustomFields(streetlightFields, map[string]interface{}{
+		"pole_number": "  17-А ",
+		"lamps":       float64(2),
+		"damage":      "блимає",
+		"dangerous":   nil,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]interface{}{"pole_number": "17-А", "lamps": float64(2), "damage": "блимає"}, values)
+
+	values, err = ValidateCustomFields(nil, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, values, "an empty object is stored, not null")
+
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+	}{
+		{"missing required", map[string]interface{}{"lamps": float64(1)}},
+		{"blank required", map[string]interface{}{"pole_number": "   "}},
+		{"unknown key", map[string]interface{}{"pole_number": "1", "color": "red"}},
+		{"number as text", map[string]interface{}{"pole_number": "1", "lamps": "два"}},
+		{"text as number", map[string]interface{}{"pole_number": float64(17)}},
+		{"unknown option", map[string]interface{}{"pole_number": "1", "damage": "зникла"}},
+		{"boolean as text", map[string]interface{}{"pole_number": "1", "dangerous": "так"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateCustomFields(streetlightFields, tt.values)
+			assert.ErrorIs(t, err, ErrInvalidCustomFields)
+		})
+	}
+}
+
+func TestKeepValidCustomFields(t *testing.T) {
+	kept := KeepValidCustomFields(streetlightFields, map[string]interface{}{
+		"lamps":     "два",
+		"damage":    "не світить",
+		"tree_type": "дуб",
+	})
+	assert.Equal(t, map[string]interface{}{"damage": "не світить"}, kept, "required fields may stay empty")
+}
+
+func TestFormatCustomFields(t *testing.T) {
+	formatted := FormatCustomFields(streetlightFields, map[string]interface{}{
+		"dangerous":   true,
+		"pole_number": "17-А",
+		"lamps":       float64(2),
+		"tree_type":   "дуб",
+	})
+	assert.Equal(t, "Номер опори: 17-А; Кількість ламп: 2; Загрожує падінням: так; tree_type: дуб", formatted)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/pkg/geo"
+)
+
+// ErrInvalidDistrict is returned when a district's name or geometry is unusable.
+var ErrInvalidDistrict = errors.New("invalid district")
+
+// SetDistrictGeometry validates a GeoJSON Polygon or MultiPolygon (optionally wrapped
+// in a Feature) and stores it on the district together with its bounding box.
+func SetDistrictGeometry(district *models.District, geometry json.RawMessage) error {
+	area, err := geo.ParseArea(geometry)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDistrict, err)
+	}
+
+	box := area.Bounds()
+	district.Geometry = geometry
+	district.MinLat = box.MinLat
+	district.MinLng = box.MinLng
+	district.MaxLat = box.MaxLat
+	district.MaxLng = box.MaxLng
+	return nil
+}
+
+// DistrictsFromGeoJSON turns a FeatureCollection into districts named by the
+// "name" property of each feature.
+func DistrictsFromGeoJSON(data []byte) ([]*models.District, error) {
+	features, err := geo.ParseFeatures(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDistrict, err)
+	}
+
+	districts := make([]*models.District, 0, len(features))
+	for i, f := range features {
+		name, _ := f.Properties["name"].(string)
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: feature %d has no \"name\" property", ErrInvalidDistrict, i)
+		}
+		district := &models.District{Name: name, IsActive: true}
+		if err := SetDistrictGeometry(district, f.Geometry); err != nil {
+			return nil, fmt.Errorf("feature %d: %w", i, err)
+		}
+		districts = append(districts, district)
+	}
+	return districts, nil
+}
+
+// districtContaining returns the first district whose polygons contain the point.
+// Districts with broken geometry are skipped.
+func districtContaining(candidates []*models.District, point geo.Point) *models.District {
+	for _, district := range candidates {
+		area, err := geo.ParseArea(district.Geometry)
+		if err != nil {
+			log.Printf("District %d has invalid geometry: %v", district.ID, err)
+			continue
+		}
+		if area.Contains(point) {
+			return district
+		}
+	}
+	return nil
+}
+
+// ResolveDistrict finds the district of a location; nil means it is outside every district.
+func (s *AppealService) ResolveDistrict(ctx context.Context, latitude, longitude float64) (*int64, error) {
+	if s.districtRepo == nil {
+		return nil, nil
+	}
+
+	point := geo.Point{Lat: latitude, Lng: longitude}
+	candidates, err := s.districtRepo.ListCandidates(ctx, point)
+	if err != nil {
+		return nil, err
+	}
+
+	district := districtContaining(candidates, point)
+	if district == nil {
+		return nil, nil
+	}
+	return &district.ID, nil
+}
+
+// routeByDistrict sends a new appeal to the service set by the routing table for its
+// category and district. It reports whether a rule matched and the appeal was routed.
+func (s *AppealService) routeByDistrict(ctx context.Context, appeal *models.Appeal) bool {
+	if s.districtRepo == nil || appeal.DistrictID == nil {
+		return false
+	}
+
+	serviceID, err := s.districtRepo.FindRoute(ctx, appeal.CategoryID, *appeal.DistrictID)
+	if err != nil {
+		log.Printf("Failed to find routing rule for appeal %d: %v", appeal.ID, err)
+		return false
+	}
+	if serviceID == nil {
+		return false
+	}
+
+	appeal.ServiceID = serviceID
+	if err := s.repo.Update(ctx, appeal); err != nil {
+		log.Printf("Failed to assign service from routing rule: %v", err)
+		appeal.ServiceID = nil
+		return false
+	}
+	log.Printf("Assigned service %d to appeal %d by district routing rule", *serviceID, appeal.ID)
+	return true
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/pkg/geo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistrictsFromGeoJSON(t *testing.T) {
+	collection := `{
+		"type": "FeatureCollection",
+		"features": [
+			{"type": "Feature", "properties": {"name": " Поділ "}, "geometry": {"type": "Polygon", "coordinates": [[[30.50, 50.46], [30.53, 50.46], [30.53, 50.48], [30.50, 50.48], [30.50, 50.46]]]}},
+			{"type": "Feature", "properties": {"name": "Оболонь"}, "geometry": {"type": "Polygon", "coordinates": [[[30.48, 50.49], [30.52, 50.49], [30.52, 50.54], [30.48, 50.54], [30.48, 50.49]]]}}
+		]
+	}`
+
+	districts, err := DistrictsFromGeoJSON([]byte(collection))
+	assert.NoError(t, err)
+	if assert.Len(t, districts, 2) {
+		assert.Equal(t, "Поділ", districts[0].Name)
+		assert.True(t, districts[0].IsActive)
+		assert.Equal(t, 50.46, districts[0].MinLat)
+		assert.Equal(t, 30.53, districts[0].MaxLng)
+	}
+
+	unnamed := `{"type": "FeatureCollection", "features": [{"type": "Feature", "properties": {}, "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}}]}`
+	_, err = DistrictsFromGeoJSON([]byte(unnamed))
+	assert.ErrorIs(t, err, ErrInvalidDistrict)
+}
+
+func TestSetDistrictGeometry_Invalid(t *testing.T) {
+	district := &models.District{Name: "Центр"}
+	err := SetDistrictGeometry(district, json.RawMessage(`{"type": "Point", "coordinates": [30.5, 50.4]}`))
+	assert.ErrorIs(t, err, ErrInvalidDistrict)
+	assert.Empty(t, district.Geometry, "geometry is left unchanged")
+}
+
+func TestDistrictContaining(t *testing.T) {
+	// Overlapping bounding boxes: only the polygons decide
+	west := &models.District{ID: 1}
+	assert.NoError(t, SetDistrictGeometry(west, json.RawMessage(`{"type": "Polygon", "coordinates": [[[0, 0], [2, 0], [0, 2], [0, 0]]]}`)))
+	east := &models.District{ID: 2}
+	assert.NoError(t, SetDistrictGeometry(east, json.RawMessage(`{"type": "Polygon", "coordinates": [[[2, 0], [2, 2], [0, 2], [2, 0]]]}`)))
+	broken := &models.District{ID: 3, Geometry: json.RawMessage(`{}`)}
+
+	candidates := []*models.District{broken, west, east}
+
+	if d := districtContaining(candidates, geo.Point{Lat: 0.5, Lng: 0.5}); assert.NotNil(t, d) {
+		assert.Equal(t, int64(1), d.ID)
+	}
+	if d := districtContaining(candidates, geo.Point{Lat: 1.5, Lng: 1.5}); assert.NotNil(t, d) {
+		assert.Equal(t, int64(2), d.ID)
+	}
+	assert.Nil(t, districtContaining(candidates, geo.Point{Lat: 3, Lng: 3}))
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/pkg/geo"
+)
+
+// ErrAppealNotOpen is returned when a citizen tries to join an appeal that is already resolved.
+var ErrAppealNotOpen = errors.New("appeal is not open")
+
+const (
+	// duplicateCandidatesLimit caps how many nearby appeals are compared per request
+	duplicateCandidatesLimit = 50
+	// maxPossibleDuplicates caps how many duplicates are returned to the citizen
+	maxPossibleDuplicates = 5
+	// stemLength is the number of leading runes kept from each word. Ukrainian words
+	// change endings a lot ("яма", "ями", "ямою"), so a prefix is a cheap stem.
+	stemLength = 5
+	// minTokenLength drops short words (prepositions, conjunctions)
+	minTokenLength = 3
+)
+
+// duplicateStopWords are frequent words that carry no meaning for comparison
+var duplicateStopWords = map[string]bool{
+	"біля": true, "поруч": true, "також": true, "дуже": true, "вже": true,
+	"який": true, "яка": true, "яке": true, "які": true, "цей": true, "ця": true,
+	"через": true, "після": true, "буде": true, "немає": true, "просимо": true,
+	"прошу": true, "будь": true, "ласка": true, "вулиці": true, "вулиця": true,
+}
+
+// FindDuplicates returns open appeals of the same category within the configured radius
+// whose description is similar to the given one, best matches first.
+func (s *AppealService) FindDuplicates(
+	ctx context.Context,
+	categoryID int64,
+	latitude, longitude float64,
+	description string,
+) ([]models.DuplicateCandidate, error) {
+	radius, threshold := s.duplicateSettings(ctx)
+	center := geo.Point{Lat: latitude, Lng: longitude}
+
+	nearby, err := s.repo.FindOpenInArea(ctx, categoryID, geo.BoundingBoxAround(center, radius), duplicateCandidatesLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := descriptionTokens(description)
+	candidates := make([]models.DuplicateCandidate, 0)
+	for _, appeal := range nearby {
+		distance := geo.DistanceMeters(center, geo.Point{Lat: appeal.Latitude, Lng: appeal.Longitude})
+		if distance > radius {
+			continue
+		}
+
+		similarity := tokenSimilarity(tokens, descriptionTokens(appeal.Description))
+		if similarity < threshold {
+			continue
+		}
+
+		candidates = append(candidates, models.DuplicateCandidate{
+			AppealID:       appeal.ID,
+			Title:          appeal.Title,
+			Status:         appeal.Status,
+			Address:        appeal.Address,
+			Latitude:       appeal.Latitude,
+			Longitude:      appeal.Longitude,
+			CreatedAt:      appeal.CreatedAt,
+			DistanceMeters: math.Round(distance),
+			Similarity:     round2(similarity),
+			Score:          duplicateScore(distance, radius, similarity),
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > maxPossibleDuplicates {
+		candidates = candidates[:maxPossibleDuplicates]
+	}
+
+	return candidates, nil
+}
+
+// AttachToAppeal joins a citizen's report to an existing open appeal instead of creating
+// a new one, together with the citizen's staged photos. A merged appeal is joined through
+// its master appeal, which carries the incident. Joining the same appeal twice is a no-op,
+// apart from the photos.
+func (s *AppealService) AttachToAppeal(
+	ctx context.Context,
+	appealID, userID int64,
+	description string,
+	photoIDs []int64,
+) (*models.Appeal, error) {
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+
+	if appeal.ParentID != nil {
+		appeal, err = s.repo.GetByID(ctx, *appeal.ParentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !IsOpenStatus(appeal.Status) {
+		return nil, ErrAppealNotOpen
+	}
+
+	if _, err := s.repo.AddSupporter(ctx, appeal.ID, userID, description, photoIDs); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByID(ctx, appeal.ID)
+}
+
+// duplicateSettings returns the search radius and similarity threshold from system settings.
+func (s *AppealService) duplicateSettings(ctx context.Context) (float64, float64) {
+	radius := models.DefaultDuplicateRadiusMeters
+	threshold := models.DefaultDuplicateSimilarityThreshold
+
+	if s.systemSettingsLoader != nil {
+		settings, err := s.systemSettingsLoader(ctx)
+		if err == nil && settings != nil {
+			if settings.DuplicateRadiusMeters > 0 {
+				radius = settings.DuplicateRadiusMeters
+			}
+			if settings.DuplicateSimilarityThreshold > 0 {
+				threshold = settings.DuplicateSimilarityThreshold
+			}
+		}
+	}
+
+	return radius, threshold
+}
+
+// descriptionTokens splits text into a set of stemmed, lower-cased words.
+func descriptionTokens(text string) map[string]bool {
+	tokens := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+
+	for _, word := range words {
+		word = strings.Trim(word, "'")
+		runes := []rune(word)
+		if len(runes) < minTokenLength || duplicateStopWords[word] {
+			continue
+		}
+		if len(runes) > stemLength {
+			runes = runes[:stemLength]
+		}
+		tokens[string(runes)] = true
+	}
+
+	return tokens
+}
+
+// tokenSimilarity is the Dice coefficient of two token sets (0..1).
+func tokenSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	common := 0
+	for token := range a {
+		if b[token] {
+			common++
+		}
+	}
+
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+// duplicateScore combines text similarity and proximity into a single 0..1 score.
+// Text weighs more: two different problems at one address are common.
+func duplicateScore(distance, radius, similarity float64) float64 {
+	proximity := 0.0
+	if radius > 0 {
+		proximity = math.Max(0, 1-distance/radius)
+	}
+	return round2(0.7*similarity + 0.3*proximity)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescriptionTokens_StemsAndDropsNoise(t *testing.T) {
+	tokens := descriptionTokens("Велика яма на дорозі біля будинку, ями вже кілька тижнів!")
+
+	// "дорозі" and "дорога" share the stem, short words and stop words are dropped
+	assert.True(t, tokens["велик"])
+	assert.True(t, tokens["яма"])
+	assert.True(t, tokens["ями"])
+	assert.True(t, tokens["дороз"])
+	assert.False(t, tokens["на"])
+	assert.False(t, tokens["біля"])
+	assert.False(t, tokens["вже"])
+}
+
+func TestTokenSimilarity(t *testing.T) {
+	a := descriptionTokens("Глибока яма посеред дороги, машини пошкоджують колеса")
+	b := descriptionTokens("Посеред дороги глибока яма, пошкодив колесо")
+	c := descriptionTokens("Не працює вуличне освітлення у дворі")
+
+	assert.Greater(t, tokenSimilarity(a, b), 0.5)
+	assert.Equal(t, 0.0, tokenSimilarity(a, c))
+	assert.Equal(t, 1.0, tokenSimilarity(a, a))
+	assert.Equal(t, 0.0, tokenSimilarity(a, map[string]bool{}))
+}
+
+func TestDuplicateScore(t *testing.T) {
+	// Same text at the same spot is a certain duplicate
+	assert.Equal(t, 1.0, duplicateScore(0, 100, 1))
+	// At the edge of the radius only text counts
+	assert.Equal(t, 0.7, duplicateScore(100, 100, 1))
+	// Closer appeals score higher for the same text similarity
+	assert.Greater(t, duplicateScore(10, 100, 0.5), duplicateScore(90, 100, 0.5))
+	// Distance beyond radius never makes the score negative
+	assert.Equal(t, 0.0, duplicateScore(500, 100, 0))
+}
+
+func TestIsOpenStatus(t *testing.T) {
+	assert.True(t, IsOpenStatus(models.StatusNew))
+	assert.True(t, IsOpenStatus(models.StatusAssigned))
+	assert.True(t, IsOpenStatus(models.StatusInProgress))
+	assert.True(t, IsOpenStatus(models.StatusReopened))
+	assert.False(t, IsOpenStatus(models.StatusCompleted))
+	assert.False(t, IsOpenStatus(models.StatusClosed))
+	assert.False(t, IsOpenStatus(models.StatusRejected))
+	assert.False(t, IsOpenStatus(models.AppealStatus("archived")))
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+	"citizen-appeals/pkg/geo"
+)
+
+// AssignmentInput is what a strategy knows when it picks an executor.
+type AssignmentInput struct {
+	Appeal *models.Appeal
+	// Candidates are the active executors of the appeal's service, ordered by user ID.
+	Candidates []*models.ExecutorWorkload
+	// LastAssignedUserID is the executor who got the service's previous automatic assignment.
+	LastAssignedUserID *int64
+}
+
+// AssignmentStrategy picks the executor for an appeal routed to a service.
+type AssignmentStrategy interface {
+	// Name identifies the strategy on the service and in appeal history.
+	Name() models.AssignmentStrategy
+	// Pick returns the chosen candidate, or nil if there is nobody to pick.
+	Pick(in AssignmentInput) *models.ExecutorWorkload
+}
+
+// assignmentStrategies are the strategies a service can select. 'manual' has no entry:
+// dispatchers and executors assign appeals themselves.
+var assignmentStrategies = map[models.AssignmentStrategy]AssignmentStrategy{
+	models.AssignmentRoundRobin: roundRobinStrategy{},
+	models.AssignmentLeastOpen:  leastOpenStrategy{},
+	models.AssignmentNearest:    nearestStrategy{},
+}
+
+// StrategyFor returns the automatic assignment strategy with the given name.
+func StrategyFor(name models.AssignmentStrategy) (AssignmentStrategy, bool) {
+	strategy, ok := assignmentStrategies[name]
+	return strategy, ok
+}
+
+// roundRobinStrategy takes executors in turn, starting after the previous pick.
+type roundRobinStrategy struct{}
+
+func (roundRobinStrategy) Name() models.AssignmentStrategy { return models.AssignmentRoundRobin }
+
+func (roundRobinStrategy) Pick(in AssignmentInput) *models.ExecutorWorkload {
+	if len(in.Candidates) == 0 {
+		return nil
+	}
+	if in.LastAssignedUserID != nil {
+		for _, c := range in.Candidates {
+			if c.UserID > *in.LastAssignedUserID {
+				return c
+			}
+		}
+	}
+	return in.Candidates[0]
+}
+
+// leastOpenStrategy picks the executor with the fewest open appeals.
+type leastOpenStrategy struct{}
+
+func (leastOpenStrategy) Name() models.AssignmentStrategy { return models.AssignmentLeastOpen }
+
+func (leastOpenStrategy) Pick(in AssignmentInput) *models.ExecutorWorkload {
+	var best *models.ExecutorWorkload
+	for _, c := range in.Candidates {
+		if best == nil || c.OpenAppeals < best.OpenAppeals {
+			best = c
+		}
+	}
+	return best
+}
+
+// nearestStrategy picks the executor whose last known location is closest to the
+// appeal; ties go to the less loaded one. Executors without a known location are
+// only considered when nobody has one, and then the least loaded wins.
+type nearestStrategy struct{}
+
+func (nearestStrategy) Name() models.AssignmentStrategy { return models.AssignmentNearest }
+
+func (nearestStrategy) Pick(in AssignmentInput) *models.ExecutorWorkload {
+	target := geo.Point{Lat: in.Appeal.Latitude, Lng: in.Appeal.Longitude}
+
+	var best *models.ExecutorWorkload
+	bestDistance := math.Inf(1)
+	for _, c := range in.Candidates {
+		if c.Latitude == nil || c.Longitude == nil {
+			continue
+		}
+		d := geo.DistanceMeters(target, geo.Point{Lat: *c.Latitude, Lng: *c.Longitude})
+		if d < bestDistance || (d == bestDistance && c.OpenAppeals < best.OpenAppeals) {
+			best = c
+			bestDistance = d
+		}
+	}
+	if best != nil {
+		return best
+	}
+
+	return leastOpenStrategy{}.Pick(in)
+}
+
+// autoAssignExecutor applies the service's assignment strategy to an appeal that has
+// just been routed to it. Appeals that already have an executor are left alone.
+// It returns the assigned executor's ID, nil if nobody was assigned.
+func (s *AppealService) autoAssignExecutor(ctx context.Context, appealID int64) (*int64, error) {
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal.ServiceID == nil || appeal.AssigneeID != nil || appeal.ParentID != nil || !IsOpenStatus(appeal.Status) {
+		return nil, nil
+	}
+
+	service, err := s.serviceRepo.GetByID(ctx, *appeal.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	strategy, ok := StrategyFor(service.AssignmentStrategy)
+	if !ok {
+		return nil, nil
+	}
+
+	candidates, err := s.userServiceRepo.GetExecutorWorkload(ctx, service.ID)
+	if err != nil {
+		return nil, err
+	}
+	lastAssigned, err := s.serviceRepo.GetLastAssignedUser(ctx, service.ID)
+	if err != nil && !errors.Is(err, repository.ErrServiceNotFound) {
+		return nil, err
+	}
+
+	picked := strategy.Pick(AssignmentInput{
+		Appeal:             appeal,
+		Candidates:         candidates,
+		LastAssignedUserID: lastAssigned,
+	})
+	if picked == nil {
+		log.Printf("No executors available for automatic assignment of appeal %d in service %d", appealID, service.ID)
+		return nil, nil
+	}
+
+	assigned, err := s.repo.AutoAssign(ctx, appealID, service.ID, picked.UserID, strategy.Name())
+	if err != nil || !assigned {
+		return nil, err
+	}
+
+	log.Printf("Appeal %d assigned to executor %d by %s strategy", appealID, picked.UserID, strategy.Name())
+	return &picked.UserID, nil
+}
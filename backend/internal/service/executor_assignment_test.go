@@ -0,0 +1,113 @@
+package service
+
+import (
+	"testing"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func candidate(id int64, open int, loc ...float64) *models.ExecutorWorkload {
+	c := &models.ExecutorWorkload{UserID: id, OpenAppeals: open}
+	if len(loc) == 2 {
+		c.Latitude = &loc[0]
+		c.Longitude = &loc[1]
+	}
+	return c
+}
+
+func TestStrategyFor(t *testing.T) {
+	for _, name := range []models.AssignmentStrategy{
+		models.AssignmentRoundRobin,
+		models.AssignmentLeastOpen,
+		models.AssignmentNearest,
+	} {
+		strategy, ok := StrategyFor(name)
+		if assert.True(t, ok, name) {
+			assert.Equal(t, name, strategy.Name())
+		}
+	}
+
+	_, ok := StrategyFor(models.AssignmentManual)
+	assert.False(t, ok, "manual assignment has no strategy")
+}
+
+func TestRoundRobinStrategy(t *testing.T) {
+	candidates := []*models.ExecutorWorkload{candidate(3, 0), candidate(7, 0), candidate(9, 0)}
+	last := func(id int64) *int64 { return &id }
+
+	tests := []struct {
+		name string
+		last *int64
+		want int64
+	}{
+		{"first assignment starts at the beginning", nil, 3},
+		{"next after previous pick", last(3), 7},
+		{"wraps around after the last executor", last(9), 3},
+		{"previous executor left the service", last(8), 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := roundRobinStrategy{}.Pick(AssignmentInput{Candidates: candidates, LastAssignedUserID: tt.last})
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tt.want, got.UserID)
+			}
+		})
+	}
+
+	assert.Nil(t, roundRobinStrategy{}.Pick(AssignmentInput{}))
+}
+
+func TestLeastOpenStrategy(t *testing.T) {
+	got := leastOpenStrategy{}.Pick(AssignmentInput{
+		Candidates: []*models.ExecutorWorkload{candidate(1, 4), candidate(2, 1), candidate(3, 1)},
+	})
+	if assert.NotNil(t, got) {
+		assert.Equal(t, int64(2), got.UserID, "ties go to the first candidate")
+	}
+
+	assert.Nil(t, leastOpenStrategy{}.Pick(AssignmentInput{}))
+}
+
+func TestNearestStrategy(t *testing.T) {
+	appeal := &models.Appeal{Latitude: 50.4501, Longitude: 30.5234}
+
+	t.Run("closest known location wins", func(t *testing.T) {
+		got := nearestStrategy{}.Pick(AssignmentInput{
+			Appeal: appeal,
+			Candidates: []*models.ExecutorWorkload{
+				candidate(1, 0, 50.5, 30.6),
+				candidate(2, 5, 50.451, 30.524),
+				candidate(3, 0),
+			},
+		})
+		if assert.NotNil(t, got) {
+			assert.Equal(t, int64(2), got.UserID)
+		}
+	})
+
+	t.Run("same location goes to the less loaded executor", func(t *testing.T) {
+		got := nearestStrategy{}.Pick(AssignmentInput{
+			Appeal: appeal,
+			Candidates: []*models.ExecutorWorkload{
+				candidate(1, 3, 50.46, 30.53),
+				candidate(2, 1, 50.46, 30.53),
+			},
+		})
+		if assert.NotNil(t, got) {
+			assert.Equal(t, int64(2), got.UserID)
+		}
+	})
+
+	t.Run("no known locations falls back to least open", func(t *testing.T) {
+		got := nearestStrategy{}.Pick(AssignmentInput{
+			Appeal:     appeal,
+			Candidates: []*models.ExecutorWorkload{candidate(1, 2), candidate(2, 0)},
+		})
+		if assert.NotNil(t, got) {
+			assert.Equal(t, int64(2), got.UserID)
+		}
+	})
+}
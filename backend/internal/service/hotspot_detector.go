@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+	"citizen-appeals/pkg/geo"
+)
+
+// trendThreshold is how much one half of the window must outweigh the other
+// for a hotspot to count as rising or falling
+const trendThreshold = 1.25
+
+// HotspotParams are the clustering parameters of a hotspot run
+type HotspotParams struct {
+	RadiusMeters float64
+	MinAppeals   int
+	WindowDays   int
+}
+
+// HotspotDetector periodically looks for recurring problem locations among the
+// appeals of the last time window and stores them.
+type HotspotDetector struct {
+	repo                 *repository.HotspotRepository
+	systemSettingsLoader func(context.Context) (*models.SystemSettings, error)
+	interval             time.Duration
+}
+
+// NewHotspotDetector creates a new HotspotDetector instance.
+func NewHotspotDetector(
+	repo *repository.HotspotRepository,
+	systemSettingsLoader func(context.Context) (*models.SystemSettings, error),
+	interval time.Duration,
+) *HotspotDetector {
+	return &HotspotDetector{
+		repo:                 repo,
+		systemSettingsLoader: systemSettingsLoader,
+		interval:             interval,
+	}
+}
+
+// Run recomputes hotspots of all categories every interval until ctx is cancelled.
+func (d *HotspotDetector) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if hotspots, err := d.Recompute(ctx, nil, 0); err != nil {
+			log.Printf("Hotspot detection failed: %v", err)
+		} else {
+			log.Printf("Hotspot detection: found %d hotspots", len(hotspots))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Recompute clusters the appeals of the last windowDays (the settings window when 0),
+// optionally of one category, and replaces the stored hotspots of that scope.
+func (d *HotspotDetector) Recompute(ctx context.Context, categoryID *int64, windowDays int) ([]*models.Hotspot, error) {
+	params := d.params(ctx)
+	if windowDays > 0 {
+		params.WindowDays = windowDays
+	}
+
+	now := time.Now()
+	since := now.AddDate(0, 0, -params.WindowDays)
+	points, err := d.repo.ListPoints(ctx, categoryID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	hotspots := BuildHotspots(points, params, now)
+	for _, h := range hotspots {
+		h.CategoryID = categoryID
+	}
+
+	if err := d.repo.Replace(ctx, categoryID, params.WindowDays, hotspots); err != nil {
+		return nil, err
+	}
+	return hotspots, nil
+}
+
+// params reads clustering parameters from the system settings, falling back to defaults.
+func (d *HotspotDetector) params(ctx context.Context) HotspotParams {
+	params := HotspotParams{
+		RadiusMeters: models.DefaultHotspotRadiusMeters,
+		MinAppeals:   models.DefaultHotspotMinAppeals,
+		WindowDays:   models.DefaultHotspotWindowDays,
+	}
+	if d.systemSettingsLoader == nil {
+		return params
+	}
+
+	settings, err := d.systemSettingsLoader(ctx)
+	if err != nil || settings == nil {
+		return params
+	}
+	if settings.HotspotRadiusMeters > 0 {
+		params.RadiusMeters = settings.HotspotRadiusMeters
+	}
+	if settings.HotspotMinAppeals > 0 {
+		params.MinAppeals = settings.HotspotMinAppeals
+	}
+	if settings.HotspotWindowDays > 0 {
+		params.WindowDays = settings.HotspotWindowDays
+	}
+	return params
+}
+
+// BuildHotspots clusters appeal locations with DBSCAN and describes every cluster:
+// centroid, radius (farthest appeal from the centroid), size, most common category,
+// and the trend between the older and the newer half of the window.
+// Hotspots are ordered by size, largest first.
+func BuildHotspots(points []*models.HotspotPoint, params HotspotParams, now time.Time) []*models.Hotspot {
+	locations := make([]geo.Point, len(points))
+	for i, p := range points {
+		locations[i] = geo.Point{Lat: p.Latitude, Lng: p.Longitude}
+	}
+
+	windowStart := now.AddDate(0, 0, -params.WindowDays)
+	midpoint := windowStart.Add(now.Sub(windowStart) / 2)
+
+	clusters := geo.DBSCAN(locations, params.RadiusMeters, params.MinAppeals)
+	hotspots := make([]*models.Hotspot, 0, len(clusters))
+	for _, members := range clusters {
+		memberLocations := make([]geo.Point, len(members))
+		for i, idx := range members {
+			memberLocations[i] = locations[idx]
+		}
+		centroid := geo.Centroid(memberLocations)
+
+		h := &models.Hotspot{
+			WindowDays:  params.WindowDays,
+			CentroidLat: centroid.Lat,
+			CentroidLng: centroid.Lng,
+			AppealCount: len(members),
+			AppealIDs:   make([]int64, 0, len(members)),
+			ComputedAt:  now,
+		}
+
+		categoryCounts := make(map[int64]int)
+		for _, idx := range members {
+			p := points[idx]
+			h.AppealIDs = append(h.AppealIDs, p.AppealID)
+			h.RadiusMeters = math.Max(h.RadiusMeters, geo.DistanceMeters(centroid, locations[idx]))
+			if p.CreatedAt.Before(midpoint) {
+				h.PreviousCount++
+			} else {
+				h.RecentCount++
+			}
+			if p.CategoryID != nil {
+				categoryCounts[*p.CategoryID]++
+			}
+		}
+		sort.Slice(h.AppealIDs, func(i, j int) bool { return h.AppealIDs[i] < h.AppealIDs[j] })
+		h.DominantCategoryID = dominantCategory(categoryCounts)
+		h.Trend = hotspotTrend(h.RecentCount, h.PreviousCount)
+
+		hotspots = append(hotspots, h)
+	}
+
+	sort.SliceStable(hotspots, func(i, j int) bool { return hotspots[i].AppealCount > hotspots[j].AppealCount })
+	return hotspots
+}
+
+// dominantCategory returns the most common category; ties go to the smaller ID.
+func dominantCategory(counts map[int64]int) *int64 {
+	var best *int64
+	bestCount := 0
+	for id, count := range counts {
+		if count > bestCount || (count == bestCount && best != nil && id < *best) {
+			id := id
+			best = &id
+			bestCount = count
+		}
+	}
+	return best
+}
+
+// hotspotTrend compares the appeals of the newer half of the window with the older half.
+func hotspotTrend(recent, previous int) models.HotspotTrend {
+	switch {
+	case float64(recent) > float64(previous)*trendThreshold:
+		return models.HotspotRising
+	case float64(previous) > float64(recent)*trendThreshold:
+		return models.HotspotFalling
+	default:
+		return models.HotspotStable
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildHotspots(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	roads := int64(1)
+	lighting := int64(2)
+
+	point := func(id int64, category *int64, lat, lng float64, daysAgo int) *models.HotspotPoint {
+		return &models.HotspotPoint{AppealID: id, CategoryID: category, Latitude: lat, Longitude: lng, CreatedAt: now.AddDate(0, 0, -daysAgo)}
+	}
+
+	points := []*models.HotspotPoint{
+		// Cluster near Maidan, mostly roads, most appeals in the last month
+		point(1, &roads, 50.4501, 30.5234, 80),
+		point(2, &roads, 50.4502, 30.5236, 20),
+		point(3, &lighting, 50.4500, 30.5233, 10),
+		point(4, &roads, 50.4503, 30.5235, 5),
+		// A smaller cluster on Podil, all old appeals
+		point(5, &lighting, 50.4650, 30.5150, 85),
+		point(6, &lighting, 50.4651, 30.5151, 70),
+		point(7, &lighting, 50.4652, 30.5149, 60),
+		// A lone appeal far away
+		point(8, &roads, 50.4000, 30.6000, 1),
+	}
+
+	hotspots := BuildHotspots(points, HotspotParams{RadiusMeters: 100, MinAppeals: 3, WindowDays: 90}, now)
+	if !assert.Len(t, hotspots, 2) {
+		return
+	}
+
+	maidan := hotspots[0]
+	assert.Equal(t, 4, maidan.AppealCount, "largest hotspot comes first")
+	assert.Equal(t, []int64{1, 2, 3, 4}, maidan.AppealIDs)
+	assert.Equal(t, &roads, maidan.DominantCategoryID)
+	assert.Equal(t, 3, maidan.RecentCount)
+	assert.Equal(t, 1, maidan.PreviousCount)
+	assert.Equal(t, models.HotspotRising, maidan.Trend)
+	assert.InDelta(t, 50.45015, maidan.CentroidLat, 1e-6)
+	assert.Greater(t, maidan.RadiusMeters, 0.0)
+	assert.Less(t, maidan.RadiusMeters, 100.0)
+	assert.Equal(t, 90, maidan.WindowDays)
+	assert.Equal(t, now, maidan.ComputedAt)
+
+	podil := hotspots[1]
+	assert.Equal(t, []int64{5, 6, 7}, podil.AppealIDs)
+	assert.Equal(t, &lighting, podil.DominantCategoryID)
+	assert.Equal(t, models.HotspotFalling, podil.Trend)
+}
+
+func TestBuildHotspots_NotEnoughAppeals(t *testing.T) {
+	now := time.Now()
+	points := []*models.HotspotPoint{
+		{AppealID: 1, Latitude: 50.45, Longitude: 30.52, CreatedAt: now},
+		{AppealID: 2, Latitude: 50.45, Longitude: 30.52, CreatedAt: now},
+	}
+
+	assert.Empty(t, BuildHotspots(points, HotspotParams{RadiusMeters: 100, MinAppeals: 3, WindowDays: 30}, now))
+	assert.Empty(t, BuildHotspots(nil, HotspotParams{RadiusMeters: 100, MinAppeals: 3, WindowDays: 30}, now))
+}
+
+func TestHotspotTrend(t *testing.T) {
+	assert.Equal(t, models.HotspotRising, hotspotTrend(5, 2))
+	assert.Equal(t, models.HotspotFalling, hotspotTrend(2, 5))
+	assert.Equal(t, models.HotspotStable, hotspotTrend(5, 5))
+	assert.Equal(t, models.HotspotStable, hotspotTrend(5, 4), "within the threshold")
+	assert.Equal(t, models.HotspotRising, hotspotTrend(1, 0))
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"citizen-appeals/internal/models"
 	"citizen-appeals/internal/repository"
@@ -92,6 +93,20 @@ func (s *NotificationService) SendAppealAssigned(ctx context.Context, appeal *mo
 	return nil
 }
 
+// SendExecutorAssigned notifies an executor that an appeal was assigned to them personally
+func (s *NotificationService) SendExecutorAssigned(ctx context.Context, appeal *models.Appeal, executorID int64) error {
+	appealID := appeal.ID
+	notification := &models.Notification{
+		UserID:   executorID,
+		AppealID: &appealID,
+		Type:     models.NotificationAppealAssigned,
+		Title:    "Вас призначено виконавцем",
+		Message:  fmt.Sprintf("Вас призначено відповідальним за звернення '%s'", appeal.Title),
+	}
+
+	return s.repo.Create(ctx, notification)
+}
+
 // SendStatusChanged sends notification to appeal creator when status changes
 func (s *NotificationService) SendStatusChanged(ctx context.Context, appeal *models.Appeal, oldStatus models.AppealStatus) error {
 	if appeal.Status == oldStatus {
@@ -99,16 +114,7 @@ func (s *NotificationService) SendStatusChanged(ctx context.Context, appeal *mod
 	}
 
 	appealID := appeal.ID
-	statusLabels := map[models.AppealStatus]string{
-		models.StatusNew:        "Нове",
-		models.StatusAssigned:    "Призначене",
-		models.StatusInProgress:  "В роботі",
-		models.StatusCompleted:   "Виконане",
-		models.StatusClosed:      "Закрите",
-		models.StatusRejected:   "Відхилене",
-	}
-
-	newStatusLabel := statusLabels[appeal.Status]
+	newStatusLabel := AppealStatusLabels[appeal.Status]
 	if newStatusLabel == "" {
 		newStatusLabel = string(appeal.Status)
 	}
@@ -121,10 +127,151 @@ func (s *NotificationService) SendStatusChanged(ctx context.Context, appeal *mod
 		Message:  fmt.Sprintf("Статус звернення '%s' змінено на: %s", appeal.Title, newStatusLabel),
 	}
 
-	if appeal.Status == models.StatusCompleted || appeal.Status == models.StatusClosed {
+	switch appeal.Status {
+	case models.StatusCompleted:
 		notification.Type = models.NotificationAppealCompleted
 		notification.Title = "Звернення виконано"
-		notification.Message = fmt.Sprintf("Ваше звернення '%s' виконано", appeal.Title)
+		notification.Message = fmt.Sprintf(
+			"Ваше звернення '%s' виконано. Підтвердіть результат або поверніть звернення на доопрацювання",
+			appeal.Title,
+		)
+	case models.StatusClosed:
+		notification.Title = "Звернення закрито"
+		notification.Message = fmt.Sprintf("Ваше звернення '%s' закрито", appeal.Title)
+	}
+
+	return s.repo.Create(ctx, notification)
+}
+
+// SendAppealReopened notifies service executors that the author rejected the result.
+// Appeals without a service go to dispatchers instead.
+func (s *NotificationService) SendAppealReopened(ctx context.Context, appeal *models.Appeal, comment string) error {
+	var recipients []*models.User
+	if appeal.ServiceID != nil {
+		executors, err := s.userRepo.GetExecutorsByService(ctx, *appeal.ServiceID)
+		if err != nil {
+			return fmt.Errorf("failed to get executors: %w", err)
+		}
+		recipients = executors
+	}
+
+	if len(recipients) == 0 {
+		users, _, err := s.userRepo.List(ctx, 1, 1000)
+		if err != nil {
+			return fmt.Errorf("failed to get dispatchers: %w", err)
+		}
+		for _, user := range users {
+			if user.Role == models.RoleDispatcher || user.Role == models.RoleAdmin {
+				recipients = append(recipients, user)
+			}
+		}
+	}
+
+	appealID := appeal.ID
+	for _, user := range recipients {
+		notification := &models.Notification{
+			UserID:   user.ID,
+			AppealID: &appealID,
+			Type:     models.NotificationAppealReopened,
+			Title:    "Звернення повторно відкрито",
+			Message:  fmt.Sprintf("Автор не погодився з результатом звернення '%s': %s", appeal.Title, comment),
+		}
+
+		if err := s.repo.Create(ctx, notification); err != nil {
+			// Log error but continue with other notifications
+			continue
+		}
+	}
+
+	return nil
+}
+
+// SendSLABreached notifies dispatchers and admins that an appeal missed an SLA deadline
+func (s *NotificationService) SendSLABreached(ctx context.Context, appeal *models.Appeal, kind models.SLABreachKind) error {
+	users, _, err := s.userRepo.List(ctx, 1, 1000)
+	if err != nil {
+		return fmt.Errorf("failed to get dispatchers: %w", err)
+	}
+
+	title := "Порушено термін виконання"
+	message := fmt.Sprintf("Звернення '%s' не виконано вчасно, пріоритет підвищено", appeal.Title)
+	if kind == models.SLABreachResponse {
+		title = "Порушено термін реагування"
+		message = fmt.Sprintf("Звернення '%s' не взято в роботу вчасно, пріоритет підвищено", appeal.Title)
+	}
+
+	appealID := appeal.ID
+	for _, user := range users {
+		if user.Role != models.RoleDispatcher && user.Role != models.RoleAdmin {
+			continue
+		}
+
+		notification := &models.Notification{
+			UserID:   user.ID,
+			AppealID: &appealID,
+			Type:     models.NotificationSLABreached,
+			Title:    title,
+			Message:  message,
+		}
+
+		if err := s.repo.Create(ctx, notification); err != nil {
+			// Log error but continue with other notifications
+			continue
+		}
+	}
+
+	return nil
+}
+
+// SendVolumeSpike notifies dispatchers and admins about an unusual burst of appeals
+func (s *NotificationService) SendVolumeSpike(ctx context.Context, alert *models.VolumeAlert) error {
+	users, _, err := s.userRepo.List(ctx, 1, 1000)
+	if err != nil {
+		return fmt.Errorf("failed to get dispatchers: %w", err)
+	}
+
+	scope := "категорії"
+	if alert.Category != nil {
+		scope = fmt.Sprintf("категорії '%s'", alert.Category.Name)
+	}
+	if alert.District != nil {
+		scope += fmt.Sprintf(" у районі '%s'", alert.District.Name)
+	}
+	message := fmt.Sprintf(
+		"%d звернень у %s за %d хв (зазвичай близько %.1f). Їх можна об'єднати в один інцидент",
+		alert.RecentCount, scope, int(alert.WindowEnd.Sub(alert.WindowStart).Minutes()), alert.BaselineCount,
+	)
+
+	for _, user := range users {
+		if user.Role != models.RoleDispatcher && user.Role != models.RoleAdmin {
+			continue
+		}
+
+		notification := &models.Notification{
+			UserID:  user.ID,
+			Type:    models.NotificationVolumeSpike,
+			Title:   "Сплеск звернень",
+			Message: message,
+		}
+
+		if err := s.repo.Create(ctx, notification); err != nil {
+			// Log error but continue with other notifications
+			continue
+		}
+	}
+
+	return nil
+}
+
+// SendSavedViewMatch notifies a user that a new appeal matches their saved views
+func (s *NotificationService) SendSavedViewMatch(ctx context.Context, userID int64, appeal *models.Appeal, viewNames []string) error {
+	appealID := appeal.ID
+	notification := &models.Notification{
+		UserID:   userID,
+		AppealID: &appealID,
+		Type:     models.NotificationSavedViewMatch,
+		Title:    "Нове звернення у збереженому вигляді",
+		Message:  fmt.Sprintf("Звернення '%s' відповідає вигляду: %s", appeal.Title, strings.Join(viewNames, ", ")),
 	}
 
 	return s.repo.Create(ctx, notification)
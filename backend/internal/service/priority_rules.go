@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/pkg/geo"
+)
+
+// defaultPriority is used when neither the request nor the category sets one
+const defaultPriority = 2
+
+// ErrInvalidPriorityRule is returned when a rule's parameters do not fit its type.
+var ErrInvalidPriorityRule = errors.New("invalid priority rule")
+
+// PriorityFacts is what the rules engine knows about an appeal.
+type PriorityFacts struct {
+	CategoryID  *int64
+	Description string
+	// PhotoCount is the number of photos the author attached
+	PhotoCount int
+	Location   geo.Point
+	// NearbyOpen are locations of other open appeals around Location,
+	// at least as far as the largest nearby_open radius
+	NearbyOpen []geo.Point
+	// SubmittedAt is checked by time_of_day rules in the server's local time
+	SubmittedAt time.Time
+}
+
+// ValidatePriorityRule checks that a rule has the parameters its type needs.
+func ValidatePriorityRule(rule *models.PriorityRule) error {
+	p := rule.Params
+	switch rule.Type {
+	case models.PriorityRuleKeyword:
+		if len(normalizeKeywords(p.Keywords)) == 0 {
+			return fmt.Errorf("%w: keyword rule needs at least one keyword", ErrInvalidPriorityRule)
+		}
+	case models.PriorityRulePhotoCount:
+		if p.MinPhotos < 1 {
+			return fmt.Errorf("%w: min_photos must be at least 1", ErrInvalidPriorityRule)
+		}
+	case models.PriorityRuleNearbyOpen:
+		if p.RadiusMeters <= 0 || p.MinAppeals < 1 {
+			return fmt.Errorf("%w: radius_meters must be positive and min_appeals at least 1", ErrInvalidPriorityRule)
+		}
+	case models.PriorityRuleTimeOfDay:
+		if _, err := parseClock(p.From); err != nil {
+			return fmt.Errorf("%w: from: %v", ErrInvalidPriorityRule, err)
+		}
+		if _, err := parseClock(p.To); err != nil {
+			return fmt.Errorf("%w: to: %v", ErrInvalidPriorityRule, err)
+		}
+	default:
+		return fmt.Errorf("%w: unknown rule type %q", ErrInvalidPriorityRule, rule.Type)
+	}
+	return nil
+}
+
+// EvaluatePriorityRules applies active rules to the facts. Rules can only raise the
+// priority: the result is the highest of base and the priorities of the rules that fired.
+func EvaluatePriorityRules(base int, rules []*models.PriorityRule, facts PriorityFacts) (int, []*models.PriorityRule) {
+	priority := base
+	fired := make([]*models.PriorityRule, 0)
+	words := descriptionWords(facts.Description)
+
+	for _, rule := range rules {
+		if !rule.IsActive {
+			continue
+		}
+		if rule.CategoryID != nil && (facts.CategoryID == nil || *rule.CategoryID != *facts.CategoryID) {
+			continue
+		}
+		if !ruleMatches(rule, facts, words) {
+			continue
+		}
+		fired = append(fired, rule)
+		if rule.Priority > priority {
+			priority = rule.Priority
+		}
+	}
+
+	return priority, fired
+}
+
+// ruleMatches checks a single rule's condition.
+func ruleMatches(rule *models.PriorityRule, facts PriorityFacts, words []string) bool {
+	p := rule.Params
+	switch rule.Type {
+	case models.PriorityRuleKeyword:
+		for _, keyword := range normalizeKeywords(p.Keywords) {
+			for _, word := range words {
+				if keywordMatches(word, keyword) {
+					return true
+				}
+			}
+		}
+		return false
+	case models.PriorityRulePhotoCount:
+		return p.MinPhotos > 0 && facts.PhotoCount >= p.MinPhotos
+	case models.PriorityRuleNearbyOpen:
+		if p.MinAppeals < 1 {
+			return false
+		}
+		count := 0
+		for _, point := range facts.NearbyOpen {
+			if geo.DistanceMeters(facts.Location, point) <= p.RadiusMeters {
+				count++
+			}
+		}
+		return count >= p.MinAppeals
+	case models.PriorityRuleTimeOfDay:
+		from, err := parseClock(p.From)
+		if err != nil {
+			return false
+		}
+		to, err := parseClock(p.To)
+		if err != nil {
+			return false
+		}
+		// Appeals loaded back from the database carry created_at in UTC
+		submitted := facts.SubmittedAt.Local()
+		now := submitted.Hour()*60 + submitted.Minute()
+		if from <= to {
+			return now >= from && now < to
+		}
+		// The window wraps midnight, e.g. 22:00-06:00
+		return now >= from || now < to
+	}
+	return false
+}
+
+// maxNearbyRadius returns the largest radius of active nearby_open rules, 0 if there are none.
+func maxNearbyRadius(rules []*models.PriorityRule) float64 {
+	radius := 0.0
+	for _, rule := range rules {
+		if rule.IsActive && rule.Type == models.PriorityRuleNearbyOpen && rule.Params.RadiusMeters > radius {
+			radius = rule.Params.RadiusMeters
+		}
+	}
+	return radius
+}
+
+// parseClock parses "HH:MM" into minutes since midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("expected HH:MM, got %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// normalizeKeywords lower-cases keywords and drops empty ones.
+func normalizeKeywords(keywords []string) []string {
+	normalized := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword != "" && keyword != "*" {
+			normalized = append(normalized, keyword)
+		}
+	}
+	return normalized
+}
+
+// inflectionEndings are the Ukrainian case and gender endings a keyword may take,
+// so "газ" finds "газу" and "газом" but not "газон" or "газета"
+var inflectionEndings = map[string]bool{
+	"а": true, "я": true, "у": true, "ю": true, "і": true, "ї": true, "е": true, "є": true,
+	"о": true, "и": true, "ом": true, "ем": true, "єм": true, "ою": true, "ею": true, "єю": true,
+	"ів": true, "їв": true, "ам": true, "ям": true, "ами": true, "ями": true, "ах": true, "ях": true,
+	"ові": true, "еві": true, "єві": true, "ий": true, "ій": true, "ого": true, "ому": true,
+	"ої": true, "им": true, "их": true, "ими": true,
+}
+
+// keywordMatches reports whether a word is the keyword or one of its inflected forms.
+// A keyword ending in "*" is a stem and matches every word it begins.
+func keywordMatches(word, keyword string) bool {
+	if stem, ok := strings.CutSuffix(keyword, "*"); ok {
+		return strings.HasPrefix(word, stem)
+	}
+	rest, ok := strings.CutPrefix(word, keyword)
+	return ok && (rest == "" || inflectionEndings[rest])
+}
+
+// descriptionWords splits text into lower-cased words.
+func descriptionWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+}
+
+// basePriority is the priority before rules: the one requested, else the category default.
+func (s *AppealService) basePriority(ctx context.Context, categoryID int64, requested *int) int {
+	if requested != nil {
+		return *requested
+	}
+	if s.categoryRepo != nil {
+		category, err := s.categoryRepo.GetByID(ctx, categoryID)
+		if err != nil {
+			log.Printf("Failed to get category %d for default priority: %v", categoryID, err)
+		} else if category.DefaultPriority >= 1 && category.DefaultPriority <= 3 {
+			return category.DefaultPriority
+		}
+	}
+	return defaultPriority
+}
+
+// applyPriorityRules runs the rules engine for an appeal and returns the resulting
+// priority and the rules that fired. Rule errors never block the appeal: the base
+// priority is kept instead.
+func (s *AppealService) applyPriorityRules(ctx context.Context, appeal *models.Appeal, photoCount int) (int, []*models.PriorityRule) {
+	if s.priorityRuleRepo == nil {
+		return appeal.Priority, nil
+	}
+
+	rules, err := s.priorityRuleRepo.List(ctx, true)
+	if err != nil {
+		log.Printf("Failed to load priority rules: %v", err)
+		return appeal.Priority, nil
+	}
+
+	facts := PriorityFacts{
+		CategoryID:  appeal.CategoryID,
+		Description: appeal.Description,
+		PhotoCount:  photoCount,
+		Location:    geo.Point{Lat: appeal.Latitude, Lng: appeal.Longitude},
+		SubmittedAt: appeal.CreatedAt,
+	}
+	if facts.SubmittedAt.IsZero() {
+		facts.SubmittedAt = time.Now()
+	}
+	if radius := maxNearbyRadius(rules); radius > 0 {
+		box := geo.BoundingBoxAround(facts.Location, radius)
+		facts.NearbyOpen, err = s.repo.ListOpenLocationsInArea(ctx, box, appeal.ID)
+		if err != nil {
+			log.Printf("Failed to count nearby appeals for priority rules: %v", err)
+		}
+	}
+
+	return EvaluatePriorityRules(appeal.Priority, rules, facts)
+}
+
+// FiredPriorityRules returns the priority rules that fired for an appeal.
+func (s *AppealService) FiredPriorityRules(ctx context.Context, appealID int64) ([]*models.AppealPriorityRule, error) {
+	if s.priorityRuleRepo == nil {
+		return nil, nil
+	}
+	return s.priorityRuleRepo.GetFired(ctx, appealID)
+}
+
+// ReevaluatePriority runs the rules engine again once the author has attached photos.
+// Only rules that had not fired for the appeal before can raise its priority, so a
+// priority lowered by a dispatcher is not bumped back by the same rules.
+// photoCount is the number of photos the author attached so far.
+func (s *AppealService) ReevaluatePriority(ctx context.Context, appealID int64, photoCount int) error {
+	if s.priorityRuleRepo == nil {
+		return nil
+	}
+
+	appeal, err := s.repo.GetByID(ctx, appealID)
+	if err != nil {
+		return err
+	}
+	if !IsOpenStatus(appeal.Status) {
+		return nil
+	}
+
+	previous, err := s.priorityRuleRepo.GetFired(ctx, appealID)
+	if err != nil {
+		return err
+	}
+	seen := make(map[int64]bool, len(previous))
+	for _, rule := range previous {
+		if rule.RuleID != nil {
+			seen[*rule.RuleID] = true
+		}
+	}
+
+	_, fired := s.applyPriorityRules(ctx, appeal, photoCount)
+	priority := appeal.Priority
+	newlyFired := make([]*models.PriorityRule, 0)
+	for _, rule := range fired {
+		if seen[rule.ID] {
+			continue
+		}
+		newlyFired = append(newlyFired, rule)
+		if rule.Priority > priority {
+			priority = rule.Priority
+		}
+	}
+	if len(newlyFired) == 0 {
+		return nil
+	}
+
+	if err := s.priorityRuleRepo.RecordFired(ctx, appealID, newlyFired); err != nil {
+		return err
+	}
+	if priority > appeal.Priority {
+		// Raised by the rules engine, so history shows it as a system action
+		if err := s.repo.UpdatePriority(ctx, appealID, priority, nil); err != nil {
+			return err
+		}
+		log.Printf("Priority of appeal %d raised from %d to %d by priority rules", appealID, appeal.Priority, priority)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/pkg/geo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func rule(id int64, ruleType models.PriorityRuleType, priority int, params models.PriorityRuleParams) *models.PriorityRule {
+	return &models.PriorityRule{ID: id, Name: string(ruleType), Type: ruleType, Params: params, Priority: priority, IsActive: true}
+}
+
+func firedIDs(rules []*models.PriorityRule) []int64 {
+	ids := make([]int64, 0, len(rules))
+	for _, r := range rules {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestEvaluatePriorityRules_Keywords(t *testing.T) {
+	rules := []*models.PriorityRule{
+		rule(1, models.PriorityRuleKeyword, 3, models.PriorityRuleParams{Keywords: []string{"Газ", "прорив"}}),
+	}
+	noon := time.Date(2024, 5, 10, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		description string
+		want        int
+	}{
+		{"Сильний запах газу в під'їзді", 3},
+		{"Прорив труби на перехресті", 3},
+		{"Яма на дорозі біля школи", 1},
+		{"Витоптали газон у сквері", 1},
+		{"Розкидані старі газети біля під'їзду", 1},
+		{"Проривом води залило двір", 3},
+	}
+
+	for _, tt := range tests {
+		priority, _ := EvaluatePriorityRules(1, rules, PriorityFacts{Description: tt.description, SubmittedAt: noon})
+		assert.Equal(t, tt.want, priority, tt.description)
+	}
+}
+
+func TestKeywordMatches(t *testing.T) {
+	assert.True(t, keywordMatches("газ", "газ"))
+	assert.True(t, keywordMatches("газом", "газ"))
+	assert.True(t, keywordMatches("дитина", "дитин"))
+	assert.True(t, keywordMatches("оголеного", "оголен"))
+	assert.False(t, keywordMatches("газон", "газ"), "another word sharing the beginning")
+	assert.False(t, keywordMatches("газета", "газ"))
+	assert.False(t, keywordMatches("га", "газ"))
+
+	assert.True(t, keywordMatches("газопровід", "газ*"), "a stem matches any continuation")
+	assert.True(t, keywordMatches("газон", "газ*"))
+}
+
+func TestEvaluatePriorityRules_OnlyRaises(t *testing.T) {
+	rules := []*models.PriorityRule{
+		rule(1, models.PriorityRulePhotoCount, 2, models.PriorityRuleParams{MinPhotos: 2}),
+	}
+
+	priority, fired := EvaluatePriorityRules(3, rules, PriorityFacts{PhotoCount: 3})
+	assert.Equal(t, 3, priority, "a lower rule priority never lowers the appeal")
+	assert.Equal(t, []int64{1}, firedIDs(fired), "the rule still fired")
+
+	priority, fired = EvaluatePriorityRules(1, rules, PriorityFacts{PhotoCount: 1})
+	assert.Equal(t, 1, priority)
+	assert.Empty(t, fired)
+}
+
+func TestEvaluatePriorityRules_NearbyOpen(t *testing.T) {
+	center := geo.Point{Lat: 50.4501, Lng: 30.5234}
+	near := geo.Point{Lat: 50.4505, Lng: 30.5238} // ~50 m
+	far := geo.Point{Lat: 50.46, Lng: 30.54}      // ~1.6 km
+
+	rules := []*models.PriorityRule{
+		rule(1, models.PriorityRuleNearbyOpen, 3, models.PriorityRuleParams{RadiusMeters: 200, MinAppeals: 2}),
+	}
+
+	priority, _ := EvaluatePriorityRules(1, rules, PriorityFacts{Location: center, NearbyOpen: []geo.Point{near, near, far}})
+	assert.Equal(t, 3, priority)
+
+	priority, _ = EvaluatePriorityRules(1, rules, PriorityFacts{Location: center, NearbyOpen: []geo.Point{near, far}})
+	assert.Equal(t, 1, priority, "appeals outside the radius are not counted")
+
+	assert.Equal(t, 200.0, maxNearbyRadius(rules))
+}
+
+func TestEvaluatePriorityRules_TimeOfDay(t *testing.T) {
+	night := rule(1, models.PriorityRuleTimeOfDay, 2, models.PriorityRuleParams{From: "22:00", To: "06:00"})
+	lunch := rule(2, models.PriorityRuleTimeOfDay, 2, models.PriorityRuleParams{From: "12:00", To: "13:30"})
+	at := func(hour, min int) PriorityFacts {
+		return PriorityFacts{SubmittedAt: time.Date(2024, 5, 10, hour, min, 0, 0, time.Local)}
+	}
+
+	tests := []struct {
+		name  string
+		facts PriorityFacts
+		want  []int64
+	}{
+		{"before midnight", at(23, 15), []int64{1}},
+		{"after midnight", at(5, 59), []int64{1}},
+		{"end of night window is exclusive", at(6, 0), []int64{}},
+		{"inside a daytime window", at(13, 0), []int64{2}},
+		{"after a daytime window", at(13, 30), []int64{}},
+		{"other time zones are read in local time", PriorityFacts{
+			SubmittedAt: time.Date(2024, 5, 10, 23, 15, 0, 0, time.Local).In(time.FixedZone("UTC+14", 14*60*60)),
+		}, []int64{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, fired := EvaluatePriorityRules(1, []*models.PriorityRule{night, lunch}, tt.facts)
+			assert.Equal(t, tt.want, firedIDs(fired))
+		})
+	}
+}
+
+func TestEvaluatePriorityRules_CategoryAndInactive(t *testing.T) {
+	roads := int64(4)
+	parks := int64(7)
+
+	scoped := rule(1, models.PriorityRuleKeyword, 3, models.PriorityRuleParams{Keywords: []string{"дитин"}})
+	scoped.CategoryID = &roads
+	inactive := rule(2, models.PriorityRuleKeyword, 3, models.PriorityRuleParams{Keywords: []string{"дитин"}})
+	inactive.IsActive = false
+	rules := []*models.PriorityRule{scoped, inactive}
+
+	_, fired := EvaluatePriorityRules(1, rules, PriorityFacts{CategoryID: &roads, Description: "Діти граються біля ями, дитина впала"})
+	assert.Equal(t, []int64{1}, firedIDs(fired))
+
+	_, fired = EvaluatePriorityRules(1, rules, PriorityFacts{CategoryID: &parks, Description: "Дитина впала"})
+	assert.Empty(t, fired, "rule is limited to another category")
+}
+
+func TestValidatePriorityRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    *models.PriorityRule
+		wantErr bool
+	}{
+		{"keyword", rule(0, models.PriorityRuleKeyword, 3, models.PriorityRuleParams{Keywords: []string{"газ"}}), false},
+		{"blank keywords", rule(0, models.PriorityRuleKeyword, 3, models.PriorityRuleParams{Keywords: []string{" "}}), true},
+		{"photo count", rule(0, models.PriorityRulePhotoCount, 2, models.PriorityRuleParams{MinPhotos: 3}), false},
+		{"photo count missing", rule(0, models.PriorityRulePhotoCount, 2, models.PriorityRuleParams{}), true},
+		{"nearby", rule(0, models.PriorityRuleNearbyOpen, 3, models.PriorityRuleParams{RadiusMeters: 100, MinAppeals: 2}), false},
+		{"nearby without radius", rule(0, models.PriorityRuleNearbyOpen, 3, models.PriorityRuleParams{MinAppeals: 2}), true},
+		{"time of day", rule(0, models.PriorityRuleTimeOfDay, 2, models.PriorityRuleParams{From: "22:00", To: "6:00"}), false},
+		{"bad clock", rule(0, models.PriorityRuleTimeOfDay, 2, models.PriorityRuleParams{From: "25:00", To: "06:00"}), true},
+		{"unknown type", rule(0, models.PriorityRuleType("weather"), 2, models.PriorityRuleParams{}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePriorityRule(tt.rule)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidPriorityRule)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
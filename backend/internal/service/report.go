@@ -0,0 +1,349 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strconv"
+	"time"
+
+	"citizen-appeals/internal/models"
+)
+
+// Report is a statistics report ready to render: a title and a list of tables
+type Report struct {
+	Title string
+	// PeriodFrom and PeriodTo are set when the report covers a chosen period
+	PeriodFrom  *time.Time
+	PeriodTo    *time.Time
+	GeneratedAt time.Time
+	Sections    []ReportSection
+}
+
+// ReportSection is one table of a report
+type ReportSection struct {
+	Title   string
+	Columns []string
+	Rows    [][]string
+}
+
+// reportColumn maps a key of a statistics row to a table column
+type reportColumn struct {
+	Key    string
+	Title  string
+	Format func(interface{}) string
+}
+
+// reportDateLayout is how days appear in report periods
+const reportDateLayout = "02.01.2006"
+
+// ReportPeriod returns the [from, to) period a date range covers when run at now.
+// Whole-day ranges end at the start of today, so a report run at 08:00 covers full days.
+func ReportPeriod(dateRange models.ReportDateRange, now time.Time) (time.Time, time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	switch dateRange {
+	case models.ReportLastDay:
+		return today.AddDate(0, 0, -1), today
+	case models.ReportLast30Days:
+		return today.AddDate(0, 0, -30), today
+	case models.ReportPreviousMonth:
+		return monthStart.AddDate(0, -1, 0), monthStart
+	case models.ReportMonthToDate:
+		return monthStart, now
+	}
+	return today.AddDate(0, 0, -7), today
+}
+
+// PeriodLabel describes the report period, e.g. "01.05.2024 – 07.05.2024"
+func (r *Report) PeriodLabel() string {
+	if r.PeriodFrom == nil || r.PeriodTo == nil {
+		return ""
+	}
+	// The period end is exclusive; a period ending at midnight ends the day before
+	to := r.PeriodTo.Add(-time.Nanosecond)
+	return r.PeriodFrom.Format(reportDateLayout) + " – " + to.Format(reportDateLayout)
+}
+
+// BuildStatisticsReport lays out the result of AppealRepository.GetStatistics
+func BuildStatisticsReport(stats map[string]interface{}, from, to time.Time) *Report {
+	report := &Report{Title: "Статистика звернень", PeriodFrom: &from, PeriodTo: &to}
+
+	report.Sections = append(report.Sections, summarySection([][2]string{
+		{"Всього звернень", formatReportValue(stats["total"])},
+		{"Середній час обробки, днів", formatReportValue(stats["avg_processing_days"])},
+		{"Прострочені (понад 30 днів)", formatReportValue(stats["overdue_count"])},
+		{"Виконані вчасно, %", formatReportValue(stats["on_time_percentage"])},
+	}))
+
+	byStatus := make(map[string]int64)
+	if counts, ok := stats["by_status"].(map[string]int64); ok {
+		for status, count := range counts {
+			byStatus[statusLabel(status)] = count
+		}
+	}
+	report.Sections = append(report.Sections, countSection("За статусом", "Статус", byStatus))
+
+	byPriority := make(map[string]int64)
+	if counts, ok := stats["by_priority"].(map[int]int64); ok {
+		for priority, count := range counts {
+			byPriority[priorityLabel(priority)] = count
+		}
+	}
+	report.Sections = append(report.Sections, countSection("За пріоритетом", "Пріоритет", byPriority))
+
+	for _, s := range []struct{ key, title, column string }{
+		{"by_category", "За категоріями", "Категорія"},
+		{"by_service", "За службами", "Служба"},
+		{"by_district", "За районами", "Район"},
+		{"by_tag", "За мітками", "Мітка"},
+	} {
+		counts, _ := stats[s.key].(map[string]int64)
+		report.Sections = append(report.Sections, countSection(s.title, s.column, counts))
+	}
+
+	report.Sections = append(report.Sections, tableSection("Динаміка по днях", stats["daily_trend"], []reportColumn{
+		{Key: "date", Title: "Дата", Format: formatReportDate},
+		{Key: "count", Title: "Звернень"},
+	}))
+
+	return report
+}
+
+// BuildAdminDashboardReport lays out the result of AppealRepository.GetAdminDashboard
+func BuildAdminDashboardReport(dashboard map[string]interface{}) *Report {
+	report := &Report{Title: "Панель адміністратора"}
+
+	report.Sections = append(report.Sections,
+		tableSection("Найшвидші служби", dashboard["top_services_by_speed"], []reportColumn{
+			{Key: "name", Title: "Служба"},
+			{Key: "total_appeals", Title: "Звернень"},
+			{Key: "avg_days", Title: "Середній час, днів"},
+		}),
+		tableSection("Усі служби", dashboard["all_services_stats"], []reportColumn{
+			{Key: "name", Title: "Служба"},
+			{Key: "total_appeals", Title: "Звернень"},
+			{Key: "new_count", Title: "Нові"},
+			{Key: "in_progress_count", Title: "В роботі"},
+			{Key: "completed_count", Title: "Виконані"},
+			{Key: "overdue_count", Title: "Прострочені"},
+			{Key: "avg_days", Title: "Середній час, днів"},
+			{Key: "on_time_percentage", Title: "Вчасно, %"},
+		}),
+		tableSection("Динаміка по місяцях", dashboard["monthly_trend"], []reportColumn{
+			{Key: "month", Title: "Місяць"},
+			{Key: "count", Title: "Звернень"},
+		}),
+		tableSection("За днями тижня", dashboard["day_of_week_stats"], []reportColumn{
+			{Key: "name", Title: "День"},
+			{Key: "count", Title: "Звернень"},
+		}),
+	)
+
+	return report
+}
+
+// BuildServiceStatisticsReport lays out the result of AppealRepository.GetServiceStatistics
+func BuildServiceStatisticsReport(stats map[string]interface{}) *Report {
+	report := &Report{Title: "Статистика служби"}
+	if service, ok := stats["service"].(map[string]interface{}); ok {
+		report.Title = fmt.Sprintf("Статистика служби «%s»", formatReportValue(service["name"]))
+	}
+
+	overall, _ := stats["overall"].(map[string]interface{})
+	report.Sections = append(report.Sections,
+		summarySection([][2]string{
+			{"Всього звернень", formatReportValue(overall["total_appeals"])},
+			{"Виконані", formatReportValue(overall["completed_count"])},
+			{"Прострочені", formatReportValue(overall["overdue_count"])},
+			{"Середній час обробки, днів", formatReportValue(overall["avg_days"])},
+			{"Виконані вчасно, %", formatReportValue(overall["on_time_percentage"])},
+			{"Повторно відкриті, %", formatReportValue(overall["reopen_rate"])},
+		}),
+		tableSection("Динаміка по місяцях", stats["monthly_trend"], []reportColumn{
+			{Key: "month", Title: "Місяць"},
+			{Key: "count", Title: "Звернень"},
+		}),
+		tableSection("За статусом", stats["status_distribution"], []reportColumn{
+			{Key: "status", Title: "Статус", Format: func(v interface{}) string { return statusLabel(formatReportValue(v)) }},
+			{Key: "count", Title: "Звернень"},
+		}),
+		tableSection("За категоріями", stats["category_distribution"], []reportColumn{
+			{Key: "category", Title: "Категорія"},
+			{Key: "count", Title: "Звернень"},
+		}),
+		tableSection("Останні звернення", stats["recent_appeals"], []reportColumn{
+			{Key: "id", Title: "№"},
+			{Key: "title", Title: "Назва"},
+			{Key: "status", Title: "Статус", Format: func(v interface{}) string { return statusLabel(formatReportValue(v)) }},
+			{Key: "created_at", Title: "Створено"},
+			{Key: "closed_at", Title: "Закрито"},
+		}),
+	)
+
+	return report
+}
+
+func summarySection(items [][2]string) ReportSection {
+	section := ReportSection{Title: "Загальні показники", Columns: []string{"Показник", "Значення"}}
+	for _, item := range items {
+		section.Rows = append(section.Rows, []string{item[0], item[1]})
+	}
+	return section
+}
+
+// countSection lists counts from the largest down; equal counts are ordered by name
+func countSection(title, column string, counts map[string]int64) ReportSection {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	section := ReportSection{Title: title, Columns: []string{column, "Звернень"}}
+	for _, name := range names {
+		section.Rows = append(section.Rows, []string{name, strconv.FormatInt(counts[name], 10)})
+	}
+	return section
+}
+
+// tableSection turns a list of statistics rows into a table with the given columns
+func tableSection(title string, value interface{}, columns []reportColumn) ReportSection {
+	section := ReportSection{Title: title}
+	for _, c := range columns {
+		section.Columns = append(section.Columns, c.Title)
+	}
+
+	rows, _ := value.([]map[string]interface{})
+	for _, row := range rows {
+		cells := make([]string, len(columns))
+		for i, c := range columns {
+			if c.Format != nil {
+				cells[i] = c.Format(row[c.Key])
+			} else {
+				cells[i] = formatReportValue(row[c.Key])
+			}
+		}
+		section.Rows = append(section.Rows, cells)
+	}
+	return section
+}
+
+func formatReportValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case float64:
+		return strconv.FormatFloat(v, 'f', 1, 64)
+	case time.Time:
+		return v.Local().Format(exportTimeLayout)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Local().Format(exportTimeLayout)
+	}
+	return fmt.Sprint(value)
+}
+
+// formatReportDate turns a YYYY-MM-DD day into DD.MM.YYYY
+func formatReportDate(value interface{}) string {
+	s := formatReportValue(value)
+	if day, err := time.Parse("2006-01-02", s); err == nil {
+		return day.Format(reportDateLayout)
+	}
+	return s
+}
+
+func statusLabel(status string) string {
+	if label, ok := AppealStatusLabels[models.AppealStatus(status)]; ok {
+		return label
+	}
+	return status
+}
+
+func priorityLabel(priority int) string {
+	if label, ok := priorityLabels[priority]; ok {
+		return label
+	}
+	return strconv.Itoa(priority)
+}
+
+var reportHTMLTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="uk">
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body style="font-family: Arial, sans-serif; color: #1f2937;">
+<h1 style="font-size: 20px;">{{.Title}}</h1>
+{{with .PeriodLabel}}<p>Період: {{.}}</p>{{end}}
+{{range .Sections}}
+<h2 style="font-size: 16px; margin-top: 24px;">{{.Title}}</h2>
+{{if .Rows}}<table style="border-collapse: collapse;" cellpadding="6">
+<tr>{{range .Columns}}<th style="border: 1px solid #d1d5db; background: #f3f4f6; text-align: left;">{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td style="border: 1px solid #d1d5db;">{{.}}</td>{{end}}</tr>
+{{end}}</table>{{else}}<p>Немає даних</p>{{end}}
+{{end}}
+<p style="color: #6b7280; font-size: 12px; margin-top: 24px;">Сформовано {{.GeneratedAt.Format "02.01.2006 15:04"}}. Повні дані — у вкладеному CSV-файлі.</p>
+</body>
+</html>
+`))
+
+// RenderReportHTML renders the report as the body of an email
+func RenderReportHTML(report *Report) (string, error) {
+	var buf bytes.Buffer
+	if err := reportHTMLTemplate.Execute(&buf, report); err != nil {
+		return "", fmt.Errorf("failed to render report: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// RenderReportCSV renders every section of the report one after another,
+// each headed by its title and followed by an empty line
+func RenderReportCSV(report *Report) ([]byte, error) {
+	var buf bytes.Buffer
+	out, err := newCSVExportWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, section := range report.Sections {
+		if i > 0 {
+			if err := out.WriteRow(nil); err != nil {
+				return nil, err
+			}
+		}
+		if err := out.WriteRow([]interface{}{section.Title}); err != nil {
+			return nil, err
+		}
+		if err := out.WriteHeader(section.Columns); err != nil {
+			return nil, err
+		}
+		for _, row := range section.Rows {
+			values := make([]interface{}, len(row))
+			for j, cell := range row {
+				values[j] = cell
+			}
+			if err := out.WriteRow(values); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := out.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+	"citizen-appeals/pkg/cron"
+	"citizen-appeals/pkg/mail"
+)
+
+// ErrInvalidReportSubscription is returned when a subscription's schedule or report settings are invalid.
+var ErrInvalidReportSubscription = errors.New("invalid report subscription")
+
+const (
+	// maxReportAttempts is how many times a run is tried before waiting for the next scheduled one
+	maxReportAttempts = 3
+	reportRetryDelay  = 5 * time.Minute
+)
+
+// ReportScheduler emails statistics reports to subscribers on their cron schedules.
+// A failed delivery is retried a few times; every attempt is recorded.
+type ReportScheduler struct {
+	repo       *repository.ReportSubscriptionRepository
+	appealRepo *repository.AppealRepository
+	sender     mail.Sender
+	interval   time.Duration
+}
+
+// NewReportScheduler creates a new ReportScheduler instance.
+func NewReportScheduler(
+	repo *repository.ReportSubscriptionRepository,
+	appealRepo *repository.AppealRepository,
+	sender mail.Sender,
+	interval time.Duration,
+) *ReportScheduler {
+	return &ReportScheduler{
+		repo:       repo,
+		appealRepo: appealRepo,
+		sender:     sender,
+		interval:   interval,
+	}
+}
+
+// Run sends due reports every interval until ctx is cancelled.
+func (s *ReportScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := s.RunDue(ctx); err != nil {
+			log.Printf("Report delivery failed: %v", err)
+		} else if n > 0 {
+			log.Printf("Report delivery: sent %d reports", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue sends every subscription whose next run has come and schedules its next run.
+// It returns how many reports were sent.
+func (s *ReportScheduler) RunDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	subs, err := s.repo.ListDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, sub := range subs {
+		attempt := sub.FailedAttempts + 1
+		_, sendErr := s.Send(ctx, sub, now, attempt)
+		if sendErr == nil {
+			sent++
+		} else {
+			log.Printf("Failed to send report subscription %d (attempt %d): %v", sub.ID, attempt, sendErr)
+		}
+
+		next, lastRun, failed := s.nextRun(sub, now, attempt, sendErr)
+		if err := s.repo.ScheduleNext(ctx, sub.ID, next, lastRun, failed); err != nil {
+			return sent, err
+		}
+	}
+
+	return sent, nil
+}
+
+// nextRun decides when a subscription runs again after an attempt: a failed attempt
+// is retried after reportRetryDelay until maxReportAttempts is reached, otherwise
+// the subscription waits for its next scheduled time.
+func (s *ReportScheduler) nextRun(sub *models.ReportSubscription, now time.Time, attempt int, sendErr error) (*time.Time, *time.Time, int) {
+	if sendErr != nil && attempt < maxReportAttempts {
+		retry := now.Add(reportRetryDelay)
+		return &retry, nil, attempt
+	}
+	if sendErr != nil {
+		log.Printf("Giving up on report subscription %d until its next scheduled run", sub.ID)
+	}
+
+	next, err := NextReportRun(sub.Schedule, now)
+	if err != nil {
+		// The schedule was validated when saved; stop running rather than retry forever
+		log.Printf("Report subscription %d has an invalid schedule: %v", sub.ID, err)
+	}
+	return next, &now, 0
+}
+
+// Send renders a subscription's report, emails it and records the attempt.
+// It does not change the subscription's schedule.
+func (s *ReportScheduler) Send(ctx context.Context, sub *models.ReportSubscription, now time.Time, attempt int) (*models.ReportDelivery, error) {
+	delivery := &models.ReportDelivery{
+		SubscriptionID: sub.ID,
+		Status:         models.ReportDeliverySent,
+		Attempt:        attempt,
+		Recipients:     sub.Recipients,
+	}
+
+	report, sendErr := s.BuildReport(ctx, sub, now)
+	if sendErr == nil {
+		delivery.PeriodFrom = report.PeriodFrom
+		delivery.PeriodTo = report.PeriodTo
+		sendErr = s.deliver(ctx, sub, report)
+	}
+
+	if sendErr != nil {
+		message := sendErr.Error()
+		delivery.Status = models.ReportDeliveryFailed
+		delivery.Error = &message
+	}
+	if err := s.repo.RecordDelivery(ctx, delivery); err != nil {
+		log.Printf("Failed to record delivery of report subscription %d: %v", sub.ID, err)
+	}
+
+	return delivery, sendErr
+}
+
+// BuildReport collects the statistics a subscription asks for
+func (s *ReportScheduler) BuildReport(ctx context.Context, sub *models.ReportSubscription, now time.Time) (*Report, error) {
+	var report *Report
+	switch sub.ReportType {
+	case models.ReportStatistics:
+		from, to := ReportPeriod(sub.DateRange, now)
+		// GetStatistics includes its upper bound while the period excludes it
+		last := to.Add(-time.Microsecond)
+		stats, err := s.appealRepo.GetStatistics(ctx, &from, &last)
+		if err != nil {
+			return nil, err
+		}
+		report = BuildStatisticsReport(stats, from, to)
+	case models.ReportAdminDashboard:
+		dashboard, err := s.appealRepo.GetAdminDashboard(ctx)
+		if err != nil {
+			return nil, err
+		}
+		report = BuildAdminDashboardReport(dashboard)
+	case models.ReportServiceStatistics:
+		if sub.ServiceID == nil {
+			return nil, fmt.Errorf("%w: service is required", ErrInvalidReportSubscription)
+		}
+		stats, err := s.appealRepo.GetServiceStatistics(ctx, *sub.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		report = BuildServiceStatisticsReport(stats)
+	default:
+		return nil, fmt.Errorf("%w: unknown report type %q", ErrInvalidReportSubscription, sub.ReportType)
+	}
+
+	report.GeneratedAt = now
+	return report, nil
+}
+
+func (s *ReportScheduler) deliver(ctx context.Context, sub *models.ReportSubscription, report *Report) error {
+	body, err := RenderReportHTML(report)
+	if err != nil {
+		return err
+	}
+	attachment, err := RenderReportCSV(report)
+	if err != nil {
+		return err
+	}
+
+	subject := fmt.Sprintf("%s: %s", sub.Name, report.Title)
+	if period := report.PeriodLabel(); period != "" {
+		subject += " (" + period + ")"
+	}
+
+	return s.sender.Send(ctx, &mail.Message{
+		To:       sub.Recipients,
+		Subject:  subject,
+		HTMLBody: body,
+		Attachments: []mail.Attachment{{
+			Filename:    fmt.Sprintf("report-%s-%s.csv", sub.ReportType, report.GeneratedAt.Format("2006-01-02")),
+			ContentType: "text/csv; charset=utf-8",
+			Data:        attachment,
+		}},
+	})
+}
+
+// NextReportRun returns the first scheduled time after now; nil if the schedule never fires
+func NextReportRun(schedule string, now time.Time) (*time.Time, error) {
+	parsed, err := cron.Parse(schedule)
+	if err != nil {
+		return nil, err
+	}
+	next := parsed.Next(now)
+	if next.IsZero() {
+		return nil, nil
+	}
+	return &next, nil
+}
+
+// ValidateReportSubscription checks the parts of a subscription the request validator cannot:
+// the cron schedule and the service required by service statistics.
+func ValidateReportSubscription(sub *models.ReportSubscription) error {
+	if _, err := cron.Parse(sub.Schedule); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidReportSubscription, err)
+	}
+	if sub.ReportType == models.ReportServiceStatistics && sub.ServiceID == nil {
+		return fmt.Errorf("%w: service_id is required for service statistics", ErrInvalidReportSubscription)
+	}
+	if len(sub.Recipients) == 0 {
+		return fmt.Errorf("%w: at least one recipient is required", ErrInvalidReportSubscription)
+	}
+	return nil
+}
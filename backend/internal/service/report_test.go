@@ -0,0 +1,182 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportPeriod(t *testing.T) {
+	now := time.Date(2024, 3, 15, 8, 30, 0, 0, time.Local)
+	day := func(month time.Month, d int) time.Time { return time.Date(2024, month, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		dateRange models.ReportDateRange
+		from, to  time.Time
+	}{
+		{models.ReportLastDay, day(3, 14), day(3, 15)},
+		{models.ReportLast7Days, day(3, 8), day(3, 15)},
+		{models.ReportLast30Days, day(2, 14), day(3, 15)},
+		{models.ReportPreviousMonth, day(2, 1), day(3, 1)},
+		{models.ReportMonthToDate, day(3, 1), now},
+		{"", day(3, 8), day(3, 15)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dateRange), func(t *testing.T) {
+			from, to := ReportPeriod(tt.dateRange, now)
+			assert.Equal(t, tt.from, from)
+			assert.Equal(t, tt.to, to)
+		})
+	}
+
+	// Previous month across a year boundary
+	from, to := ReportPeriod(models.ReportPreviousMonth, time.Date(2024, 1, 10, 9, 0, 0, 0, time.Local))
+	assert.Equal(t, time.Date(2023, 12, 1, 0, 0, 0, 0, time.Local), from)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), to)
+}
+
+func TestBuildStatisticsReport(t *testing.T) {
+	stats := map[string]interface{}{
+		"total":               int64(12),
+		"avg_processing_days": 3.25,
+		"overdue_count":       int64(1),
+		"on_time_percentage":  87.5,
+		"by_status":           map[string]int64{"new": 2, "completed": 10},
+		"by_priority":         map[int]int64{3: 4, 1: 8},
+		"by_category":         map[string]int64{"Дороги": 7, "Освітлення": 5},
+		"by_district":         map[string]int64{},
+		"by_tag":              map[string]int64{"зима": 3},
+		"daily_trend": []map[string]interface{}{
+			{"date": "2024-03-08", "count": int64(5)},
+		},
+	}
+	from := time.Date(2024, 3, 8, 0, 0, 0, 0, time.Local)
+	to := time.Date(2024, 3, 15, 0, 0, 0, 0, time.Local)
+
+	report := BuildStatisticsReport(stats, from, to)
+	assert.Equal(t, "08.03.2024 – 14.03.2024", report.PeriodLabel())
+
+	sections := make(map[string]ReportSection)
+	for _, s := range report.Sections {
+		sections[s.Title] = s
+	}
+	assert.Equal(t, [][]string{
+		{"Всього звернень", "12"},
+		{"Середній час обробки, днів", "3.2"},
+		{"Прострочені (понад 30 днів)", "1"},
+		{"Виконані вчасно, %", "87.5"},
+	}, sections["Загальні показники"].Rows)
+	assert.Equal(t, [][]string{{"Виконане", "10"}, {"Нове", "2"}}, sections["За статусом"].Rows)
+	assert.Equal(t, [][]string{{"Низький", "8"}, {"Високий", "4"}}, sections["За пріоритетом"].Rows)
+	assert.Equal(t, [][]string{{"Дороги", "7"}, {"Освітлення", "5"}}, sections["За категоріями"].Rows)
+	assert.Empty(t, sections["За районами"].Rows)
+	assert.Equal(t, [][]string{{"зима", "3"}}, sections["За мітками"].Rows)
+	assert.Empty(t, sections["За службами"].Rows, "missing keys give empty sections")
+	assert.Equal(t, [][]string{{"08.03.2024", "5"}}, sections["Динаміка по днях"].Rows)
+}
+
+func TestBuildServiceStatisticsReport(t *testing.T) {
+	report := BuildServiceStatisticsReport(map[string]interface{}{
+		"service": map[string]interface{}{"name": "Київводоканал"},
+		"overall": map[string]interface{}{"total_appeals": int64(3), "avg_days": 1.0},
+		"status_distribution": []map[string]interface{}{
+			{"status": "in_progress", "count": int64(3)},
+		},
+	})
+
+	assert.Equal(t, "Статистика служби «Київводоканал»", report.Title)
+	assert.Empty(t, report.PeriodLabel())
+	for _, s := range report.Sections {
+		if s.Title == "За статусом" {
+			assert.Equal(t, [][]string{{"В роботі", "3"}}, s.Rows)
+		}
+	}
+}
+
+func TestRenderReport(t *testing.T) {
+	report := &Report{
+		Title:       "Статистика звернень",
+		GeneratedAt: time.Date(2024, 3, 15, 8, 0, 0, 0, time.Local),
+		Sections: []ReportSection{
+			{Title: "За категоріями", Columns: []string{"Категорія", "Звернень"}, Rows: [][]string{{"<b>Дороги</b>", "7"}, {"=1+1", "1"}}},
+			{Title: "За районами", Columns: []string{"Район", "Звернень"}},
+		},
+	}
+
+	html, err := RenderReportHTML(report)
+	assert.NoError(t, err)
+	assert.Contains(t, html, "&lt;b&gt;Дороги&lt;/b&gt;", "cells are escaped")
+	assert.Contains(t, html, "Немає даних")
+	assert.Contains(t, html, "15.03.2024 08:00")
+
+	csv, err := RenderReportCSV(report)
+	assert.NoError(t, err)
+	assert.Equal(t, "\ufeff"+strings.Join([]string{
+		"За категоріями",
+		"Категорія,Звернень",
+		"<b>Дороги</b>,7",
+		"'=1+1,1",
+		"",
+		"За районами",
+		"Район,Звернень",
+		"",
+	}, "\n"), string(csv))
+}
+
+func TestValidateReportSubscription(t *testing.T) {
+	serviceID := int64(2)
+	sub := func(reportType models.ReportType, schedule string, serviceID *int64) *models.ReportSubscription {
+		return &models.ReportSubscription{ReportType: reportType, Schedule: schedule, ServiceID: serviceID, Recipients: []string{"admin@example.com"}}
+	}
+
+	tests := []struct {
+		name    string
+		sub     *models.ReportSubscription
+		wantErr bool
+	}{
+		{"weekly statistics", sub(models.ReportStatistics, "0 8 * * 1", nil), false},
+		{"macro", sub(models.ReportAdminDashboard, "@daily", nil), false},
+		{"service statistics", sub(models.ReportServiceStatistics, "0 8 1 * *", &serviceID), false},
+		{"service statistics without service", sub(models.ReportServiceStatistics, "0 8 1 * *", nil), true},
+		{"bad schedule", sub(models.ReportStatistics, "0 25 * * *", nil), true},
+		{"no recipients", &models.ReportSubscription{ReportType: models.ReportStatistics, Schedule: "@daily"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateReportSubscription(tt.sub)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidReportSubscription)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReportSchedulerNextRun(t *testing.T) {
+	s := &ReportScheduler{}
+	sub := &models.ReportSubscription{ID: 1, Schedule: "0 8 * * *"}
+	now := time.Date(2024, 3, 15, 8, 0, 30, 0, time.Local)
+	tomorrow := time.Date(2024, 3, 16, 8, 0, 0, 0, time.Local)
+
+	next, lastRun, failed := s.nextRun(sub, now, 1, nil)
+	assert.Equal(t, tomorrow, *next)
+	assert.Equal(t, now, *lastRun)
+	assert.Equal(t, 0, failed)
+
+	next, lastRun, failed = s.nextRun(sub, now, 1, assert.AnError)
+	assert.Equal(t, now.Add(reportRetryDelay), *next, "failed attempts are retried")
+	assert.Nil(t, lastRun)
+	assert.Equal(t, 1, failed)
+
+	next, lastRun, failed = s.nextRun(sub, now, maxReportAttempts, assert.AnError)
+	assert.Equal(t, tomorrow, *next, "after the last attempt the next scheduled run is awaited")
+	assert.Equal(t, now, *lastRun)
+	assert.Equal(t, 0, failed)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+)
+
+var (
+	// ErrInvalidSavedView is returned for a view whose filters or sort order cannot be listed.
+	ErrInvalidSavedView = errors.New("invalid saved view")
+	// ErrNotSavedViewOwner is returned when someone the view is shared with tries to change it.
+	ErrNotSavedViewOwner = errors.New("only the owner can change a saved view")
+)
+
+// SavedViewService lists saved views with their live counts and notifies
+// subscribers about new appeals that match their views.
+type SavedViewService struct {
+	repo                *repository.SavedViewRepository
+	appealRepo          *repository.AppealRepository
+	notificationService *NotificationService
+}
+
+func NewSavedViewService(
+	repo *repository.SavedViewRepository,
+	appealRepo *repository.AppealRepository,
+	notificationService *NotificationService,
+) *SavedViewService {
+	return &SavedViewService{
+		repo:                repo,
+		appealRepo:          appealRepo,
+		notificationService: notificationService,
+	}
+}
+
+// ApplySavedViewRequest copies the request's settings to the view. Pagination and
+// sorting are dropped from the filters, sorting is kept in the view's own fields.
+func ApplySavedViewRequest(view *models.SavedView, req *models.SavedViewRequest) error {
+	filters := req.Filters
+	filters.Page, filters.Limit, filters.Cursor = 0, 0, nil
+	filters.SortBy, filters.SortOrder = "", ""
+	if filters.Search != nil {
+		search := strings.TrimSpace(*filters.Search)
+		filters.Search = &search
+		if search == "" {
+			filters.Search = nil
+		}
+	}
+
+	if filters.Status != nil && !knownStatuses[*filters.Status] {
+		return fmt.Errorf("%w: %w %q", ErrInvalidSavedView, ErrUnknownStatus, *filters.Status)
+	}
+	if filters.Near != nil && filters.RadiusMeters <= 0 {
+		return fmt.Errorf("%w: radius_m must be positive when near is given", ErrInvalidSavedView)
+	}
+	if filters.FromDate != nil && filters.ToDate != nil && filters.ToDate.Before(*filters.FromDate) {
+		return fmt.Errorf("%w: to_date is before from_date", ErrInvalidSavedView)
+	}
+	if req.SortBy == "distance" && filters.Near == nil {
+		return fmt.Errorf("%w: sorting by distance needs a near filter", ErrInvalidSavedView)
+	}
+	if req.SortBy == "relevance" && filters.Search == nil {
+		return fmt.Errorf("%w: sorting by relevance needs a search", ErrInvalidSavedView)
+	}
+
+	view.Name = strings.TrimSpace(req.Name)
+	view.Filters = filters
+	view.SortBy = req.SortBy
+	view.SortOrder = req.SortOrder
+	if view.SortOrder == "" {
+		view.SortOrder = "desc"
+	}
+	view.SharedRole = req.SharedRole
+	view.SharedServiceID = req.SharedServiceID
+	return nil
+}
+
+// CheckSavedViewOwner allows changes to a view only to its owner
+func CheckSavedViewOwner(view *models.SavedView, userID int64) error {
+	if view.UserID != userID {
+		return ErrNotSavedViewOwner
+	}
+	return nil
+}
+
+// List retrieves the views the user may use, each with its count of matching appeals
+func (s *SavedViewService) List(ctx context.Context, userID int64, role models.UserRole) ([]*models.SavedView, error) {
+	views, err := s.repo.ListVisible(ctx, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, view := range views {
+		count, err := s.appealRepo.Count(ctx, view.ListFilters())
+		if err != nil {
+			return nil, fmt.Errorf("failed to count saved view %d: %w", view.ID, err)
+		}
+		view.Count = &count
+	}
+
+	return views, nil
+}
+
+// Get retrieves a view the user may use with its count of matching appeals
+func (s *SavedViewService) Get(ctx context.Context, id, userID int64, role models.UserRole) (*models.SavedView, error) {
+	view, err := s.repo.GetVisible(ctx, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := s.appealRepo.Count(ctx, view.ListFilters())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count saved view %d: %w", view.ID, err)
+	}
+	view.Count = &count
+
+	return view, nil
+}
+
+// ListFilters returns the filters and sort order of a view the user may use
+func (s *SavedViewService) ListFilters(ctx context.Context, id, userID int64, role models.UserRole) (*models.AppealFilters, error) {
+	view, err := s.repo.GetVisible(ctx, id, userID, role)
+	if err != nil {
+		return nil, err
+	}
+	return view.ListFilters(), nil
+}
+
+// NotifyMatches notifies the subscribers of every view the new appeal matches.
+// A user gets one notification naming all of their matching views; the author
+// of the appeal is not notified about it.
+func (s *SavedViewService) NotifyMatches(ctx context.Context, appeal *models.Appeal) error {
+	subs, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// A view shared with many subscribers is matched once
+	matched := make(map[int64]bool)
+	viewNames := make(map[int64][]string)
+	users := make([]int64, 0)
+	for _, sub := range subs {
+		if sub.UserID == appeal.UserID {
+			continue
+		}
+
+		matches, seen := matched[sub.View.ID]
+		if !seen {
+			matches, err = s.appealRepo.Matches(ctx, sub.View.ListFilters(), appeal.ID)
+			if err != nil {
+				log.Printf("Failed to match appeal %d against saved view %d: %v", appeal.ID, sub.View.ID, err)
+				continue
+			}
+			matched[sub.View.ID] = matches
+		}
+		if !matches {
+			continue
+		}
+
+		if _, ok := viewNames[sub.UserID]; !ok {
+			users = append(users, sub.UserID)
+		}
+		viewNames[sub.UserID] = append(viewNames[sub.UserID], sub.View.Name)
+	}
+
+	for _, userID := range users {
+		if err := s.notificationService.SendSavedViewMatch(ctx, userID, appeal, viewNames[userID]); err != nil {
+			log.Printf("Failed to notify user %d about saved view match: %v", userID, err)
+		}
+	}
+
+	return nil
+}
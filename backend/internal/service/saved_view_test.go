@@ -0,0 +1,93 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/pkg/geo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplySavedViewRequest(t *testing.T) {
+	status := models.StatusNew
+	categoryID := int64(4)
+	search := "  яма  "
+	cursor := "abc"
+	dispatchers := models.RoleDispatcher
+
+	req := &models.SavedViewRequest{
+		Name: " Нові транспортні ",
+		Filters: models.AppealFilters{
+			Status:     &status,
+			CategoryID: &categoryID,
+			Search:     &search,
+			Page:       3,
+			Limit:      50,
+			Cursor:     &cursor,
+			SortBy:     "status",
+			SortOrder:  "asc",
+		},
+		SortBy:     "priority",
+		SharedRole: &dispatchers,
+	}
+
+	view := &models.SavedView{UserID: 7}
+	if !assert.NoError(t, ApplySavedViewRequest(view, req)) {
+		return
+	}
+
+	assert.Equal(t, "Нові транспортні", view.Name)
+	assert.Equal(t, "priority", view.SortBy)
+	assert.Equal(t, "desc", view.SortOrder, "descending unless asked otherwise")
+	assert.Equal(t, &dispatchers, view.SharedRole)
+
+	// Pagination and sorting are not part of the saved filters
+	assert.Zero(t, view.Filters.Page)
+	assert.Zero(t, view.Filters.Limit)
+	assert.Nil(t, view.Filters.Cursor)
+	assert.Empty(t, view.Filters.SortBy)
+	if assert.NotNil(t, view.Filters.Search) {
+		assert.Equal(t, "яма", *view.Filters.Search)
+	}
+
+	filters := view.ListFilters()
+	assert.Equal(t, "priority", filters.SortBy)
+	assert.Equal(t, "desc", filters.SortOrder)
+	assert.Equal(t, &categoryID, filters.CategoryID)
+	assert.Empty(t, view.Filters.SortBy, "ListFilters does not change the view")
+}
+
+func TestApplySavedViewRequest_Invalid(t *testing.T) {
+	unknown := models.AppealStatus("lost")
+	blank := "   "
+	from := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, -1)
+	near := geo.Point{Lat: 50.45, Lng: 30.52}
+
+	tests := []struct {
+		name string
+		req  models.SavedViewRequest
+	}{
+		{"unknown status", models.SavedViewRequest{Filters: models.AppealFilters{Status: &unknown}}},
+		{"near without radius", models.SavedViewRequest{Filters: models.AppealFilters{Near: &near}}},
+		{"reversed dates", models.SavedViewRequest{Filters: models.AppealFilters{FromDate: &from, ToDate: &to}}},
+		{"distance without near", models.SavedViewRequest{SortBy: "distance"}},
+		{"relevance without search", models.SavedViewRequest{SortBy: "relevance", Filters: models.AppealFilters{Search: &blank}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Name = "Вигляд"
+			err := ApplySavedViewRequest(&models.SavedView{}, &tt.req)
+			assert.ErrorIs(t, err, ErrInvalidSavedView)
+		})
+	}
+}
+
+func TestCheckSavedViewOwner(t *testing.T) {
+	view := &models.SavedView{UserID: 3}
+	assert.NoError(t, CheckSavedViewOwner(view, 3))
+	assert.ErrorIs(t, CheckSavedViewOwner(view, 4), ErrNotSavedViewOwner)
+}
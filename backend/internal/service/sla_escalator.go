@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+)
+
+// slaEscalationBatchSize limits how many breaches are handled per run
+const slaEscalationBatchSize = 200
+
+// slaBreachStore is the part of AppealRepository the escalator works with
+type slaBreachStore interface {
+	GetSLABreaches(ctx context.Context, limit int) ([]*models.SLABreach, error)
+	EscalateSLABreach(ctx context.Context, appealID int64, kind models.SLABreachKind) (bool, error)
+}
+
+// SLAEscalator periodically looks for appeals that missed their SLA deadlines,
+// raises their priority, records the breach in history and notifies dispatchers.
+type SLAEscalator struct {
+	repo                slaBreachStore
+	notificationService *NotificationService
+	interval            time.Duration
+}
+
+// NewSLAEscalator creates a new SLAEscalator instance.
+func NewSLAEscalator(
+	repo *repository.AppealRepository,
+	notificationService *NotificationService,
+	interval time.Duration,
+) *SLAEscalator {
+	return &SLAEscalator{
+		repo:                repo,
+		notificationService: notificationService,
+		interval:            interval,
+	}
+}
+
+// Run escalates breaches every interval until ctx is cancelled.
+func (e *SLAEscalator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := e.EscalateOverdue(ctx); err != nil {
+			log.Printf("SLA escalation failed: %v", err)
+		} else if n > 0 {
+			log.Printf("SLA escalation: escalated %d appeals", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// EscalateOverdue handles all pending SLA breaches and returns how many were escalated.
+func (e *SLAEscalator) EscalateOverdue(ctx context.Context) (int, error) {
+	breaches, err := e.repo.GetSLABreaches(ctx, slaEscalationBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	escalated := 0
+	for _, breach := range breaches {
+		ok, err := e.repo.EscalateSLABreach(ctx, breach.Appeal.ID, breach.Kind)
+		if err != nil {
+			log.Printf("Failed to escalate appeal %d (%s): %v", breach.Appeal.ID, breach.Kind, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		escalated++
+
+		if e.notificationService != nil {
+			if err := e.notificationService.SendSLABreached(ctx, breach.Appeal, breach.Kind); err != nil {
+				log.Printf("Failed to send SLA breach notification: %v", err)
+			}
+		}
+	}
+
+	return escalated, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"citizen-appeals/internal/models"
+)
+
+// fakeBreachStore escalates every breach once, failing for the appeals in failing
+type fakeBreachStore struct {
+	breaches  []*models.SLABreach
+	listErr   error
+	failing   map[int64]bool
+	escalated map[string]bool
+}
+
+func (f *fakeBreachStore) GetSLABreaches(ctx context.Context, limit int) ([]*models.SLABreach, error) {
+	return f.breaches, f.listErr
+}
+
+func (f *fakeBreachStore) EscalateSLABreach(ctx context.Context, appealID int64, kind models.SLABreachKind) (bool, error) {
+	if f.failing[appealID] {
+		return false, errors.New("connection lost")
+	}
+	key := fmt.Sprintf("%s:%d", kind, appealID)
+	if f.escalated[key] {
+		return false, nil
+	}
+	f.escalated[key] = true
+	return true, nil
+}
+
+func breach(appealID int64, kind models.SLABreachKind) *models.SLABreach {
+	return &models.SLABreach{Appeal: &models.Appeal{ID: appealID}, Kind: kind}
+}
+
+func TestSLAEscalator_EscalateOverdue(t *testing.T) {
+	store := &fakeBreachStore{
+		breaches: []*models.SLABreach{
+			breach(1, models.SLABreachResponse),
+			breach(1, models.SLABreachResolution),
+			breach(2, models.SLABreachResolution),
+			breach(3, models.SLABreachResolution),
+		},
+		failing:   map[int64]bool{2: true},
+		escalated: map[string]bool{"resolution:3": true},
+	}
+	escalator := &SLAEscalator{repo: store}
+
+	n, err := escalator.EscalateOverdue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n, "failed and already escalated breaches are not counted")
+	assert.True(t, store.escalated["response:1"])
+	assert.True(t, store.escalated["resolution:1"], "both breaches of an appeal are escalated")
+
+	n, err = escalator.EscalateOverdue(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n, "a breach is escalated only once")
+}
+
+func TestSLAEscalator_EscalateOverdue_ListError(t *testing.T) {
+	escalator := &SLAEscalator{repo: &fakeBreachStore{listErr: errors.New("timeout")}}
+
+	n, err := escalator.EscalateOverdue(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 0, n)
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"citizen-appeals/internal/repository"
+	"citizen-appeals/pkg/storage"
+)
+
+// stagedPhotoBatchSize limits how many staged photos are removed per run
+const stagedPhotoBatchSize = 500
+
+// StagedPhotoCleaner periodically removes staged photos that no appeal or comment
+// claimed within the TTL, together with their files.
+type StagedPhotoCleaner struct {
+	repo     *repository.PhotoRepository
+	storage  storage.Storage
+	ttl      time.Duration
+	interval time.Duration
+}
+
+// NewStagedPhotoCleaner creates a new StagedPhotoCleaner instance.
+func NewStagedPhotoCleaner(repo *repository.PhotoRepository, storage storage.Storage, ttl, interval time.Duration) *StagedPhotoCleaner {
+	return &StagedPhotoCleaner{
+		repo:     repo,
+		storage:  storage,
+		ttl:      ttl,
+		interval: interval,
+	}
+}
+
+// Run removes expired staged photos every interval until ctx is cancelled.
+func (c *StagedPhotoCleaner) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := c.RemoveExpired(ctx); err != nil {
+			log.Printf("Staged photo cleanup failed: %v", err)
+		} else if n > 0 {
+			log.Printf("Staged photo cleanup: removed %d photos", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RemoveExpired deletes staged photos older than the TTL and then their files, variants included.
+// Records go first, so a photo claimed meanwhile never loses its file. Returns how
+// many photos were removed.
+func (c *StagedPhotoCleaner) RemoveExpired(ctx context.Context) (int, error) {
+	photos, err := c.repo.DeleteStagedBefore(ctx, time.Now().Add(-c.ttl), stagedPhotoBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, photo := range photos {
+		for _, path := range photo.StoredFiles() {
+			if err := c.storage.Delete(path); err != nil && !errors.Is(err, storage.ErrFileNotFound) {
+				log.Printf("Failed to delete file of staged photo %d: %v", photo.ID, err)
+			}
+		}
+	}
+
+	return len(photos), nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+)
+
+// ErrNoTags is returned for a bulk tag request that names no tags
+var ErrNoTags = errors.New("at least one tag is required")
+
+// TagService adds and removes tags on appeals in bulk
+type TagService struct {
+	repo *repository.TagRepository
+}
+
+func NewTagService(repo *repository.TagRepository) *TagService {
+	return &TagService{repo: repo}
+}
+
+// NormalizeTagNames trims the names and drops blank ones and case-insensitive repeats,
+// keeping the first spelling of each name
+func NormalizeTagNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.Join(strings.Fields(name), " ")
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
+
+// Tag adds the requested tags to the appeals. Names of unknown tags create free-form tags.
+func (s *TagService) Tag(ctx context.Context, req *models.BulkTagRequest, userID int64) (*models.BulkTagResult, error) {
+	names := NormalizeTagNames(req.TagNames)
+	if len(req.TagIDs) == 0 && len(names) == 0 {
+		return nil, ErrNoTags
+	}
+
+	ids, err := s.repo.EnsureByNames(ctx, names, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	added, err := s.repo.TagAppeals(ctx, req.AppealIDs, append(ids, req.TagIDs...), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BulkTagResult{Changed: added}, nil
+}
+
+// Untag removes the requested tags from the appeals; unknown names are ignored
+func (s *TagService) Untag(ctx context.Context, req *models.BulkTagRequest, userID int64) (*models.BulkTagResult, error) {
+	names := NormalizeTagNames(req.TagNames)
+	if len(req.TagIDs) == 0 && len(names) == 0 {
+		return nil, ErrNoTags
+	}
+
+	ids, err := s.repo.FindIDsByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := s.repo.UntagAppeals(ctx, req.AppealIDs, append(ids, req.TagIDs...), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BulkTagResult{Changed: removed}, nil
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTagNames(t *testing.T) {
+	names := NormalizeTagNames([]string{"  Зима ", "запит  депутата", "", "   ", "зима", "ЗАПИТ ДЕПУТАТА", "Ями"})
+	assert.Equal(t, []string{"Зима", "запит депутата", "Ями"}, names)
+
+	assert.Empty(t, NormalizeTagNames(nil))
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"citizen-appeals/internal/models"
+	"citizen-appeals/internal/repository"
+)
+
+// SpikeParams are the thresholds of volume spike detection
+type SpikeParams struct {
+	// Window is the recent period whose appeals are compared with the baseline
+	Window       time.Duration
+	BaselineDays int
+	// Threshold is how many times the recent count must exceed the baseline
+	Threshold  float64
+	MinAppeals int
+}
+
+// VolumeSpikeDetector periodically compares the recent appeal rate of every category,
+// and of every category within a district, with its rolling baseline. A spike raises
+// a volume alert and notifies dispatchers and admins.
+type VolumeSpikeDetector struct {
+	repo                 *repository.VolumeAlertRepository
+	notificationService  *NotificationService
+	systemSettingsLoader func(context.Context) (*models.SystemSettings, error)
+	interval             time.Duration
+}
+
+// NewVolumeSpikeDetector creates a new VolumeSpikeDetector instance.
+func NewVolumeSpikeDetector(
+	repo *repository.VolumeAlertRepository,
+	notificationService *NotificationService,
+	systemSettingsLoader func(context.Context) (*models.SystemSettings, error),
+	interval time.Duration,
+) *VolumeSpikeDetector {
+	return &VolumeSpikeDetector{
+		repo:                 repo,
+		notificationService:  notificationService,
+		systemSettingsLoader: systemSettingsLoader,
+		interval:             interval,
+	}
+}
+
+// Run checks for spikes every interval until ctx is cancelled.
+func (d *VolumeSpikeDetector) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := d.Check(ctx); err != nil {
+			log.Printf("Volume spike check failed: %v", err)
+		} else if n > 0 {
+			log.Printf("Volume spike check: raised %d alerts", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check compares the last window with the baseline and raises alerts for new spikes.
+// A scope that already had an alert within the last window is not alerted again.
+// It returns how many alerts were raised.
+func (d *VolumeSpikeDetector) Check(ctx context.Context) (int, error) {
+	params := d.params(ctx)
+	now := time.Now()
+	windowStart := now.Add(-params.Window)
+	baselineStart := windowStart.AddDate(0, 0, -params.BaselineDays)
+
+	recent, err := d.repo.CountAppeals(ctx, windowStart, now)
+	if err != nil {
+		return 0, err
+	}
+	if len(recent) == 0 {
+		return 0, nil
+	}
+	baseline, err := d.repo.CountAppeals(ctx, baselineStart, windowStart)
+	if err != nil {
+		return 0, err
+	}
+
+	raised := 0
+	for _, spike := range DetectVolumeSpikes(recent, baseline, params) {
+		exists, err := d.repo.ExistsSince(ctx, spike.CategoryID, spike.DistrictID, windowStart)
+		if err != nil {
+			return raised, err
+		}
+		if exists {
+			continue
+		}
+
+		spike.WindowStart = windowStart
+		spike.WindowEnd = now
+		spike.AppealIDs, err = d.repo.ListAppealIDs(ctx, spike.CategoryID, spike.DistrictID, windowStart, now)
+		if err != nil {
+			return raised, err
+		}
+		if err := d.repo.Create(ctx, spike); err != nil {
+			return raised, err
+		}
+		raised++
+
+		if d.notificationService != nil {
+			// Reload for the category and district names
+			alert, err := d.repo.GetByID(ctx, spike.ID)
+			if err != nil {
+				log.Printf("Failed to get volume alert %d for notifications: %v", spike.ID, err)
+				continue
+			}
+			if err := d.notificationService.SendVolumeSpike(ctx, alert); err != nil {
+				log.Printf("Failed to send volume spike notification: %v", err)
+			}
+		}
+	}
+
+	return raised, nil
+}
+
+// params reads spike thresholds from the system settings, falling back to defaults.
+func (d *VolumeSpikeDetector) params(ctx context.Context) SpikeParams {
+	params := SpikeParams{
+		Window:       models.DefaultSpikeWindowMinutes * time.Minute,
+		BaselineDays: models.DefaultSpikeBaselineDays,
+		Threshold:    models.DefaultSpikeThreshold,
+		MinAppeals:   models.DefaultSpikeMinAppeals,
+	}
+	if d.systemSettingsLoader == nil {
+		return params
+	}
+
+	settings, err := d.systemSettingsLoader(ctx)
+	if err != nil || settings == nil {
+		return params
+	}
+	if settings.SpikeWindowMinutes > 0 {
+		params.Window = time.Duration(settings.SpikeWindowMinutes) * time.Minute
+	}
+	if settings.SpikeBaselineDays > 0 {
+		params.BaselineDays = settings.SpikeBaselineDays
+	}
+	if settings.SpikeThreshold > 0 {
+		params.Threshold = settings.SpikeThreshold
+	}
+	if settings.SpikeMinAppeals > 0 {
+		params.MinAppeals = settings.SpikeMinAppeals
+	}
+	return params
+}
+
+type volumeScope struct {
+	categoryID int64
+	districtID int64 // 0 is the whole city
+}
+
+// DetectVolumeSpikes finds scopes whose recent count is at least MinAppeals and at least
+// Threshold times the baseline count scaled down to one window. Every category is checked
+// city-wide and within each district. A city-wide spike is only reported when none of
+// the category's districts spiked on their own, so one burst raises one alert.
+func DetectVolumeSpikes(recent, baseline []*models.VolumeCount, params SpikeParams) []*models.VolumeAlert {
+	recentTotals := volumeTotals(recent)
+	baselineTotals := volumeTotals(baseline)
+
+	// Share of the baseline period that one window takes
+	windowShare := 0.0
+	if params.BaselineDays > 0 {
+		windowShare = float64(params.Window) / float64(time.Duration(params.BaselineDays)*24*time.Hour)
+	}
+
+	spiked := make(map[volumeScope]float64)
+	districtSpiked := make(map[int64]bool)
+	for scope, count := range recentTotals {
+		expected := float64(baselineTotals[scope]) * windowShare
+		if count < params.MinAppeals || float64(count) < params.Threshold*expected {
+			continue
+		}
+		spiked[scope] = expected
+		if scope.districtID != 0 {
+			districtSpiked[scope.categoryID] = true
+		}
+	}
+
+	alerts := make([]*models.VolumeAlert, 0, len(spiked))
+	for scope, expected := range spiked {
+		if scope.districtID == 0 && districtSpiked[scope.categoryID] {
+			continue
+		}
+		alert := &models.VolumeAlert{
+			CategoryID:    scope.categoryID,
+			RecentCount:   recentTotals[scope],
+			BaselineCount: expected,
+			Status:        models.VolumeAlertOpen,
+		}
+		if scope.districtID != 0 {
+			districtID := scope.districtID
+			alert.DistrictID = &districtID
+		}
+		alerts = append(alerts, alert)
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].CategoryID != alerts[j].CategoryID {
+			return alerts[i].CategoryID < alerts[j].CategoryID
+		}
+		return volumeDistrict(alerts[i]) < volumeDistrict(alerts[j])
+	})
+	return alerts
+}
+
+// volumeTotals sums counts per category city-wide and per category within each district
+func volumeTotals(counts []*models.VolumeCount) map[volumeScope]int {
+	totals := make(map[volumeScope]int)
+	for _, c := range counts {
+		totals[volumeScope{categoryID: c.CategoryID}] += c.Count
+		if c.DistrictID != nil {
+			totals[volumeScope{categoryID: c.CategoryID, districtID: *c.DistrictID}] += c.Count
+		}
+	}
+	return totals
+}
+
+func volumeDistrict(alert *models.VolumeAlert) int64 {
+	if alert.DistrictID == nil {
+		return 0
+	}
+	return *alert.DistrictID
+}
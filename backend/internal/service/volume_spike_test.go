@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"citizen-appeals/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func volumeCount(categoryID int64, districtID *int64, count int) *models.VolumeCount {
+	return &models.VolumeCount{CategoryID: categoryID, DistrictID: districtID, Count: count}
+}
+
+func TestDetectVolumeSpikes(t *testing.T) {
+	podil := int64(1)
+	obolon := int64(2)
+	water, roads, lighting := int64(10), int64(20), int64(30)
+
+	// One hour against 14 days: a window is 1/336 of the baseline
+	params := SpikeParams{Window: time.Hour, BaselineDays: 14, Threshold: 3, MinAppeals: 10}
+
+	recent := []*models.VolumeCount{
+		// A water main burst on Podil
+		volumeCount(water, &podil, 40),
+		volumeCount(water, &obolon, 1),
+		// Potholes spread all over the city, none of the districts spikes on its own
+		volumeCount(roads, &podil, 6),
+		volumeCount(roads, &obolon, 6),
+		// Lighting is always busy
+		volumeCount(lighting, nil, 12),
+	}
+	baseline := []*models.VolumeCount{
+		volumeCount(water, &podil, 336),  // ~1 per hour
+		volumeCount(water, &obolon, 336), // ~1 per hour
+		volumeCount(roads, &podil, 336),
+		volumeCount(roads, &obolon, 336),
+		volumeCount(lighting, nil, 336*10), // ~10 per hour
+	}
+
+	alerts := DetectVolumeSpikes(recent, baseline, params)
+	if !assert.Len(t, alerts, 2) {
+		return
+	}
+
+	assert.Equal(t, water, alerts[0].CategoryID)
+	if assert.NotNil(t, alerts[0].DistrictID, "district spike replaces the city-wide one") {
+		assert.Equal(t, podil, *alerts[0].DistrictID)
+	}
+	assert.Equal(t, 40, alerts[0].RecentCount)
+	assert.InDelta(t, 1.0, alerts[0].BaselineCount, 1e-9)
+	assert.Equal(t, models.VolumeAlertOpen, alerts[0].Status)
+
+	assert.Equal(t, roads, alerts[1].CategoryID)
+	assert.Nil(t, alerts[1].DistrictID, "spread over districts raises a city-wide alert")
+	assert.Equal(t, 12, alerts[1].RecentCount)
+	assert.InDelta(t, 2.0, alerts[1].BaselineCount, 1e-9)
+}
+
+func TestDetectVolumeSpikes_MinAppeals(t *testing.T) {
+	params := SpikeParams{Window: time.Hour, BaselineDays: 14, Threshold: 3, MinAppeals: 10}
+
+	// Nine appeals of a category that never had any is still below the minimum
+	alerts := DetectVolumeSpikes([]*models.VolumeCount{volumeCount(1, nil, 9)}, nil, params)
+	assert.Empty(t, alerts)
+
+	alerts = DetectVolumeSpikes([]*models.VolumeCount{volumeCount(1, nil, 10)}, nil, params)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, 0.0, alerts[0].BaselineCount)
+	}
+}
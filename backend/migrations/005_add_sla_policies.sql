@@ -0,0 +1,58 @@
+-- +migrate Up
+-- SLA policies: response and resolution targets per category and priority
+
+CREATE TABLE IF NOT EXISTS sla_policies (
+    id BIGSERIAL PRIMARY KEY,
+    -- NULL category_id is the city-wide default for the given priority
+    category_id BIGINT REFERENCES categories(id) ON DELETE CASCADE,
+    priority INT NOT NULL CHECK (priority >= 1 AND priority <= 3),
+    response_hours INT NOT NULL CHECK (response_hours > 0),
+    resolution_hours INT NOT NULL CHECK (resolution_hours > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sla_policies_category_priority ON sla_policies (COALESCE(category_id, 0), priority);
+
+-- Default targets; resolution for low priority matches the 30-day legal deadline
+INSERT INTO sla_policies (category_id, priority, response_hours, resolution_hours) VALUES
+    (NULL, 3, 4, 72),
+    (NULL, 2, 24, 168),
+    (NULL, 1, 72, 720)
+ON CONFLICT DO NOTHING;
+
+-- Deadlines computed when the appeal is created
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS response_due_at TIMESTAMP;
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS due_at TIMESTAMP;
+-- Set by the escalator so every breach is escalated only once
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS response_escalated_at TIMESTAMP;
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS due_escalated_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_appeals_due_at_open ON appeals (due_at) WHERE status NOT IN ('completed', 'closed', 'rejected');
+CREATE INDEX IF NOT EXISTS idx_appeals_response_due_at_new ON appeals (response_due_at) WHERE status = 'new';
+
+-- Backfill deadlines for existing appeals from the default policies
+UPDATE appeals a
+SET response_due_at = a.created_at + make_interval(hours => p.response_hours),
+    due_at = a.created_at + make_interval(hours => p.resolution_hours)
+FROM sla_policies p
+WHERE p.category_id IS NULL AND p.priority = a.priority AND a.due_at IS NULL;
+
+-- Do not escalate historical breaches all at once after the migration
+UPDATE appeals SET response_escalated_at = NOW() WHERE response_due_at < NOW() AND response_escalated_at IS NULL;
+UPDATE appeals SET due_escalated_at = NOW() WHERE due_at < NOW() AND due_escalated_at IS NULL;
+
+-- System actions (e.g. SLA escalation) are recorded without an acting user
+ALTER TABLE appeal_history ALTER COLUMN user_id DROP NOT NULL;
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'sla_breached';
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_appeals_response_due_at_new;
+DROP INDEX IF EXISTS idx_appeals_due_at_open;
+ALTER TABLE appeals DROP COLUMN IF EXISTS due_escalated_at;
+ALTER TABLE appeals DROP COLUMN IF EXISTS response_escalated_at;
+ALTER TABLE appeals DROP COLUMN IF EXISTS due_at;
+ALTER TABLE appeals DROP COLUMN IF EXISTS response_due_at;
+DROP TABLE IF EXISTS sla_policies;
+-- Enum values cannot be removed; 'sla_breached' stays in notification_type
@@ -0,0 +1,28 @@
+-- +migrate Up
+-- Duplicate detection: best match found on creation and citizens attached to existing appeals
+
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS duplicate_of_id BIGINT REFERENCES appeals(id) ON DELETE SET NULL;
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS duplicate_score DOUBLE PRECISION;
+
+-- Prefilter for nearby open appeals of the same category
+CREATE INDEX IF NOT EXISTS idx_appeals_category_location_open ON appeals (category_id, latitude, longitude)
+    WHERE status NOT IN ('completed', 'closed', 'rejected');
+
+-- Citizens who reported the same problem by joining an existing appeal
+CREATE TABLE IF NOT EXISTS appeal_supporters (
+    id BIGSERIAL PRIMARY KEY,
+    appeal_id BIGINT NOT NULL REFERENCES appeals(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE(appeal_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_appeal_supporters_appeal_id ON appeal_supporters (appeal_id);
+CREATE INDEX IF NOT EXISTS idx_appeal_supporters_user_id ON appeal_supporters (user_id);
+
+-- +migrate Down
+DROP TABLE IF EXISTS appeal_supporters;
+DROP INDEX IF EXISTS idx_appeals_category_location_open;
+ALTER TABLE appeals DROP COLUMN IF EXISTS duplicate_score;
+ALTER TABLE appeals DROP COLUMN IF EXISTS duplicate_of_id;
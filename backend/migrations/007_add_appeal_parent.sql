@@ -0,0 +1,17 @@
+-- +migrate Up
+-- Parent incident: several citizen appeals folded into one master appeal
+
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS parent_id BIGINT REFERENCES appeals(id) ON DELETE SET NULL;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chk_appeals_parent_not_self') THEN
+        ALTER TABLE appeals ADD CONSTRAINT chk_appeals_parent_not_self CHECK (parent_id IS NULL OR parent_id <> id);
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_appeals_parent_id ON appeals (parent_id) WHERE parent_id IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_appeals_parent_id;
+ALTER TABLE appeals DROP CONSTRAINT IF EXISTS chk_appeals_parent_not_self;
+ALTER TABLE appeals DROP COLUMN IF EXISTS parent_id;
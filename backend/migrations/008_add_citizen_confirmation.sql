@@ -0,0 +1,23 @@
+-- +migrate Up
+-- Citizen confirmation: 'completed' means the executor is done, 'closed' means the author
+-- confirmed (or the confirmation window passed). The author may reopen instead.
+
+ALTER TYPE appeal_status ADD VALUE IF NOT EXISTS 'reopened';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'appeal_reopened';
+
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS reopen_count INT NOT NULL DEFAULT 0;
+
+-- closed_at used to be set for both statuses; it now marks the final closure only
+UPDATE appeals SET completed_at = closed_at WHERE closed_at IS NOT NULL AND completed_at IS NULL;
+UPDATE appeals SET closed_at = NULL WHERE status = 'completed';
+
+-- Auto-close lookup
+CREATE INDEX IF NOT EXISTS idx_appeals_completed_at ON appeals (completed_at) WHERE status = 'completed';
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_appeals_completed_at;
+UPDATE appeals SET closed_at = completed_at WHERE status = 'completed' AND closed_at IS NULL;
+ALTER TABLE appeals DROP COLUMN IF EXISTS reopen_count;
+ALTER TABLE appeals DROP COLUMN IF EXISTS completed_at;
+-- Enum values cannot be removed; 'reopened' and 'appeal_reopened' stay
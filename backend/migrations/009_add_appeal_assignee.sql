@@ -0,0 +1,10 @@
+-- +migrate Up
+-- Individual executor responsible for an appeal within its service
+
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS assignee_id BIGINT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_appeals_assignee_id_status ON appeals (assignee_id, status) WHERE assignee_id IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_appeals_assignee_id_status;
+ALTER TABLE appeals DROP COLUMN IF EXISTS assignee_id;
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- Automatic executor assignment per service
+
+ALTER TABLE services ADD COLUMN IF NOT EXISTS assignment_strategy VARCHAR(20) NOT NULL DEFAULT 'manual';
+ALTER TABLE services ADD COLUMN IF NOT EXISTS last_assigned_user_id BIGINT REFERENCES users(id) ON DELETE SET NULL;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chk_services_assignment_strategy') THEN
+        ALTER TABLE services ADD CONSTRAINT chk_services_assignment_strategy
+            CHECK (assignment_strategy IN ('manual', 'round_robin', 'least_open', 'nearest'));
+    END IF;
+END $$;
+
+-- +migrate Down
+ALTER TABLE services DROP CONSTRAINT IF EXISTS chk_services_assignment_strategy;
+ALTER TABLE services DROP COLUMN IF EXISTS last_assigned_user_id;
+ALTER TABLE services DROP COLUMN IF EXISTS assignment_strategy;
@@ -0,0 +1,45 @@
+-- +migrate Up
+-- Rules that raise appeal priority above the category default
+
+CREATE TABLE IF NOT EXISTS priority_rules (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    rule_type VARCHAR(30) NOT NULL CHECK (rule_type IN ('keyword', 'photo_count', 'nearby_open', 'time_of_day')),
+    -- Condition parameters, shape depends on rule_type
+    params JSONB NOT NULL DEFAULT '{}',
+    -- Priority the appeal is raised to when the rule fires
+    priority INT NOT NULL CHECK (priority >= 1 AND priority <= 3),
+    -- NULL category_id applies the rule to every category
+    category_id BIGINT REFERENCES categories(id) ON DELETE CASCADE,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Rules that fired for an appeal; name and priority are kept in case the rule changes later
+CREATE TABLE IF NOT EXISTS appeal_priority_rules (
+    id BIGSERIAL PRIMARY KEY,
+    appeal_id BIGINT NOT NULL REFERENCES appeals(id) ON DELETE CASCADE,
+    rule_id BIGINT REFERENCES priority_rules(id) ON DELETE SET NULL,
+    rule_name VARCHAR(100) NOT NULL,
+    rule_type VARCHAR(30) NOT NULL,
+    priority INT NOT NULL,
+    fired_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (appeal_id, rule_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_appeal_priority_rules_appeal_id ON appeal_priority_rules (appeal_id);
+
+-- Default rules; a re-run skips the ones that already exist by name
+INSERT INTO priority_rules (name, rule_type, params, priority)
+SELECT v.name, v.rule_type, v.params::JSONB, v.priority
+FROM (VALUES
+    ('Небезпека для життя', 'keyword', '{"keywords": ["газ", "газов", "газопровід", "газопровод", "прорив", "дитин", "пожеж", "оголен"]}', 3),
+    ('Проблема зачіпає багатьох', 'nearby_open', '{"radius_meters": 200, "min_appeals": 3}', 3),
+    ('Нічне звернення', 'time_of_day', '{"from": "22:00", "to": "06:00"}', 2)
+) AS v(name, rule_type, params, priority)
+WHERE NOT EXISTS (SELECT 1 FROM priority_rules p WHERE p.name = v.name);
+
+-- +migrate Down
+DROP TABLE IF EXISTS appeal_priority_rules;
+DROP TABLE IF EXISTS priority_rules;
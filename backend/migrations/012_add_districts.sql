@@ -0,0 +1,40 @@
+-- +migrate Up
+-- City districts and routing of appeals to services by (category, district)
+
+CREATE TABLE IF NOT EXISTS districts (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    -- GeoJSON Polygon or MultiPolygon geometry
+    geometry JSONB NOT NULL,
+    -- Bounding box of the geometry, used to prefilter point lookups
+    min_lat DOUBLE PRECISION NOT NULL,
+    min_lng DOUBLE PRECISION NOT NULL,
+    max_lat DOUBLE PRECISION NOT NULL,
+    max_lng DOUBLE PRECISION NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_districts_bounds ON districts (min_lat, max_lat, min_lng, max_lng) WHERE is_active = true;
+
+CREATE TABLE IF NOT EXISTS routing_rules (
+    id BIGSERIAL PRIMARY KEY,
+    -- NULL category_id routes every category of the district
+    category_id BIGINT REFERENCES categories(id) ON DELETE CASCADE,
+    district_id BIGINT NOT NULL REFERENCES districts(id) ON DELETE CASCADE,
+    service_id BIGINT NOT NULL REFERENCES services(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_routing_rules_category_district ON routing_rules (COALESCE(category_id, 0), district_id);
+
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS district_id BIGINT REFERENCES districts(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_appeals_district_id ON appeals (district_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_appeals_district_id;
+ALTER TABLE appeals DROP COLUMN IF EXISTS district_id;
+DROP TABLE IF EXISTS routing_rules;
+DROP TABLE IF EXISTS districts;
@@ -0,0 +1,27 @@
+-- +migrate Up
+-- Recurring problem locations found by density-based clustering of appeals
+
+CREATE TABLE IF NOT EXISTS hotspots (
+    id BIGSERIAL PRIMARY KEY,
+    -- Scope of the run that found the hotspot: NULL category_id means all categories
+    category_id BIGINT REFERENCES categories(id) ON DELETE CASCADE,
+    window_days INT NOT NULL CHECK (window_days > 0),
+    centroid_lat DOUBLE PRECISION NOT NULL,
+    centroid_lng DOUBLE PRECISION NOT NULL,
+    radius_meters DOUBLE PRECISION NOT NULL,
+    appeal_count INT NOT NULL,
+    dominant_category_id BIGINT REFERENCES categories(id) ON DELETE SET NULL,
+    -- Appeals in the newer and the older half of the window
+    recent_count INT NOT NULL DEFAULT 0,
+    previous_count INT NOT NULL DEFAULT 0,
+    trend VARCHAR(10) NOT NULL,
+    appeal_ids BIGINT[] NOT NULL DEFAULT '{}',
+    computed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_hotspots_trend CHECK (trend IN ('rising', 'stable', 'falling'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_hotspots_scope ON hotspots (COALESCE(category_id, 0), window_days);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_hotspots_scope;
+DROP TABLE IF EXISTS hotspots;
@@ -0,0 +1,33 @@
+-- +migrate Up
+-- Alerts raised when a category (optionally within a district) gets far more appeals than usual
+
+CREATE TABLE IF NOT EXISTS volume_alerts (
+    id BIGSERIAL PRIMARY KEY,
+    category_id BIGINT NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    -- NULL district_id is a city-wide spike of the category
+    district_id BIGINT REFERENCES districts(id) ON DELETE CASCADE,
+    window_start TIMESTAMP NOT NULL,
+    window_end TIMESTAMP NOT NULL,
+    recent_count INT NOT NULL,
+    -- Appeals expected in a window of the same length, averaged over the baseline period
+    baseline_count DOUBLE PRECISION NOT NULL,
+    appeal_ids BIGINT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    -- Master appeal the alert's appeals were grouped into
+    incident_appeal_id BIGINT REFERENCES appeals(id) ON DELETE SET NULL,
+    resolved_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_volume_alerts_status CHECK (status IN ('open', 'grouped', 'dismissed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_volume_alerts_scope ON volume_alerts (category_id, COALESCE(district_id, 0), created_at);
+CREATE INDEX IF NOT EXISTS idx_volume_alerts_status ON volume_alerts (status);
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'volume_spike';
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_volume_alerts_status;
+DROP INDEX IF EXISTS idx_volume_alerts_scope;
+DROP TABLE IF EXISTS volume_alerts;
+-- Enum values cannot be removed; 'volume_spike' stays in notification_type
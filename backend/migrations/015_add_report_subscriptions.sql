@@ -0,0 +1,50 @@
+-- +migrate Up
+-- Statistics reports emailed to a list of recipients on a cron schedule
+
+CREATE TABLE IF NOT EXISTS report_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(200) NOT NULL,
+    report_type VARCHAR(30) NOT NULL,
+    -- Required for service_statistics, ignored otherwise
+    service_id BIGINT REFERENCES services(id) ON DELETE CASCADE,
+    -- Period covered by the statistics report, relative to the run time
+    date_range VARCHAR(30) NOT NULL DEFAULT 'last_7_days',
+    recipients TEXT[] NOT NULL,
+    -- Standard 5-field cron expression in server local time
+    schedule VARCHAR(100) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    next_run_at TIMESTAMP,
+    last_run_at TIMESTAMP,
+    -- Consecutive failed attempts of the current run; reset once it is sent or given up
+    failed_attempts INT NOT NULL DEFAULT 0,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_report_subscriptions_report_type CHECK (report_type IN ('statistics', 'admin_dashboard', 'service_statistics')),
+    CONSTRAINT chk_report_subscriptions_date_range CHECK (date_range IN ('last_day', 'last_7_days', 'last_30_days', 'previous_month', 'month_to_date')),
+    CONSTRAINT chk_report_subscriptions_service_id CHECK (report_type <> 'service_statistics' OR service_id IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_subscriptions_due ON report_subscriptions (next_run_at) WHERE is_active;
+
+-- Every delivery attempt, successful or not
+CREATE TABLE IF NOT EXISTS report_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES report_subscriptions(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    attempt INT NOT NULL DEFAULT 1,
+    recipients TEXT[] NOT NULL,
+    period_from TIMESTAMP,
+    period_to TIMESTAMP,
+    error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_report_deliveries_status CHECK (status IN ('sent', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_report_deliveries_subscription ON report_deliveries (subscription_id, created_at DESC);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_report_deliveries_subscription;
+DROP TABLE IF EXISTS report_deliveries;
+DROP INDEX IF EXISTS idx_report_subscriptions_due;
+DROP TABLE IF EXISTS report_subscriptions;
@@ -0,0 +1,163 @@
+-- +migrate Up
+-- Full-text search over appeals and public comments with Ukrainian stemming.
+-- PostgreSQL ships no Ukrainian stemmer, so words are reduced to their stems by
+-- ukrainian_stem() before they reach the ukrainian_stemmed configuration, which
+-- then only lowercases them. Searches are stemmed the same way, so "ями" matches "яма".
+
+-- Suffix-stripping stemmer for Ukrainian (after Tochilkin's Porter-style algorithm).
+-- Endings are only removed after the first vowel of the word.
+CREATE OR REPLACE FUNCTION ukrainian_stem(word TEXT) RETURNS TEXT AS $$
+DECLARE
+    w TEXT := translate(lower(word), '''ʼ’', '');
+    prefix TEXT;
+    rv TEXT;
+    stripped TEXT;
+BEGIN
+    prefix := substring(w FROM '^[^аеиоуюяіїє]*[аеиоуюяіїє]');
+    IF prefix IS NULL THEN
+        RETURN w;
+    END IF;
+    rv := substr(w, length(prefix) + 1);
+
+    -- Perfective gerund; otherwise a reflexive ending followed by an adjective, verb or noun ending
+    stripped := regexp_replace(rv, '(ившись|ивши|вшись|вши)$', '');
+    IF stripped <> rv THEN
+        rv := stripped;
+    ELSE
+        rv := regexp_replace(rv, 'с[яьи]$', '');
+        stripped := regexp_replace(rv, '(ими|ій|ий|а|е|ова|ове|ів|є|їй|єє|еє|я|ім|ем|им|их|іх|ою|йми|іми|у|ю|ого|ому|ої)$', '');
+        IF stripped <> rv THEN
+            -- Participles end like adjectives after their own suffix
+            rv := regexp_replace(stripped, '(ий|ого|ому|им|ім|а|ій|у|ою|і|их|йми)$', '');
+        ELSE
+            stripped := regexp_replace(rv, '(сь|ся|ать|ять|ить|ють|уть|у|ю|ав|али|учи|ячи|вши|ши|е|ме|ати|яти|ити|є)$', '');
+            IF stripped <> rv THEN
+                rv := stripped;
+            ELSE
+                rv := regexp_replace(rv, '(а|ев|ов|е|ями|ами|еи|и|ей|ой|ий|й|иям|ям|ием|ем|ам|ом|о|у|ах|иях|ях|ы|ь|ию|ью|ю|ия|ья|я|і|ові|ї|ею|єю|ою|є|еві|єм|ів|їв)$', '');
+            END IF;
+        END IF;
+    END IF;
+
+    rv := regexp_replace(rv, 'и$', '');
+    -- Suffix of abstract nouns: "швидкість" and "швидкості" share "швидк"
+    rv := regexp_replace(rv, '(.)(іст|ост)$', '\1');
+    rv := regexp_replace(rv, '(ейше|ейш)$', '');
+    IF rv ~ 'ь$' THEN
+        rv := substr(rv, 1, length(rv) - 1);
+    ELSE
+        rv := regexp_replace(rv, 'нн$', 'н');
+    END IF;
+
+    RETURN prefix || rv;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE STRICT;
+
+-- Words too common to search by
+CREATE OR REPLACE FUNCTION ukrainian_stopword(word TEXT) RETURNS BOOLEAN AS $$
+    SELECT word = ANY (ARRAY[
+        'а', 'але', 'б', 'би', 'біля', 'бо', 'в', 'вже', 'ви', 'від', 'він', 'во', 'вона', 'вони', 'воно',
+        'де', 'для', 'до', 'є', 'ж', 'же', 'з', 'за', 'зі', 'і', 'із', 'й', 'його', 'її', 'їх', 'к', 'коли',
+        'ми', 'на', 'над', 'не', 'ні', 'о', 'об', 'от', 'по', 'під', 'про', 'та', 'так', 'там', 'те', 'ти',
+        'то', 'той', 'тут', 'у', 'це', 'цей', 'ці', 'ця', 'чи', 'що', 'як', 'яка', 'яке', 'який', 'які', 'я'
+    ])
+$$ LANGUAGE sql IMMUTABLE STRICT;
+
+-- Splits text into words, drops stop words and stems the rest, keeping word order
+CREATE OR REPLACE FUNCTION ukrainian_stem_text(doc TEXT) RETURNS TEXT AS $$
+    SELECT COALESCE(string_agg(ukrainian_stem(t.word), ' ' ORDER BY t.n), '')
+    FROM regexp_split_to_table(lower(COALESCE(doc, '')), '[^0-9a-zа-яіїєґ''ʼ’]+') WITH ORDINALITY AS t(word, n)
+    WHERE translate(t.word, '''ʼ’', '') <> '' AND NOT ukrainian_stopword(t.word)
+$$ LANGUAGE sql IMMUTABLE;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'ukrainian_stemmed') THEN
+        -- Input is already stemmed by ukrainian_stem_text(), the simple dictionary only lowercases it
+        CREATE TEXT SEARCH CONFIGURATION ukrainian_stemmed (COPY = pg_catalog.simple);
+    END IF;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION ukrainian_tsvector(doc TEXT, weight "char" DEFAULT 'D') RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector('ukrainian_stemmed', ukrainian_stem_text(doc)), weight)
+$$ LANGUAGE sql IMMUTABLE;
+
+-- All words of the search must match
+CREATE OR REPLACE FUNCTION ukrainian_tsquery(search TEXT) RETURNS tsquery AS $$
+    SELECT plainto_tsquery('ukrainian_stemmed', ukrainian_stem_text(search))
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Highlights the words of doc that start with a stem of the search. doc is HTML-escaped
+-- first, so the result is safe to render as HTML with matches wrapped in <mark>.
+CREATE OR REPLACE FUNCTION ukrainian_headline(doc TEXT, search TEXT, options TEXT DEFAULT '') RETURNS TEXT AS $$
+    SELECT ts_headline(
+        'simple',
+        replace(replace(replace(COALESCE(doc, ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;'),
+        COALESCE((
+            SELECT to_tsquery('simple', string_agg(quote_literal(ukrainian_stem(t.word)) || ':*', ' | '))
+            FROM regexp_split_to_table(lower(COALESCE(search, '')), '[^0-9a-zа-яіїєґ''ʼ’]+') AS t(word)
+            WHERE translate(t.word, '''ʼ’', '') <> '' AND NOT ukrainian_stopword(t.word)
+        ), ''::tsquery),
+        'StartSel=<mark>, StopSel=</mark>' || CASE WHEN options = '' THEN '' ELSE ', ' || options END
+    )
+$$ LANGUAGE sql STABLE;
+
+-- Appeals: the title weighs most, then the description, then the address
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION appeals_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        ukrainian_tsvector(NEW.title, 'A') ||
+        ukrainian_tsvector(NEW.description, 'B') ||
+        ukrainian_tsvector(NEW.address, 'C');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_appeals_search_vector ON appeals;
+CREATE TRIGGER trg_appeals_search_vector
+    BEFORE INSERT OR UPDATE OF title, description, address ON appeals
+    FOR EACH ROW EXECUTE FUNCTION appeals_search_vector_update();
+
+UPDATE appeals SET search_vector =
+    ukrainian_tsvector(title, 'A') || ukrainian_tsvector(description, 'B') || ukrainian_tsvector(address, 'C');
+
+CREATE INDEX IF NOT EXISTS idx_appeals_search_vector ON appeals USING GIN (search_vector);
+
+-- Comments, searched on request; internal comments are never searched
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+CREATE OR REPLACE FUNCTION comments_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector := ukrainian_tsvector(NEW.text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_comments_search_vector ON comments;
+CREATE TRIGGER trg_comments_search_vector
+    BEFORE INSERT OR UPDATE OF text ON comments
+    FOR EACH ROW EXECUTE FUNCTION comments_search_vector_update();
+
+UPDATE comments SET search_vector = ukrainian_tsvector(text);
+
+CREATE INDEX IF NOT EXISTS idx_comments_search_vector ON comments USING GIN (search_vector) WHERE NOT is_internal;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_comments_search_vector;
+DROP TRIGGER IF EXISTS trg_comments_search_vector ON comments;
+DROP FUNCTION IF EXISTS comments_search_vector_update();
+ALTER TABLE comments DROP COLUMN IF EXISTS search_vector;
+DROP INDEX IF EXISTS idx_appeals_search_vector;
+DROP TRIGGER IF EXISTS trg_appeals_search_vector ON appeals;
+DROP FUNCTION IF EXISTS appeals_search_vector_update();
+ALTER TABLE appeals DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS ukrainian_headline(TEXT, TEXT, TEXT);
+DROP FUNCTION IF EXISTS ukrainian_tsquery(TEXT);
+DROP FUNCTION IF EXISTS ukrainian_tsvector(TEXT, "char");
+DROP TEXT SEARCH CONFIGURATION IF EXISTS ukrainian_stemmed;
+DROP FUNCTION IF EXISTS ukrainian_stem_text(TEXT);
+DROP FUNCTION IF EXISTS ukrainian_stopword(TEXT);
+DROP FUNCTION IF EXISTS ukrainian_stem(TEXT);
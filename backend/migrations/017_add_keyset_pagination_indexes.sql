@@ -0,0 +1,15 @@
+-- +migrate Up
+-- Indexes matching the keyset pagination orders: each order ends with the id,
+-- so a page after a cursor is an index range scan instead of a skipped offset
+
+CREATE INDEX IF NOT EXISTS idx_appeals_created_at_id ON appeals (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_appeals_priority_created_at_id ON appeals (priority DESC, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_appeals_status_created_at_id ON appeals (status, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id_sent_at_id ON notifications (user_id, sent_at DESC, id DESC);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_notifications_user_id_sent_at_id;
+DROP INDEX IF EXISTS idx_appeals_status_created_at_id;
+DROP INDEX IF EXISTS idx_appeals_priority_created_at_id;
+DROP INDEX IF EXISTS idx_appeals_created_at_id;
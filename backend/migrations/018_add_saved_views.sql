@@ -0,0 +1,40 @@
+-- +migrate Up
+-- Saved appeal list views: a named filter combination with its sort order,
+-- private to its owner or shared with a role or the members of a service
+
+CREATE TABLE IF NOT EXISTS saved_views (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(200) NOT NULL,
+    -- Serialized AppealFilters without pagination and sorting
+    filters JSONB NOT NULL DEFAULT '{}',
+    sort_by VARCHAR(20) NOT NULL DEFAULT '',
+    sort_order VARCHAR(4) NOT NULL DEFAULT 'desc',
+    shared_role user_role,
+    shared_service_id BIGINT REFERENCES services(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_saved_views_sort_order CHECK (sort_order IN ('asc', 'desc'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_views_user_id ON saved_views (user_id);
+CREATE INDEX IF NOT EXISTS idx_saved_views_shared_role ON saved_views (shared_role) WHERE shared_role IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_saved_views_shared_service_id ON saved_views (shared_service_id) WHERE shared_service_id IS NOT NULL;
+
+-- Users notified when a new appeal matches a view
+CREATE TABLE IF NOT EXISTS saved_view_subscriptions (
+    view_id BIGINT NOT NULL REFERENCES saved_views(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (view_id, user_id)
+);
+
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'saved_view_match';
+
+-- +migrate Down
+DROP TABLE IF EXISTS saved_view_subscriptions;
+DROP INDEX IF EXISTS idx_saved_views_shared_service_id;
+DROP INDEX IF EXISTS idx_saved_views_shared_role;
+DROP INDEX IF EXISTS idx_saved_views_user_id;
+DROP TABLE IF EXISTS saved_views;
+-- Enum values cannot be removed; 'saved_view_match' stays in notification_type
@@ -0,0 +1,34 @@
+-- +migrate Up
+-- Labels on appeals beyond the category and service, e.g. "зима" or "запит депутата".
+-- Curated tags are maintained by admins; staff may also add free-form tags while tagging.
+
+CREATE TABLE IF NOT EXISTS tags (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(50) NOT NULL,
+    -- Badge color as #rrggbb, NULL for the default one
+    color VARCHAR(7),
+    is_curated BOOLEAN NOT NULL DEFAULT false,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_tags_color CHECK (color IS NULL OR color ~ '^#[0-9a-fA-F]{6}$')
+);
+
+-- Tag names are unique regardless of case, so "Зима" and "зима" are one tag
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name_lower ON tags (lower(name));
+
+CREATE TABLE IF NOT EXISTS appeal_tags (
+    appeal_id BIGINT NOT NULL REFERENCES appeals(id) ON DELETE CASCADE,
+    tag_id BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (appeal_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_appeal_tags_tag_id ON appeal_tags (tag_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_appeal_tags_tag_id;
+DROP TABLE IF EXISTS appeal_tags;
+DROP INDEX IF EXISTS idx_tags_name_lower;
+DROP TABLE IF EXISTS tags;
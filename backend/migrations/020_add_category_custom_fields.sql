@@ -0,0 +1,25 @@
+-- +migrate Up
+-- Category-specific fields of appeals, e.g. the pole number of a broken streetlight.
+-- A category defines its fields as a JSON array of {key, label, type, required, options};
+-- an appeal stores its values as a JSON object keyed by field key.
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE appeals ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}';
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chk_categories_custom_fields') THEN
+        ALTER TABLE categories ADD CONSTRAINT chk_categories_custom_fields
+            CHECK (jsonb_typeof(custom_fields) = 'array');
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chk_appeals_custom_fields') THEN
+        ALTER TABLE appeals ADD CONSTRAINT chk_appeals_custom_fields
+            CHECK (jsonb_typeof(custom_fields) = 'object');
+    END IF;
+END $$;
+
+-- +migrate Down
+ALTER TABLE appeals DROP CONSTRAINT IF EXISTS chk_appeals_custom_fields;
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS chk_categories_custom_fields;
+ALTER TABLE appeals DROP COLUMN IF EXISTS custom_fields;
+ALTER TABLE categories DROP COLUMN IF EXISTS custom_fields;
@@ -0,0 +1,55 @@
+-- +migrate Up
+-- Subcategories: a category may belong to a top-level parent category.
+-- A subcategory without its own default priority (NULL) takes the parent's.
+
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id BIGINT REFERENCES categories(id);
+ALTER TABLE categories ALTER COLUMN default_priority DROP NOT NULL;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chk_categories_parent_id') THEN
+        ALTER TABLE categories ADD CONSTRAINT chk_categories_parent_id
+            CHECK (parent_id IS DISTINCT FROM id);
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chk_categories_default_priority') THEN
+        ALTER TABLE categories ADD CONSTRAINT chk_categories_default_priority
+            CHECK (parent_id IS NOT NULL OR default_priority IS NOT NULL);
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+
+-- Split the broadest seeded category
+INSERT INTO categories (name, description, default_priority, parent_id)
+SELECT sub.name, sub.description, NULL, parent.id
+FROM categories parent
+CROSS JOIN (VALUES
+    ('Дороги та тротуари', 'Ями, пошкоджене покриття доріг і тротуарів, бордюри'),
+    ('Прибирання та вивезення сміття', 'Вивезення ТПВ, переповнені контейнери, стихійні звалища'),
+    ('Прибирання снігу', 'Неприбрані від снігу та ожеледі дороги, тротуари, зупинки'),
+    ('Озеленення та парки', 'Аварійні дерева, газони, клумби, парки та сквери'),
+    ('Кладовища', 'Утримання кладовищ, огорожі, доступ')
+) AS sub(name, description)
+WHERE parent.name = 'Благоустрій та Довкілля'
+ON CONFLICT (name) DO NOTHING;
+
+-- +migrate Down
+UPDATE appeals a
+SET category_id = c.parent_id
+FROM categories c
+WHERE a.category_id = c.id
+  AND c.parent_id IS NOT NULL
+  AND c.name IN ('Дороги та тротуари', 'Прибирання та вивезення сміття', 'Прибирання снігу', 'Озеленення та парки', 'Кладовища');
+DELETE FROM categories
+WHERE parent_id IS NOT NULL
+  AND name IN ('Дороги та тротуари', 'Прибирання та вивезення сміття', 'Прибирання снігу', 'Озеленення та парки', 'Кладовища');
+
+UPDATE categories c
+SET default_priority = p.default_priority
+FROM categories p
+WHERE c.parent_id = p.id AND c.default_priority IS NULL;
+
+DROP INDEX IF EXISTS idx_categories_parent_id;
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS chk_categories_default_priority;
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS chk_categories_parent_id;
+ALTER TABLE categories ALTER COLUMN default_priority SET NOT NULL;
+ALTER TABLE categories DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,13 @@
+-- +migrate Up
+-- Staged photos: uploaded before their appeal or comment exists and owned by the uploader
+-- until a create call claims them. Unclaimed ones are removed by a background job.
+
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS uploaded_by BIGINT REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_photos_staged ON photos(uploaded_at)
+    WHERE appeal_id IS NULL AND comment_id IS NULL;
+
+-- +migrate Down
+DELETE FROM photos WHERE appeal_id IS NULL AND comment_id IS NULL;
+DROP INDEX IF EXISTS idx_photos_staged;
+ALTER TABLE photos DROP COLUMN IF EXISTS uploaded_by;
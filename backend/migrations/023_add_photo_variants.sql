@@ -0,0 +1,14 @@
+-- +migrate Up
+-- Processed photos: dimensions of the upright image and its downscaled variants.
+-- Photos uploaded before processing, and WebP photos, have none.
+
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS width INTEGER;
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS height INTEGER;
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS thumb_path VARCHAR(500);
+ALTER TABLE photos ADD COLUMN IF NOT EXISTS medium_path VARCHAR(500);
+
+-- +migrate Down
+ALTER TABLE photos DROP COLUMN IF EXISTS medium_path;
+ALTER TABLE photos DROP COLUMN IF EXISTS thumb_path;
+ALTER TABLE photos DROP COLUMN IF EXISTS height;
+ALTER TABLE photos DROP COLUMN IF EXISTS width;
@@ -0,0 +1,8 @@
+-- +migrate Up
+-- History actions name tags, categories and services, which together do not fit
+-- into 100 characters.
+
+ALTER TABLE appeal_history ALTER COLUMN action TYPE TEXT;
+
+-- +migrate Down
+ALTER TABLE appeal_history ALTER COLUMN action TYPE VARCHAR(100) USING LEFT(action, 100);
@@ -0,0 +1,177 @@
+// Package cron parses standard five-field cron expressions
+// (minute hour day-of-month month day-of-week) and finds their next run time.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidExpression is returned for expressions that cannot be parsed
+var ErrInvalidExpression = errors.New("invalid cron expression")
+
+// Schedule is a parsed cron expression. Each field is a bit set of allowed values.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// A day matches when either day field matches if both are restricted,
+	// as in classic cron
+	domAny, dowAny bool
+}
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type fieldBounds struct {
+	name     string
+	min, max int
+}
+
+var (
+	minuteBounds = fieldBounds{"minute", 0, 59}
+	hourBounds   = fieldBounds{"hour", 0, 23}
+	domBounds    = fieldBounds{"day of month", 1, 31}
+	monthBounds  = fieldBounds{"month", 1, 12}
+	// 7 is another name for Sunday
+	dowBounds = fieldBounds{"day of week", 0, 7}
+)
+
+// Parse parses an expression such as "0 8 * * 1-5" or "*/15 * * * *".
+// Fields accept *, numbers, ranges (a-b), steps (*/n, a-b/n) and comma lists;
+// the @daily-style macros are supported as well.
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := macros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidExpression, len(fields))
+	}
+
+	s := &Schedule{}
+	var err error
+	if s.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1 << 0
+	}
+	s.domAny = fields[2] == "*"
+	s.dowAny = fields[4] == "*"
+
+	return s, nil
+}
+
+func parseField(field string, b fieldBounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("%w: bad step in %s field %q", ErrInvalidExpression, b.name, part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := b.min, b.max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("%w: bad range in %s field %q", ErrInvalidExpression, b.name, part)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("%w: bad value in %s field %q", ErrInvalidExpression, b.name, part)
+			}
+			lo, hi = n, n
+			if step > 1 {
+				// "5/15" means from 5 to the end in steps of 15
+				hi = b.max
+			}
+		}
+
+		if lo < b.min || hi > b.max || lo > hi {
+			return 0, fmt.Errorf("%w: %s field %q is out of range %d-%d", ErrInvalidExpression, b.name, part, b.min, b.max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// maxSearchYears bounds the search for schedules that never match, such as 30 February
+const maxSearchYears = 5
+
+// Next returns the first time after t, at a whole minute in t's location, that matches
+// the schedule. It returns the zero time if nothing matches within five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dowMatch
+	case s.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
@@ -0,0 +1,75 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func at(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		expr string
+		from string
+		want string
+	}{
+		{"* * * * *", "2024-05-10 09:30", "2024-05-10 09:31"},
+		{"0 8 * * *", "2024-05-10 09:30", "2024-05-11 08:00"},
+		{"0 8 * * *", "2024-05-10 07:59", "2024-05-10 08:00"},
+		{"*/15 * * * *", "2024-05-10 09:31", "2024-05-10 09:45"},
+		{"0 8 * * 1-5", "2024-05-10 09:00", "2024-05-13 08:00"}, // Friday -> Monday
+		{"0 9 * * 1", "2024-05-10 09:00", "2024-05-13 09:00"},
+		{"0 9 * * 7", "2024-05-10 09:00", "2024-05-12 09:00"}, // 7 is Sunday
+		{"30 6 1 * *", "2024-05-10 09:00", "2024-06-01 06:30"},
+		{"0 0 1 1 *", "2024-05-10 09:00", "2025-01-01 00:00"},
+		{"0 12 29 2 *", "2025-03-01 00:00", "2028-02-29 12:00"},
+		{"0 8,17 * * *", "2024-05-10 09:00", "2024-05-10 17:00"},
+		{"5/20 * * * *", "2024-05-10 09:26", "2024-05-10 09:45"},
+		{"@weekly", "2024-05-10 09:00", "2024-05-12 00:00"},
+		// Both day fields restricted: either one matches
+		{"0 0 13 * 5", "2024-05-10 09:00", "2024-05-13 00:00"},
+		{"0 0 13 * 5", "2024-05-14 09:00", "2024-05-17 00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr+" from "+tt.from, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if assert.NoError(t, err) {
+				assert.Equal(t, at(tt.want), s.Next(at(tt.from)))
+			}
+		})
+	}
+}
+
+func TestNext_NeverMatches(t *testing.T) {
+	s, err := Parse("0 0 30 2 *")
+	if assert.NoError(t, err) {
+		assert.True(t, s.Next(at("2024-01-01 00:00")).IsZero())
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		_, err := Parse(expr)
+		assert.ErrorIs(t, err, ErrInvalidExpression, expr)
+	}
+}
@@ -0,0 +1,129 @@
+package geo
+
+import "math"
+
+// DBSCAN groups points that have at least minPoints neighbours (the point itself
+// included) within epsMeters, together with the points reachable from them.
+// It returns clusters as indexes into points; noise points are left out.
+func DBSCAN(points []Point, epsMeters float64, minPoints int) [][]int {
+	if len(points) == 0 || epsMeters <= 0 || minPoints < 1 {
+		return nil
+	}
+
+	grid := newPointGrid(points, epsMeters)
+
+	const (
+		unvisited = 0
+		noise     = -1
+	)
+	labels := make([]int, len(points)) // 0 = unvisited, -1 = noise, n > 0 = cluster n
+	var clusters [][]int
+
+	for i := range points {
+		if labels[i] != unvisited {
+			continue
+		}
+		neighbours := grid.within(i, epsMeters)
+		if len(neighbours) < minPoints {
+			labels[i] = noise
+			continue
+		}
+
+		clusterID := len(clusters) + 1
+		labels[i] = clusterID
+		members := []int{i}
+
+		queue := neighbours
+		for len(queue) > 0 {
+			j := queue[0]
+			queue = queue[1:]
+
+			if labels[j] == noise {
+				// A border point: reachable, but does not expand the cluster
+				labels[j] = clusterID
+				members = append(members, j)
+				continue
+			}
+			if labels[j] != unvisited {
+				continue
+			}
+			labels[j] = clusterID
+			members = append(members, j)
+
+			if more := grid.within(j, epsMeters); len(more) >= minPoints {
+				queue = append(queue, more...)
+			}
+		}
+
+		clusters = append(clusters, members)
+	}
+
+	return clusters
+}
+
+// Centroid returns the mean of the points
+func Centroid(points []Point) Point {
+	var c Point
+	if len(points) == 0 {
+		return c
+	}
+	for _, p := range points {
+		c.Lat += p.Lat
+		c.Lng += p.Lng
+	}
+	c.Lat /= float64(len(points))
+	c.Lng /= float64(len(points))
+	return c
+}
+
+// pointGrid buckets points into cells at least eps wide, so the neighbours of a
+// point are always in its own or the eight surrounding cells.
+type pointGrid struct {
+	points  []Point
+	cellLat float64
+	cellLng float64
+	cells   map[[2]int64][]int
+}
+
+func newPointGrid(points []Point, epsMeters float64) *pointGrid {
+	// Longitude degrees are shortest at the latitude farthest from the equator
+	maxAbsLat := 0.0
+	for _, p := range points {
+		maxAbsLat = math.Max(maxAbsLat, math.Abs(p.Lat))
+	}
+	box := BoundingBoxAround(Point{Lat: maxAbsLat}, epsMeters)
+
+	g := &pointGrid{
+		points:  points,
+		cellLat: toDegrees(epsMeters / EarthRadiusMeters),
+		cellLng: box.MaxLng,
+		cells:   make(map[[2]int64][]int),
+	}
+	for i, p := range points {
+		key := g.key(p)
+		g.cells[key] = append(g.cells[key], i)
+	}
+	return g
+}
+
+func (g *pointGrid) key(p Point) [2]int64 {
+	return [2]int64{int64(math.Floor(p.Lat / g.cellLat)), int64(math.Floor(p.Lng / g.cellLng))}
+}
+
+// within returns the indexes of points within eps of point i, including i itself
+func (g *pointGrid) within(i int, epsMeters float64) []int {
+	center := g.points[i]
+	key := g.key(center)
+
+	var result []int
+	for dy := int64(-1); dy <= 1; dy++ {
+		for dx := int64(-1); dx <= 1; dx++ {
+			for _, j := range g.cells[[2]int64{key[0] + dy, key[1] + dx}] {
+				if DistanceMeters(center, g.points[j]) <= epsMeters {
+					result = append(result, j)
+				}
+			}
+		}
+	}
+	return result
+}
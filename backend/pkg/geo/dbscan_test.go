@@ -0,0 +1,64 @@
+package geo
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// offset moves a point by the given meters to the north and east
+func offset(p Point, north, east float64) Point {
+	box := BoundingBoxAround(p, 1)
+	return Point{Lat: p.Lat + north*(box.MaxLat-p.Lat), Lng: p.Lng + east*(box.MaxLng-p.Lng)}
+}
+
+func TestDBSCAN(t *testing.T) {
+	crossing := Point{Lat: 50.4501, Lng: 30.5234}
+	park := Point{Lat: 50.4700, Lng: 30.5000}
+
+	points := []Point{
+		crossing,
+		offset(crossing, 20, 0),
+		offset(crossing, 0, 30),
+		offset(crossing, -25, -10),
+		park,
+		offset(park, 10, 10),
+		offset(park, -15, 5),
+		{Lat: 50.40, Lng: 30.60}, // alone
+	}
+
+	clusters := DBSCAN(points, 50, 3)
+	if !assert.Len(t, clusters, 2) {
+		return
+	}
+	for _, c := range clusters {
+		sort.Ints(c)
+	}
+	assert.Equal(t, []int{0, 1, 2, 3}, clusters[0])
+	assert.Equal(t, []int{4, 5, 6}, clusters[1])
+
+	assert.Empty(t, DBSCAN(points, 50, 5), "no location has five appeals")
+	assert.Nil(t, DBSCAN(nil, 50, 3))
+}
+
+func TestDBSCAN_Chain(t *testing.T) {
+	// Points 40 m apart form one cluster although the ends are 160 m apart
+	start := Point{Lat: 50.45, Lng: 30.52}
+	var points []Point
+	for i := 0; i < 5; i++ {
+		points = append(points, offset(start, float64(i)*40, 0))
+	}
+
+	clusters := DBSCAN(points, 50, 2)
+	if assert.Len(t, clusters, 1) {
+		assert.Len(t, clusters[0], 5)
+	}
+}
+
+func TestCentroid(t *testing.T) {
+	c := Centroid([]Point{{Lat: 50, Lng: 30}, {Lat: 51, Lng: 31}})
+	assert.InDelta(t, 50.5, c.Lat, 1e-9)
+	assert.InDelta(t, 30.5, c.Lng, 1e-9)
+	assert.Equal(t, Point{}, Centroid(nil))
+}
@@ -0,0 +1,124 @@
+package geo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EarthRadiusMeters is the mean Earth radius used for distance calculations
+const EarthRadiusMeters = 6371000.0
+
+// Point is a geographic coordinate in degrees
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// BoundingBox is a rectangle in degrees
+type BoundingBox struct {
+	MinLat float64 `json:"min_lat"`
+	MinLng float64 `json:"min_lng"`
+	MaxLat float64 `json:"max_lat"`
+	MaxLng float64 `json:"max_lng"`
+}
+
+// ErrInvalidCoordinates is returned when a point or box cannot be parsed
+var ErrInvalidCoordinates = errors.New("invalid coordinates")
+
+// ParsePoint parses "lat,lng"
+func ParsePoint(s string) (Point, error) {
+	values, err := parseFloats(s, 2)
+	if err != nil {
+		return Point{}, err
+	}
+	p := Point{Lat: values[0], Lng: values[1]}
+	if !validLatLng(p.Lat, p.Lng) {
+		return Point{}, fmt.Errorf("%w: %q is out of range", ErrInvalidCoordinates, s)
+	}
+	return p, nil
+}
+
+// ParseBoundingBox parses "minLng,minLat,maxLng,maxLat", the GeoJSON bbox order
+func ParseBoundingBox(s string) (BoundingBox, error) {
+	values, err := parseFloats(s, 4)
+	if err != nil {
+		return BoundingBox{}, err
+	}
+	box := BoundingBox{MinLng: values[0], MinLat: values[1], MaxLng: values[2], MaxLat: values[3]}
+	if !validLatLng(box.MinLat, box.MinLng) || !validLatLng(box.MaxLat, box.MaxLng) {
+		return BoundingBox{}, fmt.Errorf("%w: %q is out of range", ErrInvalidCoordinates, s)
+	}
+	if box.MinLat > box.MaxLat || box.MinLng > box.MaxLng {
+		return BoundingBox{}, fmt.Errorf("%w: minimum is greater than maximum in %q", ErrInvalidCoordinates, s)
+	}
+	return box, nil
+}
+
+func parseFloats(s string, n int) ([]float64, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != n {
+		return nil, fmt.Errorf("%w: expected %d comma-separated numbers, got %q", ErrInvalidCoordinates, n, s)
+	}
+	values := make([]float64, n)
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("%w: %q is not a number", ErrInvalidCoordinates, part)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func validLatLng(lat, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
+
+// DistanceMeters returns the great-circle distance between two points (haversine formula)
+func DistanceMeters(a, b Point) float64 {
+	lat1 := toRadians(a.Lat)
+	lat2 := toRadians(b.Lat)
+	dLat := toRadians(b.Lat - a.Lat)
+	dLng := toRadians(b.Lng - a.Lng)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * EarthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// BoundingBoxAround returns a box that contains every point within radiusMeters of center.
+// It is meant for a cheap index-friendly prefilter; use DistanceMeters for the exact check.
+func BoundingBoxAround(center Point, radiusMeters float64) BoundingBox {
+	dLat := toDegrees(radiusMeters / EarthRadiusMeters)
+
+	// Longitude degrees shrink towards the poles
+	cosLat := math.Cos(toRadians(center.Lat))
+	dLng := 180.0
+	if cosLat > 1e-9 {
+		dLng = math.Min(180, toDegrees(radiusMeters/(EarthRadiusMeters*cosLat)))
+	}
+
+	return BoundingBox{
+		MinLat: math.Max(-90, center.Lat-dLat),
+		MaxLat: math.Min(90, center.Lat+dLat),
+		MinLng: math.Max(-180, center.Lng-dLng),
+		MaxLng: math.Min(180, center.Lng+dLng),
+	}
+}
+
+// Contains reports whether p lies inside the box (edges included)
+func (b BoundingBox) Contains(p Point) bool {
+	return p.Lat >= b.MinLat && p.Lat <= b.MaxLat && p.Lng >= b.MinLng && p.Lng <= b.MaxLng
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
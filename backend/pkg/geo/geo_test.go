@@ -0,0 +1,51 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistanceMeters(t *testing.T) {
+	maidan := Point{Lat: 50.4501, Lng: 30.5234}
+	lavra := Point{Lat: 50.4344, Lng: 30.5571}
+
+	assert.Equal(t, 0.0, DistanceMeters(maidan, maidan))
+	assert.InDelta(t, 2900, DistanceMeters(maidan, lavra), 100)
+	assert.InDelta(t, DistanceMeters(maidan, lavra), DistanceMeters(lavra, maidan), 1e-6)
+}
+
+func TestBoundingBoxAround_ContainsRadius(t *testing.T) {
+	center := Point{Lat: 50.4501, Lng: 30.5234}
+	box := BoundingBoxAround(center, 100)
+
+	assert.True(t, box.Contains(center))
+	// Points exactly 100m north and east must be inside the box
+	north := Point{Lat: box.MaxLat, Lng: center.Lng}
+	east := Point{Lat: center.Lat, Lng: box.MaxLng}
+	assert.InDelta(t, 100, DistanceMeters(center, north), 0.5)
+	assert.InDelta(t, 100, DistanceMeters(center, east), 0.5)
+	assert.False(t, box.Contains(Point{Lat: center.Lat + 0.01, Lng: center.Lng}))
+}
+
+func TestParsePoint(t *testing.T) {
+	p, err := ParsePoint("50.4501, 30.5234")
+	assert.NoError(t, err)
+	assert.Equal(t, Point{Lat: 50.4501, Lng: 30.5234}, p)
+
+	for _, input := range []string{"", "50.45", "50.45,30.52,1", "abc,30.52", "91,30", "50,181"} {
+		_, err := ParsePoint(input)
+		assert.ErrorIs(t, err, ErrInvalidCoordinates, input)
+	}
+}
+
+func TestParseBoundingBox(t *testing.T) {
+	box, err := ParseBoundingBox("30.40,50.38,30.65,50.52")
+	assert.NoError(t, err)
+	assert.Equal(t, BoundingBox{MinLat: 50.38, MinLng: 30.40, MaxLat: 50.52, MaxLng: 30.65}, box)
+
+	for _, input := range []string{"30.40,50.38,30.65", "30.65,50.38,30.40,50.52", "30.40,50.52,30.65,50.38", "30.40,-95,30.65,50.52", "a,b,c,d"} {
+		_, err := ParseBoundingBox(input)
+		assert.ErrorIs(t, err, ErrInvalidCoordinates, input)
+	}
+}
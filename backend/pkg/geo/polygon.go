@@ -0,0 +1,209 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrInvalidGeoJSON is returned when GeoJSON input is not a usable polygon area
+var ErrInvalidGeoJSON = errors.New("invalid GeoJSON")
+
+// Ring is a closed line of points; the last point may repeat the first one
+type Ring []Point
+
+// Polygon is an outer ring followed by optional holes
+type Polygon []Ring
+
+// Area is a set of polygons, the shape of a GeoJSON MultiPolygon.
+// A plain Polygon is an Area with one element.
+type Area []Polygon
+
+// Feature is a GeoJSON feature with a polygon area
+type Feature struct {
+	Properties map[string]interface{}
+	// Geometry is the feature's geometry object as it was given
+	Geometry json.RawMessage
+	Area     Area
+}
+
+type geoJSONObject struct {
+	Type        string                 `json:"type"`
+	Coordinates json.RawMessage        `json:"coordinates"`
+	Geometry    json.RawMessage        `json:"geometry"`
+	Features    []json.RawMessage      `json:"features"`
+	Properties  map[string]interface{} `json:"properties"`
+}
+
+// ParseArea parses a GeoJSON Polygon, MultiPolygon, Feature or FeatureCollection
+// into one area. A FeatureCollection is the union of its features; an area
+// without polygons is an error.
+func ParseArea(data []byte) (Area, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+	}
+
+	switch obj.Type {
+	case "Polygon", "MultiPolygon":
+		return parseGeometry(obj)
+	case "Feature":
+		return ParseArea(obj.Geometry)
+	case "FeatureCollection":
+		features, err := ParseFeatures(data)
+		if err != nil {
+			return nil, err
+		}
+		var area Area
+		for _, f := range features {
+			area = append(area, f.Area...)
+		}
+		if len(area) == 0 {
+			return nil, fmt.Errorf("%w: no polygons", ErrInvalidGeoJSON)
+		}
+		return area, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported type %q, expected Polygon or MultiPolygon", ErrInvalidGeoJSON, obj.Type)
+}
+
+// ParseFeatures parses a GeoJSON FeatureCollection (or a single Feature) whose
+// geometries are polygons.
+func ParseFeatures(data []byte) ([]Feature, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+	}
+
+	raw := []json.RawMessage{data}
+	switch obj.Type {
+	case "Feature":
+	case "FeatureCollection":
+		raw = obj.Features
+	default:
+		return nil, fmt.Errorf("%w: expected Feature or FeatureCollection, got %q", ErrInvalidGeoJSON, obj.Type)
+	}
+
+	features := make([]Feature, 0, len(raw))
+	for i, item := range raw {
+		var f geoJSONObject
+		if err := json.Unmarshal(item, &f); err != nil || f.Type != "Feature" {
+			return nil, fmt.Errorf("%w: feature %d is not a Feature", ErrInvalidGeoJSON, i)
+		}
+		area, err := ParseArea(f.Geometry)
+		if err != nil {
+			return nil, fmt.Errorf("feature %d: %w", i, err)
+		}
+		features = append(features, Feature{Properties: f.Properties, Geometry: f.Geometry, Area: area})
+	}
+	return features, nil
+}
+
+func parseGeometry(obj geoJSONObject) (Area, error) {
+	var polygons [][][][]float64
+	if obj.Type == "Polygon" {
+		var rings [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("%w: bad Polygon coordinates: %v", ErrInvalidGeoJSON, err)
+		}
+		polygons = [][][][]float64{rings}
+	} else if err := json.Unmarshal(obj.Coordinates, &polygons); err != nil {
+		return nil, fmt.Errorf("%w: bad MultiPolygon coordinates: %v", ErrInvalidGeoJSON, err)
+	}
+
+	if len(polygons) == 0 {
+		return nil, fmt.Errorf("%w: no polygons", ErrInvalidGeoJSON)
+	}
+
+	area := make(Area, 0, len(polygons))
+	for _, rings := range polygons {
+		if len(rings) == 0 {
+			return nil, fmt.Errorf("%w: polygon without rings", ErrInvalidGeoJSON)
+		}
+		polygon := make(Polygon, 0, len(rings))
+		for _, coords := range rings {
+			ring, err := parseRing(coords)
+			if err != nil {
+				return nil, err
+			}
+			polygon = append(polygon, ring)
+		}
+		area = append(area, polygon)
+	}
+	return area, nil
+}
+
+// parseRing converts GeoJSON positions ([lng, lat]) into a ring
+func parseRing(coords [][]float64) (Ring, error) {
+	ring := make(Ring, 0, len(coords))
+	for _, c := range coords {
+		if len(c) < 2 {
+			return nil, fmt.Errorf("%w: position needs longitude and latitude", ErrInvalidGeoJSON)
+		}
+		p := Point{Lat: c[1], Lng: c[0]}
+		if p.Lat < -90 || p.Lat > 90 || p.Lng < -180 || p.Lng > 180 {
+			return nil, fmt.Errorf("%w: position %v is out of range", ErrInvalidGeoJSON, c)
+		}
+		ring = append(ring, p)
+	}
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		ring = ring[:len(ring)-1]
+	}
+	if len(ring) < 3 {
+		return nil, fmt.Errorf("%w: ring needs at least 3 distinct positions", ErrInvalidGeoJSON)
+	}
+	return ring, nil
+}
+
+// Contains reports whether p lies inside the ring (even-odd rule)
+func (r Ring) Contains(p Point) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		a, b := r[i], r[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lng < (b.Lng-a.Lng)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Contains reports whether p lies inside the outer ring and outside every hole
+func (pg Polygon) Contains(p Point) bool {
+	if len(pg) == 0 || !pg[0].Contains(p) {
+		return false
+	}
+	for _, hole := range pg[1:] {
+		if hole.Contains(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains reports whether p lies inside any polygon of the area
+func (a Area) Contains(p Point) bool {
+	for _, pg := range a {
+		if pg.Contains(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Bounds returns the smallest box around the area's outer rings
+func (a Area) Bounds() BoundingBox {
+	box := BoundingBox{MinLat: math.Inf(1), MinLng: math.Inf(1), MaxLat: math.Inf(-1), MaxLng: math.Inf(-1)}
+	for _, pg := range a {
+		if len(pg) == 0 {
+			continue
+		}
+		for _, p := range pg[0] {
+			box.MinLat = math.Min(box.MinLat, p.Lat)
+			box.MinLng = math.Min(box.MinLng, p.Lng)
+			box.MaxLat = math.Max(box.MaxLat, p.Lat)
+			box.MaxLng = math.Max(box.MaxLng, p.Lng)
+		}
+	}
+	return box
+}
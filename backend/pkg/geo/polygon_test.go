@@ -0,0 +1,104 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// A square around central Kyiv with a square hole in the middle
+const squareWithHole = `{
+	"type": "Polygon",
+	"coordinates": [
+		[[30.40, 50.40], [30.60, 50.40], [30.60, 50.50], [30.40, 50.50], [30.40, 50.40]],
+		[[30.49, 50.44], [30.51, 50.44], [30.51, 50.46], [30.49, 50.46], [30.49, 50.44]]
+	]
+}`
+
+func TestParseArea_Polygon(t *testing.T) {
+	area, err := ParseArea([]byte(squareWithHole))
+	assert.NoError(t, err)
+	if assert.Len(t, area, 1) {
+		assert.Len(t, area[0], 2, "outer ring and one hole")
+		assert.Len(t, area[0][0], 4, "closing position is dropped")
+	}
+
+	assert.True(t, area.Contains(Point{Lat: 50.42, Lng: 30.45}))
+	assert.False(t, area.Contains(Point{Lat: 50.45, Lng: 30.50}), "inside the hole")
+	assert.False(t, area.Contains(Point{Lat: 50.55, Lng: 30.45}), "north of the square")
+	assert.False(t, area.Contains(Point{Lat: 50.45, Lng: 30.30}), "west of the square")
+
+	assert.Equal(t, BoundingBox{MinLat: 50.40, MinLng: 30.40, MaxLat: 50.50, MaxLng: 30.60}, area.Bounds())
+}
+
+func TestParseArea_FeatureAndMultiPolygon(t *testing.T) {
+	feature := `{
+		"type": "Feature",
+		"properties": {"name": "Два острови"},
+		"geometry": {
+			"type": "MultiPolygon",
+			"coordinates": [
+				[[[0, 0], [1, 0], [1, 1], [0, 1]]],
+				[[[5, 5], [6, 5], [6, 6], [5, 6]]]
+			]
+		}
+	}`
+
+	area, err := ParseArea([]byte(feature))
+	assert.NoError(t, err)
+	assert.Len(t, area, 2)
+	assert.True(t, area.Contains(Point{Lat: 0.5, Lng: 0.5}))
+	assert.True(t, area.Contains(Point{Lat: 5.5, Lng: 5.5}))
+	assert.False(t, area.Contains(Point{Lat: 3, Lng: 3}))
+}
+
+func TestParseArea_ConcavePolygon(t *testing.T) {
+	// A "U" shape: the notch between the arms is outside
+	u := `{"type": "Polygon", "coordinates": [[[0, 0], [3, 0], [3, 3], [2, 3], [2, 1], [1, 1], [1, 3], [0, 3], [0, 0]]]}`
+
+	area, err := ParseArea([]byte(u))
+	assert.NoError(t, err)
+	assert.True(t, area.Contains(Point{Lat: 2, Lng: 0.5}), "left arm")
+	assert.True(t, area.Contains(Point{Lat: 2, Lng: 2.5}), "right arm")
+	assert.False(t, area.Contains(Point{Lat: 2, Lng: 1.5}), "notch")
+}
+
+func TestParseFeatures(t *testing.T) {
+	collection := `{
+		"type": "FeatureCollection",
+		"features": [
+			{"type": "Feature", "properties": {"name": "Поділ"}, "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}},
+			{"type": "Feature", "properties": {"name": "Оболонь"}, "geometry": {"type": "Polygon", "coordinates": [[[2, 2], [3, 2], [3, 3], [2, 2]]]}}
+		]
+	}`
+
+	features, err := ParseFeatures([]byte(collection))
+	assert.NoError(t, err)
+	if assert.Len(t, features, 2) {
+		assert.Equal(t, "Поділ", features[0].Properties["name"])
+		assert.Equal(t, "Оболонь", features[1].Properties["name"])
+		assert.NotEmpty(t, features[1].Geometry)
+	}
+
+	area, err := ParseArea([]byte(collection))
+	assert.NoError(t, err)
+	assert.Len(t, area, 2, "a collection is the union of its features")
+}
+
+func TestParseArea_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"not json":          `{`,
+		"point":             `{"type": "Point", "coordinates": [30.5, 50.4]}`,
+		"too few positions": `{"type": "Polygon", "coordinates": [[[0, 0], [1, 1], [0, 0]]]}`,
+		"out of range":      `{"type": "Polygon", "coordinates": [[[0, 0], [200, 0], [1, 1]]]}`,
+		"no polygons":       `{"type": "MultiPolygon", "coordinates": []}`,
+		"empty collection":  `{"type": "FeatureCollection", "features": []}`,
+	}
+
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseArea([]byte(input))
+			assert.ErrorIs(t, err, ErrInvalidGeoJSON)
+		})
+	}
+}
@@ -0,0 +1,292 @@
+// Package imaging prepares uploaded photos for storage: it applies the EXIF orientation,
+// re-encodes the image without metadata and makes downscaled variants.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif" // registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+)
+
+var (
+	// ErrUnsupportedFormat is returned for image types the standard library cannot decode (WebP)
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	// ErrImageTooLarge is returned for images with more pixels than maxPixels
+	ErrImageTooLarge = errors.New("image dimensions too large")
+)
+
+const (
+	// maxPixels guards against decompression bombs: small files that decode to huge images.
+	// Processing holds the decoded image and up to two RGBA copies of it at 4 bytes per pixel,
+	// so 24 MP, enough for phone cameras, costs about 300 MB at worst.
+	maxPixels = 24_000_000
+
+	jpegQuality        = 90
+	variantJPEGQuality = 80
+)
+
+// Variant is a downscaled copy of a photo that fits into MaxSide × MaxSide
+type Variant struct {
+	Name    string
+	MaxSide int
+}
+
+var (
+	// Thumb is small enough for lists and map popups
+	Thumb = Variant{Name: "thumb", MaxSide: 320}
+	// Medium is for the appeal page and galleries
+	Medium = Variant{Name: "medium", MaxSide: 1280}
+)
+
+// Result is a processed photo
+type Result struct {
+	// Data is the photo without metadata, turned upright
+	Data []byte
+	// Format is the decoded format of Data: "jpeg", "png" or "gif", whatever the upload claimed
+	Format string
+	// Width and Height are the dimensions of the upright photo
+	Width  int
+	Height int
+	// Variants are JPEG encoded, by variant name
+	Variants map[string][]byte
+}
+
+// Process turns a JPEG, PNG or GIF photo upright according to its EXIF orientation,
+// re-encodes it in its own format, which drops EXIF (GPS position, camera) and other
+// metadata, and makes the variants. GIFs keep their original data so animations survive;
+// they carry no EXIF.
+func Process(data []byte, mimeType string, variants ...Variant) (*Result, error) {
+	switch mimeType {
+	case "image/jpeg", "image/jpg", "image/png", "image/gif":
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, mimeType)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	decoded, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	img := toRGBA(decoded)
+	if format == "jpeg" {
+		img = Orient(img, Orientation(data))
+	}
+
+	result := &Result{
+		Format:   format,
+		Width:    img.Bounds().Dx(),
+		Height:   img.Bounds().Dy(),
+		Variants: make(map[string][]byte, len(variants)),
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	case "png":
+		err = png.Encode(&buf, img)
+	case "gif":
+		_, err = buf.Write(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	result.Data = buf.Bytes()
+
+	for _, v := range variants {
+		var out bytes.Buffer
+		if err := jpeg.Encode(&out, flatten(Fit(img, v.MaxSide)), &jpeg.Options{Quality: variantJPEGQuality}); err != nil {
+			return nil, fmt.Errorf("failed to encode %s variant: %w", v.Name, err)
+		}
+		result.Variants[v.Name] = out.Bytes()
+	}
+
+	return result, nil
+}
+
+// toRGBA copies an image into an RGBA image with its origin at (0, 0)
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
+
+// flatten puts an image with transparency on a white background, since JPEG has no alpha
+func flatten(img *image.RGBA) *image.RGBA {
+	if img.Opaque() {
+		return img
+	}
+	dst := image.NewRGBA(img.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+	return dst
+}
+
+// Orient turns an image upright according to an EXIF orientation value (1–8).
+// Unknown values leave the image as it is.
+func Orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored
+				dx, dy = w-1-x, y
+			case 3: // upside down
+				dx, dy = w-1-x, h-1-y
+			case 4: // upside down, mirrored
+				dx, dy = x, h-1-y
+			case 5: // transposed
+				dx, dy = y, x
+			case 6: // turned left, rotate 90° clockwise
+				dx, dy = h-1-y, x
+			case 7: // transversed
+				dx, dy = h-1-y, w-1-x
+			case 8: // turned right, rotate 90° counter-clockwise
+				dx, dy = y, w-1-x
+			}
+			si := img.PixOffset(x, y)
+			di := dst.PixOffset(dx, dy)
+			copy(dst.Pix[di:di+4], img.Pix[si:si+4])
+		}
+	}
+	return dst
+}
+
+// Fit downscales an image to fit into maxSide × maxSide, keeping its proportions.
+// Every target pixel is the average of the source pixels it covers. Smaller images
+// are returned as they are.
+func Fit(img *image.RGBA, maxSide int) *image.RGBA {
+	w, h := img.Bounds().Dx(), img.Bounds().Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+
+	dw, dh := maxSide, maxSide
+	if w > h {
+		dh = max(1, h*maxSide/w)
+	} else {
+		dw = max(1, w*maxSide/h)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for dy := 0; dy < dh; dy++ {
+		y0, y1 := dy*h/dh, (dy+1)*h/dh
+		for dx := 0; dx < dw; dx++ {
+			x0, x1 := dx*w/dw, (dx+1)*w/dw
+
+			var sum [4]int
+			for y := y0; y < y1; y++ {
+				i := img.PixOffset(x0, y)
+				for x := x0; x < x1; x++ {
+					sum[0] += int(img.Pix[i])
+					sum[1] += int(img.Pix[i+1])
+					sum[2] += int(img.Pix[i+2])
+					sum[3] += int(img.Pix[i+3])
+					i += 4
+				}
+			}
+
+			n := (x1 - x0) * (y1 - y0)
+			di := dst.PixOffset(dx, dy)
+			for c := 0; c < 4; c++ {
+				dst.Pix[di+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return dst
+}
+
+// Orientation reads the EXIF orientation (1–8) of a JPEG; 1, upright, when there is none
+func Orientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	// Walk the segments before the image data, looking for the EXIF (APP1) one
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 { // start of scan, end of image
+			return 1
+		}
+		length := int(data[i+2])<<8 | int(data[i+3])
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation reads the orientation tag of the first IFD of a TIFF structure
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var u16 func([]byte) int
+	var u32 func([]byte) int
+	switch string(tiff[:2]) {
+	case "II":
+		u16 = func(b []byte) int { return int(b[0]) | int(b[1])<<8 }
+		u32 = func(b []byte) int { return u16(b) | u16(b[2:])<<16 }
+	case "MM":
+		u16 = func(b []byte) int { return int(b[0])<<8 | int(b[1]) }
+		u32 = func(b []byte) int { return u16(b)<<16 | u16(b[2:]) }
+	default:
+		return 1
+	}
+
+	ifd := u32(tiff[4:])
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := u16(tiff[ifd:])
+	for n := 0; n < entries; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		const orientationTag, shortType = 0x0112, 3
+		if u16(tiff[entry:]) == orientationTag && u16(tiff[entry+2:]) == shortType {
+			if v := u16(tiff[entry+8:]); v >= 1 && v <= 8 {
+				return v
+			}
+			return 1
+		}
+	}
+	return 1
+}
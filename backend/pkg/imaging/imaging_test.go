@@ -0,0 +1,173 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// withOrientation inserts an EXIF segment with the orientation and a GPS-looking marker
+// right after the start of a JPEG
+func withOrientation(t *testing.T, data []byte, orientation byte) []byte {
+	t.Helper()
+	tiff := []byte{
+		'M', 'M', 0x00, 0x2A, 0x00, 0x00, 0x00, 0x08, // big endian, IFD0 at 8
+		0x00, 0x01, // one entry
+		0x01, 0x12, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00, orientation, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, // no next IFD
+	}
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	payload = append(payload, []byte("GPS 50.4501 30.5234")...)
+	length := len(payload) + 2
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, 0xFF, 0xE1, byte(length>>8), byte(length))
+	out = append(out, payload...)
+	return append(out, data[2:]...)
+}
+
+func encodeJPEG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: 200, G: 30, B: 30, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestOrientation(t *testing.T) {
+	plain := encodeJPEG(t, 4, 2)
+	assert.Equal(t, 1, Orientation(plain))
+	assert.Equal(t, 6, Orientation(withOrientation(t, plain, 6)))
+	assert.Equal(t, 1, Orientation(withOrientation(t, plain, 9)), "out of range values are ignored")
+	assert.Equal(t, 1, Orientation([]byte("not a jpeg")))
+}
+
+func TestOrient(t *testing.T) {
+	// 2×1: red, blue
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	red, blue := color.RGBA{R: 255, A: 255}, color.RGBA{B: 255, A: 255}
+	img.Set(0, 0, red)
+	img.Set(1, 0, blue)
+
+	rotated := Orient(img, 6)
+	if assert.Equal(t, image.Rect(0, 0, 1, 2), rotated.Bounds()) {
+		assert.Equal(t, red, rotated.RGBAAt(0, 0), "the left pixel goes to the top when turned clockwise")
+		assert.Equal(t, blue, rotated.RGBAAt(0, 1))
+	}
+
+	rotated = Orient(img, 8)
+	assert.Equal(t, blue, rotated.RGBAAt(0, 0))
+
+	mirrored := Orient(img, 2)
+	assert.Equal(t, blue, mirrored.RGBAAt(0, 0))
+
+	assert.Same(t, img, Orient(img, 1))
+}
+
+func TestFit(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1000, 400))
+	assert.Equal(t, image.Rect(0, 0, 320, 128), Fit(img, 320).Bounds())
+
+	tall := image.NewRGBA(image.Rect(0, 0, 300, 900))
+	assert.Equal(t, image.Rect(0, 0, 106, 320), Fit(tall, 320).Bounds())
+
+	small := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	assert.Same(t, small, Fit(small, 320))
+}
+
+func TestProcess_JPEG(t *testing.T) {
+	data := withOrientation(t, encodeJPEG(t, 1600, 800), 6)
+
+	result, err := Process(data, "image/jpeg", Thumb, Medium)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 800, result.Width, "turned upright")
+	assert.Equal(t, 1600, result.Height)
+	assert.False(t, bytes.Contains(result.Data, []byte("Exif")), "EXIF is stripped")
+	assert.False(t, bytes.Contains(result.Data, []byte("GPS")))
+	assert.Equal(t, 1, Orientation(result.Data))
+
+	thumb, err := jpeg.DecodeConfig(bytes.NewReader(result.Variants["thumb"]))
+	if assert.NoError(t, err) {
+		assert.Equal(t, 160, thumb.Width)
+		assert.Equal(t, 320, thumb.Height)
+	}
+	medium, err := jpeg.DecodeConfig(bytes.NewReader(result.Variants["medium"]))
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1280, medium.Height)
+	}
+}
+
+func TestProcess_PNG(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 20))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Process(buf.Bytes(), "image/png", Thumb)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 40, result.Width)
+	assert.Equal(t, 20, result.Height)
+
+	_, format, err := image.DecodeConfig(bytes.NewReader(result.Data))
+	assert.NoError(t, err)
+	assert.Equal(t, "png", format, "keeps its format")
+
+	mislabeled, err := Process(buf.Bytes(), "image/jpeg")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "png", mislabeled.Format, "format comes from the data, not the declared type")
+	}
+
+	thumb, err := jpeg.Decode(bytes.NewReader(result.Variants["thumb"]))
+	if assert.NoError(t, err) {
+		r, g, b, _ := thumb.At(5, 5).RGBA()
+		assert.Greater(t, r>>8, uint32(240), "transparent pixels turn white")
+		assert.Greater(t, g>>8, uint32(240))
+		assert.Greater(t, b>>8, uint32(240))
+	}
+}
+
+// pngHeader returns the start of a PNG of the given size, enough for DecodeConfig
+func pngHeader(w, h uint32) []byte {
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], w)
+	binary.BigEndian.PutUint32(ihdr[8:], h)
+	ihdr[12] = 8 // bit depth
+	ihdr[13] = 6 // RGBA
+
+	out := []byte("\x89PNG\r\n\x1a\n")
+	out = binary.BigEndian.AppendUint32(out, 13)
+	out = append(out, ihdr...)
+	return binary.BigEndian.AppendUint32(out, crc32.ChecksumIEEE(ihdr))
+}
+
+func TestProcess_Errors(t *testing.T) {
+	_, err := Process([]byte("RIFF....WEBP"), "image/webp", Thumb)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	_, err = Process([]byte("not an image"), "image/jpeg", Thumb)
+	assert.Error(t, err)
+
+	_, err = Process(pngHeader(6000, 4001), "image/png", Thumb)
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+}
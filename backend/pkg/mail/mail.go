@@ -0,0 +1,126 @@
+// Package mail builds MIME messages and delivers them through a pluggable Sender.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"citizen-appeals/config"
+)
+
+// ErrNoRecipients is returned when a message has nobody to go to
+var ErrNoRecipients = errors.New("mail: message has no recipients")
+
+// Message is an HTML email with optional attachments
+type Message struct {
+	From        string
+	To          []string
+	Subject     string
+	HTMLBody    string
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to a message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Sender delivers messages
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// NewSender creates the sender selected by MAIL_DRIVER: smtp, file or log
+func NewSender(cfg *config.Config) (Sender, error) {
+	switch cfg.Mail.Driver {
+	case "smtp":
+		return NewSMTPSender(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From), nil
+	case "file":
+		return NewFileSender(cfg.Mail.OutboxDir, cfg.Mail.From)
+	case "", "log":
+		return NewLogSender(cfg.Mail.From), nil
+	}
+	return nil, fmt.Errorf("unknown MAIL_DRIVER %q, expected smtp, file or log", cfg.Mail.Driver)
+}
+
+// Build renders the message as MIME: a multipart/mixed body with the HTML part
+// followed by base64-encoded attachments.
+func Build(msg *Message) ([]byte, error) {
+	if len(msg.To) == 0 {
+		return nil, ErrNoRecipients
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+	header("From", msg.From)
+	header("To", strings.Join(msg.To, ", "))
+	header("Subject", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", fmt.Sprintf(`multipart/mixed; boundary="%s"`, writer.Boundary()))
+	buf.WriteString("\r\n")
+
+	htmlPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBase64(htmlPart, []byte(msg.HTMLBody)); err != nil {
+		return nil, err
+	}
+
+	for _, a := range msg.Attachments {
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(part, a.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBase64 writes data base64-encoded in lines of 76 characters, as MIME requires
+func writeBase64(w interface{ Write([]byte) (int, error) }, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := 76
+		if len(encoded) < n {
+			n = len(encoded)
+		}
+		if _, err := w.Write([]byte(encoded[:n] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
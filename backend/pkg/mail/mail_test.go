@@ -0,0 +1,90 @@
+package mail
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testMessage() *Message {
+	return &Message{
+		From:     "reports@example.com",
+		To:       []string{"mayor@example.com", "council@example.com"},
+		Subject:  "Звіт про звернення",
+		HTMLBody: "<h1>Звіт</h1>",
+		Attachments: []Attachment{
+			{Filename: "report.csv", ContentType: "text/csv; charset=utf-8", Data: []byte("a,b\n1,2\n")},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	data, err := Build(testMessage())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Звіт про звернення", subject)
+	assert.Equal(t, "mayor@example.com, council@example.com", parsed.Header.Get("To"))
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+
+	html, err := reader.NextPart()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "text/html; charset=UTF-8", html.Header.Get("Content-Type"))
+	}
+
+	attachment, err := reader.NextPart()
+	if assert.NoError(t, err) {
+		assert.Equal(t, "report.csv", attachment.FileName())
+	}
+
+	_, err = reader.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestBuild_NoRecipients(t *testing.T) {
+	msg := testMessage()
+	msg.To = nil
+	_, err := Build(msg)
+	assert.ErrorIs(t, err, ErrNoRecipients)
+}
+
+func TestFileSender(t *testing.T) {
+	dir := t.TempDir()
+	sender, err := NewFileSender(dir, "reports@example.com")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	msg := testMessage()
+	msg.From = ""
+	assert.NoError(t, sender.Send(context.Background(), msg))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	assert.NoError(t, err)
+	if assert.Len(t, files, 1) {
+		data, err := os.ReadFile(files[0])
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "From: reports@example.com")
+	}
+}
@@ -0,0 +1,119 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// SMTPSender delivers messages through an SMTP server. Authentication is used
+// when a username is set.
+type SMTPSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+}
+
+// NewSMTPSender creates a new SMTPSender instance
+func NewSMTPSender(host, port, username, password, from string) *SMTPSender {
+	return &SMTPSender{
+		addr:     host + ":" + port,
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+	}
+}
+
+// Send delivers msg. net/smtp has no context support, so ctx is only checked up front.
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if msg.From == "" {
+		msg.From = s.from
+	}
+
+	data, err := Build(msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if s.username != "" {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+	if err := smtp.SendMail(s.addr, auth, s.from, msg.To, data); err != nil {
+		return fmt.Errorf("mail: smtp delivery failed: %w", err)
+	}
+	return nil
+}
+
+// FileSender writes every message as an .eml file into a directory instead of
+// sending it. Useful in development and tests.
+type FileSender struct {
+	dir  string
+	from string
+	seq  atomic.Int64
+}
+
+// NewFileSender creates a new FileSender, creating dir if needed
+func NewFileSender(dir, from string) (*FileSender, error) {
+	if dir == "" {
+		dir = "./mail_outbox"
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create mail outbox directory: %w", err)
+	}
+	return &FileSender{dir: dir, from: from}, nil
+}
+
+// Send writes msg to <dir>/<timestamp>-<n>.eml
+func (s *FileSender) Send(ctx context.Context, msg *Message) error {
+	if msg.From == "" {
+		msg.From = s.from
+	}
+
+	data, err := Build(msg)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%d.eml", time.Now().Format("20060102-150405"), s.seq.Add(1))
+	path := filepath.Join(s.dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("mail: failed to write %s: %w", path, err)
+	}
+
+	log.Printf("Mail to %s saved to %s: %s", strings.Join(msg.To, ", "), path, msg.Subject)
+	return nil
+}
+
+// LogSender only logs who a message would go to. It is the default when no mail
+// driver is configured.
+type LogSender struct {
+	from string
+}
+
+// NewLogSender creates a new LogSender instance
+func NewLogSender(from string) *LogSender {
+	return &LogSender{from: from}
+}
+
+// Send logs msg without delivering it
+func (s *LogSender) Send(ctx context.Context, msg *Message) error {
+	if len(msg.To) == 0 {
+		return ErrNoRecipients
+	}
+
+	log.Printf("Mail (not sent, log driver) to %s: %s, %d attachments", strings.Join(msg.To, ", "), msg.Subject, len(msg.Attachments))
+	return nil
+}
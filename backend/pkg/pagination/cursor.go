@@ -0,0 +1,46 @@
+// Package pagination encodes opaque keyset cursors. A cursor holds the sort key values
+// of the row a page starts after, so pages stay stable while new rows arrive.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor is returned for cursors that cannot be decoded
+// or that belong to a list sorted another way
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is a position in a keyset-paginated list
+type Cursor struct {
+	// Order identifies the sort order the cursor was made for
+	Order string `json:"o"`
+	// Values are the sort key values of the row the page starts after, in order
+	Values []string `json:"v"`
+	// Backward pages run towards the start of the list (a "previous" cursor)
+	Backward bool `json:"b,omitempty"`
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses a cursor made by Encode and checks that it belongs to order
+// and has one value per sort key
+func Decode(s, order string, keys int) (Cursor, error) {
+	var c Cursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if c.Order != order || len(c.Values) != keys {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
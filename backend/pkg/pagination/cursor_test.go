@@ -0,0 +1,44 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := Cursor{Order: "a.priority DESC, a.id DESC", Values: []string{"3", "42"}, Backward: true}
+
+	encoded := c.Encode()
+	assert.NotContains(t, encoded, "=")
+	assert.NotContains(t, encoded, "+")
+	assert.NotContains(t, encoded, "/")
+
+	decoded, err := Decode(encoded, c.Order, 2)
+	if assert.NoError(t, err) {
+		assert.Equal(t, c, decoded)
+	}
+}
+
+func TestDecodeRejectsForeignCursors(t *testing.T) {
+	c := Cursor{Order: "a.created_at DESC, a.id DESC", Values: []string{"2024-05-01T10:00:00Z", "7"}}
+
+	tests := []struct {
+		name   string
+		cursor string
+		order  string
+		keys   int
+	}{
+		{"not base64", "%%%", c.Order, 2},
+		{"not json", "bm90IGpzb24", c.Order, 2},
+		{"other order", c.Encode(), "a.priority DESC, a.created_at DESC, a.id DESC", 3},
+		{"missing values", c.Encode(), c.Order, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.cursor, tt.order, tt.keys)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"citizen-appeals/config"
+	"citizen-appeals/pkg/imaging"
 )
 
 var (
@@ -23,23 +24,46 @@ var (
 	ErrStorageNotReady  = errors.New("storage not ready")
 )
 
-// AllowedMimeTypes for photos
+// AllowedMimeTypes for photos. Only formats the imaging package can strip of metadata
+// are accepted.
 var AllowedMimeTypes = map[string]bool{
 	"image/jpeg":      true,
 	"image/jpg":       true,
 	"image/png":       true,
-	"image/webp":      true,
 	"image/gif":       true,
 }
 
+// formatExtensions maps the formats the imaging package decodes to stored file extensions
+var formatExtensions = map[string]string{
+	"jpeg": ".jpg",
+	"png":  ".png",
+	"gif":  ".gif",
+}
+
 // Storage interface for file storage operations
 type Storage interface {
-	Save(file multipart.File, header *multipart.FileHeader, appealID int64, isResultPhoto bool) (string, string, int64, error)
+	Save(file multipart.File, header *multipart.FileHeader, appealID int64, isResultPhoto bool) (*SavedFile, error)
+	// SaveStaged saves a photo uploaded before its appeal or comment exists; the file stays
+	// where it is once the photo is attached
+	SaveStaged(file multipart.File, header *multipart.FileHeader, userID int64) (*SavedFile, error)
 	Get(filePath string) (io.ReadCloser, error)
 	Delete(filePath string) error
 	GetURL(filePath string) string
 }
 
+// SavedFile describes a saved photo. Photos are processed on save: turned upright, stripped
+// of metadata and given thumb and medium variants. Photos that cannot be processed are
+// rejected with ErrInvalidFileType. The extension and MimeType follow the decoded format.
+type SavedFile struct {
+	Path       string
+	MimeType   string
+	Size       int64
+	Width      int
+	Height     int
+	ThumbPath  string
+	MediumPath string
+}
+
 // LocalStorage implements Storage interface for local file system
 type LocalStorage struct {
 	basePath string
@@ -73,10 +97,34 @@ func NewLocalStorage(cfg *config.Config) (*LocalStorage, error) {
 }
 
 // Save saves a file to local storage
-func (s *LocalStorage) Save(file multipart.File, header *multipart.FileHeader, appealID int64, isResultPhoto bool) (string, string, int64, error) {
+func (s *LocalStorage) Save(file multipart.File, header *multipart.FileHeader, appealID int64, isResultPhoto bool) (*SavedFile, error) {
+	// Create subdirectory for appeal
+	subDir := fmt.Sprintf("appeal_%d", appealID)
+	if isResultPhoto {
+		subDir = filepath.Join(subDir, "results")
+	}
+
+	return s.save(file, header, subDir, appealID)
+}
+
+// SaveStaged saves a staged photo to the uploader's directory
+func (s *LocalStorage) SaveStaged(file multipart.File, header *multipart.FileHeader, userID int64) (*SavedFile, error) {
+	return s.save(file, header, filepath.Join("staged", fmt.Sprintf("user_%d", userID)), userID)
+}
+
+// save saves a processed photo and its variants to subDir, naming it by the owner ID and the time
+func (s *LocalStorage) save(file multipart.File, header *multipart.FileHeader, subDir string, ownerID int64) (*SavedFile, error) {
 	// Validate file size
 	if header.Size > s.maxSize {
-		return "", "", 0, ErrFileTooLarge
+		return nil, ErrFileTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, s.maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrFileTooLarge
 	}
 
 	// Determine MIME type
@@ -88,54 +136,70 @@ func (s *LocalStorage) Save(file multipart.File, header *multipart.FileHeader, a
 		if detectedType != "" {
 			mimeType = detectedType
 		} else {
-			// Try to detect from file content (read first 512 bytes)
-			buffer := make([]byte, 512)
-			n, _ := file.Read(buffer)
-			file.Seek(0, 0) // Reset file pointer
-			mimeType = http.DetectContentType(buffer[:n])
+			// Try to detect from file content
+			mimeType = http.DetectContentType(data)
 		}
 	}
 
 	// Validate MIME type
 	if !AllowedMimeTypes[mimeType] {
-		return "", "", 0, ErrInvalidFileType
+		return nil, ErrInvalidFileType
 	}
 
-	// Generate unique filename
-	ext := filepath.Ext(header.Filename)
-	timestamp := time.Now().UnixNano()
-	filename := fmt.Sprintf("%d_%d%s", appealID, timestamp, ext)
-	
-	// Create subdirectory for appeal
-	subDir := fmt.Sprintf("appeal_%d", appealID)
-	if isResultPhoto {
-		subDir = filepath.Join(subDir, "results")
+	processed, err := imaging.Process(data, mimeType, imaging.Thumb, imaging.Medium)
+	if err != nil {
+		if errors.Is(err, imaging.ErrImageTooLarge) {
+			return nil, ErrFileTooLarge
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFileType, err)
 	}
-	
+	ext, ok := formatExtensions[processed.Format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFileType, processed.Format)
+	}
+
+	data = processed.Data
+	variants := processed.Variants
+	saved := &SavedFile{
+		MimeType: "image/" + processed.Format,
+		Width:    processed.Width,
+		Height:   processed.Height,
+	}
+
+	// Generate unique filename; the extension follows the content, not the uploaded name
+	timestamp := time.Now().UnixNano()
+	base := fmt.Sprintf("%d_%d", ownerID, timestamp)
+
 	fullDir := filepath.Join(s.basePath, subDir)
 	if err := os.MkdirAll(fullDir, 0755); err != nil {
-		return "", "", 0, fmt.Errorf("failed to create directory: %w", err)
+		return nil, fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	// Full file path
-	filePath := filepath.Join(fullDir, filename)
-	relativePath := filepath.Join(subDir, filename)
-
-	// Create destination file
-	dst, err := os.Create(filePath)
-	if err != nil {
-		return "", "", 0, fmt.Errorf("failed to create file: %w", err)
+	saved.Path = filepath.Join(subDir, base+ext)
+	saved.Size = int64(len(data))
+	files := map[string][]byte{saved.Path: data}
+	if v, ok := variants[imaging.Thumb.Name]; ok {
+		saved.ThumbPath = filepath.Join(subDir, base+"_"+imaging.Thumb.Name+".jpg")
+		files[saved.ThumbPath] = v
+	}
+	if v, ok := variants[imaging.Medium.Name]; ok {
+		saved.MediumPath = filepath.Join(subDir, base+"_"+imaging.Medium.Name+".jpg")
+		files[saved.MediumPath] = v
 	}
-	defer dst.Close()
 
-	// Copy file content
-	written, err := io.Copy(dst, file)
-	if err != nil {
-		os.Remove(filePath) // Clean up on error
-		return "", "", 0, fmt.Errorf("failed to save file: %w", err)
+	written := make([]string, 0, len(files))
+	for relativePath, content := range files {
+		if err := os.WriteFile(filepath.Join(s.basePath, relativePath), content, 0644); err != nil {
+			// Clean up on error
+			for _, path := range append(written, relativePath) {
+				os.Remove(filepath.Join(s.basePath, path))
+			}
+			return nil, fmt.Errorf("failed to save file: %w", err)
+		}
+		written = append(written, relativePath)
 	}
 
-	return relativePath, mimeType, written, nil
+	return saved, nil
 }
 
 // Get retrieves a file from local storage
@@ -0,0 +1,205 @@
+// Package xlsx writes single-sheet XLSX workbooks row by row, so large tables can be
+// streamed without keeping them in memory.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrClosed is returned when writing to a closed Writer
+var ErrClosed = errors.New("xlsx: writer is closed")
+
+// Writer streams one worksheet into an XLSX file
+type Writer struct {
+	zw     *zip.Writer
+	sheet  *bufio.Writer
+	rows   int
+	closed bool
+}
+
+// NewWriter starts a workbook with one sheet. The workbook parts are written right
+// away; rows follow with WriteRow and Close finishes the file.
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	zw := zip.NewWriter(w)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", fmt.Sprintf(workbookXML, escape(sanitizeSheetName(sheetName)))},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+		{"xl/styles.xml", stylesXML},
+	}
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	if _, err := sheet.WriteString(sheetHeaderXML); err != nil {
+		return nil, err
+	}
+
+	return &Writer{zw: zw, sheet: sheet}, nil
+}
+
+// WriteHeader writes a row of bold column titles
+func (w *Writer) WriteHeader(titles []string) error {
+	values := make([]interface{}, len(titles))
+	for i, title := range titles {
+		values[i] = title
+	}
+	return w.writeRow(values, styleBold)
+}
+
+// WriteRow writes a row of cells. Integers and floats become numeric cells, nil leaves
+// the cell empty and anything else is written as text.
+func (w *Writer) WriteRow(values []interface{}) error {
+	return w.writeRow(values, styleDefault)
+}
+
+func (w *Writer) writeRow(values []interface{}, style int) error {
+	if w.closed {
+		return ErrClosed
+	}
+	w.rows++
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, w.rows)
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		ref := columnName(i) + strconv.Itoa(w.rows)
+		styleAttr := ""
+		if style != styleDefault {
+			styleAttr = fmt.Sprintf(` s="%d"`, style)
+		}
+
+		switch v := value.(type) {
+		case int:
+			fmt.Fprintf(&b, `<c r="%s"%s><v>%d</v></c>`, ref, styleAttr, v)
+		case int64:
+			fmt.Fprintf(&b, `<c r="%s"%s><v>%d</v></c>`, ref, styleAttr, v)
+		case float64:
+			fmt.Fprintf(&b, `<c r="%s"%s><v>%s</v></c>`, ref, styleAttr, strconv.FormatFloat(v, 'f', -1, 64))
+		default:
+			text, ok := value.(string)
+			if !ok {
+				text = fmt.Sprint(value)
+			}
+			fmt.Fprintf(&b, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, styleAttr, escape(text))
+		}
+	}
+	b.WriteString(`</row>`)
+
+	_, err := w.sheet.WriteString(b.String())
+	return err
+}
+
+// Close finishes the sheet and the zip archive. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if _, err := w.sheet.WriteString(sheetFooterXML); err != nil {
+		return err
+	}
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
+
+// columnName converts a zero-based column index into a column name: 0 is A, 26 is AA
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// sanitizeSheetName drops characters Excel does not allow in sheet names and
+// limits the name to 31 characters
+func sanitizeSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if strings.TrimSpace(name) == "" {
+		name = "Sheet1"
+	}
+	return name
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const (
+	styleDefault = 0
+	styleBold    = 1
+)
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const contentTypesXML = xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const rootRelsXML = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const workbookXML = xmlHeader + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const workbookRelsXML = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// stylesXML defines two cell formats: 0 is the default and 1 is bold
+const stylesXML = xmlHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`</styleSheet>`
+
+const sheetHeaderXML = xmlHeader + `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const sheetFooterXML = `</sheetData></worksheet>`
@@ -0,0 +1,72 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readPart(t *testing.T, data []byte, name string) string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if !assert.NoError(t, err) {
+			return ""
+		}
+		defer rc.Close()
+		content, err := io.ReadAll(rc)
+		assert.NoError(t, err)
+		return string(content)
+	}
+	t.Fatalf("part %s not found", name)
+	return ""
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "Звернення")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, w.WriteHeader([]string{"ID", "Назва", "Пріоритет"}))
+	assert.NoError(t, w.WriteRow([]interface{}{int64(7), "Яма <біля> школи & парку", 2.5}))
+	assert.NoError(t, w.WriteRow([]interface{}{int64(8), nil, 3}))
+	assert.NoError(t, w.Close())
+	assert.ErrorIs(t, w.WriteRow([]interface{}{"late"}), ErrClosed)
+
+	data := buf.Bytes()
+	assert.Contains(t, readPart(t, data, "xl/workbook.xml"), `<sheet name="Звернення"`)
+
+	sheet := readPart(t, data, "xl/worksheets/sheet1.xml")
+	assert.Contains(t, sheet, `<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">ID</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="A2"><v>7</v></c>`)
+	assert.Contains(t, sheet, `Яма &lt;біля&gt; школи &amp; парку`)
+	assert.Contains(t, sheet, `<c r="C2"><v>2.5</v></c>`)
+	assert.Contains(t, sheet, `<row r="3"><c r="A3"><v>8</v></c><c r="C3"><v>3</v></c></row>`, "nil leaves the cell out")
+	assert.Contains(t, sheet, `</sheetData></worksheet>`)
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+	assert.Equal(t, "BA", columnName(52))
+}
+
+func TestSanitizeSheetName(t *testing.T) {
+	assert.Equal(t, "Звіт 202405", sanitizeSheetName("Звіт 2024/05"))
+	assert.Equal(t, "ab", sanitizeSheetName("a[]:*?/\\b"))
+	assert.Equal(t, "Sheet1", sanitizeSheetName("  "))
+	assert.Len(t, []rune(sanitizeSheetName("Дуже довга назва аркуша, що не вміщується")), 31)
+}
@@ -20,6 +20,7 @@ import AdminDashboardPage from './pages/AdminDashboardPage'
 import ServiceDetailPage from './pages/ServiceDetailPage'
 import ExecutorDashboardPage from './pages/ExecutorDashboardPage'
 import ClassificationHistoryPage from './pages/ClassificationHistoryPage'
+import ReportSubscriptionsPage from './pages/ReportSubscriptionsPage'
 import Layout from './components/Layout'
 
 function App() {
@@ -62,6 +63,7 @@ function App() {
         <Route path="services/:serviceId" element={<ServiceDetailPage />} />
         <Route path="dashboard/executor" element={<ExecutorDashboardPage />} />
         <Route path="classifications/history" element={<ClassificationHistoryPage />} />
+        <Route path="reports" element={<ReportSubscriptionsPage />} />
         <Route path="*" element={<Navigate to="/" />} />
       </Route>
     </Routes>
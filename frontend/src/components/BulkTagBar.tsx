@@ -0,0 +1,84 @@
+import { useState } from 'react'
+import { useMutation, useQueryClient } from '@tanstack/react-query'
+import { Tag as TagIcon, X } from 'lucide-react'
+import { tagsAPI } from '../lib/api'
+import type { Tag } from '../types'
+
+interface BulkTagBarProps {
+  // Позначені у списку звернення
+  appealIds: number[]
+  tags: Tag[]
+  onClear: () => void
+}
+
+// Панель масової розмітки позначених звернень (диспетчер, адміністратор)
+export default function BulkTagBar({ appealIds, tags, onClear }: BulkTagBarProps) {
+  const queryClient = useQueryClient()
+  const [tagName, setTagName] = useState('')
+  const [message, setMessage] = useState('')
+
+  const mutation = useMutation({
+    mutationFn: async (action: 'tag' | 'untag') => {
+      const data = { appeal_ids: appealIds, tag_names: [tagName.trim()] }
+      return action === 'tag' ? tagsAPI.tag(data) : tagsAPI.untag(data)
+    },
+    onSuccess: (response, action) => {
+      queryClient.invalidateQueries({ queryKey: ['appeals'] })
+      queryClient.invalidateQueries({ queryKey: ['tags'] })
+      const changed = response.data?.changed ?? 0
+      setMessage(action === 'tag' ? `Додано міток: ${changed}` : `Знято міток: ${changed}`)
+      setTagName('')
+    },
+    onError: (err: any) => {
+      setMessage(err.response?.data?.error || 'Не вдалося змінити мітки')
+    },
+  })
+
+  return (
+    <div className="mb-4 bg-white border border-primary/40 rounded-lg shadow-sm p-3 flex flex-col sm:flex-row sm:items-center gap-2 sm:gap-3">
+      <div className="flex items-center space-x-2 text-sm font-medium text-gray-900 whitespace-nowrap">
+        <TagIcon className="h-4 w-4 text-primary" />
+        <span>Позначено: {appealIds.length}</span>
+      </div>
+      <input
+        type="text"
+        list="bulk-tag-names"
+        value={tagName}
+        onChange={(e) => setTagName(e.target.value)}
+        placeholder="Назва мітки, наприклад «зима»"
+        maxLength={50}
+        className="flex-1 border border-gray-300 rounded-lg px-3 py-1.5 text-sm focus:outline-none focus:ring-2 focus:ring-primary focus:border-transparent"
+      />
+      <datalist id="bulk-tag-names">
+        {tags.map((tag) => (
+          <option key={tag.id} value={tag.name} />
+        ))}
+      </datalist>
+      <button
+        onClick={() => mutation.mutate('tag')}
+        disabled={mutation.isPending || !tagName.trim()}
+        className="px-3 py-1.5 rounded-lg text-sm font-medium bg-primary text-white hover:bg-primary/90 disabled:opacity-50"
+      >
+        Додати мітку
+      </button>
+      <button
+        onClick={() => mutation.mutate('untag')}
+        disabled={mutation.isPending || !tagName.trim()}
+        className="px-3 py-1.5 rounded-lg text-sm font-medium bg-white text-gray-700 border border-gray-300 hover:bg-gray-50 disabled:opacity-50"
+      >
+        Зняти мітку
+      </button>
+      <button
+        onClick={() => {
+          setMessage('')
+          onClear()
+        }}
+        className="flex items-center space-x-1 text-xs text-gray-600 hover:text-red-600"
+      >
+        <X className="h-3 w-3" />
+        <span>Зняти позначку</span>
+      </button>
+      {message && <p className="text-sm text-gray-600 sm:self-center">{message}</p>}
+    </div>
+  )
+}
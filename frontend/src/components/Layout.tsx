@@ -2,7 +2,7 @@ import { Outlet, Link, useNavigate, useLocation } from 'react-router-dom'
 import { useAuth } from '../contexts/AuthContext'
 import { useQuery } from '@tanstack/react-query'
 import { notificationsAPI } from '../lib/api'
-import { LogOut, Plus, Home, Map, Users, BarChart3, Settings, Bell, History, ChevronDown, Key, Link2 } from 'lucide-react'
+import { LogOut, Plus, Home, Map, Users, BarChart3, Settings, Bell, History, ChevronDown, Key, Link2, Mail } from 'lucide-react'
 import { useState, useRef, useEffect } from 'react'
 
 // Dropdown Menu Component
@@ -105,7 +105,8 @@ export default function Layout() {
   // Визначаємо, чи активне адмін меню
   const isAdminMenuActive = location.pathname === '/users' || 
                             location.pathname === '/system-settings' || 
-                            location.pathname === '/classifications/history'
+                            location.pathname === '/classifications/history' ||
+                            location.pathname === '/reports'
 
   // Визначаємо, чи активне меню служб
   const isServicesMenuActive = location.pathname.startsWith('/services') || 
@@ -205,6 +206,7 @@ export default function Layout() {
                     <DropdownItem to="/users" label="Користувачі" icon={Users} />
                     <DropdownItem to="/system-settings" label="Налаштування системи" icon={Settings} />
                     <DropdownItem to="/classifications/history" label="Історія класифікацій" icon={History} />
+                    <DropdownItem to="/reports" label="Звіти на пошту" icon={Mail} />
                   </DropdownMenu>
                 )}
             </nav>
@@ -0,0 +1,218 @@
+import { useState } from 'react'
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
+import { Bookmark, BookmarkPlus, Bell, BellOff, Trash2, Users } from 'lucide-react'
+import { savedViewsAPI } from '../lib/api'
+import type { SavedView, SavedViewFilters } from '../types'
+
+const roleLabels: Record<string, string> = {
+  dispatcher: 'диспетчерам',
+  executor: 'виконавцям',
+  admin: 'адміністраторам',
+}
+
+interface SavedViewsBarProps {
+  userId: number
+  activeViewId: number | null
+  onSelect: (view: SavedView | null) => void
+  // Поточні фільтри та сортування сторінки, які можна зберегти як вигляд
+  currentFilters: SavedViewFilters
+  sortBy: string
+  sortOrder: 'asc' | 'desc'
+  services: { id: number; name: string }[]
+}
+
+export default function SavedViewsBar({
+  userId,
+  activeViewId,
+  onSelect,
+  currentFilters,
+  sortBy,
+  sortOrder,
+  services,
+}: SavedViewsBarProps) {
+  const queryClient = useQueryClient()
+  const [showForm, setShowForm] = useState(false)
+  const [name, setName] = useState('')
+  const [sharedRole, setSharedRole] = useState('')
+  const [sharedServiceId, setSharedServiceId] = useState('')
+  const [formError, setFormError] = useState('')
+
+  const { data: views } = useQuery({
+    queryKey: ['saved-views'],
+    queryFn: async () => {
+      const response = await savedViewsAPI.list()
+      return response.data || []
+    },
+    refetchInterval: 60 * 1000, // Лічильники оновлюються щохвилини
+  })
+
+  const createMutation = useMutation({
+    mutationFn: async () =>
+      savedViewsAPI.create({
+        name,
+        filters: currentFilters,
+        sort_by: sortBy,
+        sort_order: sortOrder,
+        shared_role: (sharedRole || null) as SavedView['shared_role'],
+        shared_service_id: sharedServiceId ? parseInt(sharedServiceId) : null,
+      }),
+    onSuccess: (response) => {
+      queryClient.invalidateQueries({ queryKey: ['saved-views'] })
+      setShowForm(false)
+      setName('')
+      setSharedRole('')
+      setSharedServiceId('')
+      setFormError('')
+      if (response.data) onSelect(response.data)
+    },
+    onError: (err: any) => {
+      setFormError(err.response?.data?.error || 'Не вдалося зберегти вигляд')
+    },
+  })
+
+  const subscribeMutation = useMutation({
+    mutationFn: async (view: SavedView) =>
+      view.subscribed ? savedViewsAPI.unsubscribe(view.id) : savedViewsAPI.subscribe(view.id),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['saved-views'] })
+    },
+  })
+
+  const deleteMutation = useMutation({
+    mutationFn: async (id: number) => savedViewsAPI.delete(id),
+    onSuccess: (_, id) => {
+      queryClient.invalidateQueries({ queryKey: ['saved-views'] })
+      if (id === activeViewId) onSelect(null)
+    },
+  })
+
+  return (
+    <div className="mb-4">
+      <div className="flex flex-wrap items-center gap-2">
+        <Bookmark className="h-4 w-4 text-gray-500" />
+        {views?.map((view) => (
+          <div
+            key={view.id}
+            className={`flex items-center rounded-full border text-xs sm:text-sm transition-all ${
+              view.id === activeViewId
+                ? 'bg-primary text-white border-primary shadow-md'
+                : 'bg-white text-gray-700 border-gray-300 hover:border-gray-400'
+            }`}
+          >
+            <button
+              onClick={() => onSelect(view.id === activeViewId ? null : view)}
+              className="flex items-center space-x-1.5 pl-3 pr-2 py-1"
+              title={
+                view.user_id !== userId
+                  ? `Спільний вигляд від ${view.owner?.first_name ?? ''} ${view.owner?.last_name ?? ''}`
+                  : view.shared_role
+                  ? `Доступний ${roleLabels[view.shared_role]}`
+                  : view.shared_service
+                  ? `Доступний службі «${view.shared_service.name}»`
+                  : 'Особистий вигляд'
+              }
+            >
+              {(view.user_id !== userId || view.shared_role || view.shared_service_id) && (
+                <Users className="h-3 w-3" />
+              )}
+              <span className="font-medium">{view.name}</span>
+              {view.count !== undefined && (
+                <span
+                  className={`px-1.5 rounded-full text-[10px] sm:text-xs font-bold ${
+                    view.id === activeViewId ? 'bg-white/20' : 'bg-gray-100 text-gray-700'
+                  }`}
+                >
+                  {view.count}
+                </span>
+              )}
+            </button>
+            <button
+              onClick={() => subscribeMutation.mutate(view)}
+              className="px-1.5 py-1 opacity-70 hover:opacity-100"
+              title={view.subscribed ? 'Не сповіщати про нові звернення' : 'Сповіщати про нові звернення'}
+            >
+              {view.subscribed ? <Bell className="h-3.5 w-3.5" /> : <BellOff className="h-3.5 w-3.5" />}
+            </button>
+            {view.user_id === userId && (
+              <button
+                onClick={() => {
+                  if (confirm(`Видалити вигляд «${view.name}»?`)) deleteMutation.mutate(view.id)
+                }}
+                className="pl-0.5 pr-2.5 py-1 opacity-70 hover:opacity-100"
+                title="Видалити вигляд"
+              >
+                <Trash2 className="h-3.5 w-3.5" />
+              </button>
+            )}
+          </div>
+        ))}
+        <button
+          onClick={() => setShowForm(!showForm)}
+          className="flex items-center space-x-1 px-3 py-1 rounded-full border border-dashed border-gray-300 text-xs sm:text-sm text-gray-600 hover:border-gray-400 hover:text-gray-900"
+        >
+          <BookmarkPlus className="h-3.5 w-3.5" />
+          <span>Зберегти фільтри</span>
+        </button>
+      </div>
+
+      {showForm && (
+        <form
+          onSubmit={(e) => {
+            e.preventDefault()
+            createMutation.mutate()
+          }}
+          className="mt-3 bg-white border border-gray-200 rounded-lg shadow-sm p-4 flex flex-col sm:flex-row sm:items-end gap-3"
+        >
+          <div className="flex-1">
+            <label className="block text-xs font-medium text-gray-700 mb-1">Назва вигляду</label>
+            <input
+              type="text"
+              value={name}
+              onChange={(e) => setName(e.target.value)}
+              placeholder="Наприклад, нові транспортні з високим пріоритетом"
+              required
+              maxLength={200}
+              className="w-full border border-gray-300 rounded-lg px-3 py-2 text-sm focus:outline-none focus:ring-2 focus:ring-primary focus:border-transparent"
+            />
+          </div>
+          <div>
+            <label className="block text-xs font-medium text-gray-700 mb-1">Поділитися з роллю</label>
+            <select
+              value={sharedRole}
+              onChange={(e) => setSharedRole(e.target.value)}
+              className="w-full border border-gray-300 rounded-lg px-3 py-2 text-sm bg-white"
+            >
+              <option value="">Ні з ким</option>
+              <option value="dispatcher">Диспетчери</option>
+              <option value="executor">Виконавці</option>
+              <option value="admin">Адміністратори</option>
+            </select>
+          </div>
+          <div>
+            <label className="block text-xs font-medium text-gray-700 mb-1">Поділитися зі службою</label>
+            <select
+              value={sharedServiceId}
+              onChange={(e) => setSharedServiceId(e.target.value)}
+              className="w-full border border-gray-300 rounded-lg px-3 py-2 text-sm bg-white"
+            >
+              <option value="">Ні з якою</option>
+              {services.map((service) => (
+                <option key={service.id} value={service.id}>
+                  {service.name}
+                </option>
+              ))}
+            </select>
+          </div>
+          <button
+            type="submit"
+            disabled={createMutation.isPending || !name.trim()}
+            className="px-4 py-2 rounded-lg text-sm font-medium bg-primary text-white hover:bg-primary/90 disabled:opacity-50"
+          >
+            Зберегти
+          </button>
+          {formError && <p className="text-sm text-red-600 sm:self-center">{formError}</p>}
+        </form>
+      )}
+    </div>
+  )
+}
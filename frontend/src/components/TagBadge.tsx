@@ -0,0 +1,15 @@
+import type { Tag } from '../types'
+
+// Мітка звернення; колір мітки задає рамку й крапку, без кольору — сіра
+export default function TagBadge({ tag }: { tag: Tag }) {
+  return (
+    <span
+      className="inline-flex items-center space-x-1 px-2 py-0.5 rounded-full border text-[10px] sm:text-xs font-medium text-gray-700 bg-white"
+      style={tag.color ? { borderColor: tag.color } : undefined}
+      title={tag.is_curated ? 'Кураторська мітка' : 'Довільна мітка'}
+    >
+      <span className="w-1.5 h-1.5 rounded-full bg-gray-400" style={tag.color ? { backgroundColor: tag.color } : undefined} />
+      <span>{tag.name}</span>
+    </span>
+  )
+}
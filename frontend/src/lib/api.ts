@@ -4,6 +4,9 @@ import type {
   Appeal,
   AppealsListParams,
   AppealsListResponse,
+  AppealMapFeatureCollection,
+  Hotspot,
+  VolumeAlert,
   LoginRequest,
   RegisterRequest,
   CreateAppealRequest,
@@ -11,6 +14,14 @@ import type {
   Photo,
   Category,
   Service,
+  ReportSubscription,
+  ReportSubscriptionInput,
+  ReportDelivery,
+  SavedView,
+  SavedViewInput,
+  Tag,
+  TagInput,
+  BulkTagInput,
   Notification,
 } from '../types'
 
@@ -116,6 +127,23 @@ export const appealsAPI = {
     return response.data
   },
 
+  // Ті самі фільтри, що й у list; cluster=true групує звернення в сітку для zoom
+  map: async (
+    params?: AppealsListParams & { cluster?: boolean; zoom?: number }
+  ): Promise<APIResponse<AppealMapFeatureCollection>> => {
+    const response = await api.get('/api/appeals/map', { params })
+    return response.data
+  },
+
+  // Вивантаження всіх відфільтрованих звернень (без пагінації) у CSV або XLSX
+  export: async (params: AppealsListParams, format: 'csv' | 'xlsx'): Promise<Blob> => {
+    const response = await api.get('/api/appeals/export', {
+      params: { ...params, format },
+      responseType: 'blob',
+    })
+    return response.data
+  },
+
   getById: async (id: number): Promise<APIResponse<Appeal>> => {
     const response = await api.get(`/api/appeals/${id}`)
     return response.data
@@ -140,6 +168,16 @@ export const appealsAPI = {
     return response.data
   },
 
+  confirm: async (id: number): Promise<APIResponse<{ message: string }>> => {
+    const response = await api.post(`/api/appeals/${id}/confirm`)
+    return response.data
+  },
+
+  reopen: async (id: number, comment: string): Promise<APIResponse<{ message: string }>> => {
+    const response = await api.post(`/api/appeals/${id}/reopen`, { comment })
+    return response.data
+  },
+
   updatePriority: async (
     id: number,
     priority: number
@@ -156,6 +194,16 @@ export const appealsAPI = {
     return response.data
   },
 
+  claim: async (id: number): Promise<APIResponse<Appeal>> => {
+    const response = await api.post(`/api/appeals/${id}/claim`)
+    return response.data
+  },
+
+  assignExecutor: async (id: number, executorId: number | null): Promise<APIResponse<Appeal>> => {
+    const response = await api.patch(`/api/appeals/${id}/assignee`, { executor_id: executorId })
+    return response.data
+  },
+
   getStatistics: async (params?: { from_date?: string; to_date?: string }): Promise<APIResponse<any>> => {
     const response = await api.get('/api/appeals/statistics', { params })
     return response.data
@@ -181,6 +229,16 @@ export const appealsAPI = {
     return response.data
   },
 
+  getHotspots: async (params?: { category_id?: number; window_days?: number }): Promise<APIResponse<Hotspot[]>> => {
+    const response = await api.get('/api/appeals/dashboard/admin/hotspots', { params })
+    return response.data
+  },
+
+  recomputeHotspots: async (data: { category_id?: number; window_days?: number }): Promise<APIResponse<Hotspot[]>> => {
+    const response = await api.post('/api/appeals/dashboard/admin/hotspots/recompute', data)
+    return response.data
+  },
+
   getExecutorDashboard: async (): Promise<APIResponse<any>> => {
     const response = await api.get('/api/appeals/dashboard/executor')
     return response.data
@@ -192,6 +250,125 @@ export const appealsAPI = {
   },
 }
 
+// Volume spike alerts API
+export const volumeAlertsAPI = {
+  list: async (status?: VolumeAlert['status']): Promise<APIResponse<VolumeAlert[]>> => {
+    const response = await api.get('/api/volume-alerts', { params: status ? { status } : undefined })
+    return response.data
+  },
+
+  group: async (id: number, masterAppealId?: number): Promise<APIResponse<VolumeAlert>> => {
+    const response = await api.post(`/api/volume-alerts/${id}/group`, { master_appeal_id: masterAppealId })
+    return response.data
+  },
+
+  dismiss: async (id: number): Promise<APIResponse<VolumeAlert>> => {
+    const response = await api.post(`/api/volume-alerts/${id}/dismiss`)
+    return response.data
+  },
+}
+
+// Report subscriptions API (admin)
+export const reportSubscriptionsAPI = {
+  list: async (): Promise<APIResponse<ReportSubscription[]>> => {
+    const response = await api.get('/api/report-subscriptions')
+    return response.data
+  },
+
+  create: async (data: ReportSubscriptionInput): Promise<APIResponse<ReportSubscription>> => {
+    const response = await api.post('/api/report-subscriptions', data)
+    return response.data
+  },
+
+  update: async (id: number, data: Partial<ReportSubscriptionInput>): Promise<APIResponse<ReportSubscription>> => {
+    const response = await api.put(`/api/report-subscriptions/${id}`, data)
+    return response.data
+  },
+
+  delete: async (id: number): Promise<APIResponse<void>> => {
+    const response = await api.delete(`/api/report-subscriptions/${id}`)
+    return response.data
+  },
+
+  deliveries: async (id: number): Promise<APIResponse<ReportDelivery[]>> => {
+    const response = await api.get(`/api/report-subscriptions/${id}/deliveries`)
+    return response.data
+  },
+
+  send: async (id: number): Promise<APIResponse<ReportDelivery>> => {
+    const response = await api.post(`/api/report-subscriptions/${id}/send`)
+    return response.data
+  },
+}
+
+// Збережені вигляди списку звернень (свої та спільні)
+export const savedViewsAPI = {
+  list: async (): Promise<APIResponse<SavedView[]>> => {
+    const response = await api.get('/api/saved-views')
+    return response.data
+  },
+
+  create: async (data: SavedViewInput): Promise<APIResponse<SavedView>> => {
+    const response = await api.post('/api/saved-views', data)
+    return response.data
+  },
+
+  update: async (id: number, data: SavedViewInput): Promise<APIResponse<SavedView>> => {
+    const response = await api.put(`/api/saved-views/${id}`, data)
+    return response.data
+  },
+
+  delete: async (id: number): Promise<APIResponse<void>> => {
+    const response = await api.delete(`/api/saved-views/${id}`)
+    return response.data
+  },
+
+  // Сповіщення про нові звернення, що відповідають вигляду
+  subscribe: async (id: number): Promise<APIResponse<{ subscribed: boolean }>> => {
+    const response = await api.post(`/api/saved-views/${id}/subscribe`)
+    return response.data
+  },
+
+  unsubscribe: async (id: number): Promise<APIResponse<{ subscribed: boolean }>> => {
+    const response = await api.delete(`/api/saved-views/${id}/subscribe`)
+    return response.data
+  },
+}
+
+// Мітки звернень (читання всім, кураторські мітки веде адміністратор)
+export const tagsAPI = {
+  list: async (): Promise<APIResponse<Tag[]>> => {
+    const response = await api.get('/api/tags')
+    return response.data
+  },
+
+  create: async (data: TagInput): Promise<APIResponse<Tag>> => {
+    const response = await api.post('/api/tags', data)
+    return response.data
+  },
+
+  update: async (id: number, data: TagInput): Promise<APIResponse<Tag>> => {
+    const response = await api.put(`/api/tags/${id}`, data)
+    return response.data
+  },
+
+  delete: async (id: number): Promise<APIResponse<void>> => {
+    const response = await api.delete(`/api/tags/${id}`)
+    return response.data
+  },
+
+  // Масова розмітка (диспетчер, адміністратор)
+  tag: async (data: BulkTagInput): Promise<APIResponse<{ changed: number }>> => {
+    const response = await api.post('/api/appeals/tag', data)
+    return response.data
+  },
+
+  untag: async (data: BulkTagInput): Promise<APIResponse<{ changed: number }>> => {
+    const response = await api.post('/api/appeals/untag', data)
+    return response.data
+  },
+}
+
 // Photos API
 export const photosAPI = {
   upload: async (
@@ -215,6 +392,20 @@ export const photosAPI = {
     return response.data
   },
 
+  // Завантажує фото до створення звернення чи коментаря; їхні id передаються в photo_ids
+  stage: async (files: File[]): Promise<APIResponse<Photo[]>> => {
+    const formData = new FormData()
+    files.forEach((file) => {
+      formData.append('photos', file)
+    })
+    const response = await api.post('/api/photos', formData, {
+      headers: {
+        'Content-Type': 'multipart/form-data',
+      },
+    })
+    return response.data
+  },
+
   list: async (appealId: number): Promise<APIResponse<Photo[]>> => {
     const response = await api.get(`/api/appeals/${appealId}/photos`)
     return response.data
@@ -267,6 +458,12 @@ export const categoriesAPI = {
     return response.data
   },
 
+  // Категорії верхнього рівня з підкатегоріями
+  tree: async (): Promise<APIResponse<Category[]>> => {
+    const response = await api.get('/api/categories/tree')
+    return response.data
+  },
+
   getById: async (id: number): Promise<APIResponse<Category>> => {
     const response = await api.get(`/api/categories/${id}`)
     return response.data
@@ -295,8 +492,11 @@ export const servicesAPI = {
     return response.data
   },
 
-  delete: async (id: number): Promise<APIResponse<{ message: string }>> => {
-    const response = await api.delete(`/api/services/${id}`)
+  // Відкриті звернення служби блокують видалення, якщо не вказано reassignTo — службу, куди їх перенести
+  delete: async (id: number, reassignTo?: number): Promise<APIResponse<{ message: string; reassigned: number }>> => {
+    const response = await api.delete(`/api/services/${id}`, {
+      params: reassignTo ? { reassign_to: reassignTo } : undefined,
+    })
     return response.data
   },
 }
@@ -406,6 +606,24 @@ export const systemSettingsAPI = {
     const response = await api.put('/api/system-settings', data)
     return response.data
   },
+
+  getBoundary: async (): Promise<APIResponse<Record<string, unknown>>> => {
+    const response = await api.get('/api/system-settings/boundary')
+    return response.data
+  },
+
+  // GeoJSON Polygon, MultiPolygon, Feature або FeatureCollection
+  updateBoundary: async (geojson: string): Promise<APIResponse<any>> => {
+    const response = await api.put('/api/system-settings/boundary', geojson, {
+      headers: { 'Content-Type': 'application/geo+json' },
+    })
+    return response.data
+  },
+
+  deleteBoundary: async (): Promise<APIResponse<any>> => {
+    const response = await api.delete('/api/system-settings/boundary')
+    return response.data
+  },
 }
 
 // Notifications API
@@ -1,8 +1,10 @@
-import { useQuery } from '@tanstack/react-query'
+import { useState } from 'react'
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
 import { useNavigate } from 'react-router-dom'
-import { appealsAPI } from '../lib/api'
+import { appealsAPI, categoriesAPI } from '../lib/api'
 import { useAuth } from '../contexts/AuthContext'
-import { TrendingUp, Clock, Calendar, Building2, AlertTriangle, CheckCircle } from 'lucide-react'
+import type { Hotspot } from '../types'
+import { TrendingUp, TrendingDown, Minus, MapPin, RefreshCw, Clock, Calendar, Building2, AlertTriangle, CheckCircle } from 'lucide-react'
 import {
   BarChart,
   Bar,
@@ -31,6 +33,36 @@ export default function AdminDashboardPage() {
     cacheTime: 5 * 60 * 1000, // Зберігати в кеші 5 хвилин
   })
 
+  // Осередки повторюваних звернень
+  const queryClient = useQueryClient()
+  const [hotspotCategoryId, setHotspotCategoryId] = useState<number | undefined>(undefined)
+
+  const { data: categories } = useQuery({
+    queryKey: ['categories'],
+    queryFn: async () => {
+      const response = await categoriesAPI.list()
+      return response.data || []
+    },
+    enabled: user?.role === 'admin' && !!user,
+  })
+
+  const { data: hotspots = [] } = useQuery({
+    queryKey: ['admin-hotspots', hotspotCategoryId],
+    queryFn: async () => {
+      const response = await appealsAPI.getHotspots({ category_id: hotspotCategoryId })
+      return response.data || []
+    },
+    enabled: user?.role === 'admin' && !!user,
+    staleTime: 60 * 1000,
+  })
+
+  const recomputeMutation = useMutation({
+    mutationFn: async () => appealsAPI.recomputeHotspots({ category_id: hotspotCategoryId }),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['admin-hotspots'] })
+    },
+  })
+
   if (!user || user.role !== 'admin') {
     return (
       <div className="bg-red-50 border border-red-200 text-red-700 px-4 py-3 rounded">
@@ -395,6 +427,89 @@ export default function AdminDashboardPage() {
           )}
         </div>
       </div>
+      {/* Hotspots */}
+      <div className="bg-white rounded-lg shadow-sm border border-gray-200 p-6">
+        <div className="flex items-center justify-between mb-4">
+          <h2 className="text-xl font-semibold text-gray-900 flex items-center space-x-2">
+            <MapPin className="h-5 w-5 text-primary" />
+            <span>Осередки повторюваних проблем</span>
+          </h2>
+          <div className="flex items-center space-x-2">
+            <select
+              value={hotspotCategoryId ?? ''}
+              onChange={(e) => setHotspotCategoryId(e.target.value ? Number(e.target.value) : undefined)}
+              className="border border-gray-300 rounded-md px-3 py-2 text-sm"
+            >
+              <option value="">Усі категорії</option>
+              {(categories || []).map((c: any) => (
+                <option key={c.id} value={c.id}>
+                  {c.name}
+                </option>
+              ))}
+            </select>
+            <button
+              onClick={() => recomputeMutation.mutate()}
+              disabled={recomputeMutation.isPending}
+              className="flex items-center space-x-1 px-3 py-2 text-sm bg-primary text-white rounded-md hover:bg-primary/90 disabled:opacity-50"
+            >
+              <RefreshCw className={`h-4 w-4 ${recomputeMutation.isPending ? 'animate-spin' : ''}`} />
+              <span>Перерахувати</span>
+            </button>
+          </div>
+        </div>
+        {hotspots.length === 0 ? (
+          <p className="text-gray-500 text-center py-8">Осередків не знайдено</p>
+        ) : (
+          <div className="overflow-x-auto">
+            <table className="min-w-full divide-y divide-gray-200">
+              <thead className="bg-gray-50">
+                <tr>
+                  <th className="px-4 py-3 text-left text-xs font-medium text-gray-500 uppercase">Центр</th>
+                  <th className="px-4 py-3 text-left text-xs font-medium text-gray-500 uppercase">Радіус</th>
+                  <th className="px-4 py-3 text-left text-xs font-medium text-gray-500 uppercase">Звернень</th>
+                  <th className="px-4 py-3 text-left text-xs font-medium text-gray-500 uppercase">Основна категорія</th>
+                  <th className="px-4 py-3 text-left text-xs font-medium text-gray-500 uppercase">Тренд</th>
+                </tr>
+              </thead>
+              <tbody className="bg-white divide-y divide-gray-200">
+                {hotspots.map((h: Hotspot) => (
+                  <tr key={h.id} className="hover:bg-gray-50">
+                    <td className="px-4 py-3 text-sm text-gray-900">
+                      {h.centroid_lat.toFixed(5)}, {h.centroid_lng.toFixed(5)}
+                    </td>
+                    <td className="px-4 py-3 text-sm text-gray-600">{Math.round(h.radius_meters)} м</td>
+                    <td className="px-4 py-3 text-sm font-semibold text-gray-900">{h.appeal_count}</td>
+                    <td className="px-4 py-3 text-sm text-gray-600">{h.dominant_category?.name || '—'}</td>
+                    <td className="px-4 py-3 text-sm">
+                      {h.trend === 'rising' && (
+                        <span className="flex items-center space-x-1 text-red-600">
+                          <TrendingUp className="h-4 w-4" />
+                          <span>Зростає ({h.previous_count} → {h.recent_count})</span>
+                        </span>
+                      )}
+                      {h.trend === 'falling' && (
+                        <span className="flex items-center space-x-1 text-green-600">
+                          <TrendingDown className="h-4 w-4" />
+                          <span>Спадає ({h.previous_count} → {h.recent_count})</span>
+                        </span>
+                      )}
+                      {h.trend === 'stable' && (
+                        <span className="flex items-center space-x-1 text-gray-600">
+                          <Minus className="h-4 w-4" />
+                          <span>Стабільно ({h.previous_count} → {h.recent_count})</span>
+                        </span>
+                      )}
+                    </td>
+                  </tr>
+                ))}
+              </tbody>
+            </table>
+            <p className="text-xs text-gray-400 mt-2">
+              Розраховано: {new Date(hotspots[0].computed_at).toLocaleString('uk-UA')}, вікно {hotspots[0].window_days} днів
+            </p>
+          </div>
+        )}
+      </div>
     </div>
   )
 }
@@ -114,6 +114,17 @@ export default function AnalyticsPage() {
         .slice(0, 10)
     : []
 
+  const districtData = stats?.by_district
+    ? Object.entries(stats.by_district).map(([name, count]) => ({ name, value: count }))
+    : []
+
+  const tagData = stats?.by_tag
+    ? Object.entries(stats.by_tag)
+        .map(([name, count]) => ({ name, value: count }))
+        .sort((a: any, b: any) => b.value - a.value)
+        .slice(0, 15)
+    : []
+
   const dailyTrendData = stats?.daily_trend || []
 
   const priorityData = stats?.by_priority
@@ -300,6 +311,20 @@ export default function AnalyticsPage() {
           </ResponsiveContainer>
         </div>
 
+        {/* Districts */}
+        <div className="bg-white rounded-lg shadow-sm border border-gray-200 p-6">
+          <h2 className="text-xl font-semibold text-gray-900 mb-4">Звернення по районах</h2>
+          <ResponsiveContainer width="100%" height={300}>
+            <BarChart data={districtData}>
+              <CartesianGrid strokeDasharray="3 3" />
+              <XAxis dataKey="name" angle={-45} textAnchor="end" height={100} />
+              <YAxis />
+              <Tooltip />
+              <Bar dataKey="value" fill="#8b5cf6" />
+            </BarChart>
+          </ResponsiveContainer>
+        </div>
+
         {/* Priority Distribution */}
         <div className="bg-white rounded-lg shadow-sm border border-gray-200 p-6">
           <h2 className="text-xl font-semibold text-gray-900 mb-4">Розподіл по пріоритетах</h2>
@@ -313,6 +338,22 @@ export default function AnalyticsPage() {
             </BarChart>
           </ResponsiveContainer>
         </div>
+
+        {/* Tags */}
+        {tagData.length > 0 && (
+          <div className="bg-white rounded-lg shadow-sm border border-gray-200 p-6">
+            <h2 className="text-xl font-semibold text-gray-900 mb-4">Звернення за мітками</h2>
+            <ResponsiveContainer width="100%" height={300}>
+              <BarChart data={tagData}>
+                <CartesianGrid strokeDasharray="3 3" />
+                <XAxis dataKey="name" angle={-45} textAnchor="end" height={100} />
+                <YAxis />
+                <Tooltip />
+                <Bar dataKey="value" fill="#f59e0b" />
+              </BarChart>
+            </ResponsiveContainer>
+          </div>
+        )}
       </div>
     </div>
   )
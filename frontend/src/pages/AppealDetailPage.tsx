@@ -6,6 +6,7 @@ import { uk } from 'date-fns/locale'
 import { useAuth } from '../contexts/AuthContext'
 import MapView from '../components/MapView'
 import PhotoUpload from '../components/PhotoUpload'
+import TagBadge from '../components/TagBadge'
 import { useState, useEffect } from 'react'
 import { MessageSquare, Edit, Trash2, Send, X, Upload, Image, History, ChevronDown, ChevronUp } from 'lucide-react'
 import type { Comment } from '../types'
@@ -42,6 +43,25 @@ export default function AppealDetailPage() {
     },
   })
 
+  const [reopenComment, setReopenComment] = useState('')
+
+  const confirmMutation = useMutation({
+    mutationFn: () => appealsAPI.confirm(appealId),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['appeal', appealId] })
+      queryClient.invalidateQueries({ queryKey: ['appeals'] })
+    },
+  })
+
+  const reopenMutation = useMutation({
+    mutationFn: (comment: string) => appealsAPI.reopen(appealId, comment),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['appeal', appealId] })
+      queryClient.invalidateQueries({ queryKey: ['appeals'] })
+      setReopenComment('')
+    },
+  })
+
   const priorityMutation = useMutation({
     mutationFn: (priority: number) => appealsAPI.updatePriority(appealId, priority),
     onSuccess: () => {
@@ -185,6 +205,7 @@ export default function AppealDetailPage() {
     completed: 'Виконане',
     closed: 'Закрите',
     rejected: 'Відхилене',
+    reopened: 'Повторно відкрите',
   }
 
   const statusColors: Record<string, string> = {
@@ -194,6 +215,7 @@ export default function AppealDetailPage() {
     completed: 'bg-green-100 text-green-800',
     closed: 'bg-gray-100 text-gray-800',
     rejected: 'bg-red-100 text-red-800',
+    reopened: 'bg-orange-100 text-orange-800',
   }
 
   const statusOptions = [
@@ -203,6 +225,7 @@ export default function AppealDetailPage() {
     { value: 'completed', label: 'Виконане' },
     { value: 'closed', label: 'Закрите' },
     { value: 'rejected', label: 'Відхилене' },
+    { value: 'reopened', label: 'Повторно відкрите' },
   ]
 
   if (isLoading) {
@@ -256,10 +279,88 @@ export default function AppealDetailPage() {
                 <span>{appeal.user.first_name} {appeal.user.last_name}</span>
               </div>
             )}
+            {(user?.role === 'dispatcher' || user?.role === 'admin') && appeal.duplicate_of_id && (
+              <div className="mt-2 text-xs sm:text-sm text-orange-700">
+                <span className="font-medium">Можливий дублікат:</span>{' '}
+                <Link to={`/appeals/${appeal.duplicate_of_id}`} className="underline">
+                  #{appeal.duplicate_of_id}
+                </Link>{' '}
+                <span>(схожість {Math.round((appeal.duplicate_score ?? 0) * 100)}%)</span>
+              </div>
+            )}
+            {appeal.parent_id && (
+              <div className="mt-2 text-xs sm:text-sm text-gray-600">
+                <span className="font-medium">Об'єднано з основним зверненням:</span>{' '}
+                <Link to={`/appeals/${appeal.parent_id}`} className="text-blue-600 underline">
+                  #{appeal.parent_id}
+                </Link>
+              </div>
+            )}
+            {!!appeal.linked_appeals?.length && (
+              <div className="mt-2 text-xs sm:text-sm text-gray-600">
+                <span className="font-medium">Пов'язані звернення:</span>{' '}
+                {appeal.linked_appeals.map((linked, index) => (
+                  <span key={linked.id}>
+                    {index > 0 && ', '}
+                    <Link to={`/appeals/${linked.id}`} className="text-blue-600 underline">
+                      #{linked.id}
+                    </Link>
+                  </span>
+                ))}
+              </div>
+            )}
+            {(user?.role === 'dispatcher' || user?.role === 'admin') && !!appeal.priority_rules?.length && (
+              <div className="mt-2 text-xs sm:text-sm text-gray-600">
+                <span className="font-medium">Пріоритет підвищено правилами:</span>{' '}
+                <span>{appeal.priority_rules.map((rule) => rule.name).join(', ')}</span>
+              </div>
+            )}
+            {(user?.role === 'dispatcher' || user?.role === 'admin' || user?.role === 'executor') && appeal.assignee && (
+              <div className="mt-2 text-xs sm:text-sm text-gray-600">
+                <span className="font-medium">Виконавець:</span>{' '}
+                <span>{appeal.assignee.first_name} {appeal.assignee.last_name}</span>
+              </div>
+            )}
+            {!!appeal.supporters_count && (
+              <div className="mt-2 text-xs sm:text-sm text-gray-600">
+                <span className="font-medium">Приєдналися мешканці:</span>{' '}
+                <span>{appeal.supporters_count}</span>
+              </div>
+            )}
           </div>
         </div>
         <p className="text-sm sm:text-base text-gray-700 mb-4 sm:mb-6">{appeal.description}</p>
 
+        {/* Author confirms or reopens a completed appeal */}
+        {appeal.status === 'completed' && user?.id === appeal.user_id && (
+          <div className="mb-4 sm:mb-6 p-3 sm:p-4 rounded-lg border bg-green-50 border-green-200 space-y-3">
+            <p className="text-sm text-green-800">
+              Виконавець повідомив про виконання. Підтвердіть результат або поверніть звернення на доопрацювання.
+            </p>
+            <button
+              onClick={() => confirmMutation.mutate()}
+              disabled={confirmMutation.isPending}
+              className="px-4 py-2 bg-green-600 text-white text-sm rounded-lg hover:bg-green-700 disabled:opacity-50"
+            >
+              {confirmMutation.isPending ? 'Збереження...' : 'Підтвердити виконання'}
+            </button>
+            <textarea
+              value={reopenComment}
+              onChange={(e) => setReopenComment(e.target.value)}
+              placeholder="Що не так з результатом? (мінімум 10 символів)"
+              className="w-full px-3 py-2 border border-gray-300 rounded-lg text-sm"
+              rows={2}
+            />
+            <button
+              onClick={() => reopenMutation.mutate(reopenComment)}
+              disabled={reopenMutation.isPending || reopenComment.trim().length < 10}
+              className="px-4 py-2 bg-orange-600 text-white text-sm rounded-lg hover:bg-orange-700 disabled:opacity-50"
+            >
+              {reopenMutation.isPending ? 'Збереження...' : 'Повернути на доопрацювання'}
+            </button>
+          </div>
+        )}
+
         {/* Processing time indicator - 30 days limit */}
         {appeal.status !== 'closed' && appeal.status !== 'rejected' && (() => {
           const createdDate = new Date(appeal.created_at)
@@ -543,6 +644,28 @@ export default function AppealDetailPage() {
               <p className="text-sm sm:text-base text-gray-900">{appeal.category.name}</p>
             </div>
           )}
+          {appeal.category?.custom_fields?.map((field) => {
+            const value = appeal.custom_fields?.[field.key]
+            if (value === undefined) return null
+            return (
+              <div key={field.key}>
+                <span className="text-xs sm:text-sm text-gray-500">{field.label}:</span>
+                <p className="text-sm sm:text-base text-gray-900">
+                  {typeof value === 'boolean' ? (value ? 'Так' : 'Ні') : String(value)}
+                </p>
+              </div>
+            )
+          })}
+          {appeal.tags && appeal.tags.length > 0 && (
+            <div>
+              <span className="text-xs sm:text-sm text-gray-500">Мітки:</span>
+              <div className="flex flex-wrap gap-1.5 mt-1">
+                {appeal.tags.map((tag) => (
+                  <TagBadge key={tag.id} tag={tag} />
+                ))}
+              </div>
+            </div>
+          )}
           <div className={`${!appeal.service_id ? 'border-2 border-red-300 bg-red-50 rounded-lg p-2 sm:p-3' : ''}`}>
             <div className="flex flex-col sm:flex-row sm:items-center sm:justify-between gap-2 sm:gap-0">
               <div className="flex-1">
@@ -671,14 +794,19 @@ export default function AppealDetailPage() {
           {photos && photos.length > 0 ? (
             <div className="grid grid-cols-2 md:grid-cols-4 gap-4 mb-4">
               {photos.map((photo) => {
+                const toAbsolute = (url: string) =>
+                  url.startsWith('http') ? url : `http://localhost:8080${url}`
                 const photoUrl = photo.url 
-                  ? (photo.url.startsWith('http') ? photo.url : `http://localhost:8080${photo.url}`)
+                  ? toAbsolute(photo.url)
                   : `http://localhost:8080/uploads/${photo.file_path}`
+                // У сітці показуємо мініатюру, оригінал відкривається за кліком
+                const thumbUrl = photo.thumb_url ? toAbsolute(photo.thumb_url) : photoUrl
                 
                 return (
                   <div key={photo.id} className="relative group">
                     <img
-                      src={photoUrl}
+                      src={thumbUrl}
+                      loading="lazy"
                       alt={photo.file_name}
                       className="w-full h-32 object-cover rounded-lg border border-gray-300 cursor-pointer hover:opacity-90"
                       onClick={() => window.open(photoUrl, '_blank')}
@@ -910,6 +1038,20 @@ export default function AppealDetailPage() {
                           </span>
                         </div>
                         <p className="text-gray-700 whitespace-pre-wrap">{comment.text}</p>
+                        {comment.photos && comment.photos.length > 0 && (
+                          <div className="mt-2 flex flex-wrap gap-2">
+                            {comment.photos.map((photo) => (
+                              <a key={photo.id} href={photo.url} target="_blank" rel="noopener noreferrer">
+                                <img
+                                  src={photo.thumb_url || photo.url}
+                                  loading="lazy"
+                                  alt={photo.file_name}
+                                  className="h-20 w-20 object-cover rounded border border-gray-200"
+                                />
+                              </a>
+                            ))}
+                          </div>
+                        )}
                       </div>
                       {(user?.id === comment.user_id || user?.role === 'admin' || user?.role === 'dispatcher') && (
                         <div className="flex items-center space-x-2">
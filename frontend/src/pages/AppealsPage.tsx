@@ -1,11 +1,15 @@
 import { useQuery } from '@tanstack/react-query'
 import { Link } from 'react-router-dom'
-import { appealsAPI, userServicesAPI, categoriesAPI, servicesAPI } from '../lib/api'
+import { appealsAPI, userServicesAPI, categoriesAPI, servicesAPI, tagsAPI } from '../lib/api'
 import { format, differenceInDays, addDays } from 'date-fns'
 import { uk } from 'date-fns/locale'
 import { useAuth } from '../contexts/AuthContext'
 import { useState, useEffect } from 'react'
-import { Filter, ArrowUpDown, ArrowUp, ArrowDown, X, List, Grid, Building2 } from 'lucide-react'
+import SavedViewsBar from '../components/SavedViewsBar'
+import TagBadge from '../components/TagBadge'
+import BulkTagBar from '../components/BulkTagBar'
+import type { SavedView, Tag } from '../types'
+import { Filter, ArrowUpDown, ArrowUp, ArrowDown, X, List, Grid, Building2, Download, Search, Tag as TagIcon } from 'lucide-react'
 
 const statusColors: Record<string, string> = {
   new: 'bg-blue-100 text-blue-800',
@@ -28,13 +32,20 @@ const statusLabels: Record<string, string> = {
 export default function AppealsPage() {
   const { user } = useAuth()
   const [viewMode, setViewMode] = useState<'all' | 'my'>('all')
-  const [sortBy, setSortBy] = useState<'created_at' | 'status' | 'priority'>('created_at')
+  const [sortBy, setSortBy] = useState<'created_at' | 'status' | 'priority' | 'relevance'>('created_at')
   const [sortOrder, setSortOrder] = useState<'desc' | 'asc'>('desc')
   const [selectedStatus, setSelectedStatus] = useState<string | null>(null)
   const [selectedCategory, setSelectedCategory] = useState<number | null>(null)
   const [selectedService, setSelectedService] = useState<number | null>(null)
   const [showFilters, setShowFilters] = useState(false)
   const [currentPage, setCurrentPage] = useState(1)
+  const [searchInput, setSearchInput] = useState('')
+  const [search, setSearch] = useState('')
+  const [searchComments, setSearchComments] = useState(false)
+  const [activeView, setActiveView] = useState<SavedView | null>(null)
+  const [selectedTags, setSelectedTags] = useState<number[]>([])
+  const [tagMatch, setTagMatch] = useState<'any' | 'all'>('any')
+  const [checkedAppeals, setCheckedAppeals] = useState<number[]>([])
   
   // For executor: show all appeals or only assigned ones
   const isExecutor = user?.role === 'executor'
@@ -82,8 +93,73 @@ export default function AppealsPage() {
     cacheTime: 10 * 60 * 1000, // Зберігати в кеші 10 хвилин
   })
 
+  // Мітки для фільтра й масової розмітки
+  const { data: tagsData } = useQuery({
+    queryKey: ['tags'],
+    queryFn: async () => {
+      const response = await tagsAPI.list()
+      return (response.data || []) as Tag[]
+    },
+    staleTime: 60 * 1000,
+  })
+
+  // Пошук запускається через 400 мс після останнього натискання клавіші
+  useEffect(() => {
+    const timer = setTimeout(() => setSearch(searchInput.trim()), 400)
+    return () => clearTimeout(timer)
+  }, [searchInput])
+
+  // Під час пошуку спочатку показуємо найрелевантніші (вигляд має власне сортування)
+  useEffect(() => {
+    if (activeView) return
+    if (search) {
+      setSortBy('relevance')
+    } else if (sortBy === 'relevance') {
+      setSortBy('created_at')
+    }
+  }, [search])
+
+  // Вибір збереженого вигляду переносить його фільтри на сторінку
+  const selectView = (view: SavedView | null) => {
+    setActiveView(view)
+    if (!view) return
+    setViewMode('all')
+    setSelectedStatus(view.filters.status || null)
+    setSelectedCategory(view.filters.category_id || null)
+    setSelectedService(view.filters.service_id || null)
+    setSearchInput(view.filters.search || '')
+    setSearch(view.filters.search || '')
+    setSearchComments(!!view.filters.search_comments)
+    setSelectedTags(view.filters.tags_all || view.filters.tags_any || [])
+    setTagMatch(view.filters.tags_all?.length ? 'all' : 'any')
+    setSortBy((view.sort_by || (view.filters.search ? 'relevance' : 'created_at')) as typeof sortBy)
+    setSortOrder(view.sort_order)
+  }
+
+  // Будь-яка зміна фільтрів після вибору вигляду означає, що користувач від нього відійшов
+  useEffect(() => {
+    if (!activeView) return
+    const f = activeView.filters
+    const viewSort = activeView.sort_by || (f.search ? 'relevance' : 'created_at')
+    const viewTags = f.tags_all || f.tags_any || []
+    if (
+      viewMode !== 'all' ||
+      selectedStatus !== (f.status || null) ||
+      selectedCategory !== (f.category_id || null) ||
+      selectedService !== (f.service_id || null) ||
+      search !== (f.search || '') ||
+      searchComments !== !!f.search_comments ||
+      selectedTags.join(',') !== viewTags.join(',') ||
+      (selectedTags.length > 0 && tagMatch !== (f.tags_all?.length ? 'all' : 'any')) ||
+      sortBy !== viewSort ||
+      sortOrder !== activeView.sort_order
+    ) {
+      setActiveView(null)
+    }
+  }, [viewMode, selectedStatus, selectedCategory, selectedService, search, searchComments, selectedTags, tagMatch, sortBy, sortOrder])
+
   const { data, isLoading, error } = useQuery({
-    queryKey: ['appeals', viewMode, user?.id, executorServices, sortBy, sortOrder, selectedStatus, selectedCategory, selectedService, currentPage],
+    queryKey: ['appeals', viewMode, user?.id, executorServices, sortBy, sortOrder, selectedStatus, selectedCategory, selectedService, search, searchComments, selectedTags, tagMatch, activeView?.id, currentPage],
     queryFn: async () => {
       const params: any = {
         page: currentPage,
@@ -91,6 +167,12 @@ export default function AppealsPage() {
         sort_by: sortBy,
         sort_order: sortOrder,
       }
+
+      // Збережений вигляд: фільтри й сортування бере бекенд, лічильник збігається зі списком
+      if (activeView) {
+        const response = await appealsAPI.list({ page: currentPage, limit: 20, view_id: activeView.id })
+        return response.data
+      }
       
       // Фільтрація на бекенді
       if (selectedStatus) {
@@ -102,6 +184,15 @@ export default function AppealsPage() {
       if (selectedService) {
         params.service_id = selectedService
       }
+      if (search) {
+        params.search = search
+        if (searchComments) {
+          params.search_comments = true
+        }
+      }
+      if (selectedTags.length > 0) {
+        params[tagMatch === 'all' ? 'tags_all' : 'tags_any'] = selectedTags.join(',')
+      }
       
       // If executor viewing "my" appeals, filter by service_id
       if (isExecutor && viewMode === 'my' && executorServices && executorServices.length > 0) {
@@ -128,16 +219,63 @@ export default function AppealsPage() {
   // Скидаємо сторінку при зміні фільтрів
   useEffect(() => {
     setCurrentPage(1)
-  }, [selectedStatus, selectedCategory, selectedService, viewMode])
+  }, [selectedStatus, selectedCategory, selectedService, search, searchComments, selectedTags, tagMatch, viewMode, activeView])
+
+  // Позначка для масової розмітки не переживає зміну фільтрів
+  useEffect(() => {
+    setCheckedAppeals([])
+  }, [selectedStatus, selectedCategory, selectedService, search, selectedTags, tagMatch, viewMode, activeView])
   
   // Підрахунок активних фільтрів
-  const activeFiltersCount = (selectedStatus ? 1 : 0) + (selectedCategory ? 1 : 0) + (selectedService ? 1 : 0)
+  const activeFiltersCount = (selectedStatus ? 1 : 0) + (selectedCategory ? 1 : 0) + (selectedService ? 1 : 0) + (selectedTags.length > 0 ? 1 : 0)
   
   // Скидання всіх фільтрів
   const clearFilters = () => {
     setSelectedStatus(null)
     setSelectedCategory(null)
     setSelectedService(null)
+    setSelectedTags([])
+  }
+
+  const toggleTag = (id: number) =>
+    setSelectedTags(selectedTags.includes(id) ? selectedTags.filter((t) => t !== id) : [...selectedTags, id].sort((a, b) => a - b))
+
+  const toggleChecked = (id: number) =>
+    setCheckedAppeals(checkedAppeals.includes(id) ? checkedAppeals.filter((a) => a !== id) : [...checkedAppeals, id])
+
+  // Вивантаження поточного списку з тими ж фільтрами й сортуванням
+  const [exporting, setExporting] = useState(false)
+  const handleExport = async (exportFormat: 'csv' | 'xlsx') => {
+    const params: any = { sort_by: sortBy, sort_order: sortOrder }
+    if (selectedStatus) params.status = selectedStatus
+    if (selectedCategory) params.category_id = selectedCategory
+    if (selectedService) params.service_id = selectedService
+    if (search) {
+      params.search = search
+      if (searchComments) params.search_comments = true
+    }
+    if (selectedTags.length > 0) {
+      params[tagMatch === 'all' ? 'tags_all' : 'tags_any'] = selectedTags.join(',')
+    }
+    if (activeView) {
+      params.view_id = activeView.id
+    }
+
+    setExporting(true)
+    try {
+      const blob = await appealsAPI.export(params, exportFormat)
+      const url = URL.createObjectURL(blob)
+      const link = document.createElement('a')
+      link.href = url
+      link.download = `appeals_${format(new Date(), 'yyyy-MM-dd')}.${exportFormat}`
+      link.click()
+      URL.revokeObjectURL(url)
+    } catch (err) {
+      console.error('Export failed:', err)
+      alert('Не вдалося вивантажити звернення')
+    } finally {
+      setExporting(false)
+    }
   }
 
   if (isLoading) {
@@ -162,6 +300,29 @@ export default function AppealsPage() {
         <div className="flex flex-col sm:flex-row sm:items-center sm:justify-between mb-3 sm:mb-4 gap-3 sm:gap-0">
           <h1 className="text-xl sm:text-3xl font-bold text-gray-900">Звернення громадян</h1>
           <div className="flex items-center space-x-2 sm:space-x-3">
+            {/* Вивантаження (адмін, диспетчер) */}
+            {isAdminOrDispatcher && (
+              <>
+                <button
+                  onClick={() => handleExport('csv')}
+                  disabled={exporting}
+                  className="flex items-center space-x-1 px-2 sm:px-3 py-2 rounded-lg text-sm font-medium bg-white text-gray-700 border border-gray-300 hover:bg-gray-50 disabled:opacity-50"
+                  title="Вивантажити в CSV"
+                >
+                  <Download className="h-4 w-4" />
+                  <span>CSV</span>
+                </button>
+                <button
+                  onClick={() => handleExport('xlsx')}
+                  disabled={exporting}
+                  className="flex items-center space-x-1 px-2 sm:px-3 py-2 rounded-lg text-sm font-medium bg-white text-gray-700 border border-gray-300 hover:bg-gray-50 disabled:opacity-50"
+                  title="Вивантажити в Excel"
+                >
+                  <Download className="h-4 w-4" />
+                  <span>XLSX</span>
+                </button>
+              </>
+            )}
             {/* Кнопка фільтрів */}
             <button
               onClick={() => setShowFilters(!showFilters)}
@@ -186,10 +347,11 @@ export default function AppealsPage() {
               <select
                 value={sortBy}
                 onChange={(e) =>
-                  setSortBy(e.target.value as 'created_at' | 'status' | 'priority')
+                  setSortBy(e.target.value as 'created_at' | 'status' | 'priority' | 'relevance')
                 }
                 className="border-0 bg-transparent text-xs sm:text-sm font-medium text-gray-700 focus:outline-none focus:ring-0 cursor-pointer"
               >
+                {search && <option value="relevance">Релевантність</option>}
                 <option value="created_at">Дата</option>
                 <option value="status">Статус</option>
                 <option value="priority">Пріоритет</option>
@@ -237,6 +399,50 @@ export default function AppealsPage() {
           </div>
         </div>
 
+        {/* Збережені вигляди (персонал) */}
+        {user && (isAdminOrDispatcher || isExecutor) && (
+          <SavedViewsBar
+            userId={user.id}
+            activeViewId={activeView?.id ?? null}
+            onSelect={selectView}
+            currentFilters={{
+              status: selectedStatus,
+              category_id: selectedCategory,
+              service_id: selectedService,
+              search: search || null,
+              search_comments: searchComments,
+              tags_any: tagMatch === 'any' && selectedTags.length > 0 ? selectedTags : null,
+              tags_all: tagMatch === 'all' && selectedTags.length > 0 ? selectedTags : null,
+            }}
+            sortBy={sortBy}
+            sortOrder={sortOrder}
+            services={(isAdminOrDispatcher ? servicesData : executorServicesList) || []}
+          />
+        )}
+
+        {/* Пошук */}
+        <div className="mb-4 flex flex-col sm:flex-row sm:items-center gap-2 sm:gap-4">
+          <div className="relative flex-1">
+            <Search className="absolute left-3 top-1/2 -translate-y-1/2 h-4 w-4 text-gray-400" />
+            <input
+              type="search"
+              value={searchInput}
+              onChange={(e) => setSearchInput(e.target.value)}
+              placeholder="Пошук за назвою, описом або адресою"
+              className="w-full border border-gray-300 rounded-lg pl-9 pr-3 py-2 text-sm bg-white focus:outline-none focus:ring-2 focus:ring-primary focus:border-transparent transition-all"
+            />
+          </div>
+          <label className="flex items-center space-x-2 text-sm text-gray-700 whitespace-nowrap">
+            <input
+              type="checkbox"
+              checked={searchComments}
+              onChange={(e) => setSearchComments(e.target.checked)}
+              className="rounded border-gray-300 text-primary focus:ring-primary"
+            />
+            <span>Шукати в коментарях</span>
+          </label>
+        </div>
+
         {/* Панель фільтрів */}
         {showFilters && (
           <div className="mb-4 bg-white border border-gray-200 rounded-lg shadow-sm p-4 animate-in slide-in-from-top-2 duration-200">
@@ -319,8 +525,57 @@ export default function AppealsPage() {
                 </div>
               )}
             </div>
+
+            {/* Фільтр за мітками */}
+            {tagsData && tagsData.length > 0 && (
+              <div className="mt-4">
+                <div className="flex items-center justify-between mb-2">
+                  <label className="block text-xs font-medium text-gray-700 flex items-center space-x-1">
+                    <TagIcon className="h-3 w-3" />
+                    <span>Мітки</span>
+                  </label>
+                  <div className="flex space-x-0.5 bg-gray-100 p-0.5 rounded-lg border border-gray-200 text-xs">
+                    <button
+                      onClick={() => setTagMatch('any')}
+                      className={`px-2 py-0.5 rounded-md ${tagMatch === 'any' ? 'bg-white text-gray-900 shadow-sm' : 'text-gray-600'}`}
+                      title="Звернення з будь-якою з вибраних міток"
+                    >
+                      Будь-яка
+                    </button>
+                    <button
+                      onClick={() => setTagMatch('all')}
+                      className={`px-2 py-0.5 rounded-md ${tagMatch === 'all' ? 'bg-white text-gray-900 shadow-sm' : 'text-gray-600'}`}
+                      title="Звернення з усіма вибраними мітками"
+                    >
+                      Усі
+                    </button>
+                  </div>
+                </div>
+                <div className="flex flex-wrap gap-2">
+                  {tagsData.map((tag) => (
+                    <button
+                      key={tag.id}
+                      onClick={() => toggleTag(tag.id)}
+                      className={`px-3 py-1 rounded-full text-xs font-medium border transition-all duration-200 ${
+                        selectedTags.includes(tag.id)
+                          ? 'bg-primary text-white border-primary shadow-md'
+                          : 'bg-gray-100 text-gray-700 border-transparent hover:bg-gray-200'
+                      }`}
+                    >
+                      {tag.name}
+                      <span className="ml-1 opacity-70">{tag.appeals_count}</span>
+                    </button>
+                  ))}
+                </div>
+              </div>
+            )}
           </div>
         )}
+
+        {/* Масова розмітка позначених звернень */}
+        {isAdminOrDispatcher && checkedAppeals.length > 0 && (
+          <BulkTagBar appealIds={checkedAppeals} tags={tagsData || []} onClear={() => setCheckedAppeals([])} />
+        )}
         
         {/* Show executor's services when viewing "my" appeals */}
         {isExecutor && viewMode === 'my' && executorServicesList.length > 0 && (
@@ -354,10 +609,20 @@ export default function AppealsPage() {
         <>
         <div className="space-y-3 sm:space-y-4">
           {data.items.map((appeal) => (
+            <div key={appeal.id} className="flex items-start gap-2 sm:gap-3">
+            {/* Позначка для масової розмітки (поза посиланням, щоб клік не відкривав звернення) */}
+            {isAdminOrDispatcher && (
+              <input
+                type="checkbox"
+                checked={checkedAppeals.includes(appeal.id)}
+                onChange={() => toggleChecked(appeal.id)}
+                className="mt-5 sm:mt-7 rounded border-gray-300 text-primary focus:ring-primary"
+                title="Позначити для розмітки"
+              />
+            )}
             <Link
-              key={appeal.id}
               to={`/appeals/${appeal.id}`}
-              className="block bg-white rounded-lg shadow-sm border border-gray-200 p-4 sm:p-6 hover:shadow-md transition-shadow relative"
+              className="flex-1 min-w-0 block bg-white rounded-lg shadow-sm border border-gray-200 p-4 sm:p-6 hover:shadow-md transition-shadow relative"
             >
               {/* Compact badge in top-right corner */}
               {appeal.status !== 'closed' && appeal.status !== 'rejected' && (() => {
@@ -410,7 +675,14 @@ export default function AppealsPage() {
               <div className="flex items-start justify-between">
                 <div className="flex-1 pr-12 sm:pr-8">
                   <div className="flex flex-col sm:flex-row sm:items-center gap-2 sm:gap-3 mb-2">
-                    <h3 className="text-base sm:text-lg font-semibold text-gray-900 pr-8 sm:pr-0">{appeal.title}</h3>
+                    {appeal.search_match ? (
+                      <h3
+                        className="text-base sm:text-lg font-semibold text-gray-900 pr-8 sm:pr-0 [&_mark]:bg-yellow-200"
+                        dangerouslySetInnerHTML={{ __html: appeal.search_match.title }}
+                      />
+                    ) : (
+                      <h3 className="text-base sm:text-lg font-semibold text-gray-900 pr-8 sm:pr-0">{appeal.title}</h3>
+                    )}
                     <div className="flex items-center gap-2 flex-wrap">
                       <span
                         className={`px-2 py-0.5 sm:py-1 text-[10px] sm:text-xs font-medium rounded-full ${statusColors[appeal.status] || statusColors.new}`}
@@ -426,7 +698,27 @@ export default function AppealsPage() {
                       </span>
                     </div>
                   </div>
-                  <p className="text-sm sm:text-base text-gray-600 mb-2 sm:mb-3 line-clamp-2">{appeal.description}</p>
+                  {appeal.search_match ? (
+                    <p
+                      className="text-sm sm:text-base text-gray-600 mb-2 sm:mb-3 line-clamp-2 [&_mark]:bg-yellow-200"
+                      dangerouslySetInnerHTML={{ __html: appeal.search_match.snippet }}
+                    />
+                  ) : (
+                    <p className="text-sm sm:text-base text-gray-600 mb-2 sm:mb-3 line-clamp-2">{appeal.description}</p>
+                  )}
+                  {appeal.tags && appeal.tags.length > 0 && (
+                    <div className="flex flex-wrap gap-1.5 mb-2 sm:mb-3">
+                      {appeal.tags.map((tag) => (
+                        <TagBadge key={tag.id} tag={tag} />
+                      ))}
+                    </div>
+                  )}
+                  {appeal.search_match?.comment_snippet && (
+                    <p className="text-xs sm:text-sm text-gray-500 mb-2 sm:mb-3 line-clamp-2 italic [&_mark]:bg-yellow-200">
+                      <span className="font-medium not-italic">Коментар:</span>{' '}
+                      <span dangerouslySetInnerHTML={{ __html: appeal.search_match.comment_snippet }} />
+                    </p>
+                  )}
                   <div className="flex flex-col sm:flex-row sm:items-center gap-1 sm:gap-0 sm:space-x-4 text-xs sm:text-sm text-gray-500">
                     <span className="truncate">{appeal.address}</span>
                     <span className="hidden sm:inline">
@@ -448,6 +740,7 @@ export default function AppealsPage() {
                 </div>
               </div>
             </Link>
+            </div>
           ))}
         </div>
         
@@ -104,15 +104,27 @@ export default function CategoryServicesPage() {
           const assignedServiceIds = assignedServices.map((cs: any) => cs.service_id)
           const isEditing = editingCategoryId === category.id
           const currentSelection = selectedServices[category.id] || assignedServiceIds
+          // Підкатегорія без власних служб використовує служби батьківської
+          const inherited: boolean = item.inherits_services
 
           return (
             <div key={category.id} className="bg-white rounded-lg shadow-sm border border-gray-200 p-6">
               <div className="flex items-start justify-between mb-4">
                 <div>
-                  <h3 className="text-lg font-semibold text-gray-900">{category.name}</h3>
+                  <h3 className="text-lg font-semibold text-gray-900">
+                    {category.name}
+                    {category.parent_id && (
+                      <span className="ml-2 text-xs font-normal text-gray-500">підкатегорія</span>
+                    )}
+                  </h3>
                   {category.description && (
                     <p className="text-sm text-gray-600 mt-1">{category.description}</p>
                   )}
+                  {inherited && (
+                    <p className="text-xs text-gray-500 mt-1">
+                      Служби успадковано від батьківської категорії. Збережіть власний перелік, щоб їх змінити; порожній перелік повертає успадкування.
+                    </p>
+                  )}
                 </div>
                 {!isEditing && (
                   <button
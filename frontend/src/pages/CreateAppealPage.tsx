@@ -5,6 +5,7 @@ import { appealsAPI, categoriesAPI, photosAPI } from '../lib/api'
 import { useAuth } from '../contexts/AuthContext'
 import MapPicker from '../components/MapPicker'
 import PhotoSelector from '../components/PhotoSelector'
+import type { CreateAppealRequest, CustomFieldValues } from '../types'
 
 // Reverse geocoding функція для отримання адреси з координат
 async function reverseGeocode(lat: number, lng: number): Promise<string> {
@@ -61,41 +62,57 @@ export default function CreateAppealPage() {
     longitude: 30.5234,
     priority: 2, // Середній за замовчуванням
   })
+  const [customFields, setCustomFields] = useState<CustomFieldValues>({})
   const [errors, setErrors] = useState<{ [key: string]: string }>({})
   const [selectedPhotos, setSelectedPhotos] = useState<File[]>([])
   const [isLoadingAddress, setIsLoadingAddress] = useState(false)
   const [addressManuallyEdited, setAddressManuallyEdited] = useState(false)
 
-  const { data: categories } = useQuery({
-    queryKey: ['categories'],
+  const { data: categoryTree } = useQuery({
+    queryKey: ['categories', 'tree'],
     queryFn: async () => {
-      const response = await categoriesAPI.list()
+      const response = await categoriesAPI.tree()
       return response.data || []
     },
     enabled: !!user,
   })
 
+  // Підкатегорії йдуть одразу за своєю категорією
+  const categories = categoryTree?.flatMap((cat) => [cat, ...(cat.children || [])])
+
+  // Додаткові поля обраної категорії
+  const categoryFields =
+    categories?.find((cat) => String(cat.id) === formData.category_id)?.custom_fields || []
+
+  const setCustomField = (key: string, value: string | number | boolean | undefined) => {
+    const next = { ...customFields }
+    if (value === undefined || value === '') {
+      delete next[key]
+    } else {
+      next[key] = value
+    }
+    setCustomFields(next)
+    if (errors[`cf.${key}`]) setErrors({ ...errors, [`cf.${key}`]: '' })
+  }
+
   const [createdAppealId, setCreatedAppealId] = useState<number | null>(null)
 
   const mutation = useMutation({
-    mutationFn: appealsAPI.create,
-    onSuccess: async (response) => {
+    // Фото завантажуються заздалегідь і прикріплюються до звернення під час створення
+    mutationFn: async (data: CreateAppealRequest) => {
+      let photoIds: number[] | undefined
+      if (selectedPhotos.length > 0) {
+        const staged = await photosAPI.stage(selectedPhotos)
+        photoIds = staged.data?.map((photo) => photo.id)
+      }
+      return appealsAPI.create({ ...data, photo_ids: photoIds })
+    },
+    onSuccess: (response) => {
       if (response.success && response.data) {
         setCreatedAppealId(response.data.id)
         // Інвалідуємо кеш списку звернень
         queryClient.invalidateQueries({ queryKey: ['appeals'] })
-        
-        // Автоматично завантажуємо фото, якщо вони були вибрані
-        if (selectedPhotos.length > 0) {
-          try {
-            await photosAPI.upload(response.data.id, selectedPhotos)
-            queryClient.invalidateQueries({ queryKey: ['photos', response.data.id] })
-            setSelectedPhotos([])
-          } catch (err) {
-            console.error('Failed to upload photos:', err)
-            // Не блокуємо успішне створення звернення, навіть якщо фото не завантажилися
-          }
-        }
+        queryClient.invalidateQueries({ queryKey: ['photos', response.data.id] })
       }
     },
   })
@@ -119,6 +136,13 @@ export default function CreateAppealPage() {
       newErrors.category_id = 'Категорія обов\'язкова'
     }
 
+    for (const field of categoryFields) {
+      const value = customFields[field.key]
+      if (field.required && (value === undefined || (typeof value === 'string' && !value.trim()))) {
+        newErrors[`cf.${field.key}`] = `Поле "${field.label}" обов'язкове`
+      }
+    }
+
     setErrors(newErrors)
     return Object.keys(newErrors).length === 0
   }
@@ -138,6 +162,7 @@ export default function CreateAppealPage() {
       longitude: formData.longitude,
       priority: formData.priority,
       category_id: parseInt(formData.category_id),
+      custom_fields: customFields,
     })
   }
 
@@ -206,13 +231,14 @@ export default function CreateAppealPage() {
             value={formData.category_id}
             onChange={(e) => {
               setFormData({ ...formData, category_id: e.target.value })
+              setCustomFields({}) // поля іншої категорії не підходять
               if (errors.category_id) setErrors({ ...errors, category_id: '' })
             }}
           >
             <option value="">Оберіть категорію</option>
             {categories?.map((cat) => (
               <option key={cat.id} value={cat.id}>
-                {cat.name}
+                {cat.parent_id ? `\u00A0\u00A0— ${cat.name}` : cat.name}
               </option>
             ))}
           </select>
@@ -221,6 +247,73 @@ export default function CreateAppealPage() {
           )}
         </div>
 
+        {categoryFields.map((field) => {
+          const id = `cf-${field.key}`
+          const value = customFields[field.key]
+          const error = errors[`cf.${field.key}`]
+          const inputClass = `w-full px-3 py-2 border rounded-md focus:outline-none focus:ring-primary focus:border-primary ${
+            error ? 'border-red-300' : 'border-gray-300'
+          }`
+
+          return (
+            <div key={field.key}>
+              {field.type === 'boolean' ? (
+                <label htmlFor={id} className="flex items-center gap-2 text-sm font-medium text-gray-700">
+                  <input
+                    id={id}
+                    type="checkbox"
+                    className="h-4 w-4 text-primary border-gray-300 rounded"
+                    checked={value === true}
+                    onChange={(e) => setCustomField(field.key, e.target.checked)}
+                  />
+                  {field.label}
+                </label>
+              ) : (
+                <>
+                  <label htmlFor={id} className="block text-sm font-medium text-gray-700 mb-2">
+                    {field.label}{field.required && ' *'}
+                  </label>
+                  {field.type === 'enum' ? (
+                    <select
+                      id={id}
+                      required={field.required}
+                      className={inputClass}
+                      value={typeof value === 'string' ? value : ''}
+                      onChange={(e) => setCustomField(field.key, e.target.value)}
+                    >
+                      <option value="">Оберіть значення</option>
+                      {field.options?.map((option) => (
+                        <option key={option} value={option}>
+                          {option}
+                        </option>
+                      ))}
+                    </select>
+                  ) : (
+                    <input
+                      id={id}
+                      type={field.type === 'number' ? 'number' : 'text'}
+                      step={field.type === 'number' ? 'any' : undefined}
+                      required={field.required}
+                      maxLength={field.type === 'text' ? 500 : undefined}
+                      className={inputClass}
+                      value={value === undefined ? '' : String(value)}
+                      onChange={(e) =>
+                        setCustomField(
+                          field.key,
+                          field.type === 'number' && e.target.value !== ''
+                            ? Number(e.target.value)
+                            : e.target.value
+                        )
+                      }
+                    />
+                  )}
+                </>
+              )}
+              {error && <p className="mt-1 text-sm text-red-600">{error}</p>}
+            </div>
+          )
+        })}
+
         <div>
           <label htmlFor="address" className="block text-sm font-medium text-gray-700 mb-2">
             Адреса *
@@ -348,7 +441,7 @@ export default function CreateAppealPage() {
           />
           {selectedPhotos.length > 0 && !mutation.isSuccess && (
             <p className="mt-2 text-sm text-gray-600">
-              Вибрано {selectedPhotos.length} фото. Вони будуть завантажені після створення звернення.
+              Вибрано {selectedPhotos.length} фото. Вони будуть прикріплені до звернення.
             </p>
           )}
         </div>
@@ -371,9 +464,6 @@ export default function CreateAppealPage() {
         {mutation.isSuccess && createdAppealId && (
           <div className="bg-green-50 border border-green-200 text-green-700 px-4 py-3 rounded">
             <p className="font-medium">Звернення успішно створено!</p>
-            {selectedPhotos.length > 0 && (
-              <p className="text-sm mt-1">Фотографії завантажуються...</p>
-            )}
           </div>
         )}
 
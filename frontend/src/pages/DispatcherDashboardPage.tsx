@@ -1,10 +1,10 @@
 import { useState } from 'react'
-import { useQuery } from '@tanstack/react-query'
-import { appealsAPI } from '../lib/api'
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
+import { appealsAPI, volumeAlertsAPI } from '../lib/api'
 import { useAuth } from '../contexts/AuthContext'
 import { format } from 'date-fns'
 import { uk } from 'date-fns/locale'
-import { AlertTriangle, Clock, Timer, Link as LinkIcon, ChevronDown, ChevronUp } from 'lucide-react'
+import { AlertTriangle, Clock, Timer, ShieldAlert, Link as LinkIcon, ChevronDown, ChevronUp, TrendingUp } from 'lucide-react'
 import { Link } from 'react-router-dom'
 
 export default function DispatcherDashboardPage() {
@@ -26,6 +26,27 @@ export default function DispatcherDashboardPage() {
     gcTime: 5 * 60 * 1000, // Зберігати в кеші 5 хвилин
   })
 
+  // Відкриті сповіщення про сплески звернень
+  const queryClient = useQueryClient()
+  const { data: volumeAlerts = [] } = useQuery({
+    queryKey: ['volume-alerts', 'open'],
+    queryFn: async () => {
+      const response = await volumeAlertsAPI.list('open')
+      return response.data || []
+    },
+    enabled: (user?.role === 'dispatcher' || user?.role === 'admin') && !!user,
+    refetchInterval: 60 * 1000,
+  })
+
+  const resolveAlertMutation = useMutation({
+    mutationFn: async ({ id, group }: { id: number; group: boolean }) =>
+      group ? volumeAlertsAPI.group(id) : volumeAlertsAPI.dismiss(id),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['volume-alerts'] })
+      queryClient.invalidateQueries({ queryKey: ['dispatcher-dashboard'] })
+    },
+  })
+
   if (!user || (user.role !== 'dispatcher' && user.role !== 'admin')) {
     return (
       <div className="bg-red-50 border border-red-200 text-red-700 px-4 py-3 rounded">
@@ -53,6 +74,7 @@ export default function DispatcherDashboardPage() {
   const overdueAppeals = (dashboard as any)?.overdue_appeals || []
   const staleAppeals = (dashboard as any)?.stale_appeals || []
   const approachingAppeals = (dashboard as any)?.approaching_appeals || []
+  const slaBreachCount = (dashboard as any)?.sla_breach_count || 0
 
   const toggleSection = (section: 'overdue' | 'approaching' | 'stale') => {
     setExpandedSections((prev) => ({
@@ -117,8 +139,47 @@ export default function DispatcherDashboardPage() {
         <h1 className="text-3xl font-bold text-gray-900">Дешборд диспетчера</h1>
       </div>
 
+      {/* Volume spike alerts */}
+      {volumeAlerts.length > 0 && (
+        <div className="space-y-3">
+          {volumeAlerts.map((alert) => (
+            <div key={alert.id} className="bg-red-50 border-2 border-red-300 rounded-lg shadow-sm p-4 flex items-center justify-between">
+              <div className="flex items-center space-x-3">
+                <TrendingUp className="h-6 w-6 text-red-600" />
+                <div>
+                  <h3 className="font-semibold text-red-700">
+                    Сплеск звернень: {alert.category?.name || `категорія #${alert.category_id}`}
+                    {alert.district && `, ${alert.district.name}`}
+                  </h3>
+                  <p className="text-sm text-red-600">
+                    {alert.recent_count} звернень з {format(new Date(alert.window_start), 'HH:mm', { locale: uk })} (зазвичай
+                    ~{alert.baseline_count.toFixed(1)})
+                  </p>
+                </div>
+              </div>
+              <div className="flex items-center space-x-2">
+                <button
+                  onClick={() => resolveAlertMutation.mutate({ id: alert.id, group: true })}
+                  disabled={resolveAlertMutation.isPending}
+                  className="px-3 py-2 text-sm bg-red-600 text-white rounded-md hover:bg-red-700 disabled:opacity-50"
+                >
+                  Об'єднати в інцидент
+                </button>
+                <button
+                  onClick={() => resolveAlertMutation.mutate({ id: alert.id, group: false })}
+                  disabled={resolveAlertMutation.isPending}
+                  className="px-3 py-2 text-sm border border-red-300 text-red-700 rounded-md hover:bg-red-100 disabled:opacity-50"
+                >
+                  Приховати
+                </button>
+              </div>
+            </div>
+          ))}
+        </div>
+      )}
+
       {/* Summary Cards */}
-      <div className="grid grid-cols-1 md:grid-cols-3 gap-4">
+      <div className="grid grid-cols-1 md:grid-cols-4 gap-4">
         <div className="bg-red-50 border-2 border-red-200 rounded-lg shadow-sm p-6">
           <div className="flex items-center space-x-3">
             <AlertTriangle className="h-8 w-8 text-red-600" />
@@ -146,6 +207,15 @@ export default function DispatcherDashboardPage() {
             </div>
           </div>
         </div>
+        <div className="bg-purple-50 border-2 border-purple-200 rounded-lg shadow-sm p-6">
+          <div className="flex items-center space-x-3">
+            <ShieldAlert className="h-8 w-8 text-purple-600" />
+            <div>
+              <h3 className="text-sm font-medium text-purple-600 mb-1">Порушення SLA</h3>
+              <p className="text-3xl font-bold text-purple-700">{slaBreachCount}</p>
+            </div>
+          </div>
+        </div>
       </div>
 
       {/* Accordion Sections */}
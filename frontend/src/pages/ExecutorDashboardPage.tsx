@@ -43,6 +43,8 @@ export default function ExecutorDashboardPage() {
   }
 
   const activeAppeals = dashboard?.active_appeals || []
+  const myAppeals = dashboard?.my_appeals || []
+  const unclaimedAppeals = dashboard?.unclaimed_appeals || []
   const myAvgTime = dashboard?.my_avg_processing_time || 0
   const serviceAvgTime = dashboard?.service_avg_processing_time || 0
 
@@ -134,7 +136,7 @@ export default function ExecutorDashboardPage() {
             <span>Мої активні завдання</span>
           </h2>
           <span className="text-sm text-gray-500">
-            Всього: {activeAppeals.length}
+            Всього: {activeAppeals.length} · Мої: {myAppeals.length} · Не взяті: {unclaimedAppeals.length}
           </span>
         </div>
         {activeAppeals.length === 0 ? (
@@ -131,6 +131,10 @@ function NotificationItem({
         return '💬'
       case 'appeal_completed':
         return '✅'
+      case 'volume_spike':
+        return '📈'
+      case 'saved_view_match':
+        return '🔖'
       default:
         return '🔔'
     }
@@ -148,6 +152,10 @@ function NotificationItem({
         return 'bg-green-100 border-green-200'
       case 'appeal_completed':
         return 'bg-green-100 border-green-200'
+      case 'volume_spike':
+        return 'bg-red-100 border-red-200'
+      case 'saved_view_match':
+        return 'bg-indigo-100 border-indigo-200'
       default:
         return 'bg-gray-100 border-gray-200'
     }
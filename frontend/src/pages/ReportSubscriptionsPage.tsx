@@ -0,0 +1,426 @@
+import { useState } from 'react'
+import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query'
+import { Plus, Send, Edit, Trash2, History, Save, X } from 'lucide-react'
+import { reportSubscriptionsAPI, servicesAPI } from '../lib/api'
+import { useAuth } from '../contexts/AuthContext'
+import type { ReportDateRange, ReportSubscription, ReportSubscriptionInput, ReportType } from '../types'
+
+const reportTypeLabels: Record<ReportType, string> = {
+  statistics: 'Статистика звернень',
+  admin_dashboard: 'Панель адміністратора',
+  service_statistics: 'Статистика служби',
+}
+
+const dateRangeLabels: Record<ReportDateRange, string> = {
+  last_day: 'Попередня доба',
+  last_7_days: 'Останні 7 днів',
+  last_30_days: 'Останні 30 днів',
+  previous_month: 'Попередній місяць',
+  month_to_date: 'З початку місяця',
+}
+
+// Готові розклади, щоб не писати cron-вираз вручну
+const schedulePresets = [
+  { label: 'Щодня о 8:00', value: '0 8 * * *' },
+  { label: 'Щопонеділка о 8:00', value: '0 8 * * 1' },
+  { label: '1-го числа о 8:00', value: '0 8 1 * *' },
+]
+
+const emptyForm: ReportSubscriptionInput = {
+  name: '',
+  report_type: 'statistics',
+  date_range: 'last_7_days',
+  recipients: [],
+  schedule: '0 8 * * 1',
+  is_active: true,
+}
+
+function formatDateTime(value?: string) {
+  return value ? new Date(value).toLocaleString('uk-UA') : '—'
+}
+
+export default function ReportSubscriptionsPage() {
+  const { user } = useAuth()
+  const queryClient = useQueryClient()
+  const [editingId, setEditingId] = useState<number | 'new' | null>(null)
+  const [form, setForm] = useState<ReportSubscriptionInput>(emptyForm)
+  const [recipientsText, setRecipientsText] = useState('')
+  const [historyId, setHistoryId] = useState<number | null>(null)
+  const [message, setMessage] = useState<{ type: 'success' | 'error'; text: string } | null>(null)
+
+  const isAdmin = user?.role === 'admin'
+
+  const { data: subscriptions, isLoading } = useQuery({
+    queryKey: ['report-subscriptions'],
+    queryFn: async () => {
+      const res = await reportSubscriptionsAPI.list()
+      return res.data || []
+    },
+    enabled: isAdmin,
+  })
+
+  const { data: services } = useQuery({
+    queryKey: ['services'],
+    queryFn: async () => {
+      const res = await servicesAPI.list()
+      return res.data || []
+    },
+    enabled: isAdmin,
+  })
+
+  const { data: deliveries } = useQuery({
+    queryKey: ['report-deliveries', historyId],
+    queryFn: async () => {
+      const res = await reportSubscriptionsAPI.deliveries(historyId!)
+      return res.data || []
+    },
+    enabled: isAdmin && historyId !== null,
+  })
+
+  const showError = (err: any, fallback: string) =>
+    setMessage({ type: 'error', text: err.response?.data?.error || fallback })
+
+  const saveMutation = useMutation({
+    mutationFn: (data: ReportSubscriptionInput) =>
+      editingId === 'new' ? reportSubscriptionsAPI.create(data) : reportSubscriptionsAPI.update(editingId as number, data),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['report-subscriptions'] })
+      setEditingId(null)
+      setMessage({ type: 'success', text: 'Підписку збережено' })
+    },
+    onError: (err: any) => showError(err, 'Не вдалося зберегти підписку'),
+  })
+
+  const deleteMutation = useMutation({
+    mutationFn: (id: number) => reportSubscriptionsAPI.delete(id),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['report-subscriptions'] })
+      setHistoryId(null)
+    },
+    onError: (err: any) => showError(err, 'Не вдалося видалити підписку'),
+  })
+
+  const sendMutation = useMutation({
+    mutationFn: (id: number) => reportSubscriptionsAPI.send(id),
+    onSuccess: (_, id) => {
+      queryClient.invalidateQueries({ queryKey: ['report-deliveries', id] })
+      setMessage({ type: 'success', text: 'Звіт надіслано' })
+    },
+    onError: (err: any, id) => {
+      queryClient.invalidateQueries({ queryKey: ['report-deliveries', id] })
+      showError(err, 'Не вдалося надіслати звіт')
+    },
+  })
+
+  const startEdit = (subscription?: ReportSubscription) => {
+    setMessage(null)
+    if (!subscription) {
+      setEditingId('new')
+      setForm(emptyForm)
+      setRecipientsText('')
+      return
+    }
+    setEditingId(subscription.id)
+    setForm({
+      name: subscription.name,
+      report_type: subscription.report_type,
+      service_id: subscription.service_id,
+      date_range: subscription.date_range,
+      recipients: subscription.recipients,
+      schedule: subscription.schedule,
+      is_active: subscription.is_active,
+    })
+    setRecipientsText(subscription.recipients.join(', '))
+  }
+
+  const handleSave = () => {
+    const recipients = recipientsText
+      .split(/[\s,;]+/)
+      .map((r) => r.trim())
+      .filter((r) => r.length > 0)
+    saveMutation.mutate({ ...form, recipients })
+  }
+
+  if (!isAdmin) {
+    return (
+      <div className="bg-red-50 border border-red-200 text-red-700 px-4 py-3 rounded">
+        У вас немає доступу до цієї сторінки
+      </div>
+    )
+  }
+
+  if (isLoading) {
+    return (
+      <div className="flex items-center justify-center h-64">
+        <div className="animate-spin rounded-full h-12 w-12 border-b-2 border-primary"></div>
+      </div>
+    )
+  }
+
+  return (
+    <div className="space-y-6">
+      <div className="flex items-center justify-between">
+        <h1 className="text-3xl font-bold text-gray-900">Звіти на пошту</h1>
+        {editingId === null && (
+          <button
+            onClick={() => startEdit()}
+            className="flex items-center space-x-2 px-4 py-2 bg-primary text-white rounded-md hover:bg-primary/90"
+          >
+            <Plus className="h-4 w-4" />
+            <span>Нова підписка</span>
+          </button>
+        )}
+      </div>
+
+      {message && (
+        <div
+          className={`rounded-md px-4 py-3 text-sm border ${
+            message.type === 'success'
+              ? 'bg-green-50 border-green-200 text-green-800'
+              : 'bg-red-50 border-red-200 text-red-700'
+          }`}
+        >
+          {message.text}
+        </div>
+      )}
+
+      {editingId !== null && (
+        <div className="bg-white rounded-lg shadow-sm border border-gray-200 p-6 space-y-4">
+          <h2 className="text-lg font-semibold text-gray-900">
+            {editingId === 'new' ? 'Нова підписка' : 'Редагування підписки'}
+          </h2>
+
+          <div>
+            <label className="block text-sm font-medium text-gray-700 mb-1">Назва</label>
+            <input
+              type="text"
+              value={form.name}
+              onChange={(e) => setForm({ ...form, name: e.target.value })}
+              className="w-full border border-gray-300 rounded-md px-3 py-2"
+              placeholder="Щотижнева статистика для мерії"
+            />
+          </div>
+
+          <div className="grid grid-cols-1 md:grid-cols-2 gap-4">
+            <div>
+              <label className="block text-sm font-medium text-gray-700 mb-1">Звіт</label>
+              <select
+                value={form.report_type}
+                onChange={(e) => setForm({ ...form, report_type: e.target.value as ReportType })}
+                disabled={editingId !== 'new'}
+                className="w-full border border-gray-300 rounded-md px-3 py-2 disabled:bg-gray-100"
+              >
+                {Object.entries(reportTypeLabels).map(([value, label]) => (
+                  <option key={value} value={value}>{label}</option>
+                ))}
+              </select>
+            </div>
+
+            {form.report_type === 'statistics' && (
+              <div>
+                <label className="block text-sm font-medium text-gray-700 mb-1">Період</label>
+                <select
+                  value={form.date_range}
+                  onChange={(e) => setForm({ ...form, date_range: e.target.value as ReportDateRange })}
+                  className="w-full border border-gray-300 rounded-md px-3 py-2"
+                >
+                  {Object.entries(dateRangeLabels).map(([value, label]) => (
+                    <option key={value} value={value}>{label}</option>
+                  ))}
+                </select>
+              </div>
+            )}
+
+            {form.report_type === 'service_statistics' && (
+              <div>
+                <label className="block text-sm font-medium text-gray-700 mb-1">Служба</label>
+                <select
+                  value={form.service_id ?? ''}
+                  onChange={(e) => setForm({ ...form, service_id: e.target.value ? Number(e.target.value) : undefined })}
+                  className="w-full border border-gray-300 rounded-md px-3 py-2"
+                >
+                  <option value="">Оберіть службу</option>
+                  {services?.map((service) => (
+                    <option key={service.id} value={service.id}>{service.name}</option>
+                  ))}
+                </select>
+              </div>
+            )}
+          </div>
+
+          <div>
+            <label className="block text-sm font-medium text-gray-700 mb-1">Отримувачі</label>
+            <textarea
+              rows={2}
+              value={recipientsText}
+              onChange={(e) => setRecipientsText(e.target.value)}
+              className="w-full border border-gray-300 rounded-md px-3 py-2"
+              placeholder="mayor@city.gov.ua, analytics@city.gov.ua"
+            />
+            <p className="mt-1 text-xs text-gray-500">Адреси через кому або з нового рядка.</p>
+          </div>
+
+          <div>
+            <label className="block text-sm font-medium text-gray-700 mb-1">Розклад (cron)</label>
+            <input
+              type="text"
+              value={form.schedule}
+              onChange={(e) => setForm({ ...form, schedule: e.target.value })}
+              className="w-full border border-gray-300 rounded-md px-3 py-2 font-mono"
+            />
+            <div className="mt-2 flex flex-wrap gap-2">
+              {schedulePresets.map((preset) => (
+                <button
+                  key={preset.value}
+                  type="button"
+                  onClick={() => setForm({ ...form, schedule: preset.value })}
+                  className="px-2 py-1 text-xs border border-gray-300 rounded-md text-gray-700 hover:bg-gray-50"
+                >
+                  {preset.label}
+                </button>
+              ))}
+            </div>
+            <p className="mt-1 text-xs text-gray-500">
+              хвилина, година, день місяця, місяць, день тижня — за часом сервера.
+            </p>
+          </div>
+
+          <label className="flex items-center space-x-2 text-sm text-gray-700">
+            <input
+              type="checkbox"
+              checked={form.is_active}
+              onChange={(e) => setForm({ ...form, is_active: e.target.checked })}
+            />
+            <span>Активна</span>
+          </label>
+
+          <div className="flex items-center space-x-2">
+            <button
+              onClick={handleSave}
+              disabled={saveMutation.isPending}
+              className="flex items-center space-x-2 px-4 py-2 bg-primary text-white rounded-md hover:bg-primary/90 disabled:opacity-50"
+            >
+              <Save className="h-4 w-4" />
+              <span>Зберегти</span>
+            </button>
+            <button
+              onClick={() => setEditingId(null)}
+              disabled={saveMutation.isPending}
+              className="flex items-center space-x-2 px-4 py-2 border border-gray-300 text-gray-700 rounded-md hover:bg-gray-50 disabled:opacity-50"
+            >
+              <X className="h-4 w-4" />
+              <span>Скасувати</span>
+            </button>
+          </div>
+        </div>
+      )}
+
+      <div className="bg-white rounded-lg shadow-sm border border-gray-200 overflow-hidden">
+        {subscriptions && subscriptions.length > 0 ? (
+          <table className="min-w-full divide-y divide-gray-200">
+            <thead className="bg-gray-50">
+              <tr>
+                <th className="px-4 py-3 text-left text-xs font-medium text-gray-500 uppercase">Назва</th>
+                <th className="px-4 py-3 text-left text-xs font-medium text-gray-500 uppercase">Звіт</th>
+                <th className="px-4 py-3 text-left text-xs font-medium text-gray-500 uppercase">Розклад</th>
+                <th className="px-4 py-3 text-left text-xs font-medium text-gray-500 uppercase">Наступне надсилання</th>
+                <th className="px-4 py-3 text-left text-xs font-medium text-gray-500 uppercase">Останнє</th>
+                <th className="px-4 py-3"></th>
+              </tr>
+            </thead>
+            <tbody className="divide-y divide-gray-200">
+              {subscriptions.map((subscription) => (
+                <tr key={subscription.id} className={subscription.is_active ? '' : 'text-gray-400'}>
+                  <td className="px-4 py-3 text-sm">
+                    <div className="font-medium">{subscription.name}</div>
+                    <div className="text-xs text-gray-500">{subscription.recipients.join(', ')}</div>
+                  </td>
+                  <td className="px-4 py-3 text-sm">
+                    {reportTypeLabels[subscription.report_type]}
+                    {subscription.report_type === 'statistics' && (
+                      <div className="text-xs text-gray-500">{dateRangeLabels[subscription.date_range]}</div>
+                    )}
+                    {subscription.service && (
+                      <div className="text-xs text-gray-500">{subscription.service.name}</div>
+                    )}
+                  </td>
+                  <td className="px-4 py-3 text-sm font-mono">{subscription.schedule}</td>
+                  <td className="px-4 py-3 text-sm">
+                    {subscription.is_active ? formatDateTime(subscription.next_run_at) : 'Вимкнено'}
+                    {subscription.failed_attempts > 0 && (
+                      <div className="text-xs text-red-600">Невдалих спроб: {subscription.failed_attempts}</div>
+                    )}
+                  </td>
+                  <td className="px-4 py-3 text-sm">{formatDateTime(subscription.last_run_at)}</td>
+                  <td className="px-4 py-3 text-sm">
+                    <div className="flex items-center justify-end space-x-2">
+                      <button
+                        onClick={() => sendMutation.mutate(subscription.id)}
+                        disabled={sendMutation.isPending}
+                        title="Надіслати зараз"
+                        className="p-2 text-gray-600 hover:text-primary disabled:opacity-50"
+                      >
+                        <Send className="h-4 w-4" />
+                      </button>
+                      <button
+                        onClick={() => setHistoryId(historyId === subscription.id ? null : subscription.id)}
+                        title="Історія надсилань"
+                        className="p-2 text-gray-600 hover:text-primary"
+                      >
+                        <History className="h-4 w-4" />
+                      </button>
+                      <button
+                        onClick={() => startEdit(subscription)}
+                        title="Редагувати"
+                        className="p-2 text-gray-600 hover:text-primary"
+                      >
+                        <Edit className="h-4 w-4" />
+                      </button>
+                      <button
+                        onClick={() => {
+                          if (confirm('Видалити підписку?')) {
+                            deleteMutation.mutate(subscription.id)
+                          }
+                        }}
+                        title="Видалити"
+                        className="p-2 text-gray-600 hover:text-red-600"
+                      >
+                        <Trash2 className="h-4 w-4" />
+                      </button>
+                    </div>
+                  </td>
+                </tr>
+              ))}
+            </tbody>
+          </table>
+        ) : (
+          <div className="p-6 text-sm text-gray-500">Підписок на звіти ще немає</div>
+        )}
+      </div>
+
+      {historyId !== null && (
+        <div className="bg-white rounded-lg shadow-sm border border-gray-200 p-6">
+          <h2 className="text-lg font-semibold text-gray-900 mb-4">Історія надсилань</h2>
+          {deliveries && deliveries.length > 0 ? (
+            <ul className="divide-y divide-gray-200">
+              {deliveries.map((delivery) => (
+                <li key={delivery.id} className="py-2 text-sm">
+                  <div className="flex items-center justify-between">
+                    <span className={delivery.status === 'sent' ? 'text-green-700' : 'text-red-700'}>
+                      {delivery.status === 'sent' ? 'Надіслано' : 'Помилка'}
+                      {delivery.attempt > 1 && ` (спроба ${delivery.attempt})`}
+                    </span>
+                    <span className="text-gray-500">{formatDateTime(delivery.created_at)}</span>
+                  </div>
+                  {delivery.error && <div className="text-xs text-red-600 mt-1">{delivery.error}</div>}
+                </li>
+              ))}
+            </ul>
+          ) : (
+            <p className="text-sm text-gray-500">Звіт ще не надсилався</p>
+          )}
+        </div>
+      )}
+    </div>
+  )
+}
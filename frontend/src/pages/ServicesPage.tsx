@@ -17,6 +17,7 @@ export default function ServicesPage() {
     contact_person: '',
     contact_phone: '',
     contact_email: '',
+    assignment_strategy: 'manual',
   })
 
   const { data: services, isLoading } = useQuery({
@@ -39,6 +40,7 @@ export default function ServicesPage() {
         contact_person: '',
         contact_phone: '',
         contact_email: '',
+        assignment_strategy: 'manual',
       })
     },
   })
@@ -54,14 +56,28 @@ export default function ServicesPage() {
         contact_person: '',
         contact_phone: '',
         contact_email: '',
+        assignment_strategy: 'manual',
       })
     },
   })
 
+  // Служба з відкритими зверненнями: пропонуємо перенести їх до іншої служби
+  const [reassigning, setReassigning] = useState<{ id: number; name: string } | null>(null)
+  const [reassignTo, setReassignTo] = useState('')
+
   const deleteMutation = useMutation({
-    mutationFn: (id: number) => servicesAPI.delete(id),
+    mutationFn: ({ id, reassignTo }: { id: number; name: string; reassignTo?: number }) =>
+      servicesAPI.delete(id, reassignTo),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ['services'] })
+      queryClient.invalidateQueries({ queryKey: ['appeals'] })
+      setReassigning(null)
+      setReassignTo('')
+    },
+    onError: (err: any, variables) => {
+      if (err.response?.status === 409) {
+        setReassigning({ id: variables.id, name: variables.name })
+      }
     },
   })
 
@@ -73,6 +89,7 @@ export default function ServicesPage() {
       contact_person: service.contact_person,
       contact_phone: service.contact_phone,
       contact_email: service.contact_email,
+      assignment_strategy: service.assignment_strategy || 'manual',
     })
     setIsCreating(false)
   }
@@ -86,6 +103,7 @@ export default function ServicesPage() {
       contact_person: '',
       contact_phone: '',
       contact_email: '',
+      assignment_strategy: 'manual',
     })
   }
 
@@ -98,6 +116,7 @@ export default function ServicesPage() {
       contact_person: '',
       contact_phone: '',
       contact_email: '',
+      assignment_strategy: 'manual',
     })
   }
 
@@ -227,6 +246,21 @@ export default function ServicesPage() {
                 placeholder="email@example.com"
               />
             </div>
+            <div>
+              <label className="block text-sm font-medium text-gray-700 mb-2">
+                Автоматичне призначення виконавця
+              </label>
+              <select
+                className="w-full px-3 py-2 border border-gray-300 rounded-md focus:outline-none focus:ring-primary focus:border-primary"
+                value={formData.assignment_strategy}
+                onChange={(e) => setFormData({ ...formData, assignment_strategy: e.target.value })}
+              >
+                <option value="manual">Вручну (диспетчер або виконавець)</option>
+                <option value="round_robin">По черзі</option>
+                <option value="least_open">Найменше відкритих звернень</option>
+                <option value="nearest">Найближчий виконавець</option>
+              </select>
+            </div>
             <div className="flex items-center space-x-2 pt-2">
               <button
                 onClick={handleSave}
@@ -249,6 +283,49 @@ export default function ServicesPage() {
         </div>
       )}
 
+      {reassigning && (
+        <div className="bg-yellow-50 border border-yellow-200 rounded-lg p-4 mb-6">
+          <p className="text-sm text-yellow-800 mb-3">
+            Служба «{reassigning.name}» має відкриті звернення. Оберіть службу, до якої їх буде перенесено;
+            закриті звернення залишаться за архівованою службою.
+          </p>
+          <div className="flex flex-wrap items-center gap-2">
+            <select
+              value={reassignTo}
+              onChange={(e) => setReassignTo(e.target.value)}
+              className="px-3 py-2 border border-gray-300 rounded-md text-sm"
+            >
+              <option value="">Оберіть службу</option>
+              {services
+                ?.filter((s: any) => s.id !== reassigning.id && s.is_active)
+                .map((s: any) => (
+                  <option key={s.id} value={s.id}>
+                    {s.name}
+                  </option>
+                ))}
+            </select>
+            <button
+              disabled={!reassignTo || deleteMutation.isPending}
+              onClick={() =>
+                deleteMutation.mutate({ ...reassigning, reassignTo: Number(reassignTo) })
+              }
+              className="px-4 py-2 text-sm bg-red-600 text-white rounded-md hover:bg-red-700 disabled:opacity-50"
+            >
+              Перенести та видалити
+            </button>
+            <button
+              onClick={() => {
+                setReassigning(null)
+                setReassignTo('')
+              }}
+              className="px-4 py-2 text-sm border border-gray-300 rounded-md hover:bg-gray-50"
+            >
+              Скасувати
+            </button>
+          </div>
+        </div>
+      )}
+
       <div className="bg-white rounded-lg shadow-sm border border-gray-200 overflow-hidden">
         <table className="min-w-full divide-y divide-gray-200">
           <thead className="bg-gray-50">
@@ -368,7 +445,7 @@ export default function ServicesPage() {
                           <button
                             onClick={() => {
                               if (confirm('Ви впевнені, що хочете видалити цю службу?')) {
-                                deleteMutation.mutate(service.id)
+                                deleteMutation.mutate({ id: service.id, name: service.name })
                               }
                             }}
                             className="text-red-600 hover:text-red-900"
@@ -20,6 +20,19 @@ export default function SystemSettingsPage() {
   const [zoom, setZoom] = useState(13)
   const [confidenceThreshold, setConfidenceThreshold] = useState(0.8)
   const [saveMessage, setSaveMessage] = useState<string | null>(null)
+  const [boundaryError, setBoundaryError] = useState<string | null>(null)
+
+  const { data: boundary } = useQuery({
+    queryKey: ['city-boundary'],
+    queryFn: async () => {
+      try {
+        const res = await systemSettingsAPI.getBoundary()
+        return res.data ?? null
+      } catch {
+        return null // межу ще не завантажено
+      }
+    },
+  })
 
   useEffect(() => {
     if (data) {
@@ -47,6 +60,25 @@ export default function SystemSettingsPage() {
     },
   })
 
+  const boundaryMutation = useMutation({
+    mutationFn: async (geojson: string | null) =>
+      geojson === null ? systemSettingsAPI.deleteBoundary() : systemSettingsAPI.updateBoundary(geojson),
+    onSuccess: () => {
+      queryClient.invalidateQueries({ queryKey: ['city-boundary'] })
+      setBoundaryError(null)
+      setSaveMessage('Межу міста збережено')
+      setTimeout(() => setSaveMessage(null), 3000)
+    },
+    onError: (err: any) => {
+      setBoundaryError(err.response?.data?.error || 'Не вдалося зберегти межу міста')
+    },
+  })
+
+  const handleBoundaryFile = async (file: File | undefined) => {
+    if (!file) return
+    boundaryMutation.mutate(await file.text())
+  }
+
   const handleLocationChange = (newLat: number, newLng: number) => {
     setLat(newLat)
     setLng(newLng)
@@ -149,6 +181,35 @@ export default function SystemSettingsPage() {
         </button>
       </div>
 
+      {/* Межа міста: звернення за її межами не приймаються */}
+      <div className="space-y-2">
+        <h2 className="text-lg font-semibold text-gray-900">Межа міста</h2>
+        <p className="text-sm text-gray-600">
+          {boundary
+            ? 'Межу завантажено. Звернення з точками поза нею відхиляються.'
+            : 'Межу не задано, звернення приймаються з будь-якої точки.'}
+        </p>
+        <div className="flex items-center space-x-3">
+          <input
+            type="file"
+            accept=".geojson,.json,application/geo+json,application/json"
+            onChange={(e) => handleBoundaryFile(e.target.files?.[0])}
+            disabled={boundaryMutation.status === 'pending'}
+            className="text-sm"
+          />
+          {boundary && (
+            <button
+              onClick={() => boundaryMutation.mutate(null)}
+              disabled={boundaryMutation.status === 'pending'}
+              className="px-3 py-1 text-sm text-red-700 border border-red-300 rounded-md hover:bg-red-50"
+            >
+              Видалити межу
+            </button>
+          )}
+        </div>
+        {boundaryError && <p className="text-sm text-red-600">{boundaryError}</p>}
+      </div>
+
       {/* Карта для вибору центру / прев'ю для користувачів */}
       <div className="mt-6">
         <p className="mb-2 text-sm text-gray-600">
@@ -13,10 +13,25 @@ export interface Category {
   id: number
   name: string
   description?: string
-  parent_id?: number
+  parent_id?: number  // батьківська категорія підкатегорії
   is_active: boolean
+  default_priority?: number
+  inherits_priority?: boolean  // пріоритет за замовчуванням узято з батьківської категорії
+  custom_fields?: CustomField[]
+  children?: Category[]  // лише в дереві категорій
+}
+
+// Додаткове поле категорії, яке заявник заповнює під час створення звернення
+export interface CustomField {
+  key: string
+  label: string
+  type: 'text' | 'number' | 'enum' | 'boolean'
+  required?: boolean
+  options?: string[]  // лише для enum
 }
 
+export type CustomFieldValues = Record<string, string | number | boolean>
+
 export interface Service {
   id: number
   name: string
@@ -26,6 +41,7 @@ export interface Service {
   contact_phone?: string
   contact_email?: string
   is_active: boolean
+  assignment_strategy?: 'manual' | 'round_robin' | 'least_open' | 'nearest'
 }
 
 export interface Appeal {
@@ -33,7 +49,9 @@ export interface Appeal {
   user_id: number
   category_id?: number
   service_id?: number
-  status: 'new' | 'assigned' | 'in_progress' | 'completed' | 'closed' | 'rejected'
+  assignee_id?: number
+  district_id?: number
+  status: 'new' | 'assigned' | 'in_progress' | 'completed' | 'closed' | 'rejected' | 'reopened'
   title: string
   description: string
   address: string
@@ -43,9 +61,87 @@ export interface Appeal {
   created_at: string
   updated_at: string
   closed_at?: string
+  completed_at?: string
+  reopen_count?: number
+  response_due_at?: string
+  due_at?: string
+  duplicate_of_id?: number
+  duplicate_score?: number
+  supporters_count?: number
+  distance_meters?: number  // лише для пошуку near
+  search_match?: AppealSearchMatch  // лише для текстового пошуку
+  parent_id?: number
+  custom_fields?: CustomFieldValues
+  linked_appeals?: Appeal[]
+  priority_rules?: AppealPriorityRule[]
   category?: Category
   service?: Service
   user?: User
+  assignee?: User
+  district?: District
+  tags?: Tag[]
+}
+
+// Мітка звернення: кураторські веде адміністратор, довільні додає персонал під час розмітки
+export interface Tag {
+  id: number
+  name: string
+  color?: string | null  // #rrggbb
+  is_curated: boolean
+  created_by?: number | null
+  created_at: string
+  updated_at: string
+  appeals_count: number
+}
+
+export interface TagInput {
+  name?: string
+  color?: string | null
+  is_curated?: boolean
+}
+
+// Масове додавання чи зняття міток; невідомі назви при додаванні створюють нові мітки
+export interface BulkTagInput {
+  appeal_ids: number[]
+  tag_ids?: number[]
+  tag_names?: string[]
+}
+
+// Підсвічені збіги повнотекстового пошуку (HTML з <mark>, екранований бекендом)
+export interface AppealSearchMatch {
+  rank: number
+  title: string
+  snippet: string
+  comment_snippet?: string
+}
+
+export interface District {
+  id: number
+  name: string
+  geometry?: Record<string, unknown>  // GeoJSON Polygon або MultiPolygon
+  min_lat: number
+  min_lng: number
+  max_lat: number
+  max_lng: number
+  is_active: boolean
+}
+
+export interface RoutingRule {
+  id: number
+  category_id?: number
+  district_id: number
+  service_id: number
+  category?: Category
+  district?: District
+  service?: Service
+}
+
+export interface AppealPriorityRule {
+  rule_id?: number
+  name: string
+  type: 'keyword' | 'photo_count' | 'nearby_open' | 'time_of_day'
+  priority: number
+  fired_at: string
 }
 
 export interface Photo {
@@ -59,6 +155,11 @@ export interface Photo {
   is_result_photo: boolean
   uploaded_at: string
   url?: string
+  // Зменшені копії та розміри; відсутні для необроблених фото
+  thumb_url?: string
+  medium_url?: string
+  width?: number
+  height?: number
 }
 
 export interface APIResponse<T = any> {
@@ -87,6 +188,10 @@ export interface CreateAppealRequest {
   address: string
   latitude: number
   longitude: number
+  priority?: number
+  custom_fields?: CustomFieldValues
+  // Завантажені заздалегідь фото автора
+  photo_ids?: number[]
 }
 
 export interface AppealsListParams {
@@ -95,20 +200,186 @@ export interface AppealsListParams {
   status?: string
   category_id?: number
   service_id?: number
+  district_id?: number
   user_id?: number
   search?: string
   sort_by?: string
   order?: 'asc' | 'desc'
   date_from?: string
   date_to?: string
+  near?: string  // "lat,lng"
+  radius_m?: number
+  bbox?: string  // "minLng,minLat,maxLng,maxLat"
+  search_comments?: boolean
+  cursor?: string
+  view_id?: number  // фільтри й сортування береться зі збереженого вигляду
+  tags_any?: string  // ID міток через кому: хоча б одна з них
+  tags_all?: string  // ID міток через кому: усі одразу
+}
+
+// GeoJSON-стрічка звернень для карти (GET /api/appeals/map)
+export interface AppealMapFeature {
+  type: 'Feature'
+  id: number | string
+  geometry: { type: 'Point'; coordinates: [number, number] }  // [lng, lat]
+  properties: {
+    // точка звернення
+    title?: string
+    status?: Appeal['status']
+    priority?: number
+    category_id?: number
+    category_name?: string
+    created_at?: string
+    // кластер (cluster=true)
+    cluster?: boolean
+    count?: number
+    by_status?: Partial<Record<Appeal['status'], number>>
+    appeal_id?: number
+  }
+}
+
+export interface AppealMapFeatureCollection {
+  type: 'FeatureCollection'
+  features: AppealMapFeature[]
+  zoom: number
+  center: [number, number]  // [lng, lat]
+  truncated?: boolean
+}
+
+// Осередок повторюваних звернень (кластеризація DBSCAN)
+export interface Hotspot {
+  id: number
+  category_id?: number  // немає — розрахунок по всіх категоріях
+  window_days: number
+  centroid_lat: number
+  centroid_lng: number
+  radius_meters: number
+  appeal_count: number
+  dominant_category_id?: number
+  recent_count: number  // звернення у новішій половині вікна
+  previous_count: number  // звернення у старшій половині вікна
+  trend: 'rising' | 'stable' | 'falling'
+  appeal_ids: number[]
+  computed_at: string
+  category?: Category
+  dominant_category?: Category
+}
+
+// Сплеск звернень у категорії (і районі, якщо відомий)
+export interface VolumeAlert {
+  id: number
+  category_id: number
+  district_id?: number  // немає — сплеск по всьому місту
+  window_start: string
+  window_end: string
+  recent_count: number
+  baseline_count: number  // скільки звернень зазвичай за таке ж вікно
+  appeal_ids: number[]
+  status: 'open' | 'grouped' | 'dismissed'
+  incident_appeal_id?: number
+  resolved_by?: number
+  resolved_at?: string
+  created_at: string
+  category?: Category
+  district?: District
+}
+
+export type ReportType = 'statistics' | 'admin_dashboard' | 'service_statistics'
+
+export type ReportDateRange = 'last_day' | 'last_7_days' | 'last_30_days' | 'previous_month' | 'month_to_date'
+
+export interface ReportSubscription {
+  id: number
+  name: string
+  report_type: ReportType
+  service_id?: number  // лише для service_statistics
+  date_range: ReportDateRange  // період враховується лише у statistics
+  recipients: string[]
+  schedule: string  // cron-вираз, наприклад "0 8 * * 1"
+  is_active: boolean
+  next_run_at?: string
+  last_run_at?: string
+  failed_attempts: number
+  created_by?: number
+  created_at: string
+  updated_at: string
+  service?: Service
+}
+
+export interface ReportSubscriptionInput {
+  name: string
+  report_type: ReportType
+  service_id?: number
+  date_range: ReportDateRange
+  recipients: string[]
+  schedule: string
+  is_active: boolean
+}
+
+export interface ReportDelivery {
+  id: number
+  subscription_id: number
+  status: 'sent' | 'failed'
+  attempt: number
+  recipients: string[]
+  period_from?: string
+  period_to?: string
+  error?: string
+  created_at: string
+}
+
+// Фільтри звернень у збереженому вигляді (серіалізовані AppealFilters бекенду)
+export interface SavedViewFilters {
+  status?: string | null
+  category_id?: number | null
+  service_id?: number | null
+  district_id?: number | null
+  assignee_id?: number | null
+  unclaimed?: boolean | null
+  overdue?: boolean | null
+  search?: string | null
+  search_comments?: boolean
+  from_date?: string | null
+  to_date?: string | null
+  tags_any?: number[] | null
+  tags_all?: number[] | null
+  custom_fields?: Record<string, string> | null
+}
+
+export interface SavedView {
+  id: number
+  user_id: number
+  name: string
+  filters: SavedViewFilters
+  sort_by: string
+  sort_order: 'asc' | 'desc'
+  shared_role?: 'dispatcher' | 'executor' | 'admin' | null
+  shared_service_id?: number | null
+  created_at: string
+  updated_at: string
+  owner?: User
+  shared_service?: Service
+  count?: number  // скільки звернень відповідає вигляду зараз
+  subscribed: boolean  // сповіщати про нові звернення
+}
+
+export interface SavedViewInput {
+  name: string
+  filters: SavedViewFilters
+  sort_by?: string
+  sort_order?: 'asc' | 'desc'
+  shared_role?: 'dispatcher' | 'executor' | 'admin' | null
+  shared_service_id?: number | null
 }
 
 export interface AppealsListResponse {
   items: Appeal[]  // Бекенд повертає items, а не appeals
-  total: number
+  total: number  // 0 у режимі курсора: загальна кількість не рахується
   page: number
   limit: number
   total_pages: number
+  next_cursor?: string  // курсор наступної сторінки (?cursor=)
+  prev_cursor?: string  // курсор попередньої сторінки
 }
 
 export interface Comment {
@@ -132,7 +403,7 @@ export interface Notification {
   id: number
   user_id: number
   appeal_id?: number
-  type: 'appeal_created' | 'appeal_assigned' | 'status_changed' | 'comment_added' | 'appeal_completed'
+  type: 'appeal_created' | 'appeal_assigned' | 'status_changed' | 'comment_added' | 'appeal_completed' | 'volume_spike' | 'saved_view_match'
   title: string
   message: string
   is_read: boolean